@@ -0,0 +1,195 @@
+// Command api_keys creates, lists, rotates and revokes API keys. The key itself is printed
+// once when it is created or rotated; only its hash is stored.
+//
+// Usage:
+//
+//	go run ./cmd/api_keys create -name "Discord bot" [-scope bot] [-va <VA ID>] [-server <Discord server ID>] [-expires-in-days 90]
+//	go run ./cmd/api_keys list [-all]
+//	go run ./cmd/api_keys rotate -id <key ID> [-grace 24h]
+//	go run ./cmd/api_keys revoke -id <key ID>
+//
+// Scopes are read_only, bot and admin; see constants.APIKeyScope.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"infinite-experiment/politburo/internal/constants"
+	"infinite-experiment/politburo/internal/db"
+	"infinite-experiment/politburo/internal/db/repositories"
+	"infinite-experiment/politburo/internal/services"
+)
+
+const usage = `usage: api_keys <command> [flags]
+
+commands:
+  create   issue a new key
+  list     list the keys
+  rotate   issue a replacement for a key
+  revoke   revoke a key
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var run func(ctx context.Context, svc *services.APIKeyService, args []string) error
+	switch os.Args[1] {
+	case "create":
+		run = create
+	case "list":
+		run = list
+	case "rotate":
+		run = rotate
+	case "revoke":
+		run = revoke
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err := db.InitPostgres(); err != nil {
+		log.Fatalf("Failed to connect to Postgres (sqlx): %v", err)
+	}
+	dsn := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
+		os.Getenv("PG_USER"), os.Getenv("PG_PASSWORD"), os.Getenv("PG_HOST"), os.Getenv("PG_PORT"), os.Getenv("PG_DB"))
+	if _, err := db.InitPostgresORM(dsn); err != nil {
+		log.Fatalf("Failed to connect to Postgres (GORM): %v", err)
+	}
+
+	svc := services.NewAPIKeyService(repositories.NewApiKeysRepo(db.DB), repositories.NewVAGormRepository(db.PgDB))
+	if err := run(context.Background(), svc, os.Args[2:]); err != nil {
+		log.Fatalf("%s: %v", os.Args[1], err)
+	}
+}
+
+func create(ctx context.Context, svc *services.APIKeyService, args []string) error {
+	fs := flag.NewFlagSet("create", flag.ExitOnError)
+	name := fs.String("name", "", "what the key is for (required)")
+	scope := fs.String("scope", constants.APIKeyScopeBot.String(), "read_only, bot or admin")
+	vaID := fs.String("va", "", "bind the key to this VA ID and its Discord server")
+	serverID := fs.String("server", "", "bind the key to this Discord server ID")
+	expiresInDays := fs.Int("expires-in-days", 0, "days until the key expires, 0 for never")
+	fs.Parse(args)
+
+	if *name == "" {
+		return fmt.Errorf("-name is required")
+	}
+
+	input := services.CreateAPIKeyInput{
+		Name:            *name,
+		Scope:           constants.APIKeyScope(*scope),
+		VAID:            *vaID,
+		DiscordServerID: *serverID,
+		CreatedBy:       "cli",
+	}
+	if *expiresInDays > 0 {
+		expiresAt := time.Now().UTC().AddDate(0, 0, *expiresInDays)
+		input.ExpiresAt = &expiresAt
+	}
+
+	issued, err := svc.Create(ctx, input)
+	if err != nil {
+		return err
+	}
+	printIssued(issued)
+	return nil
+}
+
+func list(ctx context.Context, svc *services.APIKeyService, args []string) error {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	all := fs.Bool("all", false, "include revoked keys")
+	fs.Parse(args)
+
+	keys, err := svc.List(ctx, *all)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tPREFIX\tNAME\tSCOPE\tSERVER\tEXPIRES\tLAST USED\tSTATUS")
+	for _, key := range keys {
+		status := "active"
+		switch {
+		case key.RevokedAt != nil:
+			status = "revoked"
+		case key.ExpiresAt != nil && !time.Now().UTC().Before(*key.ExpiresAt):
+			status = "expired"
+		case key.ReplacedBy != nil:
+			status = "rotated"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			key.ID, key.Prefix, key.Name, key.Scope, orDash(key.DiscordServerID), formatTime(key.ExpiresAt), formatTime(key.LastUsedAt), status)
+	}
+	return tw.Flush()
+}
+
+func rotate(ctx context.Context, svc *services.APIKeyService, args []string) error {
+	fs := flag.NewFlagSet("rotate", flag.ExitOnError)
+	id := fs.String("id", "", "ID of the key to rotate (required)")
+	grace := fs.Duration("grace", 0, "how long the old key keeps working, e.g. 24h")
+	fs.Parse(args)
+
+	if *id == "" {
+		return fmt.Errorf("-id is required")
+	}
+
+	issued, err := svc.Rotate(ctx, *id, *grace, "cli")
+	if err != nil {
+		return err
+	}
+	printIssued(issued)
+	return nil
+}
+
+func revoke(ctx context.Context, svc *services.APIKeyService, args []string) error {
+	fs := flag.NewFlagSet("revoke", flag.ExitOnError)
+	id := fs.String("id", "", "ID of the key to revoke (required)")
+	fs.Parse(args)
+
+	if *id == "" {
+		return fmt.Errorf("-id is required")
+	}
+
+	if err := svc.Revoke(ctx, *id); err != nil {
+		return err
+	}
+	fmt.Println("Revoked", *id)
+	return nil
+}
+
+func printIssued(issued *services.IssuedAPIKey) {
+	fmt.Printf("ID:      %s\n", issued.APIKey.ID)
+	fmt.Printf("Name:    %s\n", issued.APIKey.Name)
+	fmt.Printf("Scope:   %s\n", issued.APIKey.Scope)
+	if issued.APIKey.DiscordServerID != nil {
+		fmt.Printf("Server:  %s\n", *issued.APIKey.DiscordServerID)
+	}
+	if issued.APIKey.ExpiresAt != nil {
+		fmt.Printf("Expires: %s\n", formatTime(issued.APIKey.ExpiresAt))
+	}
+	fmt.Printf("\nNew API Key: %s\n", issued.Key)
+	fmt.Println("Store it now, it will not be shown again.")
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Format("2006-01-02 15:04") + " UTC"
+}
+
+func orDash(s *string) string {
+	if s == nil {
+		return "-"
+	}
+	return *s
+}
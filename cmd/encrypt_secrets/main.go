@@ -0,0 +1,132 @@
+// Command encrypt_secrets brings the credentials stored in the database up to date with the
+// primary key in SECRETS_KEYS. Run it once after enabling encryption to seal the existing
+// plain-text rows, and after every key rotation to re-wrap the rows sealed with an older key:
+//
+//  1. Prepend a new key to SECRETS_KEYS, keeping the old one: SECRETS_KEYS=new:<key>,old:<key>
+//  2. Deploy, then run encrypt_secrets
+//  3. Once the cached provider configs have expired (24 hours), drop the old key from SECRETS_KEYS
+//
+// It is safe to run repeatedly; rows that are already up to date are left alone.
+//
+// Usage:
+//
+//	go run ./cmd/encrypt_secrets [-dry-run]
+//	go run ./cmd/encrypt_secrets -generate-key
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"infinite-experiment/politburo/internal/common"
+	"infinite-experiment/politburo/internal/db"
+	"infinite-experiment/politburo/internal/db/repositories"
+	"infinite-experiment/politburo/internal/secrets"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "report what would change without writing")
+	generateKey := flag.Bool("generate-key", false, "print a new random key for SECRETS_KEYS and exit")
+	flag.Parse()
+
+	if *generateKey {
+		key, err := secrets.GenerateKey()
+		if err != nil {
+			log.Fatalf("Failed to generate key: %v", err)
+		}
+		fmt.Println(key)
+		return
+	}
+
+	keyring, err := secrets.KeyringFromEnv()
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	if keyring == nil {
+		log.Fatalf("SECRETS_KEYS is not set")
+	}
+
+	if err := db.InitPostgres(); err != nil {
+		log.Fatalf("Failed to connect to Postgres (sqlx): %v", err)
+	}
+	dsn := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
+		os.Getenv("PG_USER"), os.Getenv("PG_PASSWORD"), os.Getenv("PG_HOST"), os.Getenv("PG_PORT"), os.Getenv("PG_DB"))
+	if _, err := db.InitPostgresORM(dsn); err != nil {
+		log.Fatalf("Failed to connect to Postgres (GORM): %v", err)
+	}
+
+	ctx := context.Background()
+	rewrap := func(value string) (string, error) {
+		rewrapped, _, err := keyring.Rewrap(value)
+		return rewrapped, err
+	}
+
+	log.Printf("Sealing credentials with key %q (dry run: %t)", keyring.PrimaryKeyID(), *dryRun)
+
+	// Data provider configs
+	configRepo := repositories.NewDataProviderConfigRepo(db.PgDB, keyring)
+	configs, err := configRepo.GetAllConfigsSealed(ctx)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	updatedConfigs, failed := 0, 0
+	for _, config := range configs {
+		configData, changed, err := repositories.MapCredentialSecrets(config.ConfigData, rewrap)
+		if err != nil {
+			log.Printf("Config %s (VA %s, %s): %v", config.ID, config.VAID, config.ProviderType, err)
+			failed++
+			continue
+		}
+		if !changed {
+			continue
+		}
+		if !*dryRun {
+			if err := configRepo.UpdateConfigDataSealed(ctx, config.ID, configData); err != nil {
+				log.Printf("Config %s: %v", config.ID, err)
+				failed++
+				continue
+			}
+		}
+		updatedConfigs++
+	}
+	log.Printf("Data provider configs: %d of %d updated", updatedConfigs, len(configs))
+
+	// VA config values
+	vaRepo := repositories.NewVARepository(db.DB)
+	updatedValues, totalValues := 0, 0
+	for _, key := range common.ListSecretVAConfigKeys() {
+		rows, err := vaRepo.GetVAConfigsByKey(ctx, key)
+		if err != nil {
+			log.Fatalf("Failed to read %s values: %v", key, err)
+		}
+		totalValues += len(rows)
+
+		for _, row := range rows {
+			value, changed, err := keyring.Rewrap(row.ConfigValue)
+			if err != nil {
+				log.Printf("VA %s %s: %v", row.VAID, key, err)
+				failed++
+				continue
+			}
+			if !changed {
+				continue
+			}
+			if !*dryRun {
+				if err := vaRepo.UpsertVAConfig(ctx, row.VAID, key, value); err != nil {
+					log.Printf("VA %s %s: %v", row.VAID, key, err)
+					failed++
+					continue
+				}
+			}
+			updatedValues++
+		}
+	}
+	log.Printf("VA config values: %d of %d updated", updatedValues, totalValues)
+
+	if failed > 0 {
+		log.Fatalf("%d rows could not be updated", failed)
+	}
+}
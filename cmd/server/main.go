@@ -1,13 +1,18 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"infinite-experiment/politburo/internal/common"
 	"infinite-experiment/politburo/internal/db"
 	"infinite-experiment/politburo/internal/logging"
 	"infinite-experiment/politburo/internal/routes"
@@ -67,9 +72,14 @@ func main() {
 
 	upSince := time.Now()
 
+	// Cancelled on SIGINT/SIGTERM to stop the background jobs and workers
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	var background sync.WaitGroup
+
 	// Initialize router with Chi
 	// Note: metricsReg is created in RegisterRoutes and applied as global middleware
-	router := routes.RegisterRoutes(upSince)
+	router := routes.RegisterRoutes(ctx, upSince, &background)
 
 	// Setup metrics endpoint outside of Chi router
 	mux := http.NewServeMux()
@@ -83,5 +93,11 @@ func main() {
 	)
 
 	log.Println("Starting server on :8080")
-	log.Fatal(http.ListenAndServe(":8080", mux))
+	srv := &http.Server{Addr: ":8080", Handler: mux}
+	if err := common.ServeUntilDone(ctx, srv, &background, common.ShutdownTimeout()); err != nil {
+		logging.Error("Shutdown incomplete", "error", err.Error())
+		logging.Close()
+		os.Exit(1)
+	}
+	logging.Info("Politburo stopped")
 }
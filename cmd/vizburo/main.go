@@ -1,12 +1,17 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 
+	"infinite-experiment/politburo/internal/common"
 	"infinite-experiment/politburo/internal/db"
 	"infinite-experiment/politburo/internal/routes"
 )
@@ -40,8 +45,13 @@ func main() {
 
 	upSince := time.Now()
 
+	// Cancelled on SIGINT/SIGTERM to stop the background jobs and workers
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	var background sync.WaitGroup
+
 	// Initialize router with Chi (same router as API, includes UI and API routes)
-	router := routes.RegisterRoutes(upSince)
+	router := routes.RegisterRoutes(ctx, upSince, &background)
 
 	// Get port from environment or use default 3000
 	vizburoPort := os.Getenv("VIZBURO_PORT")
@@ -51,5 +61,9 @@ func main() {
 
 	listenAddr := ":" + vizburoPort
 	log.Println("🚀 Vizburo UI Service starting on " + listenAddr)
-	log.Fatal(http.ListenAndServe(listenAddr, router))
+	srv := &http.Server{Addr: listenAddr, Handler: router}
+	if err := common.ServeUntilDone(ctx, srv, &background, common.ShutdownTimeout()); err != nil {
+		log.Fatalf("❌ Shutdown incomplete: %v", err)
+	}
+	log.Println("Vizburo UI Service stopped")
 }
@@ -0,0 +1,151 @@
+// Package airtable builds Airtable formulas, such as the filterByFormula of a list request.
+//
+// Formulas are assembled from typed parts instead of formatted strings, so field names and
+// values are always escaped: a callsign or field name containing a quote, brace or
+// backslash can neither break a query nor change its meaning.
+//
+//	airtable.And(
+//		airtable.Equals(airtable.Field("Status"), "Approved"),
+//		airtable.IsAfter(airtable.LastModifiedTime(), since),
+//	).String()
+//	// AND({Status} = "Approved", IS_AFTER(LAST_MODIFIED_TIME(), "2024-05-01T12:00:00Z"))
+package airtable
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formula is an Airtable formula expression. The zero Formula is empty and means no filter.
+type Formula struct {
+	expr string
+}
+
+// String returns the formula text
+func (f Formula) String() string {
+	return f.expr
+}
+
+// IsZero reports whether the formula is empty
+func (f Formula) IsZero() bool {
+	return f.expr == ""
+}
+
+// Field references a field by name
+func Field(name string) Formula {
+	return Formula{"{" + fieldEscaper.Replace(name) + "}"}
+}
+
+// LastModifiedTime is the time a record was last modified, or when fields are given, the
+// last time any of those fields was modified
+func LastModifiedTime(fields ...string) Formula {
+	refs := make([]string, len(fields))
+	for i, field := range fields {
+		refs[i] = Field(field).expr
+	}
+	return Formula{"LAST_MODIFIED_TIME(" + strings.Join(refs, ", ") + ")"}
+}
+
+// Value converts a Go value into a formula literal. Strings and times become quoted
+// strings (times in RFC 3339, UTC), numbers stay numeric, booleans become TRUE() or
+// FALSE(), nil becomes BLANK() and a Formula is used as is.
+func Value(v interface{}) Formula {
+	switch v := v.(type) {
+	case Formula:
+		return v
+	case nil:
+		return Formula{"BLANK()"}
+	case string:
+		return Formula{`"` + stringEscaper.Replace(v) + `"`}
+	case time.Time:
+		return Value(v.UTC().Format(time.RFC3339))
+	case bool:
+		if v {
+			return Formula{"TRUE()"}
+		}
+		return Formula{"FALSE()"}
+	case int:
+		return Formula{strconv.Itoa(v)}
+	case int64:
+		return Formula{strconv.FormatInt(v, 10)}
+	case float64:
+		return Formula{strconv.FormatFloat(v, 'f', -1, 64)}
+	case fmt.Stringer:
+		return Value(v.String())
+	default:
+		return Value(fmt.Sprint(v))
+	}
+}
+
+// Equals is true when left equals value
+func Equals(left Formula, value interface{}) Formula {
+	return Formula{left.expr + " = " + Value(value).expr}
+}
+
+// NotEquals is true when left differs from value
+func NotEquals(left Formula, value interface{}) Formula {
+	return Formula{left.expr + " != " + Value(value).expr}
+}
+
+// In is true when left equals any of values; with no values it is always false
+func In(left Formula, values ...interface{}) Formula {
+	parts := make([]Formula, len(values))
+	for i, value := range values {
+		parts[i] = Equals(left, value)
+	}
+	return Or(parts...)
+}
+
+// IsAfter is true when the date left is after t
+func IsAfter(left Formula, t time.Time) Formula {
+	return Formula{"IS_AFTER(" + left.expr + ", " + Value(t).expr + ")"}
+}
+
+// IsBefore is true when the date left is before t
+func IsBefore(left Formula, t time.Time) Formula {
+	return Formula{"IS_BEFORE(" + left.expr + ", " + Value(t).expr + ")"}
+}
+
+// And is true when every part is. Empty parts are skipped; with none left the formula is empty.
+func And(parts ...Formula) Formula {
+	return combine("AND", Formula{}, parts)
+}
+
+// Or is true when any part is. Empty parts are skipped; with none left it is always false.
+func Or(parts ...Formula) Formula {
+	return combine("OR", Value(false), parts)
+}
+
+// Not negates a formula
+func Not(f Formula) Formula {
+	return Formula{"NOT(" + f.expr + ")"}
+}
+
+// combine joins the non-empty parts with a logical function
+func combine(function string, none Formula, parts []Formula) Formula {
+	exprs := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if !part.IsZero() {
+			exprs = append(exprs, part.expr)
+		}
+	}
+
+	switch len(exprs) {
+	case 0:
+		return none
+	case 1:
+		return Formula{exprs[0]}
+	default:
+		return Formula{function + "(" + strings.Join(exprs, ", ") + ")"}
+	}
+}
+
+var (
+	// fieldEscaper escapes a field name inside {braces}
+	fieldEscaper = strings.NewReplacer(`\`, `\\`, `}`, `\}`)
+
+	// stringEscaper escapes a string inside "double quotes"
+	stringEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+)
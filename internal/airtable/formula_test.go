@@ -0,0 +1,54 @@
+package airtable
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormula(t *testing.T) {
+	since := time.Date(2024, 5, 1, 14, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+
+	tests := []struct {
+		name    string
+		formula Formula
+		want    string
+	}{
+		{"field", Field("Callsign"), `{Callsign}`},
+		{"field with brace", Field("Hours {total}"), `{Hours {total\}}`},
+		{"field with backslash", Field(`Pilot\Name`), `{Pilot\\Name}`},
+		{"equals string", Equals(Field("Callsign"), "ABC001"), `{Callsign} = "ABC001"`},
+		{"equals single quote", Equals(Field("Name"), "O'Brien"), `{Name} = "O'Brien"`},
+		{"equals double quote", Equals(Field("Name"), `Say "hi"`), `{Name} = "Say \"hi\""`},
+		{"equals trailing backslash", Equals(Field("Name"), `ABC\`), `{Name} = "ABC\\"`},
+		{"equals injection", Equals(Field("Callsign"), `x", TRUE()) & ("`), `{Callsign} = "x\", TRUE()) & (\""`},
+		{"equals newline", Equals(Field("Notes"), "a\nb"), `{Notes} = "a\nb"`},
+		{"equals int", Equals(Field("Flights"), 12), `{Flights} = 12`},
+		{"equals float", Equals(Field("Hours"), 1.5), `{Hours} = 1.5`},
+		{"equals bool", Equals(Field("Active"), true), `{Active} = TRUE()`},
+		{"equals blank", Equals(Field("Rank"), nil), `{Rank} = BLANK()`},
+		{"not equals", NotEquals(Field("Status"), "Rejected"), `{Status} != "Rejected"`},
+		{"in", In(Field("Status"), "Approved", "Pending"), `OR({Status} = "Approved", {Status} = "Pending")`},
+		{"in single", In(Field("Status"), "Approved"), `{Status} = "Approved"`},
+		{"in none", In(Field("Status")), `FALSE()`},
+		{"is after", IsAfter(Field("Modified"), since), `IS_AFTER({Modified}, "2024-05-01T12:00:00Z")`},
+		{"is before", IsBefore(Field("Modified"), since), `IS_BEFORE({Modified}, "2024-05-01T12:00:00Z")`},
+		{"last modified", IsAfter(LastModifiedTime(), since), `IS_AFTER(LAST_MODIFIED_TIME(), "2024-05-01T12:00:00Z")`},
+		{"last modified fields", LastModifiedTime("Callsign", "Rank"), `LAST_MODIFIED_TIME({Callsign}, {Rank})`},
+		{
+			"and or",
+			And(Equals(Field("Active"), true), Or(Equals(Field("Rank"), "Captain"), Not(Equals(Field("Hours"), 0)))),
+			`AND({Active} = TRUE(), OR({Rank} = "Captain", NOT({Hours} = 0)))`,
+		},
+		{"and skips empty", And(Formula{}, Equals(Field("Active"), true)), `{Active} = TRUE()`},
+		{"and empty", And(), ``},
+		{"or empty", Or(Formula{}), `FALSE()`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.formula.String(); got != tt.want {
+				t.Errorf("got  %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"infinite-experiment/politburo/internal/auth"
+	"infinite-experiment/politburo/internal/common"
+	"infinite-experiment/politburo/internal/constants"
+	"infinite-experiment/politburo/internal/models/entities"
+	"infinite-experiment/politburo/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// APIKeyResponse represents an API key; the key itself is only returned when it is issued
+type APIKeyResponse struct {
+	ID              string     `json:"id"`
+	Name            string     `json:"name"`
+	Prefix          string     `json:"prefix"`
+	Scope           string     `json:"scope"`
+	VAID            *string    `json:"va_id,omitempty"`
+	DiscordServerID *string    `json:"discord_server_id,omitempty"`
+	ExpiresAt       *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt      *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt       *time.Time `json:"revoked_at,omitempty"`
+	ReplacedBy      *string    `json:"replaced_by,omitempty"`
+	CreatedBy       *string    `json:"created_by,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	Key             string     `json:"key,omitempty"`
+}
+
+// CreateAPIKeyRequest describes a new API key
+type CreateAPIKeyRequest struct {
+	Name            string `json:"name"`
+	Scope           string `json:"scope"`                       // "read_only", "bot" or "admin"
+	VAID            string `json:"va_id,omitempty"`             // Bind the key to a VA and its Discord server
+	DiscordServerID string `json:"discord_server_id,omitempty"` // Bind the key to a Discord server
+	ExpiresInDays   int    `json:"expires_in_days,omitempty"`   // 0 for a key that does not expire
+}
+
+// RotateAPIKeyRequest sets how long the old key keeps working
+type RotateAPIKeyRequest struct {
+	GracePeriod string `json:"grace_period,omitempty"` // A duration such as "24h"; the old key stops working immediately if empty
+}
+
+func newAPIKeyResponse(key *entities.ApiKey) APIKeyResponse {
+	return APIKeyResponse{
+		ID:              key.ID,
+		Name:            key.Name,
+		Prefix:          key.Prefix,
+		Scope:           key.Scope.String(),
+		VAID:            key.VAID,
+		DiscordServerID: key.DiscordServerID,
+		ExpiresAt:       key.ExpiresAt,
+		LastUsedAt:      key.LastUsedAt,
+		RevokedAt:       key.RevokedAt,
+		ReplacedBy:      key.ReplacedBy,
+		CreatedBy:       key.CreatedBy,
+		CreatedAt:       key.CreatedAt,
+	}
+}
+
+// respondAPIKeyError maps API key service errors to HTTP responses
+func respondAPIKeyError(w http.ResponseWriter, initTime time.Time, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrAPIKeyNotFound):
+		common.RespondError(w, initTime, err, "API key not found", http.StatusNotFound)
+	case errors.Is(err, services.ErrAPIKeyRevoked), errors.Is(err, services.ErrAPIKeyExpired):
+		common.RespondError(w, initTime, err, "API key is no longer active", http.StatusConflict)
+	case errors.Is(err, services.ErrInvalidAPIKeyScope), errors.Is(err, services.ErrAPIKeyBindingMismatch):
+		common.RespondError(w, initTime, err, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, services.ErrAPIKeyVANotFound):
+		common.RespondError(w, initTime, err, "Virtual airline not found", http.StatusNotFound)
+	default:
+		common.RespondError(w, initTime, err, message, http.StatusInternalServerError)
+	}
+}
+
+// ListAPIKeys handles GET /api/v1/admin/api-keys
+// Returns the API keys, newest first; revoked keys only with include_revoked=true (god-only)
+func (h *Handlers) ListAPIKeys() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		initTime := time.Now()
+
+		keys, err := h.deps.Services.APIKeys.List(r.Context(), r.URL.Query().Get("include_revoked") == "true")
+		if err != nil {
+			respondAPIKeyError(w, initTime, err, "Failed to fetch API keys")
+			return
+		}
+
+		response := make([]APIKeyResponse, 0, len(keys))
+		for i := range keys {
+			response = append(response, newAPIKeyResponse(&keys[i]))
+		}
+
+		common.RespondSuccess(w, initTime, "API keys fetched successfully", response)
+	}
+}
+
+// CreateAPIKey handles POST /api/v1/admin/api-keys
+// Issues a new API key; the key is only ever returned in this response (god-only)
+func (h *Handlers) CreateAPIKey() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		initTime := time.Now()
+
+		var req CreateAPIKeyRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			common.RespondError(w, initTime, err, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		if req.Name == "" {
+			common.RespondError(w, initTime, fmt.Errorf("missing name"), "Name is required", http.StatusBadRequest)
+			return
+		}
+		if req.ExpiresInDays < 0 {
+			common.RespondError(w, initTime, fmt.Errorf("negative expires_in_days"), "expires_in_days must not be negative", http.StatusBadRequest)
+			return
+		}
+
+		input := services.CreateAPIKeyInput{
+			Name:            req.Name,
+			Scope:           constants.APIKeyScope(req.Scope),
+			VAID:            req.VAID,
+			DiscordServerID: req.DiscordServerID,
+			CreatedBy:       auth.GetUserClaims(r.Context()).DiscordUserID(),
+		}
+		if req.ExpiresInDays > 0 {
+			expiresAt := time.Now().UTC().AddDate(0, 0, req.ExpiresInDays)
+			input.ExpiresAt = &expiresAt
+		}
+
+		issued, err := h.deps.Services.APIKeys.Create(r.Context(), input)
+		if err != nil {
+			respondAPIKeyError(w, initTime, err, "Failed to create API key")
+			return
+		}
+
+		response := newAPIKeyResponse(issued.APIKey)
+		response.Key = issued.Key
+		common.RespondSuccess(w, initTime, "API key created successfully; store the key now, it will not be shown again", response, http.StatusCreated)
+	}
+}
+
+// RotateAPIKey handles POST /api/v1/admin/api-keys/{key_id}/rotate
+// Issues a replacement key; the old key keeps working for the grace period (god-only)
+func (h *Handlers) RotateAPIKey() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		initTime := time.Now()
+
+		keyID := chi.URLParam(r, "key_id")
+		if keyID == "" {
+			common.RespondError(w, initTime, fmt.Errorf("missing key_id"), "Key ID is required", http.StatusBadRequest)
+			return
+		}
+
+		var req RotateAPIKeyRequest
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				common.RespondError(w, initTime, err, "Invalid request body", http.StatusBadRequest)
+				return
+			}
+		}
+		var grace time.Duration
+		if req.GracePeriod != "" {
+			var err error
+			if grace, err = time.ParseDuration(req.GracePeriod); err != nil || grace < 0 {
+				common.RespondError(w, initTime, fmt.Errorf("invalid grace_period %q", req.GracePeriod), "grace_period must be a duration such as \"24h\"", http.StatusBadRequest)
+				return
+			}
+		}
+
+		issued, err := h.deps.Services.APIKeys.Rotate(r.Context(), keyID, grace, auth.GetUserClaims(r.Context()).DiscordUserID())
+		if err != nil {
+			respondAPIKeyError(w, initTime, err, "Failed to rotate API key")
+			return
+		}
+
+		response := newAPIKeyResponse(issued.APIKey)
+		response.Key = issued.Key
+		common.RespondSuccess(w, initTime, "API key rotated successfully; store the new key now, it will not be shown again", response)
+	}
+}
+
+// RevokeAPIKey handles POST /api/v1/admin/api-keys/{key_id}/revoke
+// Revokes an API key immediately (god-only)
+func (h *Handlers) RevokeAPIKey() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		initTime := time.Now()
+
+		keyID := chi.URLParam(r, "key_id")
+		if keyID == "" {
+			common.RespondError(w, initTime, fmt.Errorf("missing key_id"), "Key ID is required", http.StatusBadRequest)
+			return
+		}
+
+		if err := h.deps.Services.APIKeys.Revoke(r.Context(), keyID); err != nil {
+			respondAPIKeyError(w, initTime, err, "Failed to revoke API key")
+			return
+		}
+
+		common.RespondSuccess(w, initTime, "API key revoked successfully", map[string]string{
+			"key_id": keyID,
+		})
+	}
+}
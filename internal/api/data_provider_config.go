@@ -2,10 +2,12 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"infinite-experiment/politburo/internal/auth"
 	"infinite-experiment/politburo/internal/common"
 	"infinite-experiment/politburo/internal/constants"
 	"infinite-experiment/politburo/internal/models/dtos"
+	"infinite-experiment/politburo/internal/secrets"
 	"infinite-experiment/politburo/internal/services"
 	"net/http"
 	"time"
@@ -44,8 +46,17 @@ func SaveDataProviderConfigHandler(deps *Dependencies) http.HandlerFunc {
 	}
 }
 
+// secretsNotConfiguredMessage is returned when credentials are saved on a server without
+// SECRETS_KEYS, which they are encrypted with
+const secretsNotConfiguredMessage = "Credentials can't be saved: encryption is not configured on the server (SECRETS_KEYS)"
+
 // handleConfigError maps config service errors to appropriate HTTP responses
 func handleConfigError(w http.ResponseWriter, initTime time.Time, err error) {
+	if errors.Is(err, secrets.ErrNoKey) {
+		common.RespondError(w, initTime, err, secretsNotConfiguredMessage, http.StatusUnprocessableEntity)
+		return
+	}
+
 	// Check if it's a ConfigError with specific error code
 	if configErr, ok := err.(*services.ConfigError); ok {
 		statusCode := http.StatusInternalServerError
@@ -6,6 +6,7 @@ import (
 	"infinite-experiment/politburo/internal/db/repositories"
 	"infinite-experiment/politburo/internal/metrics"
 	"infinite-experiment/politburo/internal/providers"
+	"infinite-experiment/politburo/internal/secrets"
 	"infinite-experiment/politburo/internal/services"
 	"log"
 	"os"
@@ -25,9 +26,18 @@ type Repositories struct {
 	PilotATSynced         *repositories.PilotATSyncedRepo
 	RouteATSynced         *repositories.RouteATSyncedRepo
 	PirepATSynced         *repositories.PirepATSyncedRepo
+	Pirep                 *repositories.PirepRepo
+	PirepOutbox           *repositories.PirepOutboxRepo
+	PilotSyncChange       *repositories.PilotSyncChangeRepo
+	SyncRun               *repositories.SyncRunRepo
+	JobSchedule           *repositories.JobScheduleRepo
+	FlightModesConfigVer  *repositories.FlightModesConfigVersionRepo
 	AircraftLivery        *repositories.AircraftLiveryRepository
 	LiveryAirtableMapping *repositories.LiveryAirtableMappingRepository
 	AirportsRepo          *repositories.AirportRepository
+	Rank                  *repositories.RankRepo
+	SyncedRecord          *repositories.SyncedRecordRepo
+	VAUserRole            *repositories.VAUserRoleRepository
 }
 
 type Services struct {
@@ -40,15 +50,22 @@ type Services struct {
 	Conf               common.VAConfigService
 	VaMgmt             services.VAManagementService
 	AirtableApi        common.AirtableApiService
-	AirtableProvider   *providers.AirtableProvider
+	DataProviders      *providers.Registry
 	AirtableSync       services.AtSyncService
 	Flights            services.FlightsService
 	PilotStats         *services.PilotStatsService
 	DataProviderConfig *services.DataProviderConfigService
+	PirepReview        *services.PirepReviewService
+	PirepDelivery      *services.PirepDeliveryService
+	PilotSync          *services.PilotSyncService
+	FlightModesConfig  *services.FlightModesConfigService
+	RouteCatalogue     *services.RouteCatalogueService
+	Rank               *services.RankService
 	AircraftLivery     *common.AircraftLiveryService
 	RedisQueue         common.RedisQueueService
 	URLSigner          *common.URLSignerService
 	Session            *common.SessionService
+	APIKeys            *services.APIKeyService
 }
 type Dependencies struct {
 	Repo     *Repositories
@@ -57,6 +74,15 @@ type Dependencies struct {
 
 func InitDependencies(metricsReg *metrics.MetricsRegistry) (*Dependencies, error) {
 
+	// Key for the credentials stored in provider configs and VA configs
+	keyring, err := secrets.KeyringFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if keyring == nil {
+		log.Println("WARNING: SECRETS_KEYS is not set: saving provider credentials or airtable_api_key will be rejected with 422 until it is")
+	}
+
 	repositories := &Repositories{
 		User:                  *repositories.NewUserRepository(db.DB),
 		UserGorm:              repositories.NewUserRepositoryGORM(db.PgDB),
@@ -64,14 +90,23 @@ func InitDependencies(metricsReg *metrics.MetricsRegistry) (*Dependencies, error
 		Va:                    *repositories.NewVARepository(db.DB),
 		VAGorm:                repositories.NewVAGormRepository(db.PgDB),
 		UserVASync:            *repositories.NewSyncRepository(db.DB),
-		DataProviderCfg:       repositories.NewDataProviderConfigRepo(db.PgDB),
+		DataProviderCfg:       repositories.NewDataProviderConfigRepo(db.PgDB, keyring),
 		VASyncHistory:         repositories.NewVASyncHistoryRepo(db.PgDB),
 		PilotATSynced:         repositories.NewPilotATSyncedRepo(db.PgDB),
 		RouteATSynced:         repositories.NewRouteATSyncedRepo(db.PgDB),
 		PirepATSynced:         repositories.NewPirepATSyncedRepo(db.PgDB),
+		Pirep:                 repositories.NewPirepRepo(db.PgDB),
+		PirepOutbox:           repositories.NewPirepOutboxRepo(db.PgDB),
+		PilotSyncChange:       repositories.NewPilotSyncChangeRepo(db.PgDB),
+		SyncRun:               repositories.NewSyncRunRepo(db.PgDB),
+		JobSchedule:           repositories.NewJobScheduleRepo(db.PgDB),
+		FlightModesConfigVer:  repositories.NewFlightModesConfigVersionRepo(db.PgDB),
 		AircraftLivery:        repositories.NewAircraftLiveryRepository(db.PgDB),
 		LiveryAirtableMapping: repositories.NewLiveryAirtableMappingRepository(db.PgDB),
 		AirportsRepo:          repositories.NewAirportRepository(db.PgDB),
+		Rank:                  repositories.NewRankRepo(db.PgDB),
+		SyncedRecord:          repositories.NewSyncedRecordRepo(db.PgDB),
+		VAUserRole:            repositories.NewVAUserRoleRepository(db.PgDB),
 	}
 
 	// Initialize cache service (Redis or in-memory based on USE_REDIS_CACHE env var)
@@ -108,19 +143,20 @@ func InitDependencies(metricsReg *metrics.MetricsRegistry) (*Dependencies, error
 	}
 
 	liveSvc := common.NewLiveAPIService()
-	confSvc := common.NewVAConfigService(&repositories.Va, cacheSvc)
+	confSvc := common.NewVAConfigService(&repositories.Va, cacheSvc, keyring)
 
 	// Initialize providers
 	liveAPIProvider := providers.NewLiveAPIProvider()
+	dataProviders := providers.NewDefaultRegistry(cacheSvc, metricsReg)
 
 	// Initialize pilot stats service first (needed by UserService)
-	pilotStatsSvc := services.NewPilotStatsService(db.DB, db.PgDB, legacyCache, repositories.DataProviderCfg, &repositories.User, confSvc, repositories.PirepATSynced, repositories.RouteATSynced)
+	pilotStatsSvc := services.NewPilotStatsService(db.DB, db.PgDB, legacyCache, repositories.DataProviderCfg, &repositories.User, confSvc, repositories.PirepATSynced, repositories.RouteATSynced, dataProviders)
 
 	// Initialize user service with both sqlx and GORM repositories and pilot stats service
 	userSvc := services.NewUserService(&repositories.User, repositories.UserGorm, pilotStatsSvc)
 
 	// Initialize data provider config service
-	dataProviderConfigSvc := services.NewDataProviderConfigService(repositories.DataProviderCfg, cacheSvc)
+	dataProviderConfigSvc := services.NewDataProviderConfigService(repositories.DataProviderCfg, cacheSvc, dataProviders, keyring)
 	if dataProviderConfigSvc == nil {
 		log.Println("WARNING: DataProviderConfigService is nil after initialization!")
 	} else {
@@ -133,9 +169,6 @@ func InitDependencies(metricsReg *metrics.MetricsRegistry) (*Dependencies, error
 	// Initialize aircraft livery service
 	aircraftLiverySvc := common.NewAircraftLiveryService(legacyCache, repositories.AircraftLivery)
 
-	// Initialize Airtable provider
-	airtableProvider := providers.NewAirtableProvider(cacheSvc)
-
 	// Initialize URL Signer service for presigned dashboard links
 	jwtSecret := []byte(os.Getenv("JWT_SECRET"))
 	if len(jwtSecret) == 0 {
@@ -146,6 +179,9 @@ func InitDependencies(metricsReg *metrics.MetricsRegistry) (*Dependencies, error
 	// Initialize session service for UI authentication
 	sessionSvc := common.NewSessionService(redisClient)
 
+	// Initialize rank service (promotes pilots on PIREP approval)
+	rankSvc := services.NewRankService(repositories.Rank, repositories.Pirep, repositories.VAUserRole)
+
 	svc := &Services{
 		User:               userSvc,
 		Reg:                *services.NewRegistrationService(liveSvc, *legacyCache, repositories.User, repositories.Va),
@@ -153,11 +189,17 @@ func InitDependencies(metricsReg *metrics.MetricsRegistry) (*Dependencies, error
 		Conf:               *confSvc,
 		VaMgmt:             *services.NewVAManagementService(repositories.Va, repositories.User),
 		AirtableApi:        *common.NewAirtableApiService(confSvc),
-		AirtableProvider:   airtableProvider,
+		DataProviders:      dataProviders,
 		AirtableSync:       *services.NewAtSyncService(legacyCache, &repositories.UserVASync),
 		Flights:            *services.NewFlightsService(legacyCache, liveSvc, confSvc, aircraftLiverySvc),
 		PilotStats:         pilotStatsSvc,
 		DataProviderConfig: dataProviderConfigSvc,
+		PirepReview:        services.NewPirepReviewService(repositories.Pirep, rankSvc),
+		PirepDelivery:      services.NewPirepDeliveryService(repositories.PirepOutbox, repositories.Pirep, dataProviders, dataProviderConfigSvc),
+		PilotSync:          services.NewPilotSyncService(repositories.PilotSyncChange, repositories.PilotATSynced, repositories.VAUserRole, dataProviders, dataProviderConfigSvc),
+		FlightModesConfig:  services.NewFlightModesConfigService(repositories.VAGorm, repositories.FlightModesConfigVer, repositories.RouteATSynced, repositories.Rank),
+		RouteCatalogue:     services.NewRouteCatalogueService(repositories.RouteATSynced, repositories.AirportsRepo),
+		Rank:               rankSvc,
 		AircraftLivery:     aircraftLiverySvc,
 		Cache:              cacheSvc,
 		LegacyCache:        legacyCache,
@@ -165,6 +207,7 @@ func InitDependencies(metricsReg *metrics.MetricsRegistry) (*Dependencies, error
 		RedisQueue:         redisQSvc,
 		URLSigner:          urlSignerSvc,
 		Session:            sessionSvc,
+		APIKeys:            services.NewAPIKeyService(&repositories.Keys, repositories.VAGorm),
 	}
 
 	return &Dependencies{
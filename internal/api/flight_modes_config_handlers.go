@@ -0,0 +1,168 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"infinite-experiment/politburo/internal/auth"
+	"infinite-experiment/politburo/internal/common"
+	"infinite-experiment/politburo/internal/models/dtos"
+	gormModels "infinite-experiment/politburo/internal/models/gorm"
+	"infinite-experiment/politburo/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ListFlightModesConfigVersions handles GET /api/v1/va/flight-modes/config/versions
+// Returns the saved versions of the VA's flight modes configuration, newest first (admin-only)
+func (h *Handlers) ListFlightModesConfigVersions() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		initTime := time.Now()
+
+		va, status, err := h.resolveClaimsVA(r)
+		if err != nil {
+			common.RespondError(w, initTime, err, "Virtual airline not found", status)
+			return
+		}
+
+		versions, err := h.deps.Services.FlightModesConfig.ListVersions(r.Context(), va.ID)
+		if err != nil {
+			common.RespondError(w, initTime, err, "Failed to fetch configuration versions", http.StatusInternalServerError)
+			return
+		}
+
+		response := make([]dtos.FlightModesConfigVersion, 0, len(versions))
+		for i := range versions {
+			response = append(response, toFlightModesConfigVersion(&versions[i], false))
+		}
+
+		common.RespondSuccess(w, initTime, "Configuration versions fetched successfully", response)
+	}
+}
+
+// GetFlightModesConfigVersion handles GET /api/v1/va/flight-modes/config/versions/{version}
+// Returns a single saved version including its configuration (admin-only)
+func (h *Handlers) GetFlightModesConfigVersion() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		initTime := time.Now()
+
+		va, status, err := h.resolveClaimsVA(r)
+		if err != nil {
+			common.RespondError(w, initTime, err, "Virtual airline not found", status)
+			return
+		}
+
+		version, err := strconv.Atoi(chi.URLParam(r, "version"))
+		if err != nil || version <= 0 {
+			common.RespondError(w, initTime, nil, "Version must be a positive integer", http.StatusBadRequest)
+			return
+		}
+
+		v, err := h.deps.Services.FlightModesConfig.GetVersion(r.Context(), va.ID, version)
+		if err != nil {
+			respondConfigVersionError(w, initTime, err)
+			return
+		}
+
+		common.RespondSuccess(w, initTime, "Configuration version fetched successfully", toFlightModesConfigVersion(v, true))
+	}
+}
+
+// DiffFlightModesConfig handles GET /api/v1/va/flight-modes/config/diff?from=N&to=M
+// Compares two saved versions; "to" defaults to the latest version (admin-only)
+func (h *Handlers) DiffFlightModesConfig() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		initTime := time.Now()
+
+		va, status, err := h.resolveClaimsVA(r)
+		if err != nil {
+			common.RespondError(w, initTime, err, "Virtual airline not found", status)
+			return
+		}
+
+		q := r.URL.Query()
+		from, err := strconv.Atoi(q.Get("from"))
+		if err != nil || from <= 0 {
+			common.RespondError(w, initTime, nil, "Query param 'from' must be a positive version number", http.StatusBadRequest)
+			return
+		}
+		to := 0
+		if q.Get("to") != "" {
+			to, err = strconv.Atoi(q.Get("to"))
+			if err != nil || to <= 0 {
+				common.RespondError(w, initTime, nil, "Query param 'to' must be a positive version number", http.StatusBadRequest)
+				return
+			}
+		}
+
+		diff, err := h.deps.Services.FlightModesConfig.Diff(r.Context(), va.ID, from, to)
+		if err != nil {
+			respondConfigVersionError(w, initTime, err)
+			return
+		}
+
+		common.RespondSuccess(w, initTime, "Configuration diff computed successfully", diff)
+	}
+}
+
+// RollbackFlightModesConfig handles POST /api/v1/va/flight-modes/config/versions/{version}/rollback
+// Re-applies a prior version by saving it as the newest version (admin-only)
+func (h *Handlers) RollbackFlightModesConfig() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		initTime := time.Now()
+
+		claims := auth.GetUserClaims(r.Context())
+		if claims == nil {
+			common.RespondError(w, initTime, nil, "Unauthorized: missing claims", http.StatusUnauthorized)
+			return
+		}
+
+		va, status, err := h.resolveClaimsVA(r)
+		if err != nil {
+			common.RespondError(w, initTime, err, "Virtual airline not found", status)
+			return
+		}
+
+		version, err := strconv.Atoi(chi.URLParam(r, "version"))
+		if err != nil || version <= 0 {
+			common.RespondError(w, initTime, nil, "Version must be a positive integer", http.StatusBadRequest)
+			return
+		}
+
+		v, err := h.deps.Services.FlightModesConfig.Rollback(r.Context(), va.ID, claims.UserID(), version)
+		if err != nil {
+			respondConfigVersionError(w, initTime, err)
+			return
+		}
+
+		common.RespondSuccess(w, initTime, "Configuration rolled back successfully", toFlightModesConfigVersion(v, false))
+	}
+}
+
+// respondConfigVersionError maps flight modes config service errors onto HTTP responses
+func respondConfigVersionError(w http.ResponseWriter, initTime time.Time, err error) {
+	if errors.Is(err, services.ErrConfigVersionNotFound) {
+		common.RespondError(w, initTime, err, "Configuration version not found", http.StatusNotFound)
+		return
+	}
+	common.RespondError(w, initTime, err, "Failed to process configuration version", http.StatusInternalServerError)
+}
+
+// toFlightModesConfigVersion maps a stored config version onto its API representation
+func toFlightModesConfigVersion(v *gormModels.FlightModesConfigVersion, withConfig bool) dtos.FlightModesConfigVersion {
+	out := dtos.FlightModesConfigVersion{
+		Version:   v.Version,
+		AuthorID:  v.AuthorID,
+		Note:      v.Note,
+		CreatedAt: v.CreatedAt,
+	}
+	if v.Author != nil && v.Author.UserName != nil {
+		out.AuthorUsername = *v.Author.UserName
+	}
+	if withConfig {
+		out.Config = v.Config
+	}
+	return out
+}
@@ -2,147 +2,183 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"infinite-experiment/politburo/internal/auth"
+	"infinite-experiment/politburo/internal/common"
+	"infinite-experiment/politburo/internal/db/repositories"
 	"infinite-experiment/politburo/internal/jobs"
-	"log"
 	"net/http"
 	"time"
+
+	"github.com/go-chi/chi/v5"
 )
 
-// JobsHandler handles manual job triggering endpoints
+// JobsHandler handles manual job triggering and job status endpoints
 type JobsHandler struct {
-	pilotSyncJob *jobs.PilotSyncJob
+	triggers *jobs.Triggers
+	tracker  *jobs.Tracker
+	vaRepo   *repositories.VAGormRepository
 }
 
 // NewJobsHandler creates a new jobs handler
-func NewJobsHandler(pilotSyncJob *jobs.PilotSyncJob) *JobsHandler {
+func NewJobsHandler(triggers *jobs.Triggers, tracker *jobs.Tracker, vaRepo *repositories.VAGormRepository) *JobsHandler {
 	return &JobsHandler{
-		pilotSyncJob: pilotSyncJob,
+		triggers: triggers,
+		tracker:  tracker,
+		vaRepo:   vaRepo,
 	}
 }
 
-// TriggerPilotSync manually triggers the pilot sync job
-// @Summary Trigger pilot sync job
-// @Description Manually trigger the pilot sync job for all VAs or a specific VA
+// TriggerJob manually triggers a background job for the caller's VA
+// @Summary Trigger a job
+// @Description Start a background job for the caller's VA. Returns a run ID to poll. A second trigger while the job runs for the VA is rejected. Global jobs (livery_sync, airport_reload) require god-mode.
 // @Tags admin,jobs
-// @Accept json
 // @Produce json
-// @Param body body TriggerPilotSyncRequest false "Optional VA ID to sync specific VA"
-// @Success 200 {object} TriggerPilotSyncResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 401 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
-// @Router /api/v1/admin/jobs/sync-pilots [post]
-func (h *JobsHandler) TriggerPilotSync() http.HandlerFunc {
+// @Param job path string true "Job name: pilot_sync, pilot_linking, route_sync, pirep_sync, entity_sync, reconcile, pirep_backfill, livery_sync or airport_reload"
+// @Success 202 {object} ManualRunResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/admin/jobs/{job}/trigger [post]
+func (h *JobsHandler) TriggerJob() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		return
-		start := time.Now()
+		initTime := time.Now()
 
-		// Parse optional request body
-		var req TriggerPilotSyncRequest
-		if r.Body != nil {
-			if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err.Error() != "EOF" {
-				respondWithError(w, http.StatusBadRequest, "Invalid request body")
-				return
-			}
+		va, status, err := claimsVA(r, h.vaRepo)
+		if err != nil {
+			common.RespondError(w, initTime, err, "Virtual airline not found", status)
+			return
 		}
 
-		// Get claims for logging
-		claims := auth.GetUserClaims(r.Context())
-		triggeredBy := claims.DiscordUserID()
-
-		log.Printf("[JobsHandler] Pilot sync manually triggered by user %s", triggeredBy)
+		name := chi.URLParam(r, "job")
+		job, ok := h.triggers.Job(name)
+		if !ok {
+			common.RespondError(w, initTime, jobs.ErrUnknownJob, fmt.Sprintf("Unknown job %q", name), http.StatusNotFound)
+			return
+		}
 
-		// If specific VA ID provided, sync only that VA
-		ctx := r.Context()
-		var syncedCount int
-		var err error
+		claims := auth.GetUserClaims(r.Context())
+		if job.Global && !auth.IsGodMode(claims.DiscordUserID()) {
+			common.RespondError(w, initTime, nil, "Unauthorized: god-mode required for jobs shared by every VA", http.StatusForbidden)
+			return
+		}
 
-		if req.VAID != "" {
-			log.Printf("[JobsHandler] Syncing specific VA: %s", req.VAID)
-			syncedCount, err = h.pilotSyncJob.SyncVAPilots(ctx, req.VAID)
-			if err != nil {
-				log.Printf("[JobsHandler] Error syncing VA %s: %v", req.VAID, err)
-				respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to sync VA: %v", err))
+		run, err := h.triggers.Start(r.Context(), name, va.ID, claims.DiscordUserID())
+		if err != nil {
+			if errors.Is(err, jobs.ErrJobAlreadyRunning) {
+				common.RespondError(w, initTime, err, fmt.Sprintf("%s is already running", name), http.StatusConflict)
 				return
 			}
-		} else {
-			// Run full sync for all VAs
-			log.Printf("[JobsHandler] Running full pilot sync")
-			err = h.pilotSyncJob.Run(ctx)
-			if err != nil {
-				log.Printf("[JobsHandler] Error running pilot sync: %v", err)
-				respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to run sync: %v", err))
+			if errors.Is(err, jobs.ErrShuttingDown) {
+				common.RespondError(w, initTime, err, "Server is shutting down, try again shortly", http.StatusServiceUnavailable)
 				return
 			}
+			common.RespondError(w, initTime, err, "Failed to trigger job", http.StatusInternalServerError)
+			return
 		}
 
-		duration := time.Since(start)
+		common.RespondSuccess(w, initTime, "Job triggered", toManualRunResponse(run), http.StatusAccepted)
+	}
+}
+
+// GetTriggeredRun returns the progress of a manually triggered run of the caller's VA
+// @Summary Get a triggered run
+// @Description Poll a manually triggered job run by its run ID. Finished runs are kept for an hour.
+// @Tags admin,jobs
+// @Produce json
+// @Param run_id path string true "Run ID returned by the trigger"
+// @Success 200 {object} ManualRunResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/admin/jobs/triggers/{run_id} [get]
+func (h *JobsHandler) GetTriggeredRun() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		initTime := time.Now()
 
-		response := TriggerPilotSyncResponse{
-			Status:       "ok",
-			Message:      "Pilot sync completed successfully",
-			ResponseTime: fmt.Sprintf("%dms", duration.Milliseconds()),
-			Data: PilotSyncResult{
-				TriggeredBy:  triggeredBy,
-				TriggeredAt:  start.Format(time.RFC3339),
-				CompletedAt:  time.Now().Format(time.RFC3339),
-				DurationMs:   int(duration.Milliseconds()),
-				PilotsSynced: syncedCount,
-				VAID:         req.VAID,
-			},
+		va, status, err := claimsVA(r, h.vaRepo)
+		if err != nil {
+			common.RespondError(w, initTime, err, "Virtual airline not found", status)
+			return
 		}
 
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(response)
+		run := h.triggers.Run(va.ID, chi.URLParam(r, "run_id"))
+		if run == nil {
+			common.RespondError(w, initTime, nil, "Run not found", http.StatusNotFound)
+			return
+		}
+
+		common.RespondSuccess(w, initTime, "Run fetched successfully", toManualRunResponse(run))
+	}
+}
+
+// toManualRunResponse maps a manual run onto its API representation
+func toManualRunResponse(run *jobs.ManualRun) ManualRunResponse {
+	response := ManualRunResponse{
+		RunID:       run.ID,
+		Job:         run.Job,
+		Global:      run.Global,
+		Status:      run.Status,
+		Processed:   run.Processed,
+		Error:       run.Error,
+		TriggeredBy: run.TriggeredBy,
+		StartedAt:   run.StartedAt.Format(time.RFC3339),
+		DurationMs:  int(time.Since(run.StartedAt).Milliseconds()),
 	}
+	if run.FinishedAt != nil {
+		response.FinishedAt = run.FinishedAt.Format(time.RFC3339)
+		response.DurationMs = int(run.FinishedAt.Sub(run.StartedAt).Milliseconds())
+	}
+	return response
 }
 
 // GetJobStatus returns the status of background jobs
 // @Summary Get job status
-// @Description Get status information about background jobs
+// @Description Get the state, last run and next run of every background job
 // @Tags admin,jobs
 // @Produce json
 // @Success 200 {object} JobStatusResponse
 // @Router /api/v1/admin/jobs/status [get]
 func (h *JobsHandler) GetJobStatus() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		// Query database for recent sync history (if we had a sync_history table)
-		// For now, return basic status
+		start := time.Now()
+
+		statuses := h.tracker.Status(r.Context())
+		jobInfos := make([]JobInfo, 0, len(statuses))
+		for _, s := range statuses {
+			info := JobInfo{
+				Name:        s.Name,
+				Description: s.Description,
+				Schedule:    "On demand",
+				Status:      "idle",
+				LastError:   s.LastError,
+			}
+			if s.Schedule != "" {
+				info.Schedule = s.Schedule
+			}
+			if s.Running {
+				info.Status = "running"
+			} else if s.LastError != "" {
+				info.Status = "error"
+			}
+			if s.LastStartedAt != nil {
+				info.LastRun = s.LastStartedAt.Format(time.RFC3339)
+			}
+			if s.LastFinishedAt != nil {
+				info.LastFinished = s.LastFinishedAt.Format(time.RFC3339)
+			}
+			if s.NextRunAt != nil {
+				info.NextRun = s.NextRunAt.Format(time.RFC3339)
+			}
+			jobInfos = append(jobInfos, info)
+		}
 
 		response := JobStatusResponse{
 			Status:       "ok",
 			Message:      "Job status retrieved",
-			ResponseTime: "5ms",
+			ResponseTime: fmt.Sprintf("%dms", time.Since(start).Milliseconds()),
 			Data: JobStatusData{
-				Jobs: []JobInfo{
-					{
-						Name:        "pilot_sync",
-						Description: "Syncs pilot data from Airtable to local database",
-						Schedule:    "Every 1 hour",
-						Status:      "running",
-						LastRun:     "", // Could track this in memory or DB
-						NextRun:     "", // Could calculate based on schedule
-					},
-					{
-						Name:        "logbook_worker",
-						Description: "Processes flight logbook entries",
-						Schedule:    "Continuous (worker)",
-						Status:      "running",
-						LastRun:     "",
-						NextRun:     "",
-					},
-					{
-						Name:        "cache_filler",
-						Description: "Pre-fills cache with frequently accessed data",
-						Schedule:    "Continuous (worker)",
-						Status:      "running",
-						LastRun:     "",
-						NextRun:     "",
-					},
-				},
+				Jobs: jobInfos,
 			},
 		}
 
@@ -154,24 +190,18 @@ func (h *JobsHandler) GetJobStatus() http.HandlerFunc {
 
 // Request/Response types
 
-type TriggerPilotSyncRequest struct {
-	VAID string `json:"va_id,omitempty"` // Optional: sync specific VA only
-}
-
-type TriggerPilotSyncResponse struct {
-	Status       string          `json:"status"`
-	Message      string          `json:"message"`
-	ResponseTime string          `json:"response_time"`
-	Data         PilotSyncResult `json:"data"`
-}
-
-type PilotSyncResult struct {
-	TriggeredBy  string `json:"triggered_by"`    // Discord ID of admin who triggered
-	TriggeredAt  string `json:"triggered_at"`    // ISO 8601 timestamp
-	CompletedAt  string `json:"completed_at"`    // ISO 8601 timestamp
-	DurationMs   int    `json:"duration_ms"`     // Duration in milliseconds
-	PilotsSynced int    `json:"pilots_synced"`   // Number of pilots synced
-	VAID         string `json:"va_id,omitempty"` // VA ID if specific VA sync
+// ManualRunResponse is a manually triggered job run
+type ManualRunResponse struct {
+	RunID       string `json:"run_id"`
+	Job         string `json:"job"`
+	Global      bool   `json:"global"`                // The job updates data shared by every VA
+	Status      string `json:"status"`                // "running", "success" or "failed"
+	Processed   int    `json:"processed"`             // Records processed, once finished
+	Error       string `json:"error,omitempty"`       // Why the run failed
+	TriggeredBy string `json:"triggered_by"`          // Discord ID of admin who triggered
+	StartedAt   string `json:"started_at"`            // ISO 8601 timestamp
+	FinishedAt  string `json:"finished_at,omitempty"` // ISO 8601 timestamp
+	DurationMs  int    `json:"duration_ms"`           // Elapsed so far while running
 }
 
 type JobStatusResponse struct {
@@ -186,10 +216,12 @@ type JobStatusData struct {
 }
 
 type JobInfo struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Schedule    string `json:"schedule"`
-	Status      string `json:"status"` // "running", "stopped", "error"
-	LastRun     string `json:"last_run,omitempty"`
-	NextRun     string `json:"next_run,omitempty"`
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	Schedule     string `json:"schedule"`
+	Status       string `json:"status"` // "running", "idle", "error"
+	LastRun      string `json:"last_run,omitempty"`
+	LastFinished string `json:"last_finished,omitempty"`
+	LastError    string `json:"last_error,omitempty"`
+	NextRun      string `json:"next_run,omitempty"`
 }
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"infinite-experiment/politburo/internal/common"
+	"infinite-experiment/politburo/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// PilotSyncConflictResponse represents a pilot field changed both in the dashboard and in the data provider
+type PilotSyncConflictResponse struct {
+	ID               string     `json:"id"`
+	VAUserRoleID     string     `json:"va_user_role_id"`
+	ProviderRecordID string     `json:"provider_record_id"`
+	Field            string     `json:"field"`
+	BaseValue        *string    `json:"base_value"`
+	LocalValue       *string    `json:"local_value"`
+	RemoteValue      *string    `json:"remote_value"`
+	LocalModifiedAt  time.Time  `json:"local_modified_at"`
+	RemoteModifiedAt *time.Time `json:"remote_modified_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+// ResolvePilotSyncConflictRequest selects which side of a conflict to keep
+type ResolvePilotSyncConflictRequest struct {
+	Keep string `json:"keep"` // "local" or "remote"
+}
+
+// ListPilotSyncConflicts handles GET /api/v1/admin/pilots/sync/conflicts
+// Returns pilot changes that were also changed in the data provider since the last sync (admin-only)
+func (h *Handlers) ListPilotSyncConflicts() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		initTime := time.Now()
+
+		va, status, err := h.resolveClaimsVA(r)
+		if err != nil {
+			common.RespondError(w, initTime, err, "Virtual airline not found", status)
+			return
+		}
+
+		limit := 100
+		if v, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && v > 0 && v <= 500 {
+			limit = v
+		}
+
+		conflicts, err := h.deps.Services.PilotSync.ListConflicts(r.Context(), va.ID, limit)
+		if err != nil {
+			common.RespondError(w, initTime, err, "Failed to fetch sync conflicts", http.StatusInternalServerError)
+			return
+		}
+
+		response := make([]PilotSyncConflictResponse, 0, len(conflicts))
+		for _, c := range conflicts {
+			response = append(response, PilotSyncConflictResponse{
+				ID:               c.Change.ID,
+				VAUserRoleID:     c.Change.VAUserRoleID,
+				ProviderRecordID: c.Change.ProviderRecordID,
+				Field:            c.Change.Field,
+				BaseValue:        c.Change.BaseValue,
+				LocalValue:       c.Change.LocalValue,
+				RemoteValue:      c.Change.RemoteValue,
+				LocalModifiedAt:  c.LocalModifiedAt,
+				RemoteModifiedAt: c.RemoteModifiedAt,
+				CreatedAt:        c.Change.CreatedAt,
+			})
+		}
+
+		common.RespondSuccess(w, initTime, "Sync conflicts fetched successfully", response)
+	}
+}
+
+// ResolvePilotSyncConflict handles POST /api/v1/admin/pilots/sync/conflicts/{change_id}/resolve
+// Keeps either the dashboard value (pushed to the provider) or the provider value (applied locally) (admin-only)
+func (h *Handlers) ResolvePilotSyncConflict() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		initTime := time.Now()
+
+		va, status, err := h.resolveClaimsVA(r)
+		if err != nil {
+			common.RespondError(w, initTime, err, "Virtual airline not found", status)
+			return
+		}
+
+		changeID := chi.URLParam(r, "change_id")
+		if changeID == "" {
+			common.RespondError(w, initTime, fmt.Errorf("missing change_id"), "Change ID is required", http.StatusBadRequest)
+			return
+		}
+
+		var req ResolvePilotSyncConflictRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			common.RespondError(w, initTime, err, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		if err := h.deps.Services.PilotSync.ResolveConflict(r.Context(), va.ID, changeID, req.Keep); err != nil {
+			switch {
+			case errors.Is(err, services.ErrInvalidConflictResolution):
+				common.RespondError(w, initTime, err, "keep must be \"local\" or \"remote\"", http.StatusBadRequest)
+			case errors.Is(err, services.ErrPilotChangeNotFound):
+				common.RespondError(w, initTime, err, "Sync conflict not found", http.StatusNotFound)
+			case req.Keep == services.PilotConflictKeepLocal:
+				// The change is back in the push queue even if this attempt failed
+				common.RespondError(w, initTime, err, "Push attempt failed; change re-queued for retry", http.StatusBadGateway)
+			default:
+				common.RespondError(w, initTime, err, "Failed to resolve sync conflict", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		common.RespondSuccess(w, initTime, "Sync conflict resolved successfully", map[string]string{
+			"change_id": changeID,
+			"kept":      req.Keep,
+		})
+	}
+}
@@ -3,7 +3,9 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"time"
@@ -126,8 +128,14 @@ func (h *Handlers) GetPirepConfig() http.HandlerFunc {
 			flight.Speed = currentFlight.SpeedKts
 		}
 
+		// Rank gates are advisory here; submission enforces them
+		standing, err := h.deps.Services.Rank.LoadStanding(r.Context(), vaGorm.ID, user.ID)
+		if err != nil {
+			log.Printf("[GetPirepConfig] Error loading pilot rank: %v", err)
+		}
+
 		// Build simplified response (without route details)
-		response := h.buildSimplePirepConfigResponse(r.Context(), vaGorm, flight)
+		response := h.buildSimplePirepConfigResponse(r.Context(), vaGorm, flight, services.NewFlightSnapshot(currentFlight), standing)
 		common.RespondSuccess(w, initTime, "PIREP configuration fetched successfully", response)
 	}
 }
@@ -196,19 +204,21 @@ func (h *Handlers) SubmitPirep() http.HandlerFunc {
 
 		// Create submission service with all dependencies
 		// Note: Service handles ALL flight data fetching internally (flight matching, livery resolution, aircraft/airline mapping)
-		validator := services.NewFlightModeValidationService(&h.deps.Services.Live, h.deps.Services.Cache)
+		validator := services.NewFlightModeValidationService(&h.deps.Services.Live, h.deps.Services.Cache, h.deps.Repo.AirportsRepo)
 		submissionService := services.NewPirepSubmissionService(
 			h.deps.Repo.UserGorm,
 			h.deps.Repo.PilotATSynced,
 			h.deps.Repo.RouteATSynced,
 			h.deps.Repo.LiveryAirtableMapping,
 			h.deps.Repo.DataProviderCfg,
-			h.deps.Services.AirtableProvider,
 			validator,
 			h.deps.Services.Cache,
 			&h.deps.Services.Flights,
 			&h.deps.Services.Conf,
 			h.deps.Services.DataProviderConfig,
+			h.deps.Repo.Pirep,
+			h.deps.Services.PirepDelivery,
+			h.deps.Services.Rank,
 		)
 
 		// Submit PIREP (service handles all flight data fetching internally)
@@ -262,28 +272,34 @@ func (h *Handlers) SetFlightModesConfig() http.HandlerFunc {
 			return
 		}
 
-		// Parse request body
-		var configPayload map[string]interface{}
-		if err := json.NewDecoder(r.Body).Decode(&configPayload); err != nil {
+		// Read raw body; the service decodes it strictly against the flight modes schema
+		raw, err := io.ReadAll(r.Body)
+		if err != nil {
 			common.RespondError(w, initTime, err, "Invalid request body", http.StatusBadRequest)
 			return
 		}
 
-		// Use service to validate and save configuration
-		configSvc := services.NewFlightModesConfigService(h.deps.Repo.VAGorm)
-		if err := configSvc.ValidateAndSaveConfig(r.Context(), vaGorm.ID, configPayload); err != nil {
-			common.RespondError(w, initTime, err, "Invalid configuration", http.StatusBadRequest)
+		// Use service to validate and save configuration as a new version
+		version, err := h.deps.Services.FlightModesConfig.ValidateAndSaveConfig(r.Context(), vaGorm.ID, claims.UserID(), raw)
+		if err != nil {
+			var cfgErr *services.FlightModesConfigError
+			if errors.As(err, &cfgErr) {
+				common.RespondErrorWithData(w, initTime, nil, "Invalid configuration", map[string]interface{}{"problems": cfgErr.Problems}, http.StatusBadRequest)
+				return
+			}
+			common.RespondError(w, initTime, err, "Failed to save configuration", http.StatusInternalServerError)
 			return
 		}
 
 		// Get the number of modes for response
-		flightModes := configPayload["flight_modes"].(map[string]interface{})
+		flightModes, _ := version.Config["flight_modes"].(map[string]interface{})
 
 		response := map[string]interface{}{
 			"success": true,
 			"message": "Flight modes configuration saved successfully",
 			"va_id":   vaGorm.ID,
 			"modes":   len(flightModes),
+			"version": version.Version,
 		}
 
 		w.Header().Set("Content-Type", "application/json")
@@ -297,6 +313,7 @@ func (h *Handlers) buildPirepConfigResponse(
 	ctx context.Context,
 	va *gormModels.VA,
 	flight *common.FlightData,
+	snapshot *services.FlightSnapshot,
 	userDiscordID string,
 ) *dtos.ConfigResponse {
 	response := &dtos.ConfigResponse{
@@ -312,46 +329,28 @@ func (h *Handlers) buildPirepConfigResponse(
 	}
 
 	// Extract flight modes from config
-	if va.FlightModesConfig == nil || len(va.FlightModesConfig) == 0 {
-		return response
-	}
-
-	flightModes, ok := va.FlightModesConfig["flight_modes"].(map[string]interface{})
-	if !ok {
+	modesConfig, err := services.LoadFlightModesConfig(va)
+	if err != nil {
 		return response
 	}
 
 	// Create validator
-	validator := services.NewFlightModeValidationService(&h.deps.Services.Live, h.deps.Services.Cache)
+	validator := services.NewFlightModeValidationService(&h.deps.Services.Live, h.deps.Services.Cache, h.deps.Repo.AirportsRepo)
 
-	// Get all routes for this VA for route selection modes
-	allRoutes, err := h.deps.Repo.RouteATSynced.GetAllByVA(ctx, va.ID)
+	// Get the VA's currently active catalogue routes (native and Airtable) for route selection modes
+	allRoutes, err := h.deps.Repo.RouteATSynced.GetActiveByVA(ctx, va.ID, time.Now())
 	if err != nil {
 		allRoutes = []gormModels.RouteATSynced{}
 	}
 
 	// Process each configured mode
-	for modeID, modeData := range flightModes {
-		modeConfig, ok := modeData.(map[string]interface{})
-		if !ok {
-			continue
-		}
-
-		// Check if enabled
-		enabled, _ := modeConfig["enabled"].(bool)
-		if !enabled {
-			continue
-		}
-
-		// Convert to FlightModeConfig struct
-		modeConfigJSON, _ := json.Marshal(modeConfig)
-		var flightModeConfig dtos.FlightModeConfig
-		if err := json.Unmarshal(modeConfigJSON, &flightModeConfig); err != nil {
+	for modeID, flightModeConfig := range modesConfig.FlightModes {
+		if !flightModeConfig.Enabled {
 			continue
 		}
 
 		// Validate mode
-		validationResult := validator.ValidateFlightForMode(ctx, flight.Route, &flightModeConfig.Validations)
+		validationResult := validator.ValidateFlightForMode(ctx, snapshot, &flightModeConfig.Validations)
 
 		modeResponse := dtos.ModeResponse{
 			ModeID:                 modeID,
@@ -368,7 +367,7 @@ func (h *Handlers) buildPirepConfigResponse(
 				modeResponse.AvailableRoutes = h.buildAvailableRoutes(allRoutes, &flightModeConfig)
 			} else if flightModeConfig.AutoRoute != nil {
 				// Add auto-route information
-				autoRoute := h.findAutoRoute(allRoutes, flightModeConfig.AutoRoute.RouteName)
+				autoRoute := h.findAutoRoute(allRoutes, &flightModeConfig)
 				if autoRoute != nil {
 					modeResponse.AutoRoute = autoRoute
 				}
@@ -391,25 +390,23 @@ func (h *Handlers) buildAvailableRoutes(allRoutes []gormModels.RouteATSynced, mo
 	// If mode has validation rules, filter routes
 	if modeConfig.Validations.AllowedRoutes != nil && len(modeConfig.Validations.AllowedRoutes) > 0 {
 		for _, route := range allRoutes {
+			if !services.RouteInDistanceBands(&route, modeConfig.Validations.DistanceBands) {
+				continue
+			}
 			for _, allowed := range modeConfig.Validations.AllowedRoutes {
 				if route.Route == allowed {
-					routes = append(routes, dtos.RouteOption{
-						RouteID:    route.ATID,
-						Name:       route.Route,
-						Multiplier: h.getRouteMultiplier(route, modeConfig),
-					})
+					routes = append(routes, h.toRouteOption(route, modeConfig))
 					break
 				}
 			}
 		}
 	} else {
-		// Return all routes
+		// Return all routes in the mode's distance bands
 		for _, route := range allRoutes {
-			routes = append(routes, dtos.RouteOption{
-				RouteID:    route.ATID,
-				Name:       route.Route,
-				Multiplier: h.getRouteMultiplier(route, modeConfig),
-			})
+			if !services.RouteInDistanceBands(&route, modeConfig.Validations.DistanceBands) {
+				continue
+			}
+			routes = append(routes, h.toRouteOption(route, modeConfig))
 		}
 	}
 
@@ -417,33 +414,50 @@ func (h *Handlers) buildAvailableRoutes(allRoutes []gormModels.RouteATSynced, mo
 }
 
 // findAutoRoute finds an auto-route by name
-func (h *Handlers) findAutoRoute(allRoutes []gormModels.RouteATSynced, routeName string) *dtos.RouteOption {
+func (h *Handlers) findAutoRoute(allRoutes []gormModels.RouteATSynced, modeConfig *dtos.FlightModeConfig) *dtos.RouteOption {
 	for _, route := range allRoutes {
-		if route.Route == routeName {
-			return &dtos.RouteOption{
-				RouteID:    route.ATID,
-				Name:       route.Route,
-				Multiplier: 1.0, // Will be overridden by mode config
-			}
+		if route.Route == modeConfig.AutoRoute.RouteName {
+			option := h.toRouteOption(route, modeConfig)
+			return &option
 		}
 	}
 	return nil
 }
 
-// getRouteMultiplier retrieves the multiplier for a route in a mode
-// TODO: Implement route-specific multiplier lookup when route multiplier system is available
-func (h *Handlers) getRouteMultiplier(route gormModels.RouteATSynced, modeConfig *dtos.FlightModeConfig) float64 {
-	// For now, return mode default multiplier
-	// In future, this can look up route-specific multipliers
-	if modeConfig.Metadata != nil {
-		if multiplier, ok := modeConfig.Metadata["multiplier"].(float64); ok {
-			return multiplier
-		}
+// toRouteOption maps a catalogue route onto a selectable route option.
+// Airtable routes are identified by their record ID, native routes by their catalogue ID.
+func (h *Handlers) toRouteOption(route gormModels.RouteATSynced, modeConfig *dtos.FlightModeConfig) dtos.RouteOption {
+	option := dtos.RouteOption{
+		RouteID:               route.ID,
+		Name:                  route.Route,
+		Multiplier:            h.getRouteMultiplier(route, modeConfig),
+		DistanceNm:            services.RouteDistanceNm(&route),
+		Haul:                  services.RouteHaul(&route),
+		BlockTimeMinutes:      services.RouteBlockMinutes(&route),
+		EstimatedBlockMinutes: services.RouteBlockEstimates(&route),
+	}
+	if route.ATID != nil {
+		option.RouteID = *route.ATID
+	}
+	if route.FlightNumber != nil {
+		option.FlightNumber = *route.FlightNumber
 	}
-	if modeConfig.AutoRoute != nil {
-		return modeConfig.AutoRoute.Multiplier
+	if route.Aircraft != nil {
+		option.Aircraft = *route.Aircraft
+	}
+	return option
+}
+
+// getRouteMultiplier retrieves the multiplier for a route in a mode.
+// A multiplier set on the route in the catalogue takes precedence over the mode default.
+func (h *Handlers) getRouteMultiplier(route gormModels.RouteATSynced, modeConfig *dtos.FlightModeConfig) float64 {
+	modeMultiplier := 1.0
+	if multiplier, ok := modeConfig.Metadata["multiplier"].(float64); ok {
+		modeMultiplier = multiplier
+	} else if modeConfig.AutoRoute != nil {
+		modeMultiplier = modeConfig.AutoRoute.Multiplier
 	}
-	return 1.0
+	return services.RouteMultiplier(&route, modeMultiplier)
 }
 
 // buildSimplePirepConfigResponse constructs a minimal SimpleConfigResponse with just available modes and user info
@@ -452,6 +466,8 @@ func (h *Handlers) buildSimplePirepConfigResponse(
 	ctx context.Context,
 	va *gormModels.VA,
 	flight *common.FlightData,
+	snapshot *services.FlightSnapshot,
+	standing *services.RankStanding,
 ) *dtos.SimpleConfigResponse {
 	response := &dtos.SimpleConfigResponse{
 		UserInfo: dtos.UserInfo{
@@ -468,49 +484,36 @@ func (h *Handlers) buildSimplePirepConfigResponse(
 	}
 
 	// Extract flight modes from config
-	if va.FlightModesConfig == nil || len(va.FlightModesConfig) == 0 {
-		return response
-	}
-
-	flightModes, ok := va.FlightModesConfig["flight_modes"].(map[string]interface{})
-	if !ok {
+	modesConfig, err := services.LoadFlightModesConfig(va)
+	if err != nil {
 		return response
 	}
 
 	// Create validator
-	validator := services.NewFlightModeValidationService(&h.deps.Services.Live, h.deps.Services.Cache)
+	validator := services.NewFlightModeValidationService(&h.deps.Services.Live, h.deps.Services.Cache, h.deps.Repo.AirportsRepo)
 
 	// Process each configured mode
-	for modeID, modeData := range flightModes {
-		modeConfig, ok := modeData.(map[string]interface{})
-		if !ok {
-			continue
-		}
-
-		// Check if enabled
-		enabled, _ := modeConfig["enabled"].(bool)
-		if !enabled {
+	for modeID, flightModeConfig := range modesConfig.FlightModes {
+		if !flightModeConfig.Enabled {
 			continue
 		}
 
 		// Get display name
-		displayName, _ := modeConfig["display_name"].(string)
+		displayName := flightModeConfig.DisplayName
 		if displayName == "" {
 			displayName = modeID
 		}
-
-		// Get requires_route_selection
-		requiresRouteSelection, _ := modeConfig["requires_route_selection"].(bool)
-
-		// Convert to FlightModeConfig struct for validation
-		modeConfigJSON, _ := json.Marshal(modeConfig)
-		var flightModeConfig dtos.FlightModeConfig
-		if err := json.Unmarshal(modeConfigJSON, &flightModeConfig); err != nil {
-			continue
-		}
+		requiresRouteSelection := flightModeConfig.RequiresRouteSelection
 
 		// Validate mode
-		validationResult := validator.ValidateFlightForMode(ctx, flight.Route, &flightModeConfig.Validations)
+		validationResult := validator.ValidateFlightForMode(ctx, snapshot, &flightModeConfig.Validations)
+		if standing != nil && validationResult.Valid {
+			if ok, reason := standing.AllowsMode(flightModeConfig.MinRank); !ok {
+				validationResult.Valid, validationResult.ErrorMsg = false, reason
+			} else if ok, reason := standing.AllowsAircraft(flight.Aircraft); !ok {
+				validationResult.Valid, validationResult.ErrorMsg = false, reason
+			}
+		}
 
 		modeResponse := dtos.SimpleModeResponse{
 			ModeID:                 modeID,
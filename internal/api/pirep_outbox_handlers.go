@@ -0,0 +1,115 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"infinite-experiment/politburo/internal/common"
+	"infinite-experiment/politburo/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// OutboxEntryResponse represents a PIREP outbox entry as returned by the admin endpoints
+type OutboxEntryResponse struct {
+	ID               string                 `json:"id"`
+	PirepID          string                 `json:"pirep_id"`
+	ProviderType     string                 `json:"provider_type"`
+	IdempotencyKey   string                 `json:"idempotency_key"`
+	Status           string                 `json:"status"`
+	Attempts         int                    `json:"attempts"`
+	MaxAttempts      int                    `json:"max_attempts"`
+	NextAttemptAt    time.Time              `json:"next_attempt_at"`
+	LastError        *string                `json:"last_error,omitempty"`
+	OutcomeUnknown   bool                   `json:"outcome_unknown"` // A failed attempt may have created the record
+	ProviderRecordID *string                `json:"provider_record_id,omitempty"`
+	Payload          map[string]interface{} `json:"payload"`
+	CreatedAt        time.Time              `json:"created_at"`
+	UpdatedAt        time.Time              `json:"updated_at"`
+}
+
+// ListPirepDeadLetters handles GET /api/v1/admin/pireps/outbox/dead
+// Returns PIREP deliveries that exhausted their retry budget (admin-only)
+func (h *Handlers) ListPirepDeadLetters() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		initTime := time.Now()
+
+		va, status, err := h.resolveClaimsVA(r)
+		if err != nil {
+			common.RespondError(w, initTime, err, "Virtual airline not found", status)
+			return
+		}
+
+		limit := 100
+		if v, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && v > 0 && v <= 500 {
+			limit = v
+		}
+
+		entries, err := h.deps.Services.PirepDelivery.ListDeadLetters(r.Context(), va.ID, limit)
+		if err != nil {
+			common.RespondError(w, initTime, err, "Failed to fetch dead letters", http.StatusInternalServerError)
+			return
+		}
+
+		response := make([]OutboxEntryResponse, 0, len(entries))
+		for _, e := range entries {
+			response = append(response, OutboxEntryResponse{
+				ID:               e.ID,
+				PirepID:          e.PirepID,
+				ProviderType:     e.ProviderType,
+				IdempotencyKey:   e.IdempotencyKey,
+				Status:           e.Status,
+				Attempts:         e.Attempts,
+				MaxAttempts:      e.MaxAttempts,
+				NextAttemptAt:    e.NextAttemptAt,
+				LastError:        e.LastError,
+				OutcomeUnknown:   e.OutcomeUnknown,
+				ProviderRecordID: e.ProviderRecordID,
+				Payload:          e.Payload,
+				CreatedAt:        e.CreatedAt,
+				UpdatedAt:        e.UpdatedAt,
+			})
+		}
+
+		common.RespondSuccess(w, initTime, "Dead letters fetched successfully", response)
+	}
+}
+
+// ReplayPirepDeadLetter handles POST /api/v1/admin/pireps/outbox/{entry_id}/replay
+// Resets a dead-lettered delivery and attempts it again immediately (admin-only)
+func (h *Handlers) ReplayPirepDeadLetter() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		initTime := time.Now()
+
+		va, status, err := h.resolveClaimsVA(r)
+		if err != nil {
+			common.RespondError(w, initTime, err, "Virtual airline not found", status)
+			return
+		}
+
+		entryID := chi.URLParam(r, "entry_id")
+		if entryID == "" {
+			common.RespondError(w, initTime, fmt.Errorf("missing entry_id"), "Outbox entry ID is required", http.StatusBadRequest)
+			return
+		}
+
+		recordID, err := h.deps.Services.PirepDelivery.ReplayDeadLetter(r.Context(), va.ID, entryID)
+		if err != nil {
+			if errors.Is(err, services.ErrOutboxEntryNotFound) {
+				common.RespondError(w, initTime, err, "Dead letter not found", http.StatusNotFound)
+				return
+			}
+			// The entry is back in the retry queue even if this attempt failed
+			common.RespondError(w, initTime, err, "Replay attempt failed; entry re-queued for retry", http.StatusBadGateway)
+			return
+		}
+
+		common.RespondSuccess(w, initTime, "Dead letter replayed successfully", map[string]string{
+			"entry_id":           entryID,
+			"provider_record_id": recordID,
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"infinite-experiment/politburo/internal/auth"
+	"infinite-experiment/politburo/internal/common"
+	"infinite-experiment/politburo/internal/constants"
+	"infinite-experiment/politburo/internal/db/repositories"
+	"infinite-experiment/politburo/internal/models/dtos"
+	gormModels "infinite-experiment/politburo/internal/models/gorm"
+	"infinite-experiment/politburo/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ListPireps handles GET /api/v1/pireps
+// Returns natively stored PIREPs for the caller's VA (staff-only).
+// Query params: status, pilot, mode, route, limit, offset
+func (h *Handlers) ListPireps() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		initTime := time.Now()
+
+		va, status, err := h.resolveClaimsVA(r)
+		if err != nil {
+			common.RespondError(w, initTime, err, "Virtual airline not found", status)
+			return
+		}
+
+		q := r.URL.Query()
+		filter := repositories.PirepFilter{
+			Status:        constants.PirepStatus(q.Get("status")),
+			PilotCallsign: q.Get("pilot"),
+			Mode:          q.Get("mode"),
+			Route:         q.Get("route"),
+			Limit:         50,
+		}
+		if v, err := strconv.Atoi(q.Get("limit")); err == nil && v > 0 && v <= 200 {
+			filter.Limit = v
+		}
+		if v, err := strconv.Atoi(q.Get("offset")); err == nil && v > 0 {
+			filter.Offset = v
+		}
+
+		pireps, total, err := h.deps.Services.PirepReview.ListPireps(r.Context(), va.ID, filter)
+		if err != nil {
+			common.RespondError(w, initTime, err, "Failed to fetch PIREPs", http.StatusBadRequest)
+			return
+		}
+
+		response := dtos.PirepListResponse{
+			Pireps: make([]dtos.PirepDetail, 0, len(pireps)),
+			Total:  total,
+		}
+		for i := range pireps {
+			response.Pireps = append(response.Pireps, toPirepDetail(&pireps[i]))
+		}
+
+		common.RespondSuccess(w, initTime, "PIREPs fetched successfully", response)
+	}
+}
+
+// ReviewPirep handles POST /api/v1/pireps/{pirep_id}/review
+// Approves or rejects a pending PIREP (staff-only)
+func (h *Handlers) ReviewPirep() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		initTime := time.Now()
+
+		claims := auth.GetUserClaims(r.Context())
+		if claims == nil {
+			common.RespondError(w, initTime, nil, "Unauthorized: missing claims", http.StatusUnauthorized)
+			return
+		}
+
+		va, status, err := h.resolveClaimsVA(r)
+		if err != nil {
+			common.RespondError(w, initTime, err, "Virtual airline not found", status)
+			return
+		}
+
+		pirepID := chi.URLParam(r, "pirep_id")
+		if pirepID == "" {
+			common.RespondError(w, initTime, fmt.Errorf("missing pirep_id"), "PIREP ID is required", http.StatusBadRequest)
+			return
+		}
+
+		var req dtos.PirepReviewRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			common.RespondError(w, initTime, err, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		pirep, err := h.deps.Services.PirepReview.ReviewPirep(
+			r.Context(),
+			va.ID,
+			pirepID,
+			claims.UserID(),
+			constants.PirepStatus(req.Decision),
+			req.Notes,
+		)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrPirepNotFound):
+				common.RespondError(w, initTime, err, "PIREP not found", http.StatusNotFound)
+			case errors.Is(err, services.ErrPirepAlreadyReviewed):
+				common.RespondError(w, initTime, err, "PIREP has already been reviewed", http.StatusConflict)
+			case errors.Is(err, services.ErrInvalidReviewDecision), errors.Is(err, services.ErrReviewNotesRequired):
+				common.RespondError(w, initTime, err, err.Error(), http.StatusBadRequest)
+			default:
+				common.RespondError(w, initTime, err, "Failed to review PIREP", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		common.RespondSuccess(w, initTime, "PIREP reviewed successfully", toPirepDetail(pirep))
+	}
+}
+
+// ResubmitPirep handles POST /api/v1/pireps/{pirep_id}/resubmit
+// Applies the pilot's corrections to a PIREP staff requested changes on and returns it to the review queue
+func (h *Handlers) ResubmitPirep() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		initTime := time.Now()
+
+		claims := auth.GetUserClaims(r.Context())
+		if claims == nil {
+			common.RespondError(w, initTime, nil, "Unauthorized: missing claims", http.StatusUnauthorized)
+			return
+		}
+
+		va, status, err := h.resolveClaimsVA(r)
+		if err != nil {
+			common.RespondError(w, initTime, err, "Virtual airline not found", status)
+			return
+		}
+
+		pirepID := chi.URLParam(r, "pirep_id")
+		if pirepID == "" {
+			common.RespondError(w, initTime, fmt.Errorf("missing pirep_id"), "PIREP ID is required", http.StatusBadRequest)
+			return
+		}
+
+		var req dtos.PirepResubmitRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			common.RespondError(w, initTime, err, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		pirep, err := h.deps.Services.PirepReview.ResubmitPirep(r.Context(), va.ID, pirepID, claims.UserID(), &req)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrPirepNotFound):
+				common.RespondError(w, initTime, err, "PIREP not found", http.StatusNotFound)
+			case errors.Is(err, services.ErrPirepNotResubmittable):
+				common.RespondError(w, initTime, err, "PIREP is not awaiting changes", http.StatusConflict)
+			case errors.Is(err, services.ErrInvalidFlightTime):
+				common.RespondError(w, initTime, err, err.Error(), http.StatusBadRequest)
+			default:
+				common.RespondError(w, initTime, err, "Failed to resubmit PIREP", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		common.RespondSuccess(w, initTime, "PIREP resubmitted successfully", toPirepDetail(pirep))
+	}
+}
+
+// resolveClaimsVA looks up the caller's VA from the Discord server ID in their claims.
+// Returns the HTTP status to use when the lookup fails.
+func (h *Handlers) resolveClaimsVA(r *http.Request) (*gormModels.VA, int, error) {
+	return claimsVA(r, h.deps.Repo.VAGorm)
+}
+
+// claimsVA is resolveClaimsVA for handlers that hold the VA repository directly
+func claimsVA(r *http.Request, vaRepo *repositories.VAGormRepository) (*gormModels.VA, int, error) {
+	claims := auth.GetUserClaims(r.Context())
+	if claims == nil {
+		return nil, http.StatusUnauthorized, fmt.Errorf("missing claims")
+	}
+
+	vaDiscordServerID := claims.DiscordServerID()
+	if vaDiscordServerID == "" {
+		return nil, http.StatusNotFound, fmt.Errorf("va not found")
+	}
+
+	va, err := vaRepo.GetByDiscordServerID(r.Context(), vaDiscordServerID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if va == nil {
+		return nil, http.StatusNotFound, fmt.Errorf("va not found")
+	}
+
+	return va, http.StatusOK, nil
+}
+
+// toPirepDetail maps a native PIREP onto its API representation
+func toPirepDetail(p *gormModels.Pirep) dtos.PirepDetail {
+	detail := dtos.PirepDetail{
+		ID:                p.ID,
+		PilotCallsign:     p.PilotCallsign,
+		Mode:              p.Mode,
+		ModeDisplayName:   p.ModeDisplayName,
+		Route:             p.Route,
+		FlightTimeSeconds: p.FlightTimeSeconds,
+		Multiplier:        p.Multiplier,
+		CreditedSeconds:   p.CreditedSeconds,
+		Aircraft:          p.Aircraft,
+		Airline:           p.Airline,
+		FuelKg:            p.FuelKg,
+		CargoKg:           p.CargoKg,
+		Passengers:        p.Passengers,
+		PilotRemarks:      p.PilotRemarks,
+		ValidationStatus:  p.ValidationStatus,
+		ValidationReason:  p.ValidationReason,
+		HistoryFlightID:   p.HistoryFlightID,
+		FlownSeconds:      p.DerivedFlightTimeSeconds,
+		FlownOrigin:       p.DerivedOrigin,
+		FlownDestination:  p.DerivedDestination,
+		Landings:          p.Landings,
+		Violations:        p.Violations,
+		Discrepancies:     p.HistoryDiscrepancies,
+		Status:            p.Status.String(),
+		ReviewedBy:        p.ReviewedBy,
+		ReviewNotes:       p.ReviewNotes,
+		ReviewedAt:        p.ReviewedAt,
+		ProviderRecordID:  p.ProviderRecordID,
+		ProviderError:     p.ProviderError,
+		SubmittedAt:       p.SubmittedAt,
+	}
+	if p.User.UserName != nil {
+		detail.PilotUsername = *p.User.UserName
+	}
+	return detail
+}
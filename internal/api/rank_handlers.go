@@ -0,0 +1,275 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"infinite-experiment/politburo/internal/auth"
+	"infinite-experiment/politburo/internal/common"
+	"infinite-experiment/politburo/internal/models/dtos"
+	gormModels "infinite-experiment/politburo/internal/models/gorm"
+	"infinite-experiment/politburo/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// defaultPromotionsLimit is the number of promotion events returned when no limit is given
+const defaultPromotionsLimit = 50
+
+// ListRanks handles GET /api/v1/va/ranks
+// Returns the VA's rank ladder, lowest rank first
+func (h *Handlers) ListRanks() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		initTime := time.Now()
+
+		va, status, err := h.resolveClaimsVA(r)
+		if err != nil {
+			common.RespondError(w, initTime, err, "Virtual airline not found", status)
+			return
+		}
+
+		ranks, err := h.deps.Services.Rank.ListRanks(r.Context(), va.ID)
+		if err != nil {
+			common.RespondError(w, initTime, err, "Failed to fetch ranks", http.StatusInternalServerError)
+			return
+		}
+
+		common.RespondSuccess(w, initTime, "Ranks fetched successfully", toRankDetails(ranks))
+	}
+}
+
+// SetRankLadder handles PUT /api/v1/va/ranks
+// Replaces the VA's rank ladder and promotes pilots who now qualify (admin-only)
+func (h *Handlers) SetRankLadder() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		initTime := time.Now()
+
+		va, status, err := h.resolveClaimsVA(r)
+		if err != nil {
+			common.RespondError(w, initTime, err, "Virtual airline not found", status)
+			return
+		}
+
+		var req dtos.RankLadderRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			common.RespondError(w, initTime, err, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		ranks, err := h.deps.Services.Rank.SetLadder(r.Context(), va.ID, req.Ranks)
+		if err != nil {
+			respondRankError(w, initTime, err, "Failed to save ranks")
+			return
+		}
+
+		common.RespondSuccess(w, initTime, "Ranks saved successfully", toRankDetails(ranks))
+	}
+}
+
+// GetMyRankProgress handles GET /api/v1/pilot/rank
+// Returns the caller's rank and progress towards the next one
+func (h *Handlers) GetMyRankProgress() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		initTime := time.Now()
+
+		claims := auth.GetUserClaims(r.Context())
+		if claims == nil {
+			common.RespondError(w, initTime, nil, "Unauthorized: missing claims", http.StatusUnauthorized)
+			return
+		}
+
+		va, status, err := h.resolveClaimsVA(r)
+		if err != nil {
+			common.RespondError(w, initTime, err, "Virtual airline not found", status)
+			return
+		}
+
+		progress, err := h.deps.Services.Rank.GetProgress(r.Context(), va.ID, claims.UserID())
+		if err != nil {
+			respondRankError(w, initTime, err, "Failed to fetch rank progress")
+			return
+		}
+
+		common.RespondSuccess(w, initTime, "Rank progress fetched successfully", progress)
+	}
+}
+
+// GetPilotRankProgress handles GET /api/v1/va/pilots/{user_id}/rank
+// Returns a pilot's rank and progress towards the next one (staff-only)
+func (h *Handlers) GetPilotRankProgress() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		initTime := time.Now()
+
+		va, status, err := h.resolveClaimsVA(r)
+		if err != nil {
+			common.RespondError(w, initTime, err, "Virtual airline not found", status)
+			return
+		}
+
+		userID := chi.URLParam(r, "user_id")
+		if userID == "" {
+			common.RespondError(w, initTime, nil, "Missing user_id in URL", http.StatusBadRequest)
+			return
+		}
+
+		progress, err := h.deps.Services.Rank.GetProgress(r.Context(), va.ID, userID)
+		if err != nil {
+			respondRankError(w, initTime, err, "Failed to fetch rank progress")
+			return
+		}
+
+		common.RespondSuccess(w, initTime, "Rank progress fetched successfully", progress)
+	}
+}
+
+// ListRankProgress handles GET /api/v1/va/ranks/progress
+// Returns every active pilot's rank and progress towards the next one (staff-only)
+func (h *Handlers) ListRankProgress() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		initTime := time.Now()
+
+		va, status, err := h.resolveClaimsVA(r)
+		if err != nil {
+			common.RespondError(w, initTime, err, "Virtual airline not found", status)
+			return
+		}
+
+		progress, err := h.deps.Services.Rank.ListProgress(r.Context(), va.ID)
+		if err != nil {
+			common.RespondError(w, initTime, err, "Failed to fetch rank progress", http.StatusInternalServerError)
+			return
+		}
+
+		common.RespondSuccess(w, initTime, "Rank progress fetched successfully", progress)
+	}
+}
+
+// ListRankPromotions handles GET /api/v1/va/ranks/promotions?limit=
+// Returns the VA's most recent automatic promotions, newest first (staff-only)
+func (h *Handlers) ListRankPromotions() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		initTime := time.Now()
+
+		va, status, err := h.resolveClaimsVA(r)
+		if err != nil {
+			common.RespondError(w, initTime, err, "Virtual airline not found", status)
+			return
+		}
+
+		limit := defaultPromotionsLimit
+		if v := r.URL.Query().Get("limit"); v != "" {
+			if n, err := strconv.Atoi(v); err == nil && n > 0 && n <= 500 {
+				limit = n
+			}
+		}
+
+		promotions, err := h.deps.Services.Rank.ListPromotions(r.Context(), va.ID, limit)
+		if err != nil {
+			common.RespondError(w, initTime, err, "Failed to fetch promotions", http.StatusInternalServerError)
+			return
+		}
+
+		response := make([]dtos.RankPromotionEvent, 0, len(promotions))
+		for i := range promotions {
+			response = append(response, toRankPromotionEvent(&promotions[i]))
+		}
+
+		common.RespondSuccess(w, initTime, "Promotions fetched successfully", response)
+	}
+}
+
+// RecordCheckride handles POST /api/v1/va/pilots/{user_id}/checkrides
+// Records a passed checkride, promoting the pilot if it completes a rank's requirements (staff-only)
+func (h *Handlers) RecordCheckride() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		initTime := time.Now()
+
+		claims := auth.GetUserClaims(r.Context())
+		if claims == nil {
+			common.RespondError(w, initTime, nil, "Unauthorized: missing claims", http.StatusUnauthorized)
+			return
+		}
+
+		va, status, err := h.resolveClaimsVA(r)
+		if err != nil {
+			common.RespondError(w, initTime, err, "Virtual airline not found", status)
+			return
+		}
+
+		userID := chi.URLParam(r, "user_id")
+		if userID == "" {
+			common.RespondError(w, initTime, nil, "Missing user_id in URL", http.StatusBadRequest)
+			return
+		}
+
+		var req dtos.CheckrideRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			common.RespondError(w, initTime, err, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		if _, err := h.deps.Services.Rank.RecordCheckride(r.Context(), va.ID, userID, claims.UserID(), &req); err != nil {
+			respondRankError(w, initTime, err, "Failed to record checkride")
+			return
+		}
+
+		progress, err := h.deps.Services.Rank.GetProgress(r.Context(), va.ID, userID)
+		if err != nil {
+			respondRankError(w, initTime, err, "Checkride recorded but failed to fetch rank progress")
+			return
+		}
+
+		common.RespondSuccess(w, initTime, "Checkride recorded successfully", progress)
+	}
+}
+
+// respondRankError maps rank service errors onto HTTP responses
+func respondRankError(w http.ResponseWriter, initTime time.Time, err error, fallback string) {
+	var ladderErr *services.RankLadderError
+	switch {
+	case errors.As(err, &ladderErr):
+		common.RespondErrorWithData(w, initTime, nil, "Invalid rank ladder", map[string]interface{}{"problems": ladderErr.Problems}, http.StatusBadRequest)
+	case errors.Is(err, services.ErrPilotNotInVA):
+		common.RespondError(w, initTime, err, "Pilot is not a member of this virtual airline", http.StatusNotFound)
+	case errors.Is(err, services.ErrCheckrideNameRequired):
+		common.RespondError(w, initTime, err, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, services.ErrCheckrideAlreadyRecorded):
+		common.RespondError(w, initTime, err, "Checkride has already been recorded for this pilot", http.StatusConflict)
+	default:
+		common.RespondError(w, initTime, err, fallback, http.StatusInternalServerError)
+	}
+}
+
+// toRankDetails maps a rank ladder onto its API representation
+func toRankDetails(ranks []gormModels.VARank) []dtos.Rank {
+	details := make([]dtos.Rank, 0, len(ranks))
+	for i := range ranks {
+		details = append(details, services.RankDetail(&ranks[i]))
+	}
+	return details
+}
+
+// toRankPromotionEvent maps a promotion onto its API representation
+func toRankPromotionEvent(p *gormModels.RankPromotion) dtos.RankPromotionEvent {
+	event := dtos.RankPromotionEvent{
+		ID:            p.ID,
+		UserID:        p.UserID,
+		PirepID:       p.PirepID,
+		CreditedHours: p.CreditedHours,
+		Flights:       p.Flights,
+		CreatedAt:     p.CreatedAt,
+	}
+	if p.User != nil && p.User.UserName != nil {
+		event.Username = *p.User.UserName
+	}
+	if p.FromRank != nil {
+		event.FromRank = p.FromRank.Name
+	}
+	if p.ToRank != nil {
+		event.ToRank = p.ToRank.Name
+	}
+	return event
+}
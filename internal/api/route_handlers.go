@@ -0,0 +1,256 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"infinite-experiment/politburo/internal/common"
+	"infinite-experiment/politburo/internal/models/dtos"
+	gormModels "infinite-experiment/politburo/internal/models/gorm"
+	"infinite-experiment/politburo/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// maxRouteImportBytes caps the size of an uploaded route CSV
+const maxRouteImportBytes = 5 << 20
+
+// ListRoutes handles GET /api/v1/va/routes
+// Returns the VA's full route catalogue, including inactive and Airtable-synced routes (staff-only)
+func (h *Handlers) ListRoutes() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		initTime := time.Now()
+
+		va, status, err := h.resolveClaimsVA(r)
+		if err != nil {
+			common.RespondError(w, initTime, err, "Virtual airline not found", status)
+			return
+		}
+
+		routes, err := h.deps.Services.RouteCatalogue.ListRoutes(r.Context(), va.ID)
+		if err != nil {
+			common.RespondError(w, initTime, err, "Failed to fetch routes", http.StatusInternalServerError)
+			return
+		}
+
+		response := make([]dtos.RouteDetail, 0, len(routes))
+		for i := range routes {
+			response = append(response, toRouteDetail(&routes[i]))
+		}
+
+		common.RespondSuccess(w, initTime, "Routes fetched successfully", response)
+	}
+}
+
+// CreateRoute handles POST /api/v1/va/routes
+// Adds a native route to the VA's catalogue (admin-only)
+func (h *Handlers) CreateRoute() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		initTime := time.Now()
+
+		va, status, err := h.resolveClaimsVA(r)
+		if err != nil {
+			common.RespondError(w, initTime, err, "Virtual airline not found", status)
+			return
+		}
+
+		var req dtos.RouteRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			common.RespondError(w, initTime, err, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		route, err := h.deps.Services.RouteCatalogue.CreateRoute(r.Context(), va.ID, &req)
+		if err != nil {
+			respondRouteError(w, initTime, err, "Failed to create route")
+			return
+		}
+
+		common.RespondSuccess(w, initTime, "Route created successfully", toRouteDetail(route))
+	}
+}
+
+// UpdateRoute handles PUT /api/v1/va/routes/{route_id}
+// Replaces a route's details; Airtable routes only accept catalogue details (admin-only)
+func (h *Handlers) UpdateRoute() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		initTime := time.Now()
+
+		va, status, err := h.resolveClaimsVA(r)
+		if err != nil {
+			common.RespondError(w, initTime, err, "Virtual airline not found", status)
+			return
+		}
+
+		routeID := chi.URLParam(r, "route_id")
+		if routeID == "" {
+			common.RespondError(w, initTime, nil, "Missing route_id in URL", http.StatusBadRequest)
+			return
+		}
+
+		var req dtos.RouteRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			common.RespondError(w, initTime, err, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		route, err := h.deps.Services.RouteCatalogue.UpdateRoute(r.Context(), va.ID, routeID, &req)
+		if err != nil {
+			respondRouteError(w, initTime, err, "Failed to update route")
+			return
+		}
+
+		common.RespondSuccess(w, initTime, "Route updated successfully", toRouteDetail(route))
+	}
+}
+
+// DeleteRoute handles DELETE /api/v1/va/routes/{route_id}
+// Removes a native route from the VA's catalogue (admin-only)
+func (h *Handlers) DeleteRoute() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		initTime := time.Now()
+
+		va, status, err := h.resolveClaimsVA(r)
+		if err != nil {
+			common.RespondError(w, initTime, err, "Virtual airline not found", status)
+			return
+		}
+
+		routeID := chi.URLParam(r, "route_id")
+		if routeID == "" {
+			common.RespondError(w, initTime, nil, "Missing route_id in URL", http.StatusBadRequest)
+			return
+		}
+
+		if err := h.deps.Services.RouteCatalogue.DeleteRoute(r.Context(), va.ID, routeID); err != nil {
+			respondRouteError(w, initTime, err, "Failed to delete route")
+			return
+		}
+
+		common.RespondSuccess(w, initTime, "Route deleted successfully", map[string]string{"id": routeID})
+	}
+}
+
+// ImportRoutes handles POST /api/v1/va/routes/import
+// Bulk creates or updates routes from a CSV file, sent either as a multipart "file" field
+// or as the raw request body (admin-only)
+func (h *Handlers) ImportRoutes() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		initTime := time.Now()
+
+		va, status, err := h.resolveClaimsVA(r)
+		if err != nil {
+			common.RespondError(w, initTime, err, "Virtual airline not found", status)
+			return
+		}
+
+		csvData, err := readRouteImport(w, r)
+		if err != nil {
+			common.RespondError(w, initTime, err, "Invalid CSV upload", http.StatusBadRequest)
+			return
+		}
+
+		result, err := h.deps.Services.RouteCatalogue.ImportCSV(r.Context(), va.ID, bytes.NewReader(csvData))
+		if err != nil {
+			respondRouteError(w, initTime, err, "Failed to import routes")
+			return
+		}
+
+		common.RespondSuccess(w, initTime, "Routes imported successfully", result)
+	}
+}
+
+// ExportRoutes handles GET /api/v1/va/routes/export
+// Downloads the VA's route catalogue as CSV in the format accepted by the import endpoint (staff-only)
+func (h *Handlers) ExportRoutes() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		initTime := time.Now()
+
+		va, status, err := h.resolveClaimsVA(r)
+		if err != nil {
+			common.RespondError(w, initTime, err, "Virtual airline not found", status)
+			return
+		}
+
+		// Render into a buffer first so a failure can still be reported as JSON
+		var buf bytes.Buffer
+		if err := h.deps.Services.RouteCatalogue.ExportCSV(r.Context(), va.ID, &buf); err != nil {
+			common.RespondError(w, initTime, err, "Failed to export routes", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", strings.ToLower(va.Code)+"-routes.csv"))
+		w.WriteHeader(http.StatusOK)
+		w.Write(buf.Bytes())
+	}
+}
+
+// readRouteImport reads an uploaded route CSV from a multipart form or the raw body
+func readRouteImport(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxRouteImportBytes)
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			return nil, fmt.Errorf("missing 'file' field: %w", err)
+		}
+		defer file.Close()
+		return io.ReadAll(file)
+	}
+
+	return io.ReadAll(r.Body)
+}
+
+// respondRouteError maps route catalogue service errors onto HTTP responses
+func respondRouteError(w http.ResponseWriter, initTime time.Time, err error, fallback string) {
+	var routeErr *services.RouteCatalogueError
+	switch {
+	case errors.As(err, &routeErr):
+		common.RespondErrorWithData(w, initTime, nil, "Invalid route", map[string]interface{}{"problems": routeErr.Problems}, http.StatusBadRequest)
+	case errors.Is(err, services.ErrRouteNotFound):
+		common.RespondError(w, initTime, err, "Route not found", http.StatusNotFound)
+	case errors.Is(err, services.ErrRouteManagedByProvider):
+		common.RespondError(w, initTime, err, "Route is synced from Airtable; deactivate it instead", http.StatusConflict)
+	default:
+		common.RespondError(w, initTime, err, fallback, http.StatusInternalServerError)
+	}
+}
+
+// toRouteDetail maps a catalogue route onto its API representation
+func toRouteDetail(route *gormModels.RouteATSynced) dtos.RouteDetail {
+	detail := dtos.RouteDetail{
+		ID:               route.ID,
+		Source:           route.Source,
+		AirtableID:       route.ATID,
+		Route:            route.Route,
+		Origin:           route.Origin,
+		Destination:      route.Destination,
+		FlightNumber:     route.FlightNumber,
+		Aircraft:         route.Aircraft,
+		DistanceNm:       route.DistanceNm,
+		BlockTimeMinutes: route.BlockTimeMinutes,
+		Multiplier:       route.Multiplier,
+		IsActive:         route.IsActive,
+		UpdatedAt:        route.UpdatedAt,
+
+		GreatCircleNm:         route.GreatCircleNm,
+		Haul:                  route.Haul,
+		EstimatedBlockMinutes: services.RouteBlockEstimates(route),
+	}
+	if route.ActiveFrom != nil {
+		from := route.ActiveFrom.Format("2006-01-02")
+		detail.ActiveFrom = &from
+	}
+	if route.ActiveUntil != nil {
+		until := route.ActiveUntil.Format("2006-01-02")
+		detail.ActiveUntil = &until
+	}
+	return detail
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"infinite-experiment/politburo/internal/common"
+)
+
+// SyncRunResponse represents one sync of an entity type by a background job
+type SyncRunResponse struct {
+	ID              string     `json:"id"`
+	Job             string     `json:"job"`
+	EntityType      string     `json:"entity_type"`
+	ProviderType    *string    `json:"provider_type,omitempty"`
+	Trigger         string     `json:"trigger"`
+	Status          string     `json:"status"`
+	Incremental     bool       `json:"incremental"`
+	StartedAt       time.Time  `json:"started_at"`
+	FinishedAt      *time.Time `json:"finished_at,omitempty"`
+	DurationMs      *int       `json:"duration_ms,omitempty"`
+	Pages           int        `json:"pages"`
+	RecordsFetched  int        `json:"records_fetched"`
+	RecordsInserted int        `json:"records_inserted"`
+	RecordsUpdated  int        `json:"records_updated"`
+	RecordsFailed   int        `json:"records_failed"`
+	RecordsDeleted  int        `json:"records_deleted"`
+	RateLimitWaits  int        `json:"rate_limit_waits"`
+	RateLimitWaitMs int        `json:"rate_limit_wait_ms"`
+	ErrorCount      int        `json:"error_count"`
+	Errors          []string   `json:"errors"`
+	Message         *string    `json:"message,omitempty"`
+}
+
+// ListSyncRuns handles GET /api/v1/admin/jobs/runs
+// Returns the VA's sync run history, newest first, optionally filtered by job and entity_type (admin-only)
+func (h *Handlers) ListSyncRuns() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		initTime := time.Now()
+
+		va, status, err := h.resolveClaimsVA(r)
+		if err != nil {
+			common.RespondError(w, initTime, err, "Virtual airline not found", status)
+			return
+		}
+
+		limit := 100
+		if v, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && v > 0 && v <= 500 {
+			limit = v
+		}
+
+		runs, err := h.deps.Repo.SyncRun.ListByVA(r.Context(), va.ID, r.URL.Query().Get("job"), r.URL.Query().Get("entity_type"), limit)
+		if err != nil {
+			common.RespondError(w, initTime, err, "Failed to fetch sync runs", http.StatusInternalServerError)
+			return
+		}
+
+		response := make([]SyncRunResponse, 0, len(runs))
+		for _, run := range runs {
+			response = append(response, SyncRunResponse{
+				ID:              run.ID,
+				Job:             run.Job,
+				EntityType:      run.EntityType,
+				ProviderType:    run.ProviderType,
+				Trigger:         run.Trigger,
+				Status:          run.Status,
+				Incremental:     run.Incremental,
+				StartedAt:       run.StartedAt,
+				FinishedAt:      run.FinishedAt,
+				DurationMs:      run.DurationMs,
+				Pages:           run.Pages,
+				RecordsFetched:  run.RecordsFetched,
+				RecordsInserted: run.RecordsInserted,
+				RecordsUpdated:  run.RecordsUpdated,
+				RecordsFailed:   run.RecordsFailed,
+				RecordsDeleted:  run.RecordsDeleted,
+				RateLimitWaits:  run.RateLimitWaits,
+				RateLimitWaitMs: run.RateLimitWaitMs,
+				ErrorCount:      run.ErrorCount,
+				Errors:          run.Errors,
+				Message:         run.Message,
+			})
+		}
+
+		common.RespondSuccess(w, initTime, "Sync runs fetched successfully", response)
+	}
+}
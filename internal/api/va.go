@@ -2,11 +2,13 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	ctxutil "infinite-experiment/politburo/internal/auth"
 	"infinite-experiment/politburo/internal/common"
 	"infinite-experiment/politburo/internal/constants"
 	"infinite-experiment/politburo/internal/models/dtos"
+	"infinite-experiment/politburo/internal/secrets"
 	"infinite-experiment/politburo/internal/services"
 	"log"
 	"net/http"
@@ -76,7 +78,7 @@ func GetVAConfigs(cfgSvc *common.VAConfigService) http.HandlerFunc {
 		resp := dtos.APIResponse{
 			Status:       strconv.FormatBool(true),
 			ResponseTime: common.GetResponseTime(initTime),
-			Data:         cfgs,
+			Data:         common.RedactVAConfigs(cfgs),
 		}
 
 		w.Header().Set("Content-Type", "application/json")
@@ -114,6 +116,14 @@ func SetConfigKeys(cfgSvc *common.VAConfigService) http.HandlerFunc {
 		}
 
 		res, err := cfgSvc.SetVaConfig(r.Context(), cfgs)
+		if errors.Is(err, secrets.ErrNoKey) {
+			common.RespondError(w, initTime, err, secretsNotConfiguredMessage, http.StatusUnprocessableEntity)
+			return
+		}
+		if errors.Is(err, secrets.ErrSealedInput) {
+			common.RespondError(w, initTime, err, err.Error(), http.StatusBadRequest)
+			return
+		}
 
 		msg := "Config set successfully"
 
@@ -122,11 +132,16 @@ func SetConfigKeys(cfgSvc *common.VAConfigService) http.HandlerFunc {
 			msg = err.Error()
 		}
 
+		var data map[string]string
+		if res != nil {
+			data = common.RedactVAConfigs(*res)
+		}
+
 		resp := dtos.APIResponse{
 			Status:       strconv.FormatBool(true),
 			ResponseTime: common.GetResponseTime(initTime),
 			Message:      msg,
-			Data:         res,
+			Data:         data,
 		}
 
 		w.Header().Set("Content-Type", "application/json")
@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"infinite-experiment/politburo/internal/models/entities"
+)
+
+// API keys look like pb_<prefix>_<secret>. The prefix is stored in the clear to identify the
+// key; the secret carries 256 bits of entropy, so a plain SHA-256 hash is enough to store it.
+const (
+	apiKeyMarker      = "pb_"
+	apiKeyPrefixBytes = 4
+	apiKeySecretBytes = 32
+)
+
+var apiKeyKey contextKey = "api_key"
+
+// GenerateAPIKey returns a new random API key and its prefix
+func GenerateAPIKey() (key string, prefix string, err error) {
+	buf := make([]byte, apiKeyPrefixBytes+apiKeySecretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+
+	prefix = apiKeyMarker + hex.EncodeToString(buf[:apiKeyPrefixBytes])
+	key = prefix + "_" + base64.RawURLEncoding.EncodeToString(buf[apiKeyPrefixBytes:])
+	return key, prefix, nil
+}
+
+// HashAPIKey returns the hash an API key is stored and looked up by
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(key)))
+	return hex.EncodeToString(sum[:])
+}
+
+// LegacyAPIKeyHash returns the hash a key issued before pb_ keys is stored by, and whether the
+// key has that form. Legacy keys are their UUID ID, hashed from its lowercase text form, so
+// they are matched whatever case the client sends them in.
+func LegacyAPIKeyHash(key string) (string, bool) {
+	key = strings.TrimSpace(key)
+	if strings.HasPrefix(key, apiKeyMarker) {
+		return "", false
+	}
+	return HashAPIKey(strings.ToLower(key)), true
+}
+
+// SetAPIKey stores the API key a request was authenticated with
+func SetAPIKey(ctx context.Context, key *entities.ApiKey) context.Context {
+	return context.WithValue(ctx, apiKeyKey, key)
+}
+
+// GetAPIKey returns the API key a request was authenticated with, or nil for other
+// authentication methods
+func GetAPIKey(ctx context.Context) *entities.ApiKey {
+	key, _ := ctx.Value(apiKeyKey).(*entities.ApiKey)
+	return key
+}
+
+// IsReadOnlyMethod reports whether an HTTP method only reads, as allowed for read_only keys
+func IsReadOnlyMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"infinite-experiment/politburo/internal/constants"
+)
+
+func TestGenerateAPIKey(t *testing.T) {
+	key, prefix, err := GenerateAPIKey()
+	if err != nil {
+		t.Fatalf("GenerateAPIKey: %v", err)
+	}
+	if !strings.HasPrefix(key, prefix+"_") || !strings.HasPrefix(prefix, "pb_") || len(prefix) != 11 {
+		t.Fatalf("key %q does not start with prefix %q", key, prefix)
+	}
+	if len(key) < 50 {
+		t.Errorf("key %q is too short", key)
+	}
+
+	other, otherPrefix, _ := GenerateAPIKey()
+	if other == key || otherPrefix == prefix {
+		t.Error("generated the same key twice")
+	}
+}
+
+func TestHashAPIKey(t *testing.T) {
+	key, _, _ := GenerateAPIKey()
+
+	hash := HashAPIKey(key)
+	if len(hash) != 64 || strings.Contains(hash, key) {
+		t.Fatalf("HashAPIKey = %q", hash)
+	}
+	if HashAPIKey(" "+key+"\n") != hash {
+		t.Error("surrounding whitespace changed the hash")
+	}
+	if HashAPIKey(key+"x") == hash {
+		t.Error("different keys have the same hash")
+	}
+}
+
+func TestLegacyAPIKeyHash(t *testing.T) {
+	// Migration 019 stored legacy keys by the hash of their lowercase UUID
+	const id = "3f2a9c1e-7b4d-4e8a-9f06-1c2d3e4f5a6b"
+	stored := HashAPIKey(id)
+
+	for _, presented := range []string{id, strings.ToUpper(id), " 3F2A9C1E-7b4d-4E8A-9f06-1C2D3E4F5A6B\n"} {
+		hash, ok := LegacyAPIKeyHash(presented)
+		if !ok || hash != stored {
+			t.Errorf("LegacyAPIKeyHash(%q) = %q, %t, want %q, true", presented, hash, ok, stored)
+		}
+	}
+
+	key, _, _ := GenerateAPIKey()
+	if hash, ok := LegacyAPIKeyHash(key); ok {
+		t.Errorf("LegacyAPIKeyHash(%q) = %q, true, want a pb_ key not treated as legacy", key, hash)
+	}
+}
+
+func TestAPIKeyScope_Includes(t *testing.T) {
+	tests := []struct {
+		scope, other constants.APIKeyScope
+		want         bool
+	}{
+		{constants.APIKeyScopeAdmin, constants.APIKeyScopeBot, true},
+		{constants.APIKeyScopeBot, constants.APIKeyScopeBot, true},
+		{constants.APIKeyScopeBot, constants.APIKeyScopeAdmin, false},
+		{constants.APIKeyScopeReadOnly, constants.APIKeyScopeBot, false},
+		{"unknown", constants.APIKeyScopeReadOnly, false},
+	}
+	for _, tt := range tests {
+		if got := tt.scope.Includes(tt.other); got != tt.want {
+			t.Errorf("%q.Includes(%q) = %t, want %t", tt.scope, tt.other, got, tt.want)
+		}
+	}
+}
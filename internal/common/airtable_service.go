@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"infinite-experiment/politburo/internal/airtable"
 	"infinite-experiment/politburo/internal/auth"
 	"infinite-experiment/politburo/internal/models/dtos"
 	"net/http"
@@ -108,12 +109,7 @@ func (s *AirtableApiService) FetchRecords(
 	}
 
 	if filterByModified && since != nil {
-		filterFormula := fmt.Sprintf(
-			`IS_AFTER({%s}, "%s")`,
-			cfg.LastModifiedAt,
-			since.UTC().Format(time.RFC3339),
-		)
-		payload["filterByFormula"] = filterFormula
+		payload["filterByFormula"] = airtable.IsAfter(airtable.Field(cfg.LastModifiedAt), *since).String()
 	}
 
 	if offset != "" {
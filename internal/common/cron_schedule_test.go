@@ -0,0 +1,110 @@
+package common
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronScheduleErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+	}{
+		{"empty", ""},
+		{"too few fields", "* * * *"},
+		{"too many fields", "* * * * * *"},
+		{"unknown shorthand", "@yearly"},
+		{"minute too large", "60 * * * *"},
+		{"hour too large", "* 24 * * *"},
+		{"day of month zero", "* * 0 * *"},
+		{"month too large", "* * * 13 *"},
+		{"day of week too large", "* * * * 8"},
+		{"not a number", "a * * * *"},
+		{"zero step", "*/0 * * * *"},
+		{"negative step", "*/-5 * * * *"},
+		{"step not a number", "*/x * * * *"},
+		{"reversed range", "30-10 * * * *"},
+		{"range not a number", "1-x * * * *"},
+		{"empty list entry", "1,,2 * * * *"},
+		{"every without duration", "@every "},
+		{"every invalid duration", "@every soon"},
+		{"every below a second", "@every 500ms"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseCronSchedule(tt.expr); err == nil {
+				t.Errorf("ParseCronSchedule(%q) succeeded, want an error", tt.expr)
+			}
+		})
+	}
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	at := func(year int, month time.Month, day, hour, minute int) time.Time {
+		return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{"every interval", "@every 90m", at(2024, 1, 1, 10, 0), at(2024, 1, 1, 11, 30)},
+		{"strictly after a firing time", "0 0 * * *", at(2024, 1, 1, 0, 0), at(2024, 1, 2, 0, 0)},
+		{"seconds are dropped", "* * * * *", at(2024, 1, 1, 10, 0).Add(30 * time.Second), at(2024, 1, 1, 10, 1)},
+		{"step over hour boundary", "*/15 * * * *", at(2024, 3, 5, 10, 50), at(2024, 3, 5, 11, 0)},
+		{"stepped range", "0 8-18/5 * * *", at(2024, 1, 1, 19, 0), at(2024, 1, 2, 8, 0)},
+		{"list", "0 6,18 * * *", at(2024, 1, 1, 7, 0), at(2024, 1, 1, 18, 0)},
+		{"hour across day boundary", "30 2 * * *", at(2024, 5, 10, 3, 0), at(2024, 5, 11, 2, 30)},
+
+		// Month and year boundaries
+		{"monthly across month boundary", "@monthly", at(2024, 1, 31, 12, 0), at(2024, 2, 1, 0, 0)},
+		{"daily across year boundary", "30 2 * * *", at(2024, 12, 31, 23, 59), at(2025, 1, 1, 2, 30)},
+		{"step across year boundary", "*/15 * * * *", at(2024, 12, 31, 23, 50), at(2025, 1, 1, 0, 0)},
+		{"yearly", "0 0 1 1 *", at(2024, 6, 15, 8, 0), at(2025, 1, 1, 0, 0)},
+		{"31st skips short months", "0 0 31 * *", at(2024, 4, 1, 0, 0), at(2024, 5, 31, 0, 0)},
+		{"30th skips February", "0 0 30 * *", at(2023, 1, 31, 0, 0), at(2023, 3, 30, 0, 0)},
+		{"29 February in a leap year", "0 0 29 2 *", at(2024, 1, 1, 0, 0), at(2024, 2, 29, 0, 0)},
+		{"29 February waits for the next leap year", "0 0 29 2 *", at(2024, 3, 1, 0, 0), at(2028, 2, 29, 0, 0)},
+		{"month range wraps into next year", "0 0 1 3-5 *", at(2024, 6, 1, 0, 0), at(2025, 3, 1, 0, 0)},
+
+		// Day of month and day of week
+		{"day of week", "0 0 * * 5", at(2024, 9, 1, 0, 0), at(2024, 9, 6, 0, 0)},
+		{"day of week across year boundary", "0 9 * * 1", at(2024, 12, 31, 0, 0), at(2025, 1, 6, 9, 0)},
+		{"weekly is Sunday", "@weekly", at(2024, 6, 3, 0, 0), at(2024, 6, 9, 0, 0)},
+		{"7 is Sunday", "0 9 * * 7", at(2024, 6, 3, 0, 0), at(2024, 6, 9, 9, 0)},
+		{"day of week range", "0 0 * * 1-5", at(2024, 6, 8, 0, 0), at(2024, 6, 10, 0, 0)},
+		{"day of month with any day of week", "0 0 15 * *", at(2024, 9, 1, 0, 0), at(2024, 9, 15, 0, 0)},
+		{"either day field matches: day of month first", "0 0 15 * 1", at(2024, 9, 10, 0, 0), at(2024, 9, 15, 0, 0)},
+		{"either day field matches: day of week first", "0 0 15 * 1", at(2024, 9, 1, 0, 0), at(2024, 9, 2, 0, 0)},
+		{"stepped day of month with any day of week", "0 0 */10 * *", at(2024, 2, 22, 0, 0), at(2024, 3, 1, 0, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseCronSchedule(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseCronSchedule(%q): %v", tt.expr, err)
+			}
+			if got := schedule.Next(tt.from); !got.Equal(tt.want) {
+				t.Errorf("Next(%s) = %s, want %s", tt.from.Format(time.RFC3339), got.Format(time.RFC3339), tt.want.Format(time.RFC3339))
+			}
+		})
+	}
+}
+
+func TestCronScheduleNextIsUTC(t *testing.T) {
+	schedule, err := ParseCronSchedule("0 0 1 * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 01:30 on 1 May in UTC+2 is still 30 April in UTC
+	from := time.Date(2024, 5, 1, 1, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+	want := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	if got := schedule.Next(from); !got.Equal(want) {
+		t.Errorf("Next(%s) = %s, want %s", from.Format(time.RFC3339), got.Format(time.RFC3339), want.Format(time.RFC3339))
+	}
+}
@@ -0,0 +1,17 @@
+package common
+
+import "math"
+
+const earthRadiusNm = 3440.065
+
+// GreatCircleDistanceNm returns the great-circle (haversine) distance between two coordinates in nautical miles
+func GreatCircleDistanceNm(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusNm * math.Asin(math.Sqrt(a))
+}
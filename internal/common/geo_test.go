@@ -0,0 +1,49 @@
+package common
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGreatCircleDistanceNm(t *testing.T) {
+	type point struct{ lat, lon float64 }
+	var (
+		jfk = point{40.639801, -73.7789}
+		lhr = point{51.4706, -0.461941}
+		cdg = point{49.012798, 2.55}
+		lax = point{33.942501, -118.407997}
+		syd = point{-33.946098, 151.177002}
+		sin = point{1.35019, 103.994003}
+	)
+
+	tests := []struct {
+		name      string
+		from, to  point
+		want      float64
+		tolerance float64 // Fraction of want
+	}{
+		// Published distances are on the ellipsoid; the sphere is within 1% of them
+		{"New York to London", jfk, lhr, 2999, 0.01},
+		{"London to Paris", lhr, cdg, 188, 0.01},
+		{"Los Angeles to Sydney across the equator and date line", lax, syd, 6507, 0.01},
+		{"Singapore to London", sin, lhr, 5891, 0.01},
+
+		// Exact on the sphere
+		{"same point", lhr, lhr, 0, 0},
+		{"equator to pole", point{0, 0}, point{90, 0}, math.Pi / 2 * earthRadiusNm, 1e-9},
+		{"half the equator", point{0, 0}, point{0, 180}, math.Pi * earthRadiusNm, 1e-9},
+		{"across the date line", point{0, 179.5}, point{0, -179.5}, math.Pi / 180 * earthRadiusNm, 1e-9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GreatCircleDistanceNm(tt.from.lat, tt.from.lon, tt.to.lat, tt.to.lon)
+			if math.Abs(got-tt.want) > tt.want*tt.tolerance+1e-9 {
+				t.Errorf("distance = %.1f nm, want %.1f nm", got, tt.want)
+			}
+			if back := GreatCircleDistanceNm(tt.to.lat, tt.to.lon, tt.from.lat, tt.from.lon); math.Abs(back-got) > 1e-9 {
+				t.Errorf("distance back = %.1f nm, want %.1f nm", back, got)
+			}
+		})
+	}
+}
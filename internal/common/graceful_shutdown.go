@@ -0,0 +1,73 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// defaultShutdownTimeout is how long shutdown waits when SHUTDOWN_TIMEOUT is not set
+const defaultShutdownTimeout = 30 * time.Second
+
+// ShutdownTimeout returns the graceful shutdown deadline configured by SHUTDOWN_TIMEOUT
+// (a Go duration such as "45s"), falling back to 30 seconds
+func ShutdownTimeout() time.Duration {
+	value := os.Getenv("SHUTDOWN_TIMEOUT")
+	if value == "" {
+		return defaultShutdownTimeout
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		log.Printf("[Shutdown] Invalid SHUTDOWN_TIMEOUT %q, using %s", value, defaultShutdownTimeout)
+		return defaultShutdownTimeout
+	}
+	return timeout
+}
+
+// ServeUntilDone serves srv until ctx is cancelled, then stops accepting connections, drains
+// the requests in flight and waits for the background jobs and workers, all within timeout.
+// It returns an error if the server fails or the deadline passes before everything stopped.
+func ServeUntilDone(ctx context.Context, srv *http.Server, background *sync.WaitGroup, timeout time.Duration) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("server stopped: %w", err)
+	case <-ctx.Done():
+	}
+
+	log.Printf("[Shutdown] Shutting down within %s", timeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var errs []error
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to drain HTTP server: %w", err))
+	} else {
+		log.Printf("[Shutdown] HTTP server drained")
+	}
+
+	// ctx is already cancelled, so the jobs and workers are finishing their current work
+	stopped := make(chan struct{})
+	go func() {
+		background.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		log.Printf("[Shutdown] Background jobs and workers stopped")
+	case <-shutdownCtx.Done():
+		errs = append(errs, errors.New("timed out waiting for background jobs and workers"))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,139 @@
+package common
+
+import (
+	"math"
+	"strings"
+
+	gormModels "infinite-experiment/politburo/internal/models/gorm"
+)
+
+// Aircraft classes used for block time estimates
+const (
+	AircraftClassTurboprop   = "turboprop"
+	AircraftClassRegionalJet = "regional_jet"
+	AircraftClassNarrowbody  = "narrowbody"
+	AircraftClassWidebody    = "widebody"
+)
+
+// Route haul classifications by great-circle distance
+const (
+	RouteHaulShort  = "short_haul"
+	RouteHaulMedium = "medium_haul"
+	RouteHaulLong   = "long_haul"
+)
+
+// Haul band upper bounds in nautical miles (short < 1000nm <= medium < 3000nm <= long)
+const (
+	shortHaulMaxNm  = 1000
+	mediumHaulMaxNm = 3000
+)
+
+// AircraftClasses lists every aircraft class, in display order
+var AircraftClasses = []string{
+	AircraftClassTurboprop,
+	AircraftClassRegionalJet,
+	AircraftClassNarrowbody,
+	AircraftClassWidebody,
+}
+
+// RouteHauls lists every haul classification, shortest first
+var RouteHauls = []string{RouteHaulShort, RouteHaulMedium, RouteHaulLong}
+
+// aircraftClassProfile is the average block speed and fixed taxi/climb/descent allowance of a class
+type aircraftClassProfile struct {
+	blockSpeedKts float64
+	overheadMin   float64
+}
+
+var aircraftClassProfiles = map[string]aircraftClassProfile{
+	AircraftClassTurboprop:   {blockSpeedKts: 270, overheadMin: 20},
+	AircraftClassRegionalJet: {blockSpeedKts: 400, overheadMin: 25},
+	AircraftClassNarrowbody:  {blockSpeedKts: 440, overheadMin: 30},
+	AircraftClassWidebody:    {blockSpeedKts: 480, overheadMin: 35},
+}
+
+// Substrings of Infinite Flight aircraft names identifying non-narrowbody classes
+var (
+	turbopropMarkers   = []string{"ATR", "DASH 8", "Q400", "DHC", "CESSNA", "TBM", "KING AIR", "C-130", "PC-12", "XCUB", "SR22"}
+	regionalJetMarkers = []string{"CRJ", "E175", "E190", "E195", "ERJ", "EMBRAER", "BAE 146", "CS100", "A220"}
+	widebodyMarkers    = []string{"A330", "A340", "A350", "A380", "747", "767", "777", "787", "MD-11", "DC-10", "C-17"}
+)
+
+// ClassifyRouteHaul returns the haul band for a great-circle distance
+func ClassifyRouteHaul(distanceNm float64) string {
+	switch {
+	case distanceNm < shortHaulMaxNm:
+		return RouteHaulShort
+	case distanceNm < mediumHaulMaxNm:
+		return RouteHaulMedium
+	default:
+		return RouteHaulLong
+	}
+}
+
+// EstimateBlockMinutes estimates gate-to-gate time for a distance flown by an aircraft class.
+// Unknown classes are estimated as narrowbody.
+func EstimateBlockMinutes(distanceNm float64, aircraftClass string) int {
+	profile, ok := aircraftClassProfiles[aircraftClass]
+	if !ok {
+		profile = aircraftClassProfiles[AircraftClassNarrowbody]
+	}
+	return int(math.Round(distanceNm/profile.blockSpeedKts*60 + profile.overheadMin))
+}
+
+// AircraftClassFor maps an aircraft name (e.g. "Boeing 777-300ER") onto an aircraft class.
+// Returns an empty string when no name is given; unrecognised aircraft are treated as narrowbody.
+func AircraftClassFor(aircraft string) string {
+	name := strings.ToUpper(strings.TrimSpace(aircraft))
+	if name == "" {
+		return ""
+	}
+
+	containsAny := func(markers []string) bool {
+		for _, m := range markers {
+			if strings.Contains(name, m) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch {
+	case containsAny(widebodyMarkers):
+		return AircraftClassWidebody
+	case containsAny(regionalJetMarkers):
+		return AircraftClassRegionalJet
+	case containsAny(turbopropMarkers):
+		return AircraftClassTurboprop
+	default:
+		return AircraftClassNarrowbody
+	}
+}
+
+// ApplyRouteEstimates computes the route's great-circle distance, haul band and per-class
+// block time estimates from its airport coordinates. Estimates are cleared when either
+// airport's coordinates are unknown.
+func ApplyRouteEstimates(route *gormModels.RouteATSynced) {
+	if !route.OriginLat.Valid || !route.OriginLon.Valid || !route.DestinationLat.Valid || !route.DestinationLon.Valid {
+		route.GreatCircleNm = nil
+		route.Haul = nil
+		route.EstimatedBlockMinutes = nil
+		return
+	}
+
+	distance := GreatCircleDistanceNm(
+		route.OriginLat.Float64, route.OriginLon.Float64,
+		route.DestinationLat.Float64, route.DestinationLon.Float64,
+	)
+	distance = math.Round(distance*10) / 10
+	haul := ClassifyRouteHaul(distance)
+
+	estimates := make(gormModels.JSONB, len(AircraftClasses))
+	for _, class := range AircraftClasses {
+		estimates[class] = EstimateBlockMinutes(distance, class)
+	}
+
+	route.GreatCircleNm = &distance
+	route.Haul = &haul
+	route.EstimatedBlockMinutes = estimates
+}
@@ -0,0 +1,124 @@
+package common
+
+import (
+	"database/sql"
+	"testing"
+
+	gormModels "infinite-experiment/politburo/internal/models/gorm"
+)
+
+func TestClassifyRouteHaul(t *testing.T) {
+	tests := []struct {
+		distanceNm float64
+		want       string
+	}{
+		{0, RouteHaulShort},
+		{500, RouteHaulShort},
+		{999.9, RouteHaulShort},
+		{1000, RouteHaulMedium},
+		{1000.1, RouteHaulMedium},
+		{2999.9, RouteHaulMedium},
+		{3000, RouteHaulLong},
+		{3000.1, RouteHaulLong},
+		{8000, RouteHaulLong},
+	}
+
+	for _, tt := range tests {
+		if got := ClassifyRouteHaul(tt.distanceNm); got != tt.want {
+			t.Errorf("ClassifyRouteHaul(%.1f) = %s, want %s", tt.distanceNm, got, tt.want)
+		}
+	}
+}
+
+func TestEstimateBlockMinutes(t *testing.T) {
+	tests := []struct {
+		name       string
+		distanceNm float64
+		class      string
+		want       int
+	}{
+		// Distance / block speed plus the class's fixed allowance, rounded to the minute
+		{"turboprop", 270, AircraftClassTurboprop, 60 + 20},
+		{"regional jet", 400, AircraftClassRegionalJet, 60 + 25},
+		{"narrowbody", 440, AircraftClassNarrowbody, 60 + 30},
+		{"widebody", 480, AircraftClassWidebody, 60 + 35},
+		{"turboprop at 1000 nm", 1000, AircraftClassTurboprop, 242},      // 222.2 + 20
+		{"regional jet at 1000 nm", 1000, AircraftClassRegionalJet, 175}, // 150 + 25
+		{"narrowbody at 1000 nm", 1000, AircraftClassNarrowbody, 166},    // 136.4 + 30
+		{"widebody at 1000 nm", 1000, AircraftClassWidebody, 160},        // 125 + 35
+		{"widebody long haul", 6000, AircraftClassWidebody, 785},         // 750 + 35
+		{"no distance is the allowance", 0, AircraftClassWidebody, 35},
+		{"unknown class is narrowbody", 440, "airship", 90},
+		{"no class is narrowbody", 440, "", 90},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EstimateBlockMinutes(tt.distanceNm, tt.class); got != tt.want {
+				t.Errorf("EstimateBlockMinutes(%.0f, %q) = %d, want %d", tt.distanceNm, tt.class, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAircraftClassFor(t *testing.T) {
+	tests := []struct {
+		aircraft string
+		want     string
+	}{
+		{"", ""},
+		{"   ", ""},
+		{"Boeing 777-300ER", AircraftClassWidebody},
+		{"Airbus A350-900", AircraftClassWidebody},
+		{"Bombardier CRJ-900", AircraftClassRegionalJet},
+		{"Embraer E175", AircraftClassRegionalJet},
+		{"Airbus A220-300", AircraftClassRegionalJet},
+		{"De Havilland Dash 8-Q400", AircraftClassTurboprop},
+		{"ATR 72-600", AircraftClassTurboprop},
+		{"cessna 172", AircraftClassTurboprop},
+		{"Boeing 737-800", AircraftClassNarrowbody},
+		{"Airbus A321", AircraftClassNarrowbody},
+		{"Unknown Prototype", AircraftClassNarrowbody},
+	}
+
+	for _, tt := range tests {
+		if got := AircraftClassFor(tt.aircraft); got != tt.want {
+			t.Errorf("AircraftClassFor(%q) = %q, want %q", tt.aircraft, got, tt.want)
+		}
+	}
+}
+
+func TestApplyRouteEstimates(t *testing.T) {
+	coord := func(v float64) sql.NullFloat64 { return sql.NullFloat64{Float64: v, Valid: true} }
+
+	// New York (JFK) to London (LHR)
+	route := &gormModels.RouteATSynced{
+		OriginLat:      coord(40.639801),
+		OriginLon:      coord(-73.7789),
+		DestinationLat: coord(51.4706),
+		DestinationLon: coord(-0.461941),
+	}
+	ApplyRouteEstimates(route)
+
+	if route.GreatCircleNm == nil || *route.GreatCircleNm != 2991.2 {
+		t.Fatalf("great circle = %v, want 2991.2 nm", route.GreatCircleNm)
+	}
+	if route.Haul == nil || *route.Haul != RouteHaulMedium {
+		t.Errorf("haul = %v, want %s", route.Haul, RouteHaulMedium)
+	}
+	if len(route.EstimatedBlockMinutes) != len(AircraftClasses) {
+		t.Errorf("got %d estimates, want one per class: %v", len(route.EstimatedBlockMinutes), route.EstimatedBlockMinutes)
+	}
+	for _, class := range AircraftClasses {
+		if got, want := route.EstimatedBlockMinutes[class], EstimateBlockMinutes(2991.2, class); got != want {
+			t.Errorf("%s estimate = %v, want %d", class, got, want)
+		}
+	}
+
+	// Losing a coordinate clears the estimates
+	route.DestinationLon = sql.NullFloat64{}
+	ApplyRouteEstimates(route)
+	if route.GreatCircleNm != nil || route.Haul != nil || route.EstimatedBlockMinutes != nil {
+		t.Errorf("estimates kept without coordinates: %v, %v, %v", route.GreatCircleNm, route.Haul, route.EstimatedBlockMinutes)
+	}
+}
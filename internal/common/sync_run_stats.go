@@ -0,0 +1,83 @@
+package common
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MaxSyncRunErrors is the number of error messages kept per sync run; later errors are only counted
+const MaxSyncRunErrors = 20
+
+type syncRunStatsKey struct{}
+
+// SyncRunStats collects what happens deep inside a sync run, such as provider rate-limit
+// waits and per-record errors, for the run history. It is carried on the context so
+// providers and record handlers can report without changing their signatures.
+// All methods are safe on a nil receiver, so callers outside a sync run need not check.
+type SyncRunStats struct {
+	mu              sync.Mutex
+	rateLimitWaits  int
+	rateLimitWaited time.Duration
+	errorCount      int
+	errors          []string
+}
+
+// WithSyncRunStats returns a context carrying stats
+func WithSyncRunStats(ctx context.Context, stats *SyncRunStats) context.Context {
+	return context.WithValue(ctx, syncRunStatsKey{}, stats)
+}
+
+// SyncRunStatsFrom returns the stats carried by ctx, or nil outside a sync run
+func SyncRunStatsFrom(ctx context.Context) *SyncRunStats {
+	stats, _ := ctx.Value(syncRunStatsKey{}).(*SyncRunStats)
+	return stats
+}
+
+// AddError counts an error, keeping its message if fewer than MaxSyncRunErrors are kept
+func (s *SyncRunStats) AddError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.errorCount++
+	if len(s.errors) < MaxSyncRunErrors {
+		s.errors = append(s.errors, err.Error())
+	}
+}
+
+// AddRateLimitWait records a pause made to respect a provider's rate limit
+func (s *SyncRunStats) AddRateLimitWait(d time.Duration) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rateLimitWaits++
+	s.rateLimitWaited += d
+}
+
+// Errors returns the total error count and the kept messages
+func (s *SyncRunStats) Errors() (int, []string) {
+	if s == nil {
+		return 0, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.errorCount, append([]string(nil), s.errors...)
+}
+
+// RateLimitWaits returns the number of rate-limit waits and their total duration
+func (s *SyncRunStats) RateLimitWaits() (int, time.Duration) {
+	if s == nil {
+		return 0, 0
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.rateLimitWaits, s.rateLimitWaited
+}
@@ -6,6 +6,7 @@ import (
 	"infinite-experiment/politburo/internal/auth"
 	"infinite-experiment/politburo/internal/constants"
 	"infinite-experiment/politburo/internal/db/repositories"
+	"infinite-experiment/politburo/internal/secrets"
 	"log"
 	"time"
 )
@@ -38,6 +39,18 @@ const (
 	ConfigKeyATFieldPIREPsFlightTime = "at_field_pireps_ft"
 
 	ConfigKeyATFieldLastModified = "at_field_last_modified"
+
+	// PIREP submission policy keys
+	ConfigKeyPirepValidationPolicy       = "pirep_validation_policy"        // strict | warn | off
+	ConfigKeyPirepFlightTimeToleranceMin = "pirep_flight_time_tolerance_min" // minutes, default 15
+
+	// Job schedule keys: a cron expression, "@every <duration>" or "off",
+	// overriding the job's default schedule for the VA
+	ConfigKeySchedulePilotSync  = "schedule_pilot_sync"
+	ConfigKeyScheduleRouteSync  = "schedule_route_sync"
+	ConfigKeySchedulePirepSync  = "schedule_pirep_sync"
+	ConfigKeyScheduleEntitySync = "schedule_entity_sync"
+	ConfigKeyScheduleReconcile  = "schedule_reconcile"
 )
 
 var AllowedVAConfigKeys = map[string]struct{}{
@@ -59,10 +72,51 @@ var AllowedVAConfigKeys = map[string]struct{}{
 	ConfigKeyATFieldLastModified:          {},
 	ConfigKeyATFieldRoutesRoute:           {},
 	ConfigKeyAirtableCallsignColumnPrefix: {},
+	ConfigKeyPirepValidationPolicy:        {},
+	ConfigKeyPirepFlightTimeToleranceMin:  {},
+	ConfigKeySchedulePilotSync:            {},
+	ConfigKeyScheduleRouteSync:            {},
+	ConfigKeySchedulePirepSync:            {},
+	ConfigKeyScheduleEntitySync:           {},
+	ConfigKeyScheduleReconcile:            {},
+}
+
+// scheduleConfigKeys are the keys whose values must parse as a job schedule
+var scheduleConfigKeys = map[string]struct{}{
+	ConfigKeySchedulePilotSync:  {},
+	ConfigKeyScheduleRouteSync:  {},
+	ConfigKeySchedulePirepSync:  {},
+	ConfigKeyScheduleEntitySync: {},
+	ConfigKeyScheduleReconcile:  {},
+}
+
+// secretConfigKeys are the keys whose values are stored sealed and redacted in responses
+var secretConfigKeys = map[string]struct{}{
+	ConfigKeyAirtableAPIKey: {},
+}
+
+// IsSecretVAConfigKey reports whether a key holds a credential
+func IsSecretVAConfigKey(k string) bool {
+	_, ok := secretConfigKeys[k]
+	return ok
+}
+
+// RedactVAConfigs returns a copy of cfgs with the credentials redacted, for responses and logs
+func RedactVAConfigs(cfgs map[string]string) map[string]string {
+	redacted := make(map[string]string, len(cfgs))
+	for key, value := range cfgs {
+		if IsSecretVAConfigKey(key) {
+			value = secrets.Redact(value)
+		}
+		redacted[key] = value
+	}
+	return redacted
 }
 
 func ListAllowedVAConfigKeys() []string { return GetKeysStructMap(AllowedVAConfigKeys) }
 
+func ListSecretVAConfigKeys() []string { return GetKeysStructMap(secretConfigKeys) }
+
 func IsValidVAConfigKey(k string) bool {
 	_, ok := AllowedVAConfigKeys[k]
 	return ok
@@ -72,13 +126,16 @@ func IsValidVAConfigKey(k string) bool {
 // Service
 ///////////////////////////////////////////////////////////////////////////////
 
+// VAConfigService reads and writes VA config values. Credentials are sealed with the
+// keyring before they are stored, and stay sealed in the cache.
 type VAConfigService struct {
-	repo  *repositories.VARepository
-	cache CacheInterface
+	repo    *repositories.VARepository
+	cache   CacheInterface
+	keyring *secrets.Keyring
 }
 
-func NewVAConfigService(r *repositories.VARepository, c CacheInterface) *VAConfigService {
-	return &VAConfigService{repo: r, cache: c}
+func NewVAConfigService(r *repositories.VARepository, c CacheInterface, k *secrets.Keyring) *VAConfigService {
+	return &VAConfigService{repo: r, cache: c, keyring: k}
 }
 
 func configCacheKey(vaID string) string {
@@ -97,12 +154,38 @@ func (s *VAConfigService) SetVaConfig(
 ) (*map[string]string, error) {
 
 	claims := auth.GetUserClaims(ctx)
-	fmt.Printf("Request Map: \n %v", cfgs)
+	fmt.Printf("Request Map: \n %v", RedactVAConfigs(cfgs))
+
+	// Validate and seal every value before saving any, so a bad value saves nothing.
+	// Secrets can't be saved without SECRETS_KEYS; the error wraps secrets.ErrNoKey, or
+	// secrets.ErrSealedInput for a secret that already looks encrypted.
+	values := make(map[string]string, len(cfgs))
 	for key, value := range cfgs {
 
 		if !IsValidVAConfigKey(key) {
 			return nil, fmt.Errorf("%q is not a valid key", key)
 		}
+		if _, ok := scheduleConfigKeys[key]; ok && value != "" && value != ScheduleOff {
+			if _, err := ParseCronSchedule(value); err != nil {
+				return nil, err
+			}
+		}
+
+		if IsSecretVAConfigKey(key) {
+			// Clients send plain secrets; a value that looks sealed would never open
+			if _, err := secrets.RequirePlain(value); err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			sealed, err := s.keyring.Seal(value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encrypt %s: %w", key, err)
+			}
+			value = sealed
+		}
+		values[key] = value
+	}
+
+	for key, value := range values {
 
 		va_id := claims.ServerID()
 
@@ -150,12 +233,13 @@ func (s *VAConfigService) GetAllConfigValues(
 	}
 
 	// Handle both map[string]string (from loader) and map[string]any (from JSON unmarshal)
+	var cfgs map[string]string
 	switch v := val.(type) {
 	case map[string]string:
-		return v, nil
+		cfgs = v
 	case map[string]any:
 		// Convert map[string]any to map[string]string
-		cfgs := make(map[string]string, len(v))
+		cfgs = make(map[string]string, len(v))
 		for key, value := range v {
 			if strVal, ok := value.(string); ok {
 				cfgs[key] = strVal
@@ -163,10 +247,30 @@ func (s *VAConfigService) GetAllConfigValues(
 				return nil, fmt.Errorf("value for key %q is not a string", key)
 			}
 		}
-		return cfgs, nil
 	default:
 		return nil, fmt.Errorf("cache type assertion failed: expected map[string]string or map[string]any, got %T", val)
 	}
+
+	return s.openSecrets(vaID, cfgs), nil
+}
+
+// openSecrets returns a copy of cached config values with the credentials decrypted. A
+// credential that can't be decrypted (no or rotated-out key) is left out, so that it only
+// breaks the features using it and not the VA's other settings.
+func (s *VAConfigService) openSecrets(vaID string, cfgs map[string]string) map[string]string {
+	opened := make(map[string]string, len(cfgs))
+	for key, value := range cfgs {
+		if IsSecretVAConfigKey(key) {
+			plain, err := s.keyring.Open(value)
+			if err != nil {
+				log.Printf("[VAConfigService] Failed to decrypt %s of VA %s, leaving it out: %v", key, vaID, err)
+				continue
+			}
+			value = plain
+		}
+		opened[key] = value
+	}
+	return opened
 }
 
 // ---------------------------------------------------------------------------
@@ -189,7 +293,7 @@ func (s *VAConfigService) GetConfigVal(
 
 		return "", false
 	}
-	log.Printf("\nConfigs: %v", cfgs)
+	log.Printf("\nConfigs: %v", RedactVAConfigs(cfgs))
 	return cfgs[key], true
 }
 
@@ -0,0 +1,31 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"infinite-experiment/politburo/internal/secrets"
+)
+
+func TestVAConfigService_SetVaConfig_RejectsSealedSecrets(t *testing.T) {
+	key, err := secrets.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	keyring, err := secrets.ParseKeyring("k1:" + key)
+	if err != nil {
+		t.Fatalf("ParseKeyring: %v", err)
+	}
+	cache := NewCacheService(60, 60)
+	t.Cleanup(func() { cache.Close() })
+
+	// Nothing is saved, so the service needs no repository
+	service := NewVAConfigService(nil, cache, keyring)
+	_, err = service.SetVaConfig(context.Background(), map[string]string{
+		ConfigKeyAirtableAPIKey: "enc:v1:k1:bm90:YSBrZXk",
+	})
+	if !errors.Is(err, secrets.ErrSealedInput) {
+		t.Errorf("SetVaConfig with a sealed-looking secret = %v, want ErrSealedInput", err)
+	}
+}
@@ -0,0 +1,30 @@
+package constants
+
+// APIKeyScope limits what an API key may do. Scopes are ordered: each includes the ones before it.
+type APIKeyScope string
+
+const (
+	APIKeyScopeReadOnly APIKeyScope = "read_only" // GET requests on behalf of the Discord user in the request headers
+	APIKeyScopeBot      APIKeyScope = "bot"       // Any request on behalf of the Discord user (the Discord bot)
+	APIKeyScopeAdmin    APIKeyScope = "admin"     // Also system administration, such as managing API keys
+)
+
+// apiKeyScopeLevels orders the scopes for Includes
+var apiKeyScopeLevels = map[APIKeyScope]int{
+	APIKeyScopeReadOnly: 1,
+	APIKeyScopeBot:      2,
+	APIKeyScopeAdmin:    3,
+}
+
+func (s APIKeyScope) String() string { return string(s) }
+
+// IsValid reports whether s is a known scope
+func (s APIKeyScope) IsValid() bool {
+	_, ok := apiKeyScopeLevels[s]
+	return ok
+}
+
+// Includes reports whether a key with scope s may do what scope other allows
+func (s APIKeyScope) Includes(other APIKeyScope) bool {
+	return s.IsValid() && apiKeyScopeLevels[s] >= apiKeyScopeLevels[other]
+}
@@ -0,0 +1,21 @@
+package constants
+
+// Pilot fields pushed back to the data provider when changed in the dashboard,
+// named by their pilot schema internal name
+const (
+	PilotSyncFieldCallsign = "callsign"
+	PilotSyncFieldRole     = "role"
+	PilotSyncFieldActive   = "active"
+)
+
+// PilotSyncFields lists the pilot fields that sync in both directions
+var PilotSyncFields = []string{PilotSyncFieldCallsign, PilotSyncFieldRole, PilotSyncFieldActive}
+
+// Pilot change states (pilot_sync_changes.status)
+const (
+	PilotChangeStatusPending  = "pending"  // waiting to be pushed to the provider
+	PilotChangeStatusPushed   = "pushed"   // written to the provider
+	PilotChangeStatusConflict = "conflict" // both sides changed the field since the last sync
+	PilotChangeStatusResolved = "resolved" // conflict settled in favour of the provider's value
+	PilotChangeStatusFailed   = "failed"   // could not be pushed; see last_error
+)
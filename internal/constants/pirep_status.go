@@ -0,0 +1,79 @@
+package constants
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"slices"
+)
+
+// PirepStatus mirrors the Postgres ENUM 'pirep_status'
+type PirepStatus string
+
+const (
+	PirepStatusPending  PirepStatus = "pending"
+	PirepStatusApproved PirepStatus = "approved"
+	PirepStatusRejected PirepStatus = "rejected"
+
+	PirepStatusChangesRequested PirepStatus = "changes_requested"
+)
+
+// Stringer ­– convenient for fmt / logs
+func (s PirepStatus) String() string { return string(s) }
+
+// IsValid reports whether the status is one of the known lifecycle states
+func (s PirepStatus) IsValid() bool {
+	switch s {
+	case PirepStatusPending, PirepStatusApproved, PirepStatusRejected, PirepStatusChangesRequested:
+		return true
+	}
+	return false
+}
+
+// ReviewablePirepStatuses are the states staff can still act on
+var ReviewablePirepStatuses = []PirepStatus{PirepStatusPending, PirepStatusChangesRequested}
+
+// IsReviewable reports whether staff can still act on a PIREP in this state
+func (s PirepStatus) IsReviewable() bool {
+	return slices.Contains(ReviewablePirepStatuses, s)
+}
+
+// Scan implements the sql.Scanner interface
+func (s *PirepStatus) Scan(src interface{}) error {
+	if src == nil {
+		*s = ""
+		return nil
+	}
+	switch v := src.(type) {
+	case string:
+		*s = PirepStatus(v)
+	case []byte:
+		*s = PirepStatus(v)
+	default:
+		return fmt.Errorf("PirepStatus: cannot scan type %T", src)
+	}
+	return nil
+}
+
+// Value implements the driver.Valuer interface
+func (s PirepStatus) Value() (driver.Value, error) { return string(s), nil }
+
+// PIREP outbox entry states (pirep_outbox.status)
+const (
+	OutboxStatusPending   = "pending"
+	OutboxStatusDelivered = "delivered"
+	OutboxStatusDead      = "dead"
+)
+
+// Per-VA policy for re-validating the pilot's live flight at PIREP submit time
+const (
+	PirepValidationPolicyStrict = "strict" // reject PIREPs that fail validation
+	PirepValidationPolicyWarn   = "warn"   // accept but flag PIREPs that fail validation
+	PirepValidationPolicyOff    = "off"    // skip validation entirely
+)
+
+// Outcome of submit-time flight validation (pireps.validation_status)
+const (
+	PirepValidationPassed  = "passed"
+	PirepValidationFailed  = "failed"
+	PirepValidationSkipped = "skipped"
+)
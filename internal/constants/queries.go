@@ -22,6 +22,12 @@ const (
 	FROM va_configs
 	WHERE va_id = $1	`
 
+	GetVAConfigsByKey = `
+	SELECT id, va_id, config_key, config_value, created_at, updated_at
+	FROM va_configs
+	WHERE config_key = $1
+	ORDER BY va_id`
+
 	UpsertVAConfig = `
 	INSERT INTO va_configs (va_id, config_key, config_value)
 	VALUES ($1, $2, $3)
@@ -31,8 +37,44 @@ const (
 		updated_at = NOW();
 	`
 
-	GetStatusByApiKey = `
-	SELECT id, status from api_keys where id = $1
+	apiKeyColumns = `id, name, prefix, key_hash, scope, va_id, discord_server_id, expires_at,
+	last_used_at, revoked_at, replaced_by, created_by, created_at`
+
+	GetApiKeyByHash = `
+	SELECT ` + apiKeyColumns + ` FROM api_keys WHERE key_hash = $1
+	`
+
+	GetApiKeyByID = `
+	SELECT ` + apiKeyColumns + ` FROM api_keys WHERE id = $1
+	`
+
+	ListApiKeys = `
+	SELECT ` + apiKeyColumns + ` FROM api_keys
+	WHERE $1 OR revoked_at IS NULL
+	ORDER BY created_at DESC
+	`
+
+	InsertApiKey = `
+	INSERT INTO api_keys (name, prefix, key_hash, scope, va_id, discord_server_id, expires_at, created_by, created_at)
+	VALUES (:name, :prefix, :key_hash, :scope, :va_id, :discord_server_id, :expires_at, :created_by, :created_at)
+	RETURNING id, created_at
+	`
+
+	RevokeApiKey = `
+	UPDATE api_keys SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL
+	`
+
+	// Ends a rotated key's grace period, never extending an earlier expiry
+	ReplaceApiKey = `
+	UPDATE api_keys
+	SET replaced_by = $2, expires_at = LEAST(COALESCE(expires_at, $3), $3)
+	WHERE id = $1 AND revoked_at IS NULL AND replaced_by IS NULL
+	`
+
+	// Written at most once a minute per key
+	TouchApiKey = `
+	UPDATE api_keys SET last_used_at = NOW()
+	WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')
 	`
 
 	InsertUser = `
@@ -0,0 +1,7 @@
+package constants
+
+// Route sources recorded on route_at_synced.source
+const (
+	RouteSourceAirtable = "airtable" // Pulled from the VA's Airtable base by RouteSyncJob
+	RouteSourceNative   = "native"   // Managed through the route catalogue API/dashboard
+)
@@ -6,3 +6,23 @@ const (
 	SyncEventRoutesAT  = "ROUTES_AT_SYNC"
 	SyncEventPirepsAT  = "PIREPS_AT_SYNC"
 )
+
+// SyncEventForEntity returns the sync history event for an entity type synced from a data provider.
+// The built-in entity types keep their original event names so existing history still applies.
+func SyncEventForEntity(entityType string) string {
+	switch entityType {
+	case "pilot":
+		return SyncEventPilotsAT
+	case "route":
+		return SyncEventRoutesAT
+	case "pirep":
+		return SyncEventPirepsAT
+	default:
+		return "ENTITY_SYNC:" + entityType
+	}
+}
+
+// ReconcileEventForEntity returns the sync history event for a full reconciliation of an entity type
+func ReconcileEventForEntity(entityType string) string {
+	return SyncEventForEntity(entityType) + "_RECONCILE"
+}
@@ -0,0 +1,376 @@
+// Package datasync syncs entities from each VA's active data provider into the local database.
+//
+// The Engine is provider-agnostic: it resolves the VA's provider through the provider
+// registry, pages through the records of an entity schema (incrementally when the schema
+// has a last-modified field) and hands each page to the RecordHandler registered for the
+// entity type. Entity types without a dedicated handler are stored as raw records.
+//
+// Incremental syncs never see records deleted in the provider, so ReconcileEntity
+// periodically fetches the complete record set and lets handlers that implement
+// Reconciler soft-delete whatever has vanished.
+package datasync
+
+import (
+	"context"
+	"fmt"
+	"infinite-experiment/politburo/internal/common"
+	"infinite-experiment/politburo/internal/constants"
+	"infinite-experiment/politburo/internal/db/repositories"
+	"infinite-experiment/politburo/internal/models/dtos"
+	"infinite-experiment/politburo/internal/providers"
+	"log"
+	"time"
+)
+
+// defaultPageSize is the number of records fetched per provider request
+const defaultPageSize = 100
+
+// RecordHandler stores the records of one entity type fetched from a data provider
+type RecordHandler interface {
+	// HandleRecords stores one page of records, returning how many were stored and how many failed
+	HandleRecords(ctx context.Context, vaID string, schema *dtos.EntitySchema, records []providers.RecordWithID) (stored int, failed int)
+}
+
+// DeferredHandler is implemented by handlers that hand records to a queue rather than storing
+// them. While Deferred reports true the sync is not recorded in history, so the next run
+// fetches the same window again instead of skipping records that were never stored.
+type DeferredHandler interface {
+	Deferred() bool
+}
+
+// Reconciler is implemented by handlers whose stored records can be checked against the
+// provider's complete record set
+type Reconciler interface {
+	// Reconcile soft-deletes the VA's stored records whose ID is not in remoteIDs,
+	// returning how many were deleted
+	Reconcile(ctx context.Context, vaID string, remoteIDs []string) (deleted int, err error)
+}
+
+// ExistingCounter is implemented by handlers that can tell how many of a page's records are
+// already stored, so that a run can report inserts and updates separately
+type ExistingCounter interface {
+	CountExisting(ctx context.Context, vaID string, schema *dtos.EntitySchema, recordIDs []string) (int, error)
+}
+
+// Result summarises one entity sync of one VA
+type Result struct {
+	VAID         string
+	EntityType   string
+	ProviderType string
+	Incremental  bool
+	Pages        int
+	Fetched      int
+	Stored       int
+	Inserted     int // Stored records that were new (only counted by an ExistingCounter handler)
+	Updated      int // Stored records that already existed
+	Failed       int
+	Deleted      int    // Records soft-deleted by a reconciliation
+	Skipped      string // Why nothing was synced, empty when the sync ran
+	Duration     time.Duration
+
+	// Collected through common.SyncRunStats
+	RateLimitWaits  int
+	RateLimitWaited time.Duration
+	ErrorCount      int
+	Errors          []string // The first common.MaxSyncRunErrors error messages
+}
+
+// Engine runs entity syncs for VAs
+type Engine struct {
+	configRepo      *repositories.DataProviderConfigRepo
+	syncHistoryRepo *repositories.VASyncHistoryRepo
+	registry        *providers.Registry
+	handlers        map[string]RecordHandler
+	fallback        RecordHandler
+}
+
+// NewEngine creates a sync engine. The fallback handler stores entity types without a
+// registered handler; pass nil to skip them.
+func NewEngine(
+	configRepo *repositories.DataProviderConfigRepo,
+	syncHistoryRepo *repositories.VASyncHistoryRepo,
+	registry *providers.Registry,
+	fallback RecordHandler,
+) *Engine {
+	return &Engine{
+		configRepo:      configRepo,
+		syncHistoryRepo: syncHistoryRepo,
+		registry:        registry,
+		handlers:        make(map[string]RecordHandler),
+		fallback:        fallback,
+	}
+}
+
+// Register sets the handler for an entity type
+func (e *Engine) Register(entityType string, handler RecordHandler) {
+	e.handlers[entityType] = handler
+}
+
+// HasHandler reports whether an entity type has a dedicated handler
+func (e *Engine) HasHandler(entityType string) bool {
+	_, ok := e.handlers[entityType]
+	return ok
+}
+
+// ActiveVAIDs returns the VAs that have an active data provider config
+func (e *Engine) ActiveVAIDs(ctx context.Context) ([]string, error) {
+	return e.configRepo.GetActiveVAIDs(ctx)
+}
+
+// SyncEntity syncs one entity type of a VA from its active data provider.
+// A VA without an active config, schema or handler is not an error; the result says why it was skipped.
+func (e *Engine) SyncEntity(ctx context.Context, vaID, entityType string) (*Result, error) {
+	result, _, err := e.sync(ctx, vaID, entityType, false)
+	return result, err
+}
+
+// ReconcileEntity fetches every record of an entity type regardless of the last sync time,
+// stores them (repairing any drift) and soft-deletes stored records the provider no longer has.
+// The counts are recorded in sync history under the entity's reconcile event.
+// If deleting fails the result of the fetch is returned along with the error.
+func (e *Engine) ReconcileEntity(ctx context.Context, vaID, entityType string) (*Result, error) {
+	reconciler, ok := e.handlers[entityType].(Reconciler)
+	if !ok {
+		return &Result{VAID: vaID, EntityType: entityType, Skipped: fmt.Sprintf("%s records cannot be reconciled", entityType)}, nil
+	}
+
+	result, remoteIDs, err := e.sync(ctx, vaID, entityType, true)
+	if err != nil || result.Skipped != "" {
+		return result, err
+	}
+
+	// An empty record set is far more likely a misconfigured table or filter than a VA
+	// that deleted everything, so never treat it as a reason to delete
+	if len(remoteIDs) == 0 {
+		log.Printf("[SyncEngine] VA %s: %s reconciliation fetched no records, not deleting anything", vaID, entityType)
+	} else {
+		deleted, err := reconciler.Reconcile(ctx, vaID, remoteIDs)
+		if err != nil {
+			return result, fmt.Errorf("failed to reconcile %s records: %w", entityType, err)
+		}
+		result.Deleted = deleted
+	}
+
+	log.Printf("[SyncEngine] VA %s: %s reconciliation completed. Fetched: %d, Stored: %d, Failed: %d, Deleted: %d",
+		vaID, entityType, result.Fetched, result.Stored, result.Failed, result.Deleted)
+
+	counts := repositories.SyncCounts{
+		Fetched: result.Fetched,
+		Stored:  result.Stored,
+		Failed:  result.Failed,
+		Deleted: result.Deleted,
+	}
+	if err := e.syncHistoryRepo.RecordReconciliation(ctx, vaID, constants.ReconcileEventForEntity(entityType), counts); err != nil {
+		log.Printf("[SyncEngine] VA %s: Warning - failed to record %s reconciliation history: %v", vaID, entityType, err)
+	}
+
+	return result, nil
+}
+
+// sync fetches an entity type's records and hands them to its handler. A full sync ignores
+// the last sync time and also returns the IDs of every record fetched.
+// When fetching fails part way the partial result is returned along with the error.
+func (e *Engine) sync(ctx context.Context, vaID, entityType string, full bool) (*Result, []string, error) {
+	start := time.Now()
+	result := &Result{VAID: vaID, EntityType: entityType}
+
+	stats := &common.SyncRunStats{}
+	ctx = common.WithSyncRunStats(ctx, stats)
+	defer func() {
+		result.Duration = time.Since(start)
+		result.RateLimitWaits, result.RateLimitWaited = stats.RateLimitWaits()
+		result.ErrorCount, result.Errors = stats.Errors()
+	}()
+
+	config, err := e.configRepo.GetActiveConfigForVA(ctx, vaID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get active config: %w", err)
+	}
+	if config == nil {
+		result.Skipped = "no active data provider config"
+		return result, nil, nil
+	}
+	result.ProviderType = config.ProviderType
+
+	configData, err := repositories.ParseConfigData(config.ConfigData)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse config data: %w", err)
+	}
+
+	schema := configData.GetSchemaByType(entityType)
+	if schema == nil {
+		result.Skipped = fmt.Sprintf("no %s schema configured", entityType)
+		return result, nil, nil
+	}
+	if !schema.Enabled {
+		result.Skipped = fmt.Sprintf("%s schema is disabled", entityType)
+		return result, nil, nil
+	}
+
+	handler, ok := e.handlers[entityType]
+	if !ok {
+		handler = e.fallback
+	}
+	if handler == nil {
+		result.Skipped = fmt.Sprintf("no handler for entity type %s", entityType)
+		return result, nil, nil
+	}
+
+	client, err := e.registry.Client(config.ProviderType, configData)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	event := constants.SyncEventForEntity(entityType)
+	var modifiedSince *string
+	if !full {
+		modifiedSince = e.modifiedSince(ctx, vaID, event, schema)
+	}
+	result.Incremental = modifiedSince != nil
+
+	ctx = withProviderType(ctx, config.ProviderType)
+
+	filters := &providers.SyncFilters{
+		Limit:         defaultPageSize,
+		ModifiedSince: modifiedSince,
+	}
+
+	// A page in progress when ctx is cancelled is still fetched and stored; the sync then
+	// stops without recording history, so the next run picks up from the same point
+	pageCtx := context.WithoutCancel(ctx)
+
+	var remoteIDs []string
+	for {
+		if err := ctx.Err(); err != nil {
+			return result, nil, fmt.Errorf("%s sync stopped after %d pages: %w", entityType, result.Pages, err)
+		}
+
+		result.Pages++
+		recordSet, err := client.FetchRecords(pageCtx, schema, filters)
+		if err != nil {
+			err = fmt.Errorf("failed to fetch %s records (page %d): %w", entityType, result.Pages, err)
+			stats.AddError(err)
+			return result, nil, err
+		}
+
+		result.Fetched += len(recordSet.Records)
+		if full {
+			for _, record := range recordSet.Records {
+				remoteIDs = append(remoteIDs, record.ID)
+			}
+		}
+		if len(recordSet.Records) > 0 {
+			existing := countExisting(pageCtx, handler, vaID, schema, recordSet.Records)
+			stored, failed := handler.HandleRecords(pageCtx, vaID, schema, recordSet.Records)
+			result.Stored += stored
+			result.Failed += failed
+			if existing >= 0 {
+				updated := min(existing, stored)
+				result.Updated += updated
+				result.Inserted += stored - updated
+			}
+		}
+
+		if !recordSet.HasMore {
+			break
+		}
+		filters.Offset = recordSet.Offset
+	}
+
+	log.Printf("[SyncEngine] VA %s: %s sync from %s completed in %s (incremental: %t). Pages: %d, Fetched: %d, Stored: %d, Failed: %d",
+		vaID, entityType, config.ProviderType, time.Since(start).Truncate(time.Millisecond), result.Incremental,
+		result.Pages, result.Fetched, result.Stored, result.Failed)
+
+	if deferred, ok := handler.(DeferredHandler); ok && deferred.Deferred() {
+		return result, remoteIDs, nil
+	}
+
+	// Record successful sync in sync history
+	if err := e.syncHistoryRepo.RecordSync(ctx, vaID, event); err != nil {
+		log.Printf("[SyncEngine] VA %s: Warning - failed to record %s sync history: %v", vaID, entityType, err)
+		// Don't fail the sync operation if history recording fails
+	}
+
+	return result, remoteIDs, nil
+}
+
+// CustomEntityTypes returns the enabled entity types of a VA's active config that have no
+// dedicated handler (career mode and custom entity types)
+func (e *Engine) CustomEntityTypes(ctx context.Context, vaID string) ([]string, error) {
+	config, err := e.configRepo.GetActiveConfigForVA(ctx, vaID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active config: %w", err)
+	}
+	if config == nil {
+		return nil, nil
+	}
+
+	configData, err := repositories.ParseConfigData(config.ConfigData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config data: %w", err)
+	}
+
+	var entityTypes []string
+	for _, schema := range configData.Schemas {
+		if !schema.Enabled || e.HasHandler(schema.EntityType) {
+			continue
+		}
+		entityTypes = append(entityTypes, schema.EntityType)
+	}
+
+	return entityTypes, nil
+}
+
+// modifiedSince returns the VA's last sync time for the event as an ISO 8601 cutoff,
+// or nil for a full sync
+func (e *Engine) modifiedSince(ctx context.Context, vaID, event string, schema *dtos.EntitySchema) *string {
+	if schema.LastModifiedField == "" {
+		return nil
+	}
+
+	lastSyncTime, err := e.syncHistoryRepo.GetLastSyncTime(ctx, vaID, event)
+	if err != nil {
+		log.Printf("[SyncEngine] VA %s: Error getting last sync timestamp: %v. Doing full sync.", vaID, err)
+		return nil
+	}
+	if lastSyncTime == nil {
+		return nil
+	}
+
+	timestamp := lastSyncTime.Format(time.RFC3339)
+	return &timestamp
+}
+
+// providerTypeKey carries the provider type of the sync run to its record handlers
+type providerTypeKey struct{}
+
+func withProviderType(ctx context.Context, providerType string) context.Context {
+	return context.WithValue(ctx, providerTypeKey{}, providerType)
+}
+
+// providerTypeFrom returns the provider type the records being handled came from
+func providerTypeFrom(ctx context.Context) string {
+	providerType, _ := ctx.Value(providerTypeKey{}).(string)
+	return providerType
+}
+
+// countExisting returns how many of a page's records the handler already stores,
+// or -1 when the handler cannot tell
+func countExisting(ctx context.Context, handler RecordHandler, vaID string, schema *dtos.EntitySchema, records []providers.RecordWithID) int {
+	counter, ok := handler.(ExistingCounter)
+	if !ok {
+		return -1
+	}
+
+	ids := make([]string, len(records))
+	for i, record := range records {
+		ids[i] = record.ID
+	}
+
+	existing, err := counter.CountExisting(ctx, vaID, schema, ids)
+	if err != nil {
+		log.Printf("[SyncEngine] VA %s: Error counting existing %s records: %v", vaID, schema.EntityType, err)
+		return -1
+	}
+	return existing
+}
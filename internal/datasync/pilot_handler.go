@@ -0,0 +1,235 @@
+package datasync
+
+import (
+	"context"
+	"fmt"
+	"infinite-experiment/politburo/internal/common"
+	"infinite-experiment/politburo/internal/constants"
+	"infinite-experiment/politburo/internal/db/repositories"
+	"infinite-experiment/politburo/internal/models/dtos"
+	gormModels "infinite-experiment/politburo/internal/models/gorm"
+	"infinite-experiment/politburo/internal/providers"
+	"log"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PilotHandler stores pilot records in pilot_at_synced and links them to registered members
+type PilotHandler struct {
+	db                *gorm.DB
+	cache             common.CacheInterface
+	pilotATSyncedRepo *repositories.PilotATSyncedRepo
+	changeRepo        *repositories.PilotSyncChangeRepo
+}
+
+// NewPilotHandler creates a new pilot record handler
+func NewPilotHandler(db *gorm.DB, cache common.CacheInterface, pilotATSyncedRepo *repositories.PilotATSyncedRepo, changeRepo *repositories.PilotSyncChangeRepo) *PilotHandler {
+	return &PilotHandler{
+		db:                db,
+		cache:             cache,
+		pilotATSyncedRepo: pilotATSyncedRepo,
+		changeRepo:        changeRepo,
+	}
+}
+
+// HandleRecords upserts a page of pilot records
+func (h *PilotHandler) HandleRecords(ctx context.Context, vaID string, schema *dtos.EntitySchema, records []providers.RecordWithID) (int, int) {
+	stored, failed := 0, 0
+	for _, record := range records {
+		if err := h.upsertPilot(ctx, vaID, record.ID, record.Fields, schema); err != nil {
+			log.Printf("[PilotHandler] VA %s: Error upserting record %s: %v", vaID, record.ID, err)
+			common.SyncRunStatsFrom(ctx).AddError(fmt.Errorf("pilot record %s: %w", record.ID, err))
+			failed++
+			continue
+		}
+		stored++
+	}
+	return stored, failed
+}
+
+// CountExisting counts the records already stored in pilot_at_synced
+func (h *PilotHandler) CountExisting(ctx context.Context, vaID string, schema *dtos.EntitySchema, recordIDs []string) (int, error) {
+	count, err := h.pilotATSyncedRepo.CountByATIDs(ctx, vaID, recordIDs)
+	return int(count), err
+}
+
+// Reconcile soft-deletes pilot records that no longer exist in the provider and unlinks
+// members from them
+func (h *PilotHandler) Reconcile(ctx context.Context, vaID string, remoteIDs []string) (int, error) {
+	deleted, err := h.pilotATSyncedRepo.SoftDeleteMissing(ctx, vaID, remoteIDs)
+	return int(deleted), err
+}
+
+// upsertPilot updates or creates a pilot record in va_user_roles and pilot_at_synced
+func (h *PilotHandler) upsertPilot(ctx context.Context, vaID string, recordID string, record map[string]interface{}, schema *dtos.EntitySchema) error {
+	// Extract callsign from record using field mapping
+	callsignField := schema.GetFieldMapping("callsign")
+	if callsignField == nil {
+		return fmt.Errorf("callsign field not configured in schema")
+	}
+
+	rawCallsign, ok := record[callsignField.AirtableName]
+	if !ok {
+		return fmt.Errorf("callsign field '%s' not found in record", callsignField.AirtableName)
+	}
+
+	callsign, ok := rawCallsign.(string)
+	if !ok {
+		return fmt.Errorf("callsign is not a string: %v", rawCallsign)
+	}
+
+	// Clean and validate callsign
+	callsign = strings.TrimSpace(callsign)
+	if callsign == "" {
+		return fmt.Errorf("callsign is empty")
+	}
+
+	// Upsert into pilot_at_synced table first (keeps our database in sync with the provider)
+	pilotATSynced := &gormModels.PilotATSynced{
+		ATID:       recordID,
+		Callsign:   callsign,
+		Registered: false, // Will be updated to true if found in va_user_roles
+		ServerID:   vaID,
+	}
+
+	// Find user by callsign in va_user_roles for this VA
+	// If found, update airtable_pilot_id and mark as registered
+
+	var existingRole struct {
+		ID       string  `gorm:"column:id"`
+		UserID   string  `gorm:"column:user_id"`
+		VAID     string  `gorm:"column:va_id"`
+		Callsign *string `gorm:"column:callsign"`
+	}
+
+	err := h.db.WithContext(ctx).
+		Table("va_user_roles").
+		Where("va_id = ? AND LOWER(callsign) = LOWER(?)", vaID, callsign).
+		Select("id, user_id, va_id, callsign").
+		First(&existingRole).Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			// Pilot not found in database - this is expected for pilots who haven't registered yet
+			log.Printf("[PilotHandler] Callsign %s not found in VA %s - pilot may not be registered yet", callsign, vaID)
+			// Still upsert into pilot_at_synced with registered=false
+			if err := h.pilotATSyncedRepo.Upsert(ctx, pilotATSynced); err != nil {
+				log.Printf("[PilotHandler] Warning: failed to upsert into pilot_at_synced: %v", err)
+			}
+			h.recordSyncState(ctx, vaID, recordID, record, schema)
+			return nil
+		}
+		return fmt.Errorf("failed to query existing role: %w", err)
+	}
+
+	// User found - mark as registered
+	pilotATSynced.Registered = true
+
+	// Upsert into pilot_at_synced
+	if err := h.pilotATSyncedRepo.Upsert(ctx, pilotATSynced); err != nil {
+		log.Printf("[PilotHandler] Warning: failed to upsert into pilot_at_synced: %v", err)
+	}
+	h.recordSyncState(ctx, vaID, recordID, record, schema)
+
+	// Update the airtable_pilot_id and updated_at timestamp in va_user_roles
+	err = h.db.WithContext(ctx).
+		Table("va_user_roles").
+		Where("id = ?", existingRole.ID).
+		Updates(map[string]interface{}{
+			"airtable_pilot_id": recordID,
+			"updated_at":        time.Now(),
+		}).Error
+
+	if err != nil {
+		return fmt.Errorf("failed to update airtable_pilot_id for callsign %s: %w", callsign, err)
+	}
+
+	log.Printf("[PilotHandler] Updated airtable_pilot_id for callsign %s (record: %s)", callsign, recordID)
+
+	// Invalidate cache for this pilot's stats
+	cacheKey := fmt.Sprintf("pilot_stats:%s:%s", vaID, recordID)
+	h.cache.Delete(cacheKey)
+
+	return nil
+}
+
+// recordSyncState flags dashboard changes of this record that the provider has also changed
+// since they were made, then stores the provider's values as the base for the next comparison
+func (h *PilotHandler) recordSyncState(ctx context.Context, vaID string, recordID string, record map[string]interface{}, schema *dtos.EntitySchema) {
+	remote := PilotSyncValues(schema, record)
+
+	changes, err := h.changeRepo.ListOpenByRecord(ctx, vaID, recordID)
+	if err != nil {
+		log.Printf("[PilotHandler] Warning: failed to fetch open changes for record %s: %v", recordID, err)
+	}
+	for _, change := range changes {
+		remoteValue, ok := remote[change.Field]
+		if !ok || !PilotChangeConflicts(&change, remoteValue) {
+			continue
+		}
+		if err := h.changeRepo.MarkConflict(ctx, change.ID, remoteValue); err != nil {
+			log.Printf("[PilotHandler] Warning: failed to mark change %s as conflicting: %v", change.ID, err)
+			continue
+		}
+		log.Printf("[PilotHandler] VA %s: %s of record %s changed on both sides (change %s)", vaID, change.Field, recordID, change.ID)
+	}
+
+	var remoteModifiedAt *time.Time
+	if schema.LastModifiedField != "" {
+		if modified, err := time.Parse(time.RFC3339, providers.FieldString(record[schema.LastModifiedField])); err == nil {
+			remoteModifiedAt = &modified
+		}
+	}
+
+	if err := h.pilotATSyncedRepo.UpdateSyncState(ctx, vaID, recordID, remote, remoteModifiedAt); err != nil {
+		log.Printf("[PilotHandler] Warning: failed to update sync state for record %s: %v", recordID, err)
+	}
+}
+
+// PilotSyncValues returns a provider record's values of the two-way pilot fields mapped in
+// the schema, normalised for comparison with local values
+func PilotSyncValues(schema *dtos.EntitySchema, record map[string]interface{}) map[string]string {
+	values := make(map[string]string)
+	for _, field := range constants.PilotSyncFields {
+		mapping := schema.GetFieldMapping(field)
+		if mapping == nil {
+			continue
+		}
+		values[field] = NormalizePilotSyncValue(field, record[mapping.AirtableName])
+	}
+	return values
+}
+
+// NormalizePilotSyncValue renders a two-way pilot field value as comparable text.
+// Roles compare case-insensitively and the active flag accepts checkbox and text forms.
+func NormalizePilotSyncValue(field string, value interface{}) string {
+	text := strings.TrimSpace(providers.FieldString(value))
+	switch field {
+	case constants.PilotSyncFieldRole:
+		return strings.ToLower(text)
+	case constants.PilotSyncFieldActive:
+		switch strings.ToLower(text) {
+		case "true", "1", "yes", "y", "checked", "active":
+			return "true"
+		default:
+			return "false"
+		}
+	default:
+		return text
+	}
+}
+
+// PilotChangeConflicts reports whether the provider's current value of a changed field has
+// moved away from the value the change was made against, to something other than the change itself
+func PilotChangeConflicts(change *gormModels.PilotSyncChange, remoteValue string) bool {
+	var base, local string
+	if change.BaseValue != nil {
+		base = *change.BaseValue
+	}
+	if change.LocalValue != nil {
+		local = *change.LocalValue
+	}
+	return remoteValue != base && remoteValue != local
+}
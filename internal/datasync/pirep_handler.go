@@ -0,0 +1,243 @@
+package datasync
+
+import (
+	"context"
+	"fmt"
+	"infinite-experiment/politburo/internal/common"
+	"infinite-experiment/politburo/internal/db/repositories"
+	"infinite-experiment/politburo/internal/models/dtos"
+	gormModels "infinite-experiment/politburo/internal/models/gorm"
+	"infinite-experiment/politburo/internal/providers"
+	"log"
+	"strings"
+	"time"
+)
+
+// PirepHandler stores PIREP records in pirep_at_synced. When a Redis queue is provided,
+// records are enqueued per VA and stored asynchronously by the PIREP queue workers.
+type PirepHandler struct {
+	pirepATSyncedRepo *repositories.PirepATSyncedRepo
+	redisQueue        *common.RedisQueueService // Redis queue for async processing
+	useQueue          bool                      // Whether to use queue-based processing
+}
+
+// NewPirepHandler creates a new PIREP record handler; pass a nil queue to store records directly
+func NewPirepHandler(pirepATSyncedRepo *repositories.PirepATSyncedRepo, redisQueue *common.RedisQueueService) *PirepHandler {
+	return &PirepHandler{
+		pirepATSyncedRepo: pirepATSyncedRepo,
+		redisQueue:        redisQueue,
+		useQueue:          redisQueue != nil, // Use queue if provided
+	}
+}
+
+// Deferred reports whether records are enqueued rather than stored
+func (h *PirepHandler) Deferred() bool {
+	return h.useQueue
+}
+
+// HandleRecords enqueues or upserts a page of PIREP records. Enqueued records count as stored.
+func (h *PirepHandler) HandleRecords(ctx context.Context, vaID string, schema *dtos.EntitySchema, records []providers.RecordWithID) (int, int) {
+	if h.useQueue {
+		return h.enqueue(ctx, vaID, records)
+	}
+
+	stored, failed := 0, 0
+	for _, record := range records {
+		if err := h.UpsertPirep(ctx, vaID, record.ID, record.Fields, record.CreatedTime, schema); err != nil {
+			log.Printf("[PirepHandler] VA %s: Error upserting record %s: %v", vaID, record.ID, err)
+			common.SyncRunStatsFrom(ctx).AddError(fmt.Errorf("PIREP record %s: %w", record.ID, err))
+			failed++
+			continue
+		}
+		stored++
+	}
+	return stored, failed
+}
+
+// CountExisting counts the records already stored in pirep_at_synced
+func (h *PirepHandler) CountExisting(ctx context.Context, vaID string, schema *dtos.EntitySchema, recordIDs []string) (int, error) {
+	count, err := h.pirepATSyncedRepo.CountByATIDs(ctx, vaID, recordIDs)
+	return int(count), err
+}
+
+// Reconcile soft-deletes PIREP records that no longer exist in the provider
+func (h *PirepHandler) Reconcile(ctx context.Context, vaID string, remoteIDs []string) (int, error) {
+	deleted, err := h.pirepATSyncedRepo.SoftDeleteMissing(ctx, vaID, remoteIDs)
+	return int(deleted), err
+}
+
+// enqueue adds a page of PIREP records to the VA's Redis stream
+func (h *PirepHandler) enqueue(ctx context.Context, vaID string, records []providers.RecordWithID) (int, int) {
+	streamName := fmt.Sprintf("pirep:sync:%s", vaID)
+
+	// Ensure consumer group exists
+	if err := h.redisQueue.CreateConsumerGroup(ctx, streamName, "pirep-workers"); err != nil {
+		log.Printf("[PirepHandler] VA %s: Warning - failed to create consumer group: %v", vaID, err)
+		// Continue anyway - group might already exist
+	}
+
+	var queueItems []*common.PirepQueueItem
+	for _, record := range records {
+		queueItems = append(queueItems, &common.PirepQueueItem{
+			VATID:            vaID,
+			AirtableRecordID: record.ID,
+			Fields:           record.Fields,
+			CreatedTime:      record.CreatedTime,
+		})
+	}
+
+	if err := h.redisQueue.EnqueuePirepBatch(ctx, streamName, queueItems); err != nil {
+		log.Printf("[PirepHandler] VA %s: Error enqueuing batch: %v", vaID, err)
+		common.SyncRunStatsFrom(ctx).AddError(fmt.Errorf("enqueue %d PIREP records: %w", len(queueItems), err))
+		return 0, len(queueItems)
+	}
+
+	log.Printf("[PirepHandler] VA %s: Enqueued %d records to %s", vaID, len(queueItems), streamName)
+	return len(queueItems), 0
+}
+
+// UpsertPirep updates or creates a PIREP record in pirep_at_synced.
+// It is exported for the queue worker that drains PIREPs enqueued by HandleRecords.
+func (h *PirepHandler) UpsertPirep(ctx context.Context, vaID string, recordID string, record map[string]interface{}, createdTime string, schema *dtos.EntitySchema) error {
+	// Extract field mappings
+	routeField := schema.GetFieldMapping("route")
+	flightModeField := schema.GetFieldMapping("flight_mode")
+	flightTimeField := schema.GetFieldMapping("flight_time")
+	pilotCallsignField := schema.GetFieldMapping("pilot_callsign")
+	aircraftField := schema.GetFieldMapping("aircraft")
+	liveryField := schema.GetFieldMapping("livery")
+	routeATIDField := schema.GetFieldMapping("route_at_id")
+	pilotATIDField := schema.GetFieldMapping("pilot_at_id")
+
+	// Extract route (optional but recommended)
+	var route string
+	if routeField != nil {
+		if rawRoute, ok := record[routeField.AirtableName]; ok {
+			if routeStr, ok := rawRoute.(string); ok {
+				route = strings.TrimSpace(routeStr)
+			}
+		}
+	}
+
+	// Extract flight mode (optional)
+	var flightMode string
+	if flightModeField != nil {
+		if rawMode, ok := record[flightModeField.AirtableName]; ok {
+			if modeStr, ok := rawMode.(string); ok {
+				flightMode = strings.TrimSpace(modeStr)
+			}
+		}
+	}
+
+	// Extract flight time (optional)
+	var flightTime *float64
+	if flightTimeField != nil {
+		if rawTime, ok := record[flightTimeField.AirtableName]; ok {
+			switch v := rawTime.(type) {
+			case float64:
+				flightTime = &v
+			case int:
+				ft := float64(v)
+				flightTime = &ft
+			}
+		}
+	}
+
+	// Extract pilot callsign (optional but recommended)
+	var pilotCallsign string
+	if pilotCallsignField != nil {
+		if rawCallsign, ok := record[pilotCallsignField.AirtableName]; ok {
+			if callsignStr, ok := rawCallsign.(string); ok {
+				pilotCallsign = strings.TrimSpace(callsignStr)
+			}
+		}
+	}
+
+	// Extract aircraft (optional - use string as is)
+	var aircraft string
+	if aircraftField != nil {
+		if rawAircraft, ok := record[aircraftField.AirtableName]; ok {
+			if aircraftStr, ok := rawAircraft.(string); ok {
+				aircraft = strings.TrimSpace(aircraftStr)
+			}
+		}
+	}
+
+	// Extract livery (optional - use string as is)
+	var livery string
+	if liveryField != nil {
+		if rawLivery, ok := record[liveryField.AirtableName]; ok {
+			if liveryStr, ok := rawLivery.(string); ok {
+				livery = strings.TrimSpace(liveryStr)
+			}
+		}
+	}
+
+	// Extract route_at_id (optional reference)
+	var routeATID *string
+	if routeATIDField != nil {
+		if rawRouteID, ok := record[routeATIDField.AirtableName]; ok {
+			// Airtable returns array of record IDs for linked records
+			if idArray, ok := rawRouteID.([]interface{}); ok && len(idArray) > 0 {
+				if idStr, ok := idArray[0].(string); ok {
+					routeATID = &idStr
+				}
+			}
+		}
+	}
+
+	// Extract pilot_at_id (optional reference)
+	var pilotATID *string
+	if pilotATIDField != nil {
+		if rawPilotID, ok := record[pilotATIDField.AirtableName]; ok {
+			// Airtable returns array of record IDs for linked records
+			if idArray, ok := rawPilotID.([]interface{}); ok && len(idArray) > 0 {
+				if idStr, ok := idArray[0].(string); ok {
+					pilotATID = &idStr
+				}
+			}
+		}
+	}
+
+	// Parse provider created time
+	var atCreatedTime *time.Time
+	if createdTime != "" {
+		if t, err := time.Parse(time.RFC3339, createdTime); err == nil {
+			atCreatedTime = &t
+		}
+	}
+
+	// Create PIREP entity
+	pirepATSynced := &gormModels.PirepATSynced{
+		ATID:          recordID,
+		ServerID:      vaID,
+		Route:         route,
+		FlightMode:    flightMode,
+		FlightTime:    flightTime,
+		PilotCallsign: pilotCallsign,
+		Aircraft:      aircraft,
+		Livery:        livery,
+		RouteATID:     routeATID,
+		PilotATID:     pilotATID,
+		ATCreatedTime: atCreatedTime,
+	}
+
+	// Upsert into pirep_at_synced table
+	if err := h.pirepATSyncedRepo.Upsert(ctx, pirepATSynced); err != nil {
+		return fmt.Errorf("failed to upsert PIREP: %w", err)
+	}
+
+	// Log with relevant info
+	log.Printf("[PirepHandler] Upserted PIREP: pilot=%s, route=%s, aircraft=%s, livery=%s, mode=%s, time=%.2fh (record: %s)",
+		pilotCallsign, route, aircraft, livery, flightMode, getFlightTimeValue(flightTime), recordID)
+
+	return nil
+}
+
+// Helper to get flight time value safely
+func getFlightTimeValue(ft *float64) float64 {
+	if ft == nil {
+		return 0.0
+	}
+	return *ft
+}
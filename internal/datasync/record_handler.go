@@ -0,0 +1,60 @@
+package datasync
+
+import (
+	"context"
+	"fmt"
+	"infinite-experiment/politburo/internal/common"
+	"infinite-experiment/politburo/internal/db/repositories"
+	"infinite-experiment/politburo/internal/models/dtos"
+	gormModels "infinite-experiment/politburo/internal/models/gorm"
+	"infinite-experiment/politburo/internal/providers"
+	"log"
+	"time"
+)
+
+// RecordStoreHandler stores records of entity types without a dedicated table (career mode and
+// custom types) as raw field maps in va_synced_records
+type RecordStoreHandler struct {
+	syncedRecordRepo *repositories.SyncedRecordRepo
+}
+
+// NewRecordStoreHandler creates a new raw record handler
+func NewRecordStoreHandler(syncedRecordRepo *repositories.SyncedRecordRepo) *RecordStoreHandler {
+	return &RecordStoreHandler{syncedRecordRepo: syncedRecordRepo}
+}
+
+// HandleRecords upserts a page of raw records
+func (h *RecordStoreHandler) HandleRecords(ctx context.Context, vaID string, schema *dtos.EntitySchema, records []providers.RecordWithID) (int, int) {
+	providerType := providerTypeFrom(ctx)
+
+	stored, failed := 0, 0
+	for _, record := range records {
+		synced := &gormModels.SyncedRecord{
+			VAID:         vaID,
+			EntityType:   schema.EntityType,
+			ProviderType: providerType,
+			RecordID:     record.ID,
+			Fields:       gormModels.JSONB(record.Fields),
+		}
+		if record.CreatedTime != "" {
+			if t, err := time.Parse(time.RFC3339, record.CreatedTime); err == nil {
+				synced.ProviderCreatedAt = &t
+			}
+		}
+
+		if err := h.syncedRecordRepo.Upsert(ctx, synced); err != nil {
+			log.Printf("[RecordStoreHandler] VA %s: Error upserting %s record %s: %v", vaID, schema.EntityType, record.ID, err)
+			common.SyncRunStatsFrom(ctx).AddError(fmt.Errorf("%s record %s: %w", schema.EntityType, record.ID, err))
+			failed++
+			continue
+		}
+		stored++
+	}
+	return stored, failed
+}
+
+// CountExisting counts the records already stored in va_synced_records
+func (h *RecordStoreHandler) CountExisting(ctx context.Context, vaID string, schema *dtos.EntitySchema, recordIDs []string) (int, error) {
+	count, err := h.syncedRecordRepo.CountByRecordIDs(ctx, vaID, schema.EntityType, recordIDs)
+	return int(count), err
+}
@@ -0,0 +1,189 @@
+package datasync
+
+import (
+	"context"
+	"fmt"
+	"infinite-experiment/politburo/internal/common"
+	"infinite-experiment/politburo/internal/constants"
+	"infinite-experiment/politburo/internal/db/repositories"
+	"infinite-experiment/politburo/internal/models/dtos"
+	gormModels "infinite-experiment/politburo/internal/models/gorm"
+	"infinite-experiment/politburo/internal/providers"
+	"log"
+	"strings"
+)
+
+// RouteHandler stores route records in route_at_synced, enriched with airport coordinates
+type RouteHandler struct {
+	routeATSyncedRepo *repositories.RouteATSyncedRepo
+	airportRepo       *repositories.AirportRepository
+}
+
+// NewRouteHandler creates a new route record handler
+func NewRouteHandler(routeATSyncedRepo *repositories.RouteATSyncedRepo, airportRepo *repositories.AirportRepository) *RouteHandler {
+	return &RouteHandler{
+		routeATSyncedRepo: routeATSyncedRepo,
+		airportRepo:       airportRepo,
+	}
+}
+
+// HandleRecords upserts a page of route records
+func (h *RouteHandler) HandleRecords(ctx context.Context, vaID string, schema *dtos.EntitySchema, records []providers.RecordWithID) (int, int) {
+	stored, failed := 0, 0
+	for _, record := range records {
+		if err := h.upsertRoute(ctx, vaID, record.ID, record.Fields, schema); err != nil {
+			log.Printf("[RouteHandler] VA %s: Error upserting record %s: %v", vaID, record.ID, err)
+			common.SyncRunStatsFrom(ctx).AddError(fmt.Errorf("route record %s: %w", record.ID, err))
+			failed++
+			continue
+		}
+		stored++
+	}
+	return stored, failed
+}
+
+// CountExisting counts the records already stored in route_at_synced
+func (h *RouteHandler) CountExisting(ctx context.Context, vaID string, schema *dtos.EntitySchema, recordIDs []string) (int, error) {
+	count, err := h.routeATSyncedRepo.CountByATIDs(ctx, vaID, recordIDs)
+	return int(count), err
+}
+
+// Reconcile soft-deletes synced routes that no longer exist in the provider
+func (h *RouteHandler) Reconcile(ctx context.Context, vaID string, remoteIDs []string) (int, error) {
+	deleted, err := h.routeATSyncedRepo.SoftDeleteMissing(ctx, vaID, remoteIDs)
+	return int(deleted), err
+}
+
+// upsertRoute updates or creates a route record in route_at_synced
+func (h *RouteHandler) upsertRoute(ctx context.Context, vaID string, recordID string, record map[string]interface{}, schema *dtos.EntitySchema) error {
+	// Extract field mappings
+	originField := schema.GetFieldMapping("origin")
+	destField := schema.GetFieldMapping("destination")
+	routeField := schema.GetFieldMapping("route")
+
+	// Route is MANDATORY
+	if routeField == nil {
+		return fmt.Errorf("route field not configured in schema")
+	}
+
+	// Extract route (required)
+	rawRoute, ok := record[routeField.AirtableName]
+	if !ok {
+		return fmt.Errorf("route field '%s' not found in record", routeField.AirtableName)
+	}
+	route, ok := rawRoute.(string)
+	if !ok {
+		return fmt.Errorf("route is not a string: %v", rawRoute)
+	}
+	route = strings.TrimSpace(route)
+	if route == "" {
+		return fmt.Errorf("route field is empty")
+	}
+
+	// Extract origin (optional - can be empty for event routes)
+	var origin string
+	if originField != nil {
+		if rawOrigin, ok := record[originField.AirtableName]; ok {
+			if originStr, ok := rawOrigin.(string); ok {
+				origin = strings.TrimSpace(originStr)
+			}
+		}
+	}
+
+	// Extract destination (optional - can be empty for event routes)
+	var destination string
+	if destField != nil {
+		if rawDest, ok := record[destField.AirtableName]; ok {
+			if destStr, ok := rawDest.(string); ok {
+				destination = strings.TrimSpace(destStr)
+			}
+		}
+	}
+
+	// Create route entity
+	routeATSynced := &gormModels.RouteATSynced{
+		ATID:        &recordID,
+		ServerID:    vaID,
+		Source:      constants.RouteSourceAirtable,
+		Origin:      origin,
+		Destination: destination,
+		Route:       route,
+		IsActive:    true,
+	}
+
+	// Parse route field to extract ICAO codes and enrich with airport coordinates
+	h.enrichRouteWithAirportData(ctx, routeATSynced)
+
+	// Derive great-circle distance, haul band and block time estimates from the coordinates
+	common.ApplyRouteEstimates(routeATSynced)
+
+	// Upsert into route_at_synced table
+	if err := h.routeATSyncedRepo.Upsert(ctx, routeATSynced); err != nil {
+		return fmt.Errorf("failed to upsert route: %w", err)
+	}
+
+	// Log with route as primary identifier and coordinates if available
+	var logMsg string
+	if routeATSynced.OriginLat.Valid && routeATSynced.DestinationLat.Valid {
+		logMsg = fmt.Sprintf("[RouteHandler] Upserted route '%s' with coordinates: origin (%.4f, %.4f) dest (%.4f, %.4f), %.1f nm %s (record: %s)",
+			route, routeATSynced.OriginLat.Float64, routeATSynced.OriginLon.Float64,
+			routeATSynced.DestinationLat.Float64, routeATSynced.DestinationLon.Float64,
+			*routeATSynced.GreatCircleNm, *routeATSynced.Haul, recordID)
+	} else if origin != "" && destination != "" {
+		logMsg = fmt.Sprintf("[RouteHandler] Upserted route '%s' (%s → %s) (no airport data found) (record: %s)",
+			route, origin, destination, recordID)
+	} else {
+		logMsg = fmt.Sprintf("[RouteHandler] Upserted route '%s' (event/special) (record: %s)", route, recordID)
+	}
+	log.Print(logMsg)
+
+	return nil
+}
+
+// enrichRouteWithAirportData parses the route field (format: KJFK-EGLL) and enriches with airport coordinates
+func (h *RouteHandler) enrichRouteWithAirportData(ctx context.Context, routeATSynced *gormModels.RouteATSynced) {
+	if routeATSynced.Route == "" {
+		return
+	}
+
+	// Parse route field on "-" separator
+	parts := strings.Split(routeATSynced.Route, "-")
+	if len(parts) != 2 {
+		// Route doesn't match expected format, skip gracefully
+		log.Printf("[RouteHandler] Route '%s' does not match ICAO-ICAO format, skipping airport enrichment", routeATSynced.Route)
+		return
+	}
+
+	originICAO := strings.TrimSpace(parts[0])
+	destICAO := strings.TrimSpace(parts[1])
+
+	// Look up origin airport
+	if originICAO != "" {
+		if origin, err := h.airportRepo.FindByICAO(ctx, originICAO); err != nil {
+			log.Printf("[RouteHandler] Error looking up origin airport %s: %v", originICAO, err)
+		} else if origin != nil {
+			routeATSynced.OriginLat.Float64 = origin.Latitude
+			routeATSynced.OriginLat.Valid = true
+			routeATSynced.OriginLon.Float64 = origin.Longitude
+			routeATSynced.OriginLon.Valid = true
+		} else {
+			// Origin airport not found
+			log.Printf("[RouteHandler] Origin airport %s not found in database", originICAO)
+		}
+	}
+
+	// Look up destination airport
+	if destICAO != "" {
+		if dest, err := h.airportRepo.FindByICAO(ctx, destICAO); err != nil {
+			log.Printf("[RouteHandler] Error looking up destination airport %s: %v", destICAO, err)
+		} else if dest != nil {
+			routeATSynced.DestinationLat.Float64 = dest.Latitude
+			routeATSynced.DestinationLat.Valid = true
+			routeATSynced.DestinationLon.Float64 = dest.Longitude
+			routeATSynced.DestinationLon.Valid = true
+		} else {
+			// Destination airport not found
+			log.Printf("[RouteHandler] Destination airport %s not found in database", destICAO)
+		}
+	}
+}
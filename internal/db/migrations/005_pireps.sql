@@ -0,0 +1,99 @@
+--
+-- Native PIREP store. PIREPs are written here first and then pushed to the
+-- VA's data provider (if any); provider_* columns track the outbound push.
+--
+
+--
+-- Name: pirep_status; Type: TYPE; Schema: public; Owner: -
+--
+
+CREATE TYPE public.pirep_status AS ENUM (
+    'pending',
+    'approved',
+    'rejected'
+);
+
+
+--
+-- Name: pireps; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.pireps (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    va_id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    pilot_callsign character varying(50),
+    mode character varying(50) NOT NULL,
+    mode_display_name character varying(100),
+    route text,
+    route_id uuid,
+    route_at_id character varying(20),
+    flight_time_seconds integer DEFAULT 0 NOT NULL,
+    multiplier numeric(6,2) DEFAULT 1.0 NOT NULL,
+    credited_seconds integer DEFAULT 0 NOT NULL,
+    aircraft character varying(100),
+    airline character varying(100),
+    livery_id character varying(100),
+    flight_id character varying(100),
+    fuel_kg integer,
+    cargo_kg integer,
+    passengers integer,
+    pilot_remarks text,
+    status public.pirep_status DEFAULT 'pending'::public.pirep_status NOT NULL,
+    reviewed_by uuid,
+    review_notes text,
+    reviewed_at timestamp without time zone,
+    provider_type character varying(50),
+    provider_record_id character varying(100),
+    provider_synced_at timestamp without time zone,
+    provider_error text,
+    submitted_at timestamp without time zone DEFAULT now() NOT NULL,
+    created_at timestamp without time zone DEFAULT now(),
+    updated_at timestamp without time zone DEFAULT now()
+);
+
+
+--
+-- Name: pireps pireps_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.pireps
+    ADD CONSTRAINT pireps_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: pireps pireps_va_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.pireps
+    ADD CONSTRAINT pireps_va_id_fkey FOREIGN KEY (va_id) REFERENCES public.virtual_airlines(id) ON DELETE CASCADE;
+
+
+--
+-- Name: pireps pireps_user_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.pireps
+    ADD CONSTRAINT pireps_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE;
+
+
+--
+-- Name: pireps pireps_reviewed_by_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.pireps
+    ADD CONSTRAINT pireps_reviewed_by_fkey FOREIGN KEY (reviewed_by) REFERENCES public.users(id) ON DELETE SET NULL;
+
+
+--
+-- Name: idx_pireps_va_status; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_pireps_va_status ON public.pireps USING btree (va_id, status, submitted_at DESC);
+
+
+--
+-- Name: idx_pireps_user; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_pireps_user ON public.pireps USING btree (va_id, user_id, submitted_at DESC);
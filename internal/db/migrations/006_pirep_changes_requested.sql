@@ -0,0 +1,6 @@
+--
+-- Allow staff to send a PIREP back to the pilot for changes instead of
+-- outright rejecting it.
+--
+
+ALTER TYPE public.pirep_status ADD VALUE IF NOT EXISTS 'changes_requested';
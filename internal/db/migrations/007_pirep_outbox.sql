@@ -0,0 +1,66 @@
+--
+-- Durable outbox for delivering native PIREPs to the VA's data provider.
+-- Each PIREP has at most one outbox entry (idempotency_key = PIREP ID); the
+-- outbox job retries failed deliveries with exponential backoff and moves
+-- entries to 'dead' once max_attempts is reached.
+--
+
+--
+-- Name: pirep_outbox; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.pirep_outbox (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    pirep_id uuid NOT NULL,
+    va_id uuid NOT NULL,
+    provider_type character varying(50) NOT NULL,
+    idempotency_key character varying(100) NOT NULL,
+    payload jsonb DEFAULT '{}'::jsonb NOT NULL,
+    status character varying(20) DEFAULT 'pending'::character varying NOT NULL,
+    attempts integer DEFAULT 0 NOT NULL,
+    max_attempts integer DEFAULT 8 NOT NULL,
+    next_attempt_at timestamp without time zone DEFAULT now() NOT NULL,
+    last_error text,
+    provider_record_id character varying(100),
+    delivered_at timestamp without time zone,
+    created_at timestamp without time zone DEFAULT now(),
+    updated_at timestamp without time zone DEFAULT now()
+);
+
+
+--
+-- Name: pirep_outbox pirep_outbox_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.pirep_outbox
+    ADD CONSTRAINT pirep_outbox_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: pirep_outbox pirep_outbox_idempotency_key_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.pirep_outbox
+    ADD CONSTRAINT pirep_outbox_idempotency_key_key UNIQUE (idempotency_key);
+
+
+--
+-- Name: pirep_outbox pirep_outbox_pirep_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.pirep_outbox
+    ADD CONSTRAINT pirep_outbox_pirep_id_fkey FOREIGN KEY (pirep_id) REFERENCES public.pireps(id) ON DELETE CASCADE;
+
+
+--
+-- Name: idx_pirep_outbox_due; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_pirep_outbox_due ON public.pirep_outbox USING btree (next_attempt_at) WHERE ((status)::text = 'pending'::text);
+
+
+--
+-- Name: idx_pirep_outbox_va_status; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_pirep_outbox_va_status ON public.pirep_outbox USING btree (va_id, status);
@@ -0,0 +1,8 @@
+--
+-- Record the outcome of submit-time live flight validation on each PIREP
+--
+
+ALTER TABLE public.pireps
+    ADD COLUMN validation_policy character varying(10),
+    ADD COLUMN validation_status character varying(20),
+    ADD COLUMN validation_reason text;
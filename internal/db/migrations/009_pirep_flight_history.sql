@@ -0,0 +1,13 @@
+--
+-- Record the flight derived from the pilot's Infinite Flight history on each
+-- PIREP, along with any discrepancies against what the pilot filed
+--
+
+ALTER TABLE public.pireps
+    ADD COLUMN history_flight_id character varying(100),
+    ADD COLUMN derived_flight_time_seconds integer,
+    ADD COLUMN derived_origin character varying(10),
+    ADD COLUMN derived_destination character varying(10),
+    ADD COLUMN landings integer,
+    ADD COLUMN violations integer,
+    ADD COLUMN history_discrepancies text;
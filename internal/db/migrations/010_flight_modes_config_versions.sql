@@ -0,0 +1,41 @@
+--
+-- Version history for virtual_airlines.flight_modes_config. Every save (and
+-- rollback) appends a new version; the latest version is mirrored onto the VA.
+--
+
+--
+-- Name: flight_modes_config_versions; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.flight_modes_config_versions (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    va_id uuid NOT NULL,
+    version integer NOT NULL,
+    config jsonb DEFAULT '{}'::jsonb NOT NULL,
+    author_id uuid,
+    note text,
+    created_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+
+ALTER TABLE ONLY public.flight_modes_config_versions
+    ADD CONSTRAINT flight_modes_config_versions_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY public.flight_modes_config_versions
+    ADD CONSTRAINT flight_modes_config_versions_va_version_key UNIQUE (va_id, version);
+
+ALTER TABLE ONLY public.flight_modes_config_versions
+    ADD CONSTRAINT flight_modes_config_versions_va_id_fkey FOREIGN KEY (va_id) REFERENCES public.virtual_airlines(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY public.flight_modes_config_versions
+    ADD CONSTRAINT flight_modes_config_versions_author_id_fkey FOREIGN KEY (author_id) REFERENCES public.users(id) ON DELETE SET NULL;
+
+
+--
+-- Seed version 1 from configs saved before versioning existed
+--
+
+INSERT INTO public.flight_modes_config_versions (va_id, version, config, note)
+SELECT id, 1, flight_modes_config, 'Imported existing configuration'
+FROM public.virtual_airlines
+WHERE flight_modes_config IS NOT NULL AND flight_modes_config <> '{}'::jsonb;
@@ -0,0 +1,25 @@
+--
+-- Turn route_at_synced into the VA route catalogue. Routes can now be managed
+-- natively (no Airtable record), and carry scheduling details used when
+-- offering routes to pilots and crediting PIREPs.
+--
+
+ALTER TABLE public.route_at_synced
+    ALTER COLUMN at_id DROP NOT NULL,
+    ADD COLUMN source character varying(20) DEFAULT 'airtable'::character varying NOT NULL,
+    ADD COLUMN flight_number character varying(20),
+    ADD COLUMN aircraft character varying(100),
+    ADD COLUMN distance_nm numeric(8,1),
+    ADD COLUMN block_time_minutes integer,
+    ADD COLUMN multiplier numeric(6,2),
+    ADD COLUMN active_from date,
+    ADD COLUMN active_until date,
+    ADD COLUMN is_active boolean DEFAULT true NOT NULL;
+
+--
+-- Native routes are unique by name within a VA; Airtable routes stay keyed by at_id
+--
+
+CREATE UNIQUE INDEX idx_route_at_synced_native_route
+    ON public.route_at_synced USING btree (server_id, lower((route)::text))
+    WHERE ((source)::text = 'native'::text);
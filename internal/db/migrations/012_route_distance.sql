@@ -0,0 +1,46 @@
+--
+-- Great-circle distance, haul classification and per-aircraft-class block time
+-- estimates for every route, derived from its airport coordinates
+--
+
+ALTER TABLE public.route_at_synced
+    ADD COLUMN great_circle_nm numeric(8,1),
+    ADD COLUMN haul character varying(20),
+    ADD COLUMN estimated_block_minutes jsonb;
+
+--
+-- Backfill routes that already have coordinates. Speeds and overheads match
+-- common.EstimateBlockMinutes; haul bands match common.ClassifyRouteHaul.
+--
+
+WITH distances AS (
+  SELECT
+    id,
+    ROUND((2 * 3440.065 * ASIN(SQRT(
+      POWER(SIN(RADIANS(destination_lat - origin_lat) / 2), 2) +
+      COS(RADIANS(origin_lat)) * COS(RADIANS(destination_lat)) *
+      POWER(SIN(RADIANS(destination_lon - origin_lon) / 2), 2)
+    )))::numeric, 1) AS nm
+  FROM route_at_synced
+  WHERE origin_lat IS NOT NULL AND origin_lon IS NOT NULL
+    AND destination_lat IS NOT NULL AND destination_lon IS NOT NULL
+)
+UPDATE route_at_synced r
+SET
+  great_circle_nm = d.nm,
+  haul = CASE
+    WHEN d.nm < 1000 THEN 'short_haul'
+    WHEN d.nm < 3000 THEN 'medium_haul'
+    ELSE 'long_haul'
+  END,
+  estimated_block_minutes = jsonb_build_object(
+    'turboprop',    ROUND(d.nm / 270 * 60 + 20),
+    'regional_jet', ROUND(d.nm / 400 * 60 + 25),
+    'narrowbody',   ROUND(d.nm / 440 * 60 + 30),
+    'widebody',     ROUND(d.nm / 480 * 60 + 35)
+  ),
+  updated_at = now()
+FROM distances d
+WHERE r.id = d.id;
+
+CREATE INDEX idx_route_at_synced_haul ON public.route_at_synced USING btree (server_id, haul);
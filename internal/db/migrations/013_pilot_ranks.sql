@@ -0,0 +1,105 @@
+--
+-- Native rank ladder per VA. Pilots are promoted automatically once their
+-- approved PIREP totals and passed checkrides meet a rank's requirements.
+--
+
+--
+-- Name: va_ranks; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.va_ranks (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    va_id uuid NOT NULL,
+    name character varying(100) NOT NULL,
+    "position" integer NOT NULL,
+    min_hours numeric(8,1) DEFAULT 0 NOT NULL,
+    min_flights integer DEFAULT 0 NOT NULL,
+    required_checkrides text[] DEFAULT '{}'::text[] NOT NULL,
+    allowed_aircraft text[] DEFAULT '{}'::text[] NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+ALTER TABLE ONLY public.va_ranks
+    ADD CONSTRAINT va_ranks_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY public.va_ranks
+    ADD CONSTRAINT va_ranks_va_id_fkey FOREIGN KEY (va_id) REFERENCES public.virtual_airlines(id) ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX idx_va_ranks_va_name ON public.va_ranks USING btree (va_id, lower((name)::text));
+
+CREATE INDEX idx_va_ranks_va_position ON public.va_ranks USING btree (va_id, "position");
+
+
+--
+-- Name: pilot_checkrides; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.pilot_checkrides (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    va_id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    name character varying(100) NOT NULL,
+    examiner_id uuid,
+    notes text,
+    passed_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+ALTER TABLE ONLY public.pilot_checkrides
+    ADD CONSTRAINT pilot_checkrides_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY public.pilot_checkrides
+    ADD CONSTRAINT pilot_checkrides_va_id_fkey FOREIGN KEY (va_id) REFERENCES public.virtual_airlines(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY public.pilot_checkrides
+    ADD CONSTRAINT pilot_checkrides_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY public.pilot_checkrides
+    ADD CONSTRAINT pilot_checkrides_examiner_id_fkey FOREIGN KEY (examiner_id) REFERENCES public.users(id) ON DELETE SET NULL;
+
+CREATE UNIQUE INDEX idx_pilot_checkrides_va_user_name ON public.pilot_checkrides USING btree (va_id, user_id, lower((name)::text));
+
+
+--
+-- Name: rank_promotions; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.rank_promotions (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    va_id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    from_rank_id uuid,
+    to_rank_id uuid,
+    credited_hours numeric(8,1) DEFAULT 0 NOT NULL,
+    flights integer DEFAULT 0 NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+ALTER TABLE ONLY public.rank_promotions
+    ADD CONSTRAINT rank_promotions_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY public.rank_promotions
+    ADD CONSTRAINT rank_promotions_va_id_fkey FOREIGN KEY (va_id) REFERENCES public.virtual_airlines(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY public.rank_promotions
+    ADD CONSTRAINT rank_promotions_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY public.rank_promotions
+    ADD CONSTRAINT rank_promotions_from_rank_id_fkey FOREIGN KEY (from_rank_id) REFERENCES public.va_ranks(id) ON DELETE SET NULL;
+
+ALTER TABLE ONLY public.rank_promotions
+    ADD CONSTRAINT rank_promotions_to_rank_id_fkey FOREIGN KEY (to_rank_id) REFERENCES public.va_ranks(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_rank_promotions_va_created ON public.rank_promotions USING btree (va_id, created_at DESC);
+
+
+--
+-- Current rank of each pilot
+--
+
+ALTER TABLE public.va_user_roles
+    ADD COLUMN rank_id uuid,
+    ADD COLUMN rank_achieved_at timestamp without time zone;
+
+ALTER TABLE ONLY public.va_user_roles
+    ADD CONSTRAINT va_user_roles_rank_id_fkey FOREIGN KEY (rank_id) REFERENCES public.va_ranks(id) ON DELETE SET NULL;
@@ -0,0 +1,28 @@
+--
+-- Raw records of entity types that have no dedicated table (career mode and
+-- custom schemas), synced from the VA's data provider by the generic sync engine
+--
+
+--
+-- Name: va_synced_records; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.va_synced_records (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    va_id uuid NOT NULL,
+    entity_type character varying(50) NOT NULL,
+    provider_type character varying(50) NOT NULL,
+    record_id character varying(100) NOT NULL,
+    fields jsonb DEFAULT '{}'::jsonb NOT NULL,
+    provider_created_at timestamp without time zone,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+ALTER TABLE ONLY public.va_synced_records
+    ADD CONSTRAINT va_synced_records_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY public.va_synced_records
+    ADD CONSTRAINT va_synced_records_va_id_fkey FOREIGN KEY (va_id) REFERENCES public.virtual_airlines(id) ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX idx_va_synced_records_record ON public.va_synced_records USING btree (va_id, entity_type, record_id);
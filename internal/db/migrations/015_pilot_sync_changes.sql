@@ -0,0 +1,50 @@
+--
+-- Bidirectional pilot sync. pilot_at_synced keeps the provider's values of the
+-- two-way fields as of the last sync (the merge base) and when the provider last
+-- changed the record. Dashboard edits are queued in pilot_sync_changes and pushed
+-- to the provider; a change becomes a conflict when the provider's value also
+-- moved away from the base.
+--
+
+ALTER TABLE public.pilot_at_synced
+    ADD COLUMN synced_fields jsonb DEFAULT '{}'::jsonb NOT NULL,
+    ADD COLUMN remote_modified_at timestamp without time zone,
+    ADD COLUMN synced_at timestamp without time zone;
+
+
+--
+-- Name: pilot_sync_changes; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.pilot_sync_changes (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    va_id uuid NOT NULL,
+    va_user_role_id uuid NOT NULL,
+    provider_record_id character varying(100) NOT NULL,
+    field character varying(50) NOT NULL,
+    base_value text,
+    local_value text,
+    remote_value text,
+    status character varying(20) DEFAULT 'pending'::character varying NOT NULL,
+    attempts integer DEFAULT 0 NOT NULL,
+    last_error text,
+    pushed_at timestamp without time zone,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+ALTER TABLE ONLY public.pilot_sync_changes
+    ADD CONSTRAINT pilot_sync_changes_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY public.pilot_sync_changes
+    ADD CONSTRAINT pilot_sync_changes_va_id_fkey FOREIGN KEY (va_id) REFERENCES public.virtual_airlines(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY public.pilot_sync_changes
+    ADD CONSTRAINT pilot_sync_changes_va_user_role_id_fkey FOREIGN KEY (va_user_role_id) REFERENCES public.va_user_roles(id) ON DELETE CASCADE;
+
+-- At most one open (pending or conflicting) change per pilot field
+CREATE UNIQUE INDEX idx_pilot_sync_changes_open ON public.pilot_sync_changes USING btree (va_user_role_id, field) WHERE ((status)::text = ANY ((ARRAY['pending'::character varying, 'conflict'::character varying])::text[]));
+
+CREATE INDEX idx_pilot_sync_changes_status ON public.pilot_sync_changes USING btree (status, created_at);
+
+CREATE INDEX idx_pilot_sync_changes_record ON public.pilot_sync_changes USING btree (va_id, provider_record_id);
@@ -0,0 +1,27 @@
+--
+-- Full reconciliation of synced provider records. Records that no longer exist in
+-- the VA's data provider are soft-deleted rather than removed, and each VA's last
+-- reconciliation of an entity type is recorded in va_sync_history with its counts.
+--
+
+ALTER TABLE public.pilot_at_synced
+    ADD COLUMN deleted_at timestamp without time zone;
+
+ALTER TABLE public.route_at_synced
+    ADD COLUMN deleted_at timestamp without time zone;
+
+ALTER TABLE public.pirep_at_synced
+    ADD COLUMN deleted_at timestamp without time zone;
+
+CREATE INDEX idx_pilot_at_synced_deleted_at ON public.pilot_at_synced USING btree (deleted_at);
+
+CREATE INDEX idx_route_at_synced_deleted_at ON public.route_at_synced USING btree (deleted_at);
+
+CREATE INDEX idx_pirep_at_synced_deleted_at ON public.pirep_at_synced USING btree (deleted_at);
+
+-- Counts of the last run, set for reconciliation events
+ALTER TABLE public.va_sync_history
+    ADD COLUMN records_fetched integer,
+    ADD COLUMN records_stored integer,
+    ADD COLUMN records_failed integer,
+    ADD COLUMN records_deleted integer;
@@ -0,0 +1,45 @@
+--
+-- One row per sync of an entity type for a VA, written by the background sync
+-- jobs with the run's timing, record counts, rate-limit waits and first errors.
+-- va_sync_history keeps only the last successful sync time per event.
+--
+
+--
+-- Name: sync_runs; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.sync_runs (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    job character varying(50) NOT NULL,
+    va_id uuid NOT NULL,
+    entity_type character varying(50) NOT NULL,
+    provider_type character varying(50),
+    trigger character varying(20) DEFAULT 'scheduled'::character varying NOT NULL,
+    status character varying(20) NOT NULL,
+    incremental boolean DEFAULT false NOT NULL,
+    started_at timestamp without time zone NOT NULL,
+    finished_at timestamp without time zone,
+    duration_ms integer,
+    pages integer DEFAULT 0 NOT NULL,
+    records_fetched integer DEFAULT 0 NOT NULL,
+    records_inserted integer DEFAULT 0 NOT NULL,
+    records_updated integer DEFAULT 0 NOT NULL,
+    records_failed integer DEFAULT 0 NOT NULL,
+    records_deleted integer DEFAULT 0 NOT NULL,
+    rate_limit_waits integer DEFAULT 0 NOT NULL,
+    rate_limit_wait_ms integer DEFAULT 0 NOT NULL,
+    error_count integer DEFAULT 0 NOT NULL,
+    errors text[] DEFAULT '{}'::text[] NOT NULL,
+    message text,
+    created_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+ALTER TABLE ONLY public.sync_runs
+    ADD CONSTRAINT sync_runs_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY public.sync_runs
+    ADD CONSTRAINT sync_runs_va_id_fkey FOREIGN KEY (va_id) REFERENCES public.virtual_airlines(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_sync_runs_va_started ON public.sync_runs USING btree (va_id, started_at DESC);
+
+CREATE INDEX idx_sync_runs_job_started ON public.sync_runs USING btree (job, started_at DESC);
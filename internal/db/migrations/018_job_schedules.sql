@@ -0,0 +1,27 @@
+--
+-- Shared schedule state of the background jobs, so that every API replica agrees on
+-- when a job last ran and is next due. Jobs that run per VA have one row per VA
+-- (scope is the VA ID); jobs that run once for everything use an empty scope.
+-- Replicas take a Postgres advisory lock on the job and scope before running it.
+--
+
+--
+-- Name: job_schedules; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.job_schedules (
+    job character varying(50) NOT NULL,
+    scope character varying(64) DEFAULT ''::character varying NOT NULL,
+    schedule character varying(100) NOT NULL,
+    next_run_at timestamp without time zone NOT NULL,
+    last_started_at timestamp without time zone,
+    last_finished_at timestamp without time zone,
+    last_error text,
+    last_instance character varying(255),
+    updated_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+ALTER TABLE ONLY public.job_schedules
+    ADD CONSTRAINT job_schedules_pkey PRIMARY KEY (job, scope);
+
+CREATE INDEX idx_job_schedules_next_run ON public.job_schedules USING btree (next_run_at);
@@ -0,0 +1,40 @@
+--
+-- API keys are random secrets shown once at creation; only their SHA-256 hash is stored,
+-- with a short prefix to tell keys apart in listings and logs. Each key has a scope
+-- (read_only, bot or admin) and may be bound to one Discord server (and its VA).
+-- Revoked keys are kept for auditing; rotated keys point at their replacement.
+--
+
+ALTER TABLE public.api_keys
+    ADD COLUMN name character varying(100) DEFAULT ''::character varying NOT NULL,
+    ADD COLUMN prefix character varying(20),
+    ADD COLUMN key_hash character(64),
+    ADD COLUMN scope character varying(20) DEFAULT 'bot'::character varying NOT NULL,
+    ADD COLUMN va_id uuid,
+    ADD COLUMN discord_server_id character varying(255),
+    ADD COLUMN expires_at timestamp without time zone,
+    ADD COLUMN last_used_at timestamp without time zone,
+    ADD COLUMN revoked_at timestamp without time zone,
+    ADD COLUMN replaced_by uuid,
+    ADD COLUMN created_by character varying(255),
+    ADD COLUMN created_at timestamp without time zone DEFAULT now() NOT NULL;
+
+-- Existing keys are their own ID: keep them working through the hash of the ID until they
+-- are rotated, and revoke the inactive ones
+UPDATE public.api_keys SET
+    name = 'Legacy key',
+    prefix = 'legacy_' || left(id::text, 8),
+    key_hash = encode(sha256(convert_to(id::text, 'UTF8')), 'hex'),
+    revoked_at = CASE WHEN status THEN NULL ELSE now() END;
+
+ALTER TABLE public.api_keys
+    ALTER COLUMN prefix SET NOT NULL,
+    ALTER COLUMN key_hash SET NOT NULL,
+    DROP COLUMN status;
+
+ALTER TABLE ONLY public.api_keys
+    ADD CONSTRAINT api_keys_key_hash_key UNIQUE (key_hash),
+    ADD CONSTRAINT api_keys_prefix_key UNIQUE (prefix),
+    ADD CONSTRAINT api_keys_scope_check CHECK (scope IN ('read_only', 'bot', 'admin')),
+    ADD CONSTRAINT api_keys_va_id_fkey FOREIGN KEY (va_id) REFERENCES public.virtual_airlines(id) ON DELETE CASCADE,
+    ADD CONSTRAINT api_keys_replaced_by_fkey FOREIGN KEY (replaced_by) REFERENCES public.api_keys(id) ON DELETE SET NULL;
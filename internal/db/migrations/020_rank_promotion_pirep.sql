@@ -0,0 +1,12 @@
+--
+-- A promotion earned by approving a PIREP records the PIREP, so that each approval
+-- promotes the pilot at most once
+--
+
+ALTER TABLE public.rank_promotions
+    ADD COLUMN pirep_id uuid;
+
+ALTER TABLE ONLY public.rank_promotions
+    ADD CONSTRAINT rank_promotions_pirep_id_fkey FOREIGN KEY (pirep_id) REFERENCES public.pireps(id) ON DELETE SET NULL;
+
+CREATE UNIQUE INDEX idx_rank_promotions_pirep ON public.rank_promotions USING btree (pirep_id) WHERE (pirep_id IS NOT NULL);
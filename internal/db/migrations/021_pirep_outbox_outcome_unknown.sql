@@ -0,0 +1,8 @@
+--
+-- A failed delivery only makes a retry unsafe when the request may have reached the
+-- provider (e.g. a response timeout). outcome_unknown records that, so schemas without
+-- an idempotency_key mapping keep retrying with backoff after every other failure.
+--
+
+ALTER TABLE public.pirep_outbox
+    ADD COLUMN outcome_unknown boolean DEFAULT false NOT NULL;
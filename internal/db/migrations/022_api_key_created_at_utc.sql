@@ -0,0 +1,11 @@
+--
+-- API key times are UTC wall time (timestamp without time zone), but created_at defaulted
+-- to now() in the session's time zone, skewing the lifetime rotated keys inherit. The
+-- service now sets created_at itself; default to UTC too and convert the existing rows.
+--
+
+ALTER TABLE public.api_keys
+    ALTER COLUMN created_at SET DEFAULT (now() AT TIME ZONE 'utc');
+
+UPDATE public.api_keys
+    SET created_at = (created_at AT TIME ZONE current_setting('TimeZone')) AT TIME ZONE 'utc';
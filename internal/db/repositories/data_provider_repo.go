@@ -6,16 +6,23 @@ import (
 	"fmt"
 	"infinite-experiment/politburo/internal/models"
 	"infinite-experiment/politburo/internal/models/dtos"
+	"infinite-experiment/politburo/internal/secrets"
 
 	"gorm.io/gorm"
 )
 
+// secretCredentialFields are the keys of config_data.credentials that are stored sealed
+var secretCredentialFields = []string{"api_key", "service_account_json", "signing_secret"}
+
+// DataProviderConfigRepo stores data provider configs. The secret credentials in
+// config_data are sealed on write and opened on read, so callers only see plain values.
 type DataProviderConfigRepo struct {
-	db *gorm.DB
+	db      *gorm.DB
+	keyring *secrets.Keyring
 }
 
-func NewDataProviderConfigRepo(db *gorm.DB) *DataProviderConfigRepo {
-	return &DataProviderConfigRepo{db: db}
+func NewDataProviderConfigRepo(db *gorm.DB, keyring *secrets.Keyring) *DataProviderConfigRepo {
+	return &DataProviderConfigRepo{db: db, keyring: keyring}
 }
 
 // GetActiveConfig fetches the active config for a VA by provider type
@@ -33,7 +40,88 @@ func (r *DataProviderConfigRepo) GetActiveConfig(ctx context.Context, vaID, prov
 		return nil, fmt.Errorf("failed to get active config: %w", err)
 	}
 
-	return &config, nil
+	return &config, r.openSecrets(&config)
+}
+
+// GetActiveConfigForVA fetches the VA's active config whatever its provider type.
+// Only one config is expected to be active; the most recently updated one wins otherwise.
+func (r *DataProviderConfigRepo) GetActiveConfigForVA(ctx context.Context, vaID string) (*models.DataProviderConfig, error) {
+	var config models.DataProviderConfig
+
+	err := r.db.WithContext(ctx).
+		Where("va_id = ? AND is_active = ?", vaID, true).
+		Order("updated_at DESC").
+		First(&config).Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil // No config found
+		}
+		return nil, fmt.Errorf("failed to get active config: %w", err)
+	}
+
+	return &config, r.openSecrets(&config)
+}
+
+// GetConfig fetches the config for a VA by provider type, active or not
+func (r *DataProviderConfigRepo) GetConfig(ctx context.Context, vaID, providerType string) (*models.DataProviderConfig, error) {
+	var config models.DataProviderConfig
+
+	err := r.db.WithContext(ctx).
+		Where("va_id = ? AND provider_type = ?", vaID, providerType).
+		First(&config).Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get config: %w", err)
+	}
+
+	return &config, r.openSecrets(&config)
+}
+
+// DeactivateOtherConfigs deactivates every active config of a VA except keepID,
+// returning the provider types that were deactivated
+func (r *DataProviderConfigRepo) DeactivateOtherConfigs(ctx context.Context, vaID, keepID string) ([]string, error) {
+	var providerTypes []string
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.DataProviderConfig{}).
+			Where("va_id = ? AND id <> ? AND is_active = ?", vaID, keepID, true).
+			Pluck("provider_type", &providerTypes).Error; err != nil {
+			return err
+		}
+		if len(providerTypes) == 0 {
+			return nil
+		}
+		return tx.Model(&models.DataProviderConfig{}).
+			Where("va_id = ? AND id <> ? AND is_active = ?", vaID, keepID, true).
+			Update("is_active", false).Error
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to deactivate other configs: %w", err)
+	}
+
+	return providerTypes, nil
+}
+
+// GetActiveVAIDs returns the IDs of VAs that have an active config of any provider type
+func (r *DataProviderConfigRepo) GetActiveVAIDs(ctx context.Context) ([]string, error) {
+	var vaIDs []string
+
+	err := r.db.WithContext(ctx).
+		Model(&models.DataProviderConfig{}).
+		Where("is_active = ?", true).
+		Distinct().
+		Pluck("va_id", &vaIDs).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active VAs: %w", err)
+	}
+
+	return vaIDs, nil
 }
 
 // GetConfigByID fetches a config by its ID
@@ -51,7 +139,7 @@ func (r *DataProviderConfigRepo) GetConfigByID(ctx context.Context, configID str
 		return nil, fmt.Errorf("failed to get config by ID: %w", err)
 	}
 
-	return &config, nil
+	return &config, r.openSecrets(&config)
 }
 
 // GetConfigsByVA fetches all configs for a VA
@@ -67,27 +155,115 @@ func (r *DataProviderConfigRepo) GetConfigsByVA(ctx context.Context, vaID string
 		return nil, fmt.Errorf("failed to get configs for VA: %w", err)
 	}
 
+	for i := range configs {
+		if err := r.openSecrets(&configs[i]); err != nil {
+			return nil, err
+		}
+	}
 	return configs, nil
 }
 
 // CreateConfig creates a new config
 func (r *DataProviderConfigRepo) CreateConfig(ctx context.Context, config *models.DataProviderConfig) error {
-	err := r.db.WithContext(ctx).Create(config).Error
+	return r.withSealedSecrets(config, func() error {
+		if err := r.db.WithContext(ctx).Create(config).Error; err != nil {
+			return fmt.Errorf("failed to create config: %w", err)
+		}
+		return nil
+	})
+}
+
+// UpdateConfig updates an existing config
+func (r *DataProviderConfigRepo) UpdateConfig(ctx context.Context, config *models.DataProviderConfig) error {
+	return r.withSealedSecrets(config, func() error {
+		if err := r.db.WithContext(ctx).Save(config).Error; err != nil {
+			return fmt.Errorf("failed to update config: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetAllConfigsSealed fetches every config of every VA, with its credentials as stored
+func (r *DataProviderConfigRepo) GetAllConfigsSealed(ctx context.Context) ([]models.DataProviderConfig, error) {
+	var configs []models.DataProviderConfig
+	if err := r.db.WithContext(ctx).Order("created_at").Find(&configs).Error; err != nil {
+		return nil, fmt.Errorf("failed to get configs: %w", err)
+	}
+	return configs, nil
+}
+
+// UpdateConfigDataSealed writes config_data as given, without sealing it again
+func (r *DataProviderConfigRepo) UpdateConfigDataSealed(ctx context.Context, configID string, configData models.JSONB) error {
+	err := r.db.WithContext(ctx).
+		Model(&models.DataProviderConfig{}).
+		Where("id = ?", configID).
+		UpdateColumn("config_data", configData).Error
 	if err != nil {
-		return fmt.Errorf("failed to create config: %w", err)
+		return fmt.Errorf("failed to update config data: %w", err)
 	}
 	return nil
 }
 
-// UpdateConfig updates an existing config
-func (r *DataProviderConfigRepo) UpdateConfig(ctx context.Context, config *models.DataProviderConfig) error {
-	err := r.db.WithContext(ctx).Save(config).Error
+// openSecrets replaces the sealed credentials of a config read from the database with plain ones
+func (r *DataProviderConfigRepo) openSecrets(config *models.DataProviderConfig) error {
+	opened, _, err := MapCredentialSecrets(config.ConfigData, r.keyring.Open)
 	if err != nil {
-		return fmt.Errorf("failed to update config: %w", err)
+		return fmt.Errorf("failed to decrypt credentials of config %s: %w", config.ID, err)
 	}
+	config.ConfigData = opened
 	return nil
 }
 
+// withSealedSecrets runs save with the config's credentials sealed, leaving them plain afterwards
+func (r *DataProviderConfigRepo) withSealedSecrets(config *models.DataProviderConfig, save func() error) error {
+	plain := config.ConfigData
+	sealed, _, err := MapCredentialSecrets(plain, r.keyring.Seal)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt credentials: %w", err)
+	}
+
+	config.ConfigData = sealed
+	defer func() { config.ConfigData = plain }()
+	return save()
+}
+
+// MapCredentialSecrets returns a copy of config_data with fn applied to each secret
+// credential, and reports whether any of them changed. configData is left untouched.
+func MapCredentialSecrets(configData models.JSONB, fn func(string) (string, error)) (models.JSONB, bool, error) {
+	creds, ok := configData["credentials"].(map[string]interface{})
+	if !ok {
+		return configData, false, nil
+	}
+
+	mapped := make(map[string]interface{}, len(creds))
+	for key, value := range creds {
+		mapped[key] = value
+	}
+
+	changed := false
+	for _, field := range secretCredentialFields {
+		value, ok := mapped[field].(string)
+		if !ok || value == "" {
+			continue
+		}
+		result, err := fn(value)
+		if err != nil {
+			return nil, false, fmt.Errorf("%s: %w", field, err)
+		}
+		if result != value {
+			mapped[field] = result
+			changed = true
+		}
+	}
+
+	copied := make(models.JSONB, len(configData))
+	for key, value := range configData {
+		copied[key] = value
+	}
+	copied["credentials"] = mapped
+	return copied, changed, nil
+}
+
 // DeleteConfig deletes a config
 func (r *DataProviderConfigRepo) DeleteConfig(ctx context.Context, configID string) error {
 	result := r.db.WithContext(ctx).
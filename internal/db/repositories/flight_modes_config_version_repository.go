@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"infinite-experiment/politburo/internal/models/gorm"
+
+	gormlib "gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FlightModesConfigVersionRepo handles flight_modes_config_versions table operations
+type FlightModesConfigVersionRepo struct {
+	db *gormlib.DB
+}
+
+// NewFlightModesConfigVersionRepo creates a new flight modes config version repository
+func NewFlightModesConfigVersionRepo(db *gormlib.DB) *FlightModesConfigVersionRepo {
+	return &FlightModesConfigVersionRepo{db: db}
+}
+
+// CreateAndApply appends a new version and makes it the VA's active configuration in a single transaction.
+// The version number is assigned here; the VA row is locked so concurrent saves get distinct numbers.
+func (r *FlightModesConfigVersionRepo) CreateAndApply(ctx context.Context, version *gorm.FlightModesConfigVersion) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gormlib.DB) error {
+		var va gorm.VA
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			Where("id = ?", version.VAID).
+			First(&va).Error; err != nil {
+			if err == gormlib.ErrRecordNotFound {
+				return fmt.Errorf("VA not found with ID: %s", version.VAID)
+			}
+			return err
+		}
+
+		var latest int
+		if err := tx.Model(&gorm.FlightModesConfigVersion{}).
+			Where("va_id = ?", version.VAID).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&latest).Error; err != nil {
+			return err
+		}
+		version.Version = latest + 1
+
+		if err := tx.Create(version).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&gorm.VA{}).
+			Where("id = ?", version.VAID).
+			Update("flight_modes_config", version.Config).Error
+	})
+}
+
+// List returns all versions for a VA, newest first
+func (r *FlightModesConfigVersionRepo) List(ctx context.Context, vaID string) ([]gorm.FlightModesConfigVersion, error) {
+	var versions []gorm.FlightModesConfigVersion
+
+	err := r.db.WithContext(ctx).
+		Preload("Author").
+		Where("va_id = ?", vaID).
+		Order("version DESC").
+		Find(&versions).Error
+
+	return versions, err
+}
+
+// FindByVersion finds a specific version of a VA's configuration
+func (r *FlightModesConfigVersionRepo) FindByVersion(ctx context.Context, vaID string, version int) (*gorm.FlightModesConfigVersion, error) {
+	var v gorm.FlightModesConfigVersion
+
+	err := r.db.WithContext(ctx).
+		Preload("Author").
+		Where("va_id = ? AND version = ?", vaID, version).
+		First(&v).Error
+
+	if err != nil {
+		if err == gormlib.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+// FindLatest finds the most recent version of a VA's configuration
+func (r *FlightModesConfigVersionRepo) FindLatest(ctx context.Context, vaID string) (*gorm.FlightModesConfigVersion, error) {
+	var v gorm.FlightModesConfigVersion
+
+	err := r.db.WithContext(ctx).
+		Preload("Author").
+		Where("va_id = ?", vaID).
+		Order("version DESC").
+		First(&v).Error
+
+	if err != nil {
+		if err == gormlib.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &v, nil
+}
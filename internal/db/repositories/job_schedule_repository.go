@@ -0,0 +1,142 @@
+package repositories
+
+import (
+	"context"
+	"hash/fnv"
+	"time"
+
+	"infinite-experiment/politburo/internal/models/gorm"
+
+	gormlib "gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// jobLockSpace is the first key of the scheduler's advisory locks, keeping them apart
+// from any other advisory locks taken on the database
+const jobLockSpace int32 = 0x4a4f4253 // "JOBS"
+
+// JobScheduleRepo handles job_schedules table operations and the advisory locks that
+// keep replicas from running the same job at once
+type JobScheduleRepo struct {
+	db *gormlib.DB
+}
+
+// NewJobScheduleRepo creates a new job schedule repository
+func NewJobScheduleRepo(db *gormlib.DB) *JobScheduleRepo {
+	return &JobScheduleRepo{db: db}
+}
+
+// Find returns the schedule state of a job scope, or nil if it has none yet
+func (r *JobScheduleRepo) Find(ctx context.Context, job string, scope string) (*gorm.JobSchedule, error) {
+	var schedule gorm.JobSchedule
+
+	err := r.db.WithContext(ctx).
+		Where("job = ? AND scope = ?", job, scope).
+		First(&schedule).Error
+
+	if err != nil {
+		if err == gormlib.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &schedule, nil
+}
+
+// Ensure returns the schedule state of a job scope, creating it with the given schedule and
+// first run time if it has none. If another replica created it first, its row wins.
+func (r *JobScheduleRepo) Ensure(ctx context.Context, job string, scope string, schedule string, nextRunAt time.Time) (*gorm.JobSchedule, error) {
+	row := &gorm.JobSchedule{
+		Job:       job,
+		Scope:     scope,
+		Schedule:  schedule,
+		NextRunAt: nextRunAt,
+	}
+
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(row).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Find(ctx, job, scope)
+}
+
+// Reschedule replaces the schedule of a job scope and its next run time
+func (r *JobScheduleRepo) Reschedule(ctx context.Context, job string, scope string, schedule string, nextRunAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&gorm.JobSchedule{}).
+		Where("job = ? AND scope = ?", job, scope).
+		Updates(map[string]interface{}{
+			"schedule":    schedule,
+			"next_run_at": nextRunAt,
+			"updated_at":  time.Now(),
+		}).Error
+}
+
+// MarkStarted records that an instance started a run of a job scope
+func (r *JobScheduleRepo) MarkStarted(ctx context.Context, job string, scope string, instance string, startedAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&gorm.JobSchedule{}).
+		Where("job = ? AND scope = ?", job, scope).
+		Updates(map[string]interface{}{
+			"last_started_at": startedAt,
+			"last_instance":   instance,
+			"updated_at":      time.Now(),
+		}).Error
+}
+
+// MarkFinished records the end of a run of a job scope and when it is next due
+func (r *JobScheduleRepo) MarkFinished(ctx context.Context, job string, scope string, finishedAt time.Time, nextRunAt time.Time, lastError *string) error {
+	return r.db.WithContext(ctx).
+		Model(&gorm.JobSchedule{}).
+		Where("job = ? AND scope = ?", job, scope).
+		Updates(map[string]interface{}{
+			"last_finished_at": finishedAt,
+			"next_run_at":      nextRunAt,
+			"last_error":       lastError,
+			"updated_at":       time.Now(),
+		}).Error
+}
+
+// TryLock takes the session advisory lock of a job scope without waiting. When it is
+// taken, the returned unlock function must be called to release it; the lock is also
+// released if this process dies and its connection closes.
+func (r *JobScheduleRepo) TryLock(ctx context.Context, job string, scope string) (func(), bool, error) {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Session locks belong to a connection, so hold one for the lock's lifetime
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	key := jobLockKey(job, scope)
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1, $2)", jobLockSpace, key).Scan(&locked); err != nil {
+		conn.Close()
+		return nil, false, err
+	}
+	if !locked {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	unlock := func() {
+		conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1, $2)", jobLockSpace, key)
+		conn.Close()
+	}
+	return unlock, true, nil
+}
+
+// jobLockKey hashes a job scope into the second key of its advisory lock
+func jobLockKey(job string, scope string) int32 {
+	h := fnv.New32a()
+	h.Write([]byte(job + ":" + scope))
+	return int32(h.Sum32())
+}
@@ -2,8 +2,10 @@ package repositories
 
 import (
 	"context"
+	"fmt"
 	"infinite-experiment/politburo/internal/constants"
 	"infinite-experiment/politburo/internal/models/entities"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -16,14 +18,88 @@ func NewApiKeysRepo(db *sqlx.DB) *KeysRepo {
 	return &KeysRepo{db}
 }
 
-func (r *KeysRepo) GetStatus(ctx context.Context, key string) (*entities.ApiKey, error) {
-	var keyRes entities.ApiKey
+// GetByHash returns the key with the given hash, or sql.ErrNoRows
+func (r *KeysRepo) GetByHash(ctx context.Context, keyHash string) (*entities.ApiKey, error) {
+	var key entities.ApiKey
 
-	err := r.db.QueryRowxContext(ctx, constants.GetStatusByApiKey, key).StructScan(&keyRes)
+	if err := r.db.GetContext(ctx, &key, constants.GetApiKeyByHash, keyHash); err != nil {
+		return nil, err
+	}
 
-	if err != nil {
+	return &key, nil
+}
+
+// GetByID returns a key, or sql.ErrNoRows
+func (r *KeysRepo) GetByID(ctx context.Context, id string) (*entities.ApiKey, error) {
+	var key entities.ApiKey
+
+	if err := r.db.GetContext(ctx, &key, constants.GetApiKeyByID, id); err != nil {
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+// List returns the keys, newest first, including revoked ones only if asked to
+func (r *KeysRepo) List(ctx context.Context, includeRevoked bool) ([]entities.ApiKey, error) {
+	keys := []entities.ApiKey{}
+
+	if err := r.db.SelectContext(ctx, &keys, constants.ListApiKeys, includeRevoked); err != nil {
 		return nil, err
 	}
 
-	return &keyRes, nil
+	return keys, nil
+}
+
+// Create inserts a key, filling in its ID and creation time
+func (r *KeysRepo) Create(ctx context.Context, key *entities.ApiKey) error {
+	return insertApiKey(ctx, r.db, key)
+}
+
+// Replace inserts newKey as the replacement of the key oldID, which stays valid until
+// graceUntil. It fails if the old key is revoked or was already replaced.
+func (r *KeysRepo) Replace(ctx context.Context, oldID string, newKey *entities.ApiKey, graceUntil time.Time) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertApiKey(ctx, tx, newKey); err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, constants.ReplaceApiKey, oldID, newKey.ID, graceUntil)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("api key %s is revoked or already rotated", oldID)
+	}
+
+	return tx.Commit()
+}
+
+// Revoke revokes a key and reports whether it was active
+func (r *KeysRepo) Revoke(ctx context.Context, id string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, constants.RevokeApiKey, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// TouchLastUsed records that a key was just used
+func (r *KeysRepo) TouchLastUsed(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, constants.TouchApiKey, id)
+	return err
+}
+
+func insertApiKey(ctx context.Context, db sqlx.ExtContext, key *entities.ApiKey) error {
+	query, args, err := sqlx.Named(constants.InsertApiKey, key)
+	if err != nil {
+		return err
+	}
+	return db.QueryRowxContext(ctx, db.Rebind(query), args...).Scan(&key.ID, &key.CreatedAt)
 }
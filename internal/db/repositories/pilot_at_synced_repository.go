@@ -2,6 +2,8 @@ package repositories
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
 	"infinite-experiment/politburo/internal/models/gorm"
 
@@ -25,7 +27,7 @@ func NewPilotATSyncedRepo(db *gormlib.DB) *PilotATSyncedRepo {
 	return &PilotATSyncedRepo{db: db}
 }
 
-// Upsert inserts or updates a pilot record from Airtable
+// Upsert inserts or updates a pilot record from Airtable, restoring it if it was soft-deleted
 // ON CONFLICT (server_id, at_id) DO UPDATE
 func (r *PilotATSyncedRepo) Upsert(ctx context.Context, pilot *gorm.PilotATSynced) error {
 	return r.db.WithContext(ctx).
@@ -34,7 +36,7 @@ func (r *PilotATSyncedRepo) Upsert(ctx context.Context, pilot *gorm.PilotATSynce
 				{Name: "server_id"},
 				{Name: "at_id"},
 			},
-			DoUpdates: clause.AssignmentColumns([]string{"callsign", "registered"}),
+			DoUpdates: clause.AssignmentColumns([]string{"callsign", "registered", "deleted_at"}),
 		}).
 		Create(pilot).Error
 }
@@ -99,3 +101,78 @@ func (r *PilotATSyncedRepo) FindByATID(ctx context.Context, vaID string, atID st
 
 	return &pilot, nil
 }
+
+// UpdateSyncState records the provider's values of the two-way sync fields and when the
+// provider last modified the record, as seen by an inbound sync
+func (r *PilotATSyncedRepo) UpdateSyncState(ctx context.Context, vaID string, atID string, fields map[string]string, remoteModifiedAt *time.Time) error {
+	synced := make(gorm.JSONB, len(fields))
+	for field, value := range fields {
+		synced[field] = value
+	}
+
+	return r.db.WithContext(ctx).
+		Model(&gorm.PilotATSynced{}).
+		Where("server_id = ? AND at_id = ?", vaID, atID).
+		Updates(map[string]interface{}{
+			"synced_fields":      synced,
+			"remote_modified_at": remoteModifiedAt,
+			"synced_at":          time.Now(),
+		}).Error
+}
+
+// SetSyncedField records a single field value written to the provider by an outbound sync
+func (r *PilotATSyncedRepo) SetSyncedField(ctx context.Context, vaID string, atID string, field string, value string) error {
+	return r.db.WithContext(ctx).
+		Model(&gorm.PilotATSynced{}).
+		Where("server_id = ? AND at_id = ?", vaID, atID).
+		Update("synced_fields", gormlib.Expr("synced_fields || jsonb_build_object(?::text, ?::text)", field, value)).Error
+}
+
+// SoftDeleteMissing soft-deletes a VA's pilot records whose Airtable ID is not in atIDs
+// and unlinks members from them. Returns the number of records deleted.
+func (r *PilotATSyncedRepo) SoftDeleteMissing(ctx context.Context, vaID string, atIDs []string) (int64, error) {
+	var deleted int64
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gormlib.DB) error {
+		var err error
+		deleted, err = softDeleteMissing(tx, &gorm.PilotATSynced{}, vaID, atIDs)
+		if err != nil || deleted == 0 {
+			return err
+		}
+
+		return tx.Table("va_user_roles").
+			Where("va_id = ? AND airtable_pilot_id IN (?)", vaID,
+				tx.Unscoped().Model(&gorm.PilotATSynced{}).Select("at_id").Where("server_id = ? AND deleted_at IS NOT NULL", vaID)).
+			Update("airtable_pilot_id", nil).Error
+	})
+
+	return deleted, err
+}
+
+// softDeleteMissing soft-deletes the rows of an *_at_synced model belonging to a VA whose
+// at_id is not in atIDs. The IDs are sent as one JSON array so large record sets stay
+// within the bind parameter limit.
+func softDeleteMissing(db *gormlib.DB, model interface{}, vaID string, atIDs []string) (int64, error) {
+	keep, err := json.Marshal(atIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	result := db.
+		Where("server_id = ? AND at_id IS NOT NULL", vaID).
+		Where("NOT (at_id = ANY (ARRAY(SELECT jsonb_array_elements_text(?::jsonb))))", string(keep)).
+		Delete(model)
+
+	return result.RowsAffected, result.Error
+}
+
+// CountByATIDs counts the VA's stored pilot records among atIDs, including soft-deleted ones
+func (r *PilotATSyncedRepo) CountByATIDs(ctx context.Context, vaID string, atIDs []string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Model(&gorm.PilotATSynced{}).
+		Where("server_id = ? AND at_id IN ?", vaID, atIDs).
+		Count(&count).Error
+	return count, err
+}
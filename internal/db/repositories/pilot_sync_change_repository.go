@@ -0,0 +1,194 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"infinite-experiment/politburo/internal/constants"
+	"infinite-experiment/politburo/internal/models/gorm"
+
+	gormlib "gorm.io/gorm"
+)
+
+// openPilotChangeStatuses are the states of a change that has not been settled yet
+var openPilotChangeStatuses = []string{constants.PilotChangeStatusPending, constants.PilotChangeStatusConflict}
+
+// PilotSyncChangeRepo handles pilot_sync_changes table operations
+type PilotSyncChangeRepo struct {
+	db *gormlib.DB
+}
+
+// NewPilotSyncChangeRepo creates a new pilot sync change repository
+func NewPilotSyncChangeRepo(db *gormlib.DB) *PilotSyncChangeRepo {
+	return &PilotSyncChangeRepo{db: db}
+}
+
+// Create inserts a new change
+func (r *PilotSyncChangeRepo) Create(ctx context.Context, change *gorm.PilotSyncChange) error {
+	return r.db.WithContext(ctx).Create(change).Error
+}
+
+// FindOpen finds the pending or conflicting change of a pilot field
+func (r *PilotSyncChangeRepo) FindOpen(ctx context.Context, vaUserRoleID string, field string) (*gorm.PilotSyncChange, error) {
+	var change gorm.PilotSyncChange
+
+	err := r.db.WithContext(ctx).
+		Where("va_user_role_id = ? AND field = ? AND status IN ?", vaUserRoleID, field, openPilotChangeStatuses).
+		First(&change).Error
+
+	if err != nil {
+		if err == gormlib.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &change, nil
+}
+
+// FindByID finds a change by VA ID and change ID
+func (r *PilotSyncChangeRepo) FindByID(ctx context.Context, vaID string, id string) (*gorm.PilotSyncChange, error) {
+	var change gorm.PilotSyncChange
+
+	err := r.db.WithContext(ctx).
+		Where("va_id = ? AND id = ?", vaID, id).
+		First(&change).Error
+
+	if err != nil {
+		if err == gormlib.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &change, nil
+}
+
+// UpdateLocalValue replaces the local value of an open change with a newer edit
+func (r *PilotSyncChangeRepo) UpdateLocalValue(ctx context.Context, id string, localValue string) error {
+	return r.db.WithContext(ctx).
+		Model(&gorm.PilotSyncChange{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"local_value": localValue,
+			"updated_at":  time.Now(),
+		}).Error
+}
+
+// ListPending returns up to limit pending changes across all VAs, oldest first
+func (r *PilotSyncChangeRepo) ListPending(ctx context.Context, limit int) ([]gorm.PilotSyncChange, error) {
+	var changes []gorm.PilotSyncChange
+
+	err := r.db.WithContext(ctx).
+		Where("status = ?", constants.PilotChangeStatusPending).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&changes).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// ListOpenByRecord returns the open changes of a provider record
+func (r *PilotSyncChangeRepo) ListOpenByRecord(ctx context.Context, vaID string, providerRecordID string) ([]gorm.PilotSyncChange, error) {
+	var changes []gorm.PilotSyncChange
+
+	err := r.db.WithContext(ctx).
+		Where("va_id = ? AND provider_record_id = ? AND status IN ?", vaID, providerRecordID, openPilotChangeStatuses).
+		Find(&changes).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// ListByStatus returns changes for a VA in the given status, most recently changed first
+func (r *PilotSyncChangeRepo) ListByStatus(ctx context.Context, vaID string, status string, limit int) ([]gorm.PilotSyncChange, error) {
+	var changes []gorm.PilotSyncChange
+
+	query := r.db.WithContext(ctx).
+		Where("va_id = ? AND status = ?", vaID, status).
+		Order("updated_at DESC")
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if err := query.Find(&changes).Error; err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// MarkPushed records that the local value was written to the provider
+func (r *PilotSyncChangeRepo) MarkPushed(ctx context.Context, id string) error {
+	now := time.Now()
+
+	return r.db.WithContext(ctx).
+		Model(&gorm.PilotSyncChange{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     constants.PilotChangeStatusPushed,
+			"pushed_at":  now,
+			"last_error": nil,
+		}).Error
+}
+
+// MarkConflict records that the provider's value also changed since the last sync
+func (r *PilotSyncChangeRepo) MarkConflict(ctx context.Context, id string, remoteValue string) error {
+	return r.db.WithContext(ctx).
+		Model(&gorm.PilotSyncChange{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":       constants.PilotChangeStatusConflict,
+			"remote_value": remoteValue,
+		}).Error
+}
+
+// MarkFailed records a failed push attempt, giving up on the change when failed is set
+func (r *PilotSyncChangeRepo) MarkFailed(ctx context.Context, id string, attempts int, errMsg string, failed bool) error {
+	status := constants.PilotChangeStatusPending
+	if failed {
+		status = constants.PilotChangeStatusFailed
+	}
+
+	return r.db.WithContext(ctx).
+		Model(&gorm.PilotSyncChange{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     status,
+			"attempts":   attempts,
+			"last_error": errMsg,
+		}).Error
+}
+
+// Requeue moves a conflicting change back to pending against a new base value, so it
+// overwrites the provider's value on the next push
+func (r *PilotSyncChangeRepo) Requeue(ctx context.Context, vaID string, id string, baseValue string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&gorm.PilotSyncChange{}).
+		Where("va_id = ? AND id = ? AND status = ?", vaID, id, constants.PilotChangeStatusConflict).
+		Updates(map[string]interface{}{
+			"status":     constants.PilotChangeStatusPending,
+			"base_value": baseValue,
+			"attempts":   0,
+		})
+
+	return result.RowsAffected > 0, result.Error
+}
+
+// MarkResolved settles a conflicting change in favour of the provider's value
+func (r *PilotSyncChangeRepo) MarkResolved(ctx context.Context, vaID string, id string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&gorm.PilotSyncChange{}).
+		Where("va_id = ? AND id = ? AND status = ?", vaID, id, constants.PilotChangeStatusConflict).
+		Update("status", constants.PilotChangeStatusResolved)
+
+	return result.RowsAffected > 0, result.Error
+}
@@ -19,7 +19,7 @@ func NewPirepATSyncedRepo(db *gormlib.DB) *PirepATSyncedRepo {
 	return &PirepATSyncedRepo{db: db}
 }
 
-// Upsert inserts or updates a PIREP record from Airtable
+// Upsert inserts or updates a PIREP record from Airtable, restoring it if it was soft-deleted
 // ON CONFLICT (server_id, at_id) DO UPDATE
 func (r *PirepATSyncedRepo) Upsert(ctx context.Context, pirep *gorm.PirepATSynced) error {
 	return r.db.WithContext(ctx).
@@ -30,7 +30,7 @@ func (r *PirepATSyncedRepo) Upsert(ctx context.Context, pirep *gorm.PirepATSynce
 			},
 			DoUpdates: clause.AssignmentColumns([]string{
 				"route", "flight_mode", "flight_time", "pilot_callsign",
-				"aircraft", "livery", "route_at_id", "pilot_at_id", "at_created_time", "updated_at",
+				"aircraft", "livery", "route_at_id", "pilot_at_id", "at_created_time", "updated_at", "deleted_at",
 			}),
 		}).
 		Create(pirep).Error
@@ -141,3 +141,20 @@ func (r *PirepATSyncedRepo) FindByATIDs(ctx context.Context, vaID string, atIDs
 
 	return pireps, nil
 }
+
+// SoftDeleteMissing soft-deletes a VA's PIREP records whose Airtable ID is not in atIDs.
+// Returns the number of records deleted.
+func (r *PirepATSyncedRepo) SoftDeleteMissing(ctx context.Context, vaID string, atIDs []string) (int64, error) {
+	return softDeleteMissing(r.db.WithContext(ctx), &gorm.PirepATSynced{}, vaID, atIDs)
+}
+
+// CountByATIDs counts the VA's stored PIREP records among atIDs, including soft-deleted ones
+func (r *PirepATSyncedRepo) CountByATIDs(ctx context.Context, vaID string, atIDs []string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Model(&gorm.PirepATSynced{}).
+		Where("server_id = ? AND at_id IN ?", vaID, atIDs).
+		Count(&count).Error
+	return count, err
+}
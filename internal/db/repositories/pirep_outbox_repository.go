@@ -0,0 +1,157 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"infinite-experiment/politburo/internal/constants"
+	"infinite-experiment/politburo/internal/models/gorm"
+
+	gormlib "gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PirepOutboxRepo handles pirep_outbox table operations
+type PirepOutboxRepo struct {
+	db *gormlib.DB
+}
+
+// NewPirepOutboxRepo creates a new PIREP outbox repository
+func NewPirepOutboxRepo(db *gormlib.DB) *PirepOutboxRepo {
+	return &PirepOutboxRepo{db: db}
+}
+
+// ClaimDue locks and returns up to limit pending entries whose next attempt is due.
+// Claimed entries have next_attempt_at pushed out by lease so that concurrent
+// workers (or other replicas) skip them while delivery is in flight.
+func (r *PirepOutboxRepo) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]gorm.PirepOutbox, error) {
+	var entries []gorm.PirepOutbox
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gormlib.DB) error {
+		if err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", constants.OutboxStatusPending, time.Now()).
+			Order("next_attempt_at ASC").
+			Limit(limit).
+			Find(&entries).Error; err != nil {
+			return err
+		}
+
+		if len(entries) == 0 {
+			return nil
+		}
+
+		ids := make([]string, len(entries))
+		for i, e := range entries {
+			ids[i] = e.ID
+		}
+
+		return tx.Model(&gorm.PirepOutbox{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", time.Now().Add(lease)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// FindByID finds an outbox entry by VA ID and entry ID
+func (r *PirepOutboxRepo) FindByID(ctx context.Context, vaID string, id string) (*gorm.PirepOutbox, error) {
+	var entry gorm.PirepOutbox
+
+	err := r.db.WithContext(ctx).
+		Where("va_id = ? AND id = ?", vaID, id).
+		First(&entry).Error
+
+	if err != nil {
+		if err == gormlib.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// MarkDelivered records a successful delivery
+func (r *PirepOutboxRepo) MarkDelivered(ctx context.Context, id string, recordID string) error {
+	now := time.Now()
+
+	return r.db.WithContext(ctx).
+		Model(&gorm.PirepOutbox{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":             constants.OutboxStatusDelivered,
+			"provider_record_id": recordID,
+			"delivered_at":       now,
+			"last_error":         nil,
+			"updated_at":         now,
+		}).Error
+}
+
+// MarkFailed records a failed attempt and schedules the next one, or dead-letters the entry.
+// outcomeUnknown records that an attempt may have created the provider record.
+func (r *PirepOutboxRepo) MarkFailed(ctx context.Context, id string, attempts int, errMsg string, outcomeUnknown bool, nextAttemptAt time.Time, dead bool) error {
+	status := constants.OutboxStatusPending
+	if dead {
+		status = constants.OutboxStatusDead
+	}
+
+	return r.db.WithContext(ctx).
+		Model(&gorm.PirepOutbox{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":          status,
+			"attempts":        attempts,
+			"last_error":      errMsg,
+			"outcome_unknown": outcomeUnknown,
+			"next_attempt_at": nextAttemptAt,
+			"updated_at":      time.Now(),
+		}).Error
+}
+
+// ExtendLease pushes back the next attempt of a pending entry whose delivery is still in flight
+func (r *PirepOutboxRepo) ExtendLease(ctx context.Context, id string, until time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&gorm.PirepOutbox{}).
+		Where("id = ? AND status = ? AND next_attempt_at < ?", id, constants.OutboxStatusPending, until).
+		Update("next_attempt_at", until).Error
+}
+
+// ListByStatus returns outbox entries for a VA in the given status, newest first
+func (r *PirepOutboxRepo) ListByStatus(ctx context.Context, vaID string, status string, limit int) ([]gorm.PirepOutbox, error) {
+	var entries []gorm.PirepOutbox
+
+	query := r.db.WithContext(ctx).
+		Where("va_id = ? AND status = ?", vaID, status).
+		Order("updated_at DESC")
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if err := query.Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// Requeue moves a dead-lettered entry back to pending with a fresh attempt budget. Replaying
+// confirms the record was not created, so the entry's unknown outcome is cleared.
+func (r *PirepOutboxRepo) Requeue(ctx context.Context, vaID string, id string, nextAttemptAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&gorm.PirepOutbox{}).
+		Where("va_id = ? AND id = ? AND status = ?", vaID, id, constants.OutboxStatusDead).
+		Updates(map[string]interface{}{
+			"status":          constants.OutboxStatusPending,
+			"attempts":        0,
+			"outcome_unknown": false,
+			"next_attempt_at": nextAttemptAt,
+			"updated_at":      time.Now(),
+		})
+
+	return result.RowsAffected > 0, result.Error
+}
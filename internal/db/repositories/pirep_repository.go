@@ -0,0 +1,270 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"infinite-experiment/politburo/internal/constants"
+	"infinite-experiment/politburo/internal/models/gorm"
+
+	gormlib "gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PirepRepo handles pireps table operations (native PIREP store)
+type PirepRepo struct {
+	db *gormlib.DB
+}
+
+// NewPirepRepo creates a new native PIREP repository
+func NewPirepRepo(db *gormlib.DB) *PirepRepo {
+	return &PirepRepo{db: db}
+}
+
+// PirepFilter narrows down PIREP listings. Empty fields are ignored.
+type PirepFilter struct {
+	Status        constants.PirepStatus
+	UserID        string
+	PilotCallsign string
+	Mode          string
+	Route         string
+	Limit         int
+	Offset        int
+}
+
+// Create inserts a new PIREP
+func (r *PirepRepo) Create(ctx context.Context, pirep *gorm.Pirep) error {
+	return r.db.WithContext(ctx).Create(pirep).Error
+}
+
+// CreateWithOutbox inserts a new PIREP and, if given, its outbox entry in a single transaction
+// so a stored PIREP is never left without its pending delivery
+func (r *PirepRepo) CreateWithOutbox(ctx context.Context, pirep *gorm.Pirep, outbox *gorm.PirepOutbox) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gormlib.DB) error {
+		if err := tx.Create(pirep).Error; err != nil {
+			return err
+		}
+		if outbox == nil {
+			return nil
+		}
+
+		outbox.PirepID = pirep.ID
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "idempotency_key"}},
+			DoNothing: true,
+		}).Create(outbox).Error
+	})
+}
+
+// FindByID finds a PIREP by VA ID and PIREP ID
+func (r *PirepRepo) FindByID(ctx context.Context, vaID string, pirepID string) (*gorm.Pirep, error) {
+	var pirep gorm.Pirep
+
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("va_id = ? AND id = ?", vaID, pirepID).
+		First(&pirep).Error
+
+	if err != nil {
+		if err == gormlib.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &pirep, nil
+}
+
+// List returns PIREPs for a VA matching the filter, newest first, along with the total match count
+func (r *PirepRepo) List(ctx context.Context, vaID string, filter PirepFilter) ([]gorm.Pirep, int64, error) {
+	var (
+		pireps []gorm.Pirep
+		total  int64
+	)
+
+	query := r.db.WithContext(ctx).
+		Model(&gorm.Pirep{}).
+		Where("va_id = ?", vaID)
+
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.UserID != "" {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.PilotCallsign != "" {
+		query = query.Where("LOWER(pilot_callsign) = LOWER(?)", filter.PilotCallsign)
+	}
+	if filter.Mode != "" {
+		query = query.Where("mode = ?", filter.Mode)
+	}
+	if filter.Route != "" {
+		query = query.Where("LOWER(route) = LOWER(?)", filter.Route)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query = query.Preload("User").Order("submitted_at DESC")
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	if err := query.Find(&pireps).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return pireps, total, nil
+}
+
+// UpdateReview records a review decision on a PIREP that is still reviewable. It reports
+// false when the PIREP was reviewed concurrently (or does not exist), leaving it untouched.
+func (r *PirepRepo) UpdateReview(
+	ctx context.Context,
+	vaID string,
+	pirepID string,
+	status constants.PirepStatus,
+	reviewerID string,
+	notes *string,
+) (bool, error) {
+	now := time.Now()
+
+	result := r.db.WithContext(ctx).
+		Model(&gorm.Pirep{}).
+		Where("va_id = ? AND id = ? AND status IN ?", vaID, pirepID, constants.ReviewablePirepStatuses).
+		Updates(map[string]interface{}{
+			"status":       status,
+			"reviewed_by":  reviewerID,
+			"review_notes": notes,
+			"reviewed_at":  now,
+			"updated_at":   now,
+		})
+
+	return result.RowsAffected > 0, result.Error
+}
+
+// Resubmit applies a pilot's corrections to their PIREP and moves it back to pending. It reports
+// false when the PIREP is not the pilot's or no longer awaits changes, leaving it untouched.
+func (r *PirepRepo) Resubmit(
+	ctx context.Context,
+	vaID string,
+	pirepID string,
+	userID string,
+	updates map[string]interface{},
+) (bool, error) {
+	now := time.Now()
+
+	fields := make(map[string]interface{}, len(updates)+5)
+	for k, v := range updates {
+		fields[k] = v
+	}
+	fields["status"] = constants.PirepStatusPending
+	fields["reviewed_by"] = nil
+	fields["reviewed_at"] = nil
+	fields["submitted_at"] = now
+	fields["updated_at"] = now
+
+	result := r.db.WithContext(ctx).
+		Model(&gorm.Pirep{}).
+		Where("va_id = ? AND id = ? AND user_id = ? AND status = ?", vaID, pirepID, userID, constants.PirepStatusChangesRequested).
+		Updates(fields)
+
+	return result.RowsAffected > 0, result.Error
+}
+
+// MarkProviderSynced records a successful push of the PIREP to the data provider
+func (r *PirepRepo) MarkProviderSynced(ctx context.Context, pirepID string, providerType string, recordID string) error {
+	now := time.Now()
+
+	return r.db.WithContext(ctx).
+		Model(&gorm.Pirep{}).
+		Where("id = ?", pirepID).
+		Updates(map[string]interface{}{
+			"provider_type":      providerType,
+			"provider_record_id": recordID,
+			"provider_synced_at": now,
+			"provider_error":     nil,
+			"updated_at":         now,
+		}).Error
+}
+
+// MarkProviderFailed records a failed push of the PIREP to the data provider
+func (r *PirepRepo) MarkProviderFailed(ctx context.Context, pirepID string, providerType string, errMsg string) error {
+	return r.db.WithContext(ctx).
+		Model(&gorm.Pirep{}).
+		Where("id = ?", pirepID).
+		Updates(map[string]interface{}{
+			"provider_type":  providerType,
+			"provider_error": errMsg,
+			"updated_at":     time.Now(),
+		}).Error
+}
+
+// CountByStatus returns PIREP counts per status for a VA
+func (r *PirepRepo) CountByStatus(ctx context.Context, vaID string) (map[constants.PirepStatus]int64, error) {
+	var rows []struct {
+		Status constants.PirepStatus
+		Count  int64
+	}
+
+	err := r.db.WithContext(ctx).
+		Model(&gorm.Pirep{}).
+		Select("status, COUNT(*) AS count").
+		Where("va_id = ?", vaID).
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[constants.PirepStatus]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// PilotPirepTotals is a pilot's approved PIREP totals in a VA
+type PilotPirepTotals struct {
+	UserID          string
+	Flights         int
+	CreditedSeconds int64
+}
+
+// ApprovedTotals returns the approved PIREP count and credited flight time for a pilot
+func (r *PirepRepo) ApprovedTotals(ctx context.Context, vaID string, userID string) (PilotPirepTotals, error) {
+	totals := PilotPirepTotals{UserID: userID}
+
+	err := r.db.WithContext(ctx).
+		Model(&gorm.Pirep{}).
+		Select("COUNT(*) AS flights, COALESCE(SUM(credited_seconds), 0) AS credited_seconds").
+		Where("va_id = ? AND user_id = ? AND status = ?", vaID, userID, constants.PirepStatusApproved).
+		Scan(&totals).Error
+
+	return totals, err
+}
+
+// ApprovedTotalsByVA returns approved PIREP totals for every pilot of a VA with at least one approved PIREP
+func (r *PirepRepo) ApprovedTotalsByVA(ctx context.Context, vaID string) (map[string]PilotPirepTotals, error) {
+	var rows []PilotPirepTotals
+
+	err := r.db.WithContext(ctx).
+		Model(&gorm.Pirep{}).
+		Select("user_id, COUNT(*) AS flights, COALESCE(SUM(credited_seconds), 0) AS credited_seconds").
+		Where("va_id = ? AND status = ?", vaID, constants.PirepStatusApproved).
+		Group("user_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[string]PilotPirepTotals, len(rows))
+	for _, row := range rows {
+		totals[row.UserID] = row
+	}
+	return totals, nil
+}
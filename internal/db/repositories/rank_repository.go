@@ -0,0 +1,213 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"infinite-experiment/politburo/internal/models/gorm"
+
+	gormlib "gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errPromotionSkipped rolls back a promotion that was already made or no longer applies
+var errPromotionSkipped = errors.New("promotion skipped")
+
+// RankRepo handles the rank ladder (va_ranks), pilot checkrides and promotion history
+type RankRepo struct {
+	db *gormlib.DB
+}
+
+// NewRankRepo creates a new rank repository
+func NewRankRepo(db *gormlib.DB) *RankRepo {
+	return &RankRepo{db: db}
+}
+
+// ListByVA returns a VA's rank ladder, lowest rank first
+func (r *RankRepo) ListByVA(ctx context.Context, vaID string) ([]gorm.VARank, error) {
+	var ranks []gorm.VARank
+
+	err := r.db.WithContext(ctx).
+		Where("va_id = ?", vaID).
+		Order("position ASC").
+		Find(&ranks).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ranks, nil
+}
+
+// ReplaceLadder replaces a VA's rank ladder in a single transaction.
+// Ranks are matched to existing ones by name (case-insensitive) so pilots keep their rank;
+// ranks no longer in the ladder are deleted and their holders become unranked.
+func (r *RankRepo) ReplaceLadder(ctx context.Context, vaID string, ranks []gorm.VARank) ([]gorm.VARank, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gormlib.DB) error {
+		var existing []gorm.VARank
+		if err := tx.Where("va_id = ?", vaID).Find(&existing).Error; err != nil {
+			return err
+		}
+		byName := make(map[string]gorm.VARank, len(existing))
+		for _, rank := range existing {
+			byName[strings.ToLower(rank.Name)] = rank
+		}
+
+		keep := make(map[string]bool, len(ranks))
+		for i := range ranks {
+			ranks[i].VAID = vaID
+			if prev, ok := byName[strings.ToLower(ranks[i].Name)]; ok {
+				ranks[i].ID = prev.ID
+				ranks[i].CreatedAt = prev.CreatedAt
+				keep[prev.ID] = true
+			}
+		}
+
+		for _, rank := range existing {
+			if !keep[rank.ID] {
+				if err := tx.Delete(&gorm.VARank{}, "id = ?", rank.ID).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		for i := range ranks {
+			ranks[i].UpdatedAt = time.Now()
+			if ranks[i].ID == "" {
+				if err := tx.Create(&ranks[i]).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			if err := tx.Save(&ranks[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ranks, nil
+}
+
+// CreateCheckride records a passed checkride
+func (r *RankRepo) CreateCheckride(ctx context.Context, checkride *gorm.PilotCheckride) error {
+	return r.db.WithContext(ctx).Create(checkride).Error
+}
+
+// FindCheckride finds a pilot's passed checkride by name (case-insensitive)
+func (r *RankRepo) FindCheckride(ctx context.Context, vaID string, userID string, name string) (*gorm.PilotCheckride, error) {
+	var checkride gorm.PilotCheckride
+
+	err := r.db.WithContext(ctx).
+		Where("va_id = ? AND user_id = ? AND LOWER(name) = LOWER(?)", vaID, userID, name).
+		First(&checkride).Error
+
+	if err != nil {
+		if err == gormlib.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &checkride, nil
+}
+
+// ListCheckrides returns the checkrides a pilot has passed in a VA
+func (r *RankRepo) ListCheckrides(ctx context.Context, vaID string, userID string) ([]gorm.PilotCheckride, error) {
+	var checkrides []gorm.PilotCheckride
+
+	err := r.db.WithContext(ctx).
+		Where("va_id = ? AND user_id = ?", vaID, userID).
+		Order("passed_at ASC").
+		Find(&checkrides).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return checkrides, nil
+}
+
+// ListCheckridesByVA returns every passed checkride in a VA grouped by user ID
+func (r *RankRepo) ListCheckridesByVA(ctx context.Context, vaID string) (map[string][]gorm.PilotCheckride, error) {
+	var checkrides []gorm.PilotCheckride
+
+	err := r.db.WithContext(ctx).
+		Where("va_id = ?", vaID).
+		Order("passed_at ASC").
+		Find(&checkrides).Error
+	if err != nil {
+		return nil, err
+	}
+
+	byUser := make(map[string][]gorm.PilotCheckride)
+	for _, c := range checkrides {
+		byUser[c.UserID] = append(byUser[c.UserID], c)
+	}
+	return byUser, nil
+}
+
+// Promote moves a pilot to a new rank and records the promotion event in a single transaction.
+// It reports false and changes nothing when the promotion's PIREP has already earned a
+// promotion, or when the pilot no longer holds promotion.FromRankID.
+func (r *RankRepo) Promote(ctx context.Context, promotion *gorm.RankPromotion) (bool, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gormlib.DB) error {
+		// The unique index on pirep_id skips a second promotion for the same PIREP
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(promotion)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errPromotionSkipped
+		}
+
+		query := tx.Model(&gorm.UserVARole{}).
+			Where("va_id = ? AND user_id = ?", promotion.VAID, promotion.UserID)
+		if promotion.FromRankID == nil {
+			query = query.Where("rank_id IS NULL")
+		} else {
+			query = query.Where("rank_id = ?", *promotion.FromRankID)
+		}
+		result = query.Updates(map[string]interface{}{
+			"rank_id":          promotion.ToRankID,
+			"rank_achieved_at": time.Now(),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errPromotionSkipped
+		}
+		return nil
+	})
+	if errors.Is(err, errPromotionSkipped) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// ListPromotions returns a VA's most recent promotion events, newest first
+func (r *RankRepo) ListPromotions(ctx context.Context, vaID string, limit int) ([]gorm.RankPromotion, error) {
+	var promotions []gorm.RankPromotion
+
+	query := r.db.WithContext(ctx).
+		Preload("User").
+		Preload("FromRank").
+		Preload("ToRank").
+		Where("va_id = ?", vaID).
+		Order("created_at DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if err := query.Find(&promotions).Error; err != nil {
+		return nil, err
+	}
+
+	return promotions, nil
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"infinite-experiment/politburo/internal/models/gorm"
 
@@ -19,7 +20,7 @@ func NewRouteATSyncedRepo(db *gormlib.DB) *RouteATSyncedRepo {
 	return &RouteATSyncedRepo{db: db}
 }
 
-// Upsert inserts or updates a route record from Airtable
+// Upsert inserts or updates a route record from Airtable, restoring it if it was soft-deleted
 // ON CONFLICT (server_id, at_id) DO UPDATE
 func (r *RouteATSyncedRepo) Upsert(ctx context.Context, route *gorm.RouteATSynced) error {
 	return r.db.WithContext(ctx).
@@ -28,7 +29,12 @@ func (r *RouteATSyncedRepo) Upsert(ctx context.Context, route *gorm.RouteATSynce
 				{Name: "server_id"},
 				{Name: "at_id"},
 			},
-			DoUpdates: clause.AssignmentColumns([]string{"origin", "destination", "route", "updated_at"}),
+			DoUpdates: clause.AssignmentColumns([]string{
+				"origin", "destination", "route",
+				"origin_lat", "origin_lon", "destination_lat", "destination_lon",
+				"great_circle_nm", "haul", "estimated_block_minutes",
+				"updated_at", "deleted_at",
+			}),
 		}).
 		Create(route).Error
 }
@@ -96,3 +102,119 @@ func (r *RouteATSyncedRepo) FindByName(ctx context.Context, vaID string, routeNa
 
 	return &route, nil
 }
+
+// activeOn restricts a query to routes that are enabled and inside their active dates on the given day
+func activeOn(db *gormlib.DB, on time.Time) *gormlib.DB {
+	day := on.Format("2006-01-02")
+	return db.Where("is_active = ?", true).
+		Where("(active_from IS NULL OR active_from <= ?)", day).
+		Where("(active_until IS NULL OR active_until >= ?)", day)
+}
+
+// GetActiveByVA returns the routes pilots can fly on the given day (enabled and within active dates)
+func (r *RouteATSyncedRepo) GetActiveByVA(ctx context.Context, vaID string, on time.Time) ([]gorm.RouteATSynced, error) {
+	var routes []gorm.RouteATSynced
+
+	err := activeOn(r.db.WithContext(ctx), on).
+		Where("server_id = ?", vaID).
+		Order("origin ASC, destination ASC").
+		Find(&routes).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return routes, nil
+}
+
+// FindActiveByName finds a route by name (case-insensitive) that is flyable on the given day
+func (r *RouteATSyncedRepo) FindActiveByName(ctx context.Context, vaID string, routeName string, on time.Time) (*gorm.RouteATSynced, error) {
+	var route gorm.RouteATSynced
+
+	err := activeOn(r.db.WithContext(ctx), on).
+		Where("server_id = ? AND LOWER(route) = LOWER(?)", vaID, routeName).
+		First(&route).Error
+
+	if err != nil {
+		if err == gormlib.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &route, nil
+}
+
+// FindByID finds a route by VA ID and route ID
+func (r *RouteATSyncedRepo) FindByID(ctx context.Context, vaID string, routeID string) (*gorm.RouteATSynced, error) {
+	var route gorm.RouteATSynced
+
+	err := r.db.WithContext(ctx).
+		Where("server_id = ? AND id = ?", vaID, routeID).
+		First(&route).Error
+
+	if err != nil {
+		if err == gormlib.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &route, nil
+}
+
+// Create inserts a new route
+func (r *RouteATSyncedRepo) Create(ctx context.Context, route *gorm.RouteATSynced) error {
+	return r.db.WithContext(ctx).Create(route).Error
+}
+
+// Save updates all columns of an existing route
+func (r *RouteATSyncedRepo) Save(ctx context.Context, route *gorm.RouteATSynced) error {
+	route.UpdatedAt = time.Now()
+	return r.db.WithContext(ctx).Save(route).Error
+}
+
+// SaveAll creates or updates a batch of routes in a single transaction.
+// Routes with an empty ID are inserted, the rest are updated in place.
+func (r *RouteATSyncedRepo) SaveAll(ctx context.Context, routes []*gorm.RouteATSynced) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gormlib.DB) error {
+		for _, route := range routes {
+			if route.ID == "" {
+				if err := tx.Create(route).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			route.UpdatedAt = time.Now()
+			if err := tx.Save(route).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Delete removes a route by VA ID and route ID
+func (r *RouteATSyncedRepo) Delete(ctx context.Context, vaID string, routeID string) error {
+	return r.db.WithContext(ctx).
+		Unscoped().
+		Where("server_id = ? AND id = ?", vaID, routeID).
+		Delete(&gorm.RouteATSynced{}).Error
+}
+
+// SoftDeleteMissing soft-deletes a VA's synced routes whose Airtable ID is not in atIDs.
+// Native routes are not affected. Returns the number of routes deleted.
+func (r *RouteATSyncedRepo) SoftDeleteMissing(ctx context.Context, vaID string, atIDs []string) (int64, error) {
+	return softDeleteMissing(r.db.WithContext(ctx), &gorm.RouteATSynced{}, vaID, atIDs)
+}
+
+// CountByATIDs counts the VA's stored routes among atIDs, including soft-deleted ones
+func (r *RouteATSyncedRepo) CountByATIDs(ctx context.Context, vaID string, atIDs []string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Model(&gorm.RouteATSynced{}).
+		Where("server_id = ? AND at_id IN ?", vaID, atIDs).
+		Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"context"
+
+	"infinite-experiment/politburo/internal/models/gorm"
+
+	gormlib "gorm.io/gorm"
+)
+
+// SyncRunRepo handles sync_runs table operations
+type SyncRunRepo struct {
+	db *gormlib.DB
+}
+
+// NewSyncRunRepo creates a new sync run repository
+func NewSyncRunRepo(db *gormlib.DB) *SyncRunRepo {
+	return &SyncRunRepo{db: db}
+}
+
+// Create inserts a finished sync run
+func (r *SyncRunRepo) Create(ctx context.Context, run *gorm.SyncRun) error {
+	return r.db.WithContext(ctx).Create(run).Error
+}
+
+// ListByVA returns a VA's sync runs, newest first, optionally filtered by job and entity type
+func (r *SyncRunRepo) ListByVA(ctx context.Context, vaID string, job string, entityType string, limit int) ([]gorm.SyncRun, error) {
+	var runs []gorm.SyncRun
+
+	query := r.db.WithContext(ctx).
+		Where("va_id = ?", vaID).
+		Order("started_at DESC")
+
+	if job != "" {
+		query = query.Where("job = ?", job)
+	}
+	if entityType != "" {
+		query = query.Where("entity_type = ?", entityType)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if err := query.Find(&runs).Error; err != nil {
+		return nil, err
+	}
+
+	return runs, nil
+}
+
+// LatestByJob returns the most recent run of each job across all VAs
+func (r *SyncRunRepo) LatestByJob(ctx context.Context) ([]gorm.SyncRun, error) {
+	var runs []gorm.SyncRun
+
+	err := r.db.WithContext(ctx).
+		Raw("SELECT DISTINCT ON (job) * FROM sync_runs ORDER BY job, started_at DESC").
+		Scan(&runs).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return runs, nil
+}
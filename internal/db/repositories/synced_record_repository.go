@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"infinite-experiment/politburo/internal/models/gorm"
+
+	gormlib "gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SyncedRecordRepo handles va_synced_records table operations
+type SyncedRecordRepo struct {
+	db *gormlib.DB
+}
+
+// NewSyncedRecordRepo creates a new synced record repository
+func NewSyncedRecordRepo(db *gormlib.DB) *SyncedRecordRepo {
+	return &SyncedRecordRepo{db: db}
+}
+
+// Upsert inserts or updates a raw provider record
+// ON CONFLICT (va_id, entity_type, record_id) DO UPDATE
+func (r *SyncedRecordRepo) Upsert(ctx context.Context, record *gorm.SyncedRecord) error {
+	record.UpdatedAt = time.Now()
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{
+				{Name: "va_id"},
+				{Name: "entity_type"},
+				{Name: "record_id"},
+			},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"provider_type", "fields", "provider_created_at", "updated_at",
+			}),
+		}).
+		Create(record).Error
+}
+
+// ListByEntity returns a VA's records of one entity type, most recently updated first
+func (r *SyncedRecordRepo) ListByEntity(ctx context.Context, vaID, entityType string, limit int) ([]gorm.SyncedRecord, error) {
+	var records []gorm.SyncedRecord
+
+	err := r.db.WithContext(ctx).
+		Where("va_id = ? AND entity_type = ?", vaID, entityType).
+		Order("updated_at DESC").
+		Limit(limit).
+		Find(&records).Error
+
+	return records, err
+}
+
+// CountByRecordIDs counts the VA's stored records of an entity type among recordIDs
+func (r *SyncedRecordRepo) CountByRecordIDs(ctx context.Context, vaID, entityType string, recordIDs []string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&gorm.SyncedRecord{}).
+		Where("va_id = ? AND entity_type = ? AND record_id IN ?", vaID, entityType, recordIDs).
+		Count(&count).Error
+	return count, err
+}
@@ -96,6 +96,17 @@ func (r *VARepository) GetVAConfigs(ctx context.Context, vaID string) (*[]entiti
 	return &configs, nil
 }
 
+// GetVAConfigsByKey returns the values of one config key across every VA
+func (r *VARepository) GetVAConfigsByKey(ctx context.Context, key string) ([]entities.VAConfig, error) {
+	var configs []entities.VAConfig
+
+	if err := r.db.SelectContext(ctx, &configs, constants.GetVAConfigsByKey, key); err != nil {
+		return nil, err
+	}
+
+	return configs, nil
+}
+
 func (r *VARepository) UpsertVAConfig(ctx context.Context, vaID, key, value string) error {
 	_, err := r.db.ExecContext(ctx, constants.UpsertVAConfig, vaID, key, value)
 	return err
@@ -40,6 +40,36 @@ func (r *VASyncHistoryRepo) RecordSync(ctx context.Context, vaID string, event s
 	return err
 }
 
+// SyncCounts are the record counts of a sync run
+type SyncCounts struct {
+	Fetched int
+	Stored  int
+	Failed  int
+	Deleted int
+}
+
+// RecordReconciliation records a completed reconciliation for a VA together with its counts
+func (r *VASyncHistoryRepo) RecordReconciliation(ctx context.Context, vaID string, event string, counts SyncCounts) error {
+	now := time.Now()
+
+	syncHistory := gorm.VASyncHistory{
+		VAID:       vaID,
+		Event:      event,
+		LastSyncAt: &now,
+	}
+
+	return r.db.WithContext(ctx).
+		Where("va_id = ? AND event = ?", vaID, event).
+		Assign(gorm.VASyncHistory{
+			LastSyncAt:     &now,
+			RecordsFetched: &counts.Fetched,
+			RecordsStored:  &counts.Stored,
+			RecordsFailed:  &counts.Failed,
+			RecordsDeleted: &counts.Deleted,
+		}).
+		FirstOrCreate(&syncHistory).Error
+}
+
 // GetLastSyncTimeForEvent retrieves the most recent sync timestamp across all VAs for a specific event
 // Used to check if we should run initial sync on app restart
 func (r *VASyncHistoryRepo) GetLastSyncTimeForEvent(ctx context.Context, event string) (*time.Time, error) {
@@ -59,3 +89,22 @@ func (r *VASyncHistoryRepo) GetLastSyncTimeForEvent(ctx context.Context, event s
 
 	return syncHistory.LastSyncAt, nil
 }
+
+// GetLastSyncTime retrieves a VA's most recent sync timestamp for a specific event
+// Used as the modified-since cutoff for incremental syncs
+func (r *VASyncHistoryRepo) GetLastSyncTime(ctx context.Context, vaID string, event string) (*time.Time, error) {
+	var syncHistory gorm.VASyncHistory
+
+	err := r.db.WithContext(ctx).
+		Where("va_id = ? AND event = ?", vaID, event).
+		First(&syncHistory).Error
+
+	if err != nil {
+		if err == gormlib.ErrRecordNotFound {
+			return nil, nil // No sync history found
+		}
+		return nil, err
+	}
+
+	return syncHistory.LastSyncAt, nil
+}
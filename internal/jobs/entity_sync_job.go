@@ -0,0 +1,79 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"infinite-experiment/politburo/internal/datasync"
+	"log"
+	"time"
+)
+
+// EntitySyncJob syncs the entity types without a dedicated job (career mode and custom types)
+// from each VA's data provider into va_synced_records
+type EntitySyncJob struct {
+	engine  *datasync.Engine
+	tracker *Tracker
+}
+
+// NewEntitySyncJob creates a new entity sync job instance
+func NewEntitySyncJob(engine *datasync.Engine, tracker *Tracker) *EntitySyncJob {
+	return &EntitySyncJob{engine: engine, tracker: tracker}
+}
+
+// Run executes the entity sync job for all VAs with an active data provider
+func (j *EntitySyncJob) Run(ctx context.Context) error {
+	start := time.Now()
+
+	vaIDs, err := j.engine.ActiveVAIDs(ctx)
+	if err != nil {
+		log.Printf("[EntitySyncJob] Error fetching active VAs: %v", err)
+		return fmt.Errorf("failed to fetch active VAs: %w", err)
+	}
+
+	totalSynced := 0
+	for _, vaID := range vaIDs {
+		synced, err := j.SyncVAEntities(ctx, vaID)
+		if err != nil {
+			log.Printf("[EntitySyncJob] Error syncing entities for VA %s: %v", vaID, err)
+			// Continue with other VAs even if one fails
+			continue
+		}
+		totalSynced += synced
+	}
+
+	if totalSynced > 0 {
+		log.Printf("[EntitySyncJob] Completed entity sync in %s. Total records synced: %d",
+			time.Since(start).Truncate(time.Millisecond), totalSynced)
+	}
+
+	return nil
+}
+
+// SyncVAEntities syncs the career mode and custom entity types of a specific VA (exported for manual triggering)
+func (j *EntitySyncJob) SyncVAEntities(ctx context.Context, vaID string) (int, error) {
+	entityTypes, err := j.engine.CustomEntityTypes(ctx, vaID)
+	if err != nil {
+		return 0, err
+	}
+
+	synced := 0
+	for _, entityType := range entityTypes {
+		started := time.Now()
+		result, err := j.engine.SyncEntity(ctx, vaID, entityType)
+		j.tracker.RecordSync(ctx, JobEntitySync, vaID, entityType, started, result, err)
+		if err != nil {
+			log.Printf("[EntitySyncJob] Error syncing %s records for VA %s: %v", entityType, vaID, err)
+			// Continue with other entity types even if one fails
+			continue
+		}
+		synced += result.Stored
+	}
+
+	return synced, nil
+}
+
+// RunVA syncs a VA's entities (the scheduled unit of work)
+func (j *EntitySyncJob) RunVA(ctx context.Context, vaID string) error {
+	_, err := j.SyncVAEntities(ctx, vaID)
+	return err
+}
@@ -3,9 +3,13 @@ package jobs
 import (
 	"context"
 	"infinite-experiment/politburo/internal/common"
+	"infinite-experiment/politburo/internal/datasync"
 	"infinite-experiment/politburo/internal/db/repositories"
+	"infinite-experiment/politburo/internal/providers"
+	"infinite-experiment/politburo/internal/services"
 	"infinite-experiment/politburo/internal/workers"
-	"time"
+	"log"
+	"sync"
 
 	"gorm.io/gorm"
 )
@@ -15,12 +19,19 @@ type JobsContainer struct {
 	PilotSync     *PilotSyncJob
 	RouteSync     *RouteSyncJob
 	PirepSync     *PirepSyncJob
+	EntitySync    *EntitySyncJob
+	Reconcile     *ReconcileJob
 	PIREPBackfill *workers.PIREPBackfill
+	PirepOutbox   *PirepOutboxJob
+	PilotChanges  *PilotChangeSyncJob
+	Scheduler     *Scheduler
 }
 
-// InitializeJobs initializes and starts all background jobs
+// InitializeJobs initializes and starts all background jobs. They run until ctx is
+// cancelled and are added to background so shutdown can wait for the runs in progress.
 func InitializeJobs(
 	ctx context.Context,
+	background *sync.WaitGroup,
 	db *gorm.DB,
 	cache common.CacheInterface,
 	configRepo *repositories.DataProviderConfigRepo,
@@ -28,41 +39,65 @@ func InitializeJobs(
 	pilotATSyncedRepo *repositories.PilotATSyncedRepo,
 	routeATSyncedRepo *repositories.RouteATSyncedRepo,
 	pirepATSyncedRepo *repositories.PirepATSyncedRepo,
+	syncedRecordRepo *repositories.SyncedRecordRepo,
+	jobScheduleRepo *repositories.JobScheduleRepo,
+	pilotSyncChangeRepo *repositories.PilotSyncChangeRepo,
 	airportIcaoRepo *repositories.AirportRepository,
 	vaConfigService *common.VAConfigService,
 	redisQueue *common.RedisQueueService,
+	providerRegistry *providers.Registry,
+	pirepDeliverySvc *services.PirepDeliveryService,
+	pilotSyncSvc *services.PilotSyncService,
+	metaCache *workers.MetaCacheWorker,
+	tracker *Tracker,
 ) *JobsContainer {
-	// Initialize pilot sync job (syncs pilots from Airtable every hour)
-	pilotSyncJob := NewPilotSyncJob(
-		db,
-		cache,
-		configRepo,
-		syncHistoryRepo,
-		pilotATSyncedRepo,
-		vaConfigService,
-	)
+	tracker.Register(JobPilotSync, "Syncs pilots from each VA's data provider", "*/10 * * * *")
+	tracker.Register(JobPilotLinking, "Links synced pilots to registered users", "*/10 * * * *")
+	tracker.Register(JobRouteSync, "Syncs routes from each VA's data provider", "*/10 * * * *")
+	tracker.Register(JobPirepSync, "Syncs PIREPs from each VA's data provider", "*/10 * * * *")
+	tracker.Register(JobEntitySync, "Syncs career mode and custom entity types", "*/10 * * * *")
+	tracker.Register(JobReconcile, "Fully resyncs pilots, routes and PIREPs and removes deleted records", "0 3 * * *")
+	tracker.Register(JobPirepBackfill, "Backfills missing pilot and route data on synced PIREPs", "*/10 * * * *")
+	tracker.Register(JobPirepOutbox, "Retries failed PIREP deliveries to data providers", "@every 30s")
+	tracker.Register(JobPilotChanges, "Pushes dashboard pilot changes to data providers", "@every 1m")
+	tracker.Register(JobLiverySync, "Refreshes the world status and syncs aircraft and liveries from the Infinite Flight API", "@every 6h")
+	tracker.Register(JobAirportReload, "Reloads the airport database", "")
 
-	// Initialize route sync job (syncs routes from Airtable every hour)
-	routeSyncJob := NewRouteSyncJob(
-		db,
-		cache,
+	// Initialize the sync engine with a handler per built-in entity type;
+	// career mode and custom entity types are stored as raw records
+	engine := datasync.NewEngine(
 		configRepo,
 		syncHistoryRepo,
-		routeATSyncedRepo,
-		airportIcaoRepo,
+		providerRegistry,
+		datasync.NewRecordStoreHandler(syncedRecordRepo),
 	)
+	engine.Register("pilot", datasync.NewPilotHandler(db, cache, pilotATSyncedRepo, pilotSyncChangeRepo))
+	engine.Register("route", datasync.NewRouteHandler(routeATSyncedRepo, airportIcaoRepo))
+	engine.Register("pirep", datasync.NewPirepHandler(pirepATSyncedRepo, redisQueue))
 
-	// Initialize PIREP sync job (syncs PIREPs from Airtable every hour)
-	pirepSyncJob := NewPirepSyncJob(
+	// Initialize pilot sync job (syncs pilots from the VA's data provider)
+	pilotSyncJob := NewPilotSyncJob(
 		db,
-		cache,
-		configRepo,
+		engine,
 		syncHistoryRepo,
-		pirepATSyncedRepo,
-		redisQueue,
+		pilotATSyncedRepo,
+		vaConfigService,
+		tracker,
 	)
 
-	// Initialize PIREP backfill job (backfills missing pilot/route data every 15 minutes)
+	// Initialize route sync job (syncs routes from the VA's data provider)
+	routeSyncJob := NewRouteSyncJob(engine, tracker)
+
+	// Initialize PIREP sync job (syncs PIREPs from the VA's data provider)
+	pirepSyncJob := NewPirepSyncJob(engine, syncHistoryRepo, tracker)
+
+	// Initialize entity sync job (syncs career mode and custom entity types)
+	entitySyncJob := NewEntitySyncJob(engine, tracker)
+
+	// Initialize reconciliation job (full resync of pilots, routes and PIREPs)
+	reconcileJob := NewReconcileJob(engine, tracker)
+
+	// Initialize PIREP backfill job (backfills missing pilot/route data)
 	pirepBackfillJob := workers.NewPIREPBackfill(
 		db,
 		cache,
@@ -70,16 +105,61 @@ func InitializeJobs(
 		*pilotATSyncedRepo,
 	)
 
-	// Start scheduled sync jobs in background (all run every hour)
-	go pilotSyncJob.RunScheduled(ctx, 10*time.Minute)
-	go routeSyncJob.RunScheduled(ctx, 10*time.Minute)
-	go pirepSyncJob.RunScheduled(ctx, 10*time.Minute)
-	go pirepBackfillJob.RunScheduled(ctx, 10*time.Minute)
+	// Initialize PIREP outbox job (retries failed PIREP deliveries to data providers)
+	pirepOutboxJob := NewPirepOutboxJob(pirepDeliverySvc)
+
+	// Initialize pilot change sync job (pushes dashboard pilot changes to data providers)
+	pilotChangeSyncJob := NewPilotChangeSyncJob(pilotSyncSvc)
+
+	// Schedule the jobs. Per-VA jobs run separately for each VA with an active data
+	// provider and can be rescheduled by the VA; only one replica runs each due job.
+	scheduler := NewScheduler(jobScheduleRepo, tracker, engine, vaConfigService)
+	scheduledJobs := []ScheduledJob{
+		{Name: JobPilotSync, Schedule: "*/10 * * * *", ConfigKey: common.ConfigKeySchedulePilotSync, RunOnStart: true, RunVA: pilotSyncJob.RunVA},
+		{Name: JobRouteSync, Schedule: "*/10 * * * *", ConfigKey: common.ConfigKeyScheduleRouteSync, RunOnStart: true, RunVA: routeSyncJob.RunVA},
+		{Name: JobPirepSync, Schedule: "*/10 * * * *", ConfigKey: common.ConfigKeySchedulePirepSync, RunOnStart: true, RunVA: pirepSyncJob.RunVA},
+		{Name: JobEntitySync, Schedule: "*/10 * * * *", ConfigKey: common.ConfigKeyScheduleEntitySync, RunOnStart: true, RunVA: entitySyncJob.RunVA},
+		{Name: JobReconcile, Schedule: "0 3 * * *", ConfigKey: common.ConfigKeyScheduleReconcile, RunVA: reconcileJob.RunVA},
+		{
+			Name:     JobPirepBackfill,
+			Schedule: "*/10 * * * *",
+			Run: func(ctx context.Context) error {
+				_, err := pirepBackfillJob.BackfillVAPireps(ctx, "", 100, 500)
+				return err
+			},
+		},
+		{Name: JobPirepOutbox, Schedule: "@every 30s", Run: pirepOutboxJob.Run},
+		{Name: JobPilotChanges, Schedule: "@every 1m", Run: pilotChangeSyncJob.Run},
+		{
+			Name:       JobLiverySync,
+			Schedule:   "@every 6h",
+			RunOnStart: true,
+			Run: func(ctx context.Context) error {
+				_, err := metaCache.Sync(ctx)
+				return err
+			},
+		},
+	}
+	for _, job := range scheduledJobs {
+		if err := scheduler.Add(job); err != nil {
+			log.Printf("[Jobs] Error scheduling job: %v", err)
+		}
+	}
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		scheduler.Start(ctx)
+	}()
 
 	return &JobsContainer{
 		PilotSync:     pilotSyncJob,
 		RouteSync:     routeSyncJob,
 		PirepSync:     pirepSyncJob,
+		EntitySync:    entitySyncJob,
+		Reconcile:     reconcileJob,
 		PIREPBackfill: pirepBackfillJob,
+		PirepOutbox:   pirepOutboxJob,
+		PilotChanges:  pilotChangeSyncJob,
+		Scheduler:     scheduler,
 	}
 }
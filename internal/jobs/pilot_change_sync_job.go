@@ -0,0 +1,35 @@
+package jobs
+
+import (
+	"context"
+	"log"
+
+	"infinite-experiment/politburo/internal/services"
+)
+
+// pilotChangeBatchSize is the number of pending pilot changes pushed per round
+const pilotChangeBatchSize = 50
+
+// PilotChangeSyncJob pushes pilot changes made in the dashboard to the VA's data provider
+type PilotChangeSyncJob struct {
+	pilotSyncService *services.PilotSyncService
+}
+
+// NewPilotChangeSyncJob creates a new pilot change sync job
+func NewPilotChangeSyncJob(pilotSyncService *services.PilotSyncService) *PilotChangeSyncJob {
+	return &PilotChangeSyncJob{pilotSyncService: pilotSyncService}
+}
+
+// Run pushes one batch of pending changes. Changes that fail stay pending until their
+// attempts run out, so draining further in the same run would retry them straight away.
+func (j *PilotChangeSyncJob) Run(ctx context.Context) error {
+	processed, err := j.pilotSyncService.PushPending(ctx, pilotChangeBatchSize)
+	if err != nil {
+		return err
+	}
+
+	if processed > 0 {
+		log.Printf("[PilotChangeSyncJob] Processed %d pilot changes", processed)
+	}
+	return nil
+}
@@ -38,11 +38,11 @@ func (j *PilotLinkingJob) Run(ctx context.Context) error {
 	start := time.Now()
 	log.Printf("[PilotLinkingJob] Starting pilot linking at %s", start.Format(time.RFC3339))
 
-	// Get all VAs that have active Airtable configs
+	// Get all VAs that have an active data provider config
 	var vaIDs []string
 	err := j.db.WithContext(ctx).
 		Table("va_data_provider_configs").
-		Where("provider_type = ? AND is_active = ?", "airtable", true).
+		Where("is_active = ?", true).
 		Pluck("va_id", &vaIDs).Error
 
 	if err != nil {
@@ -51,11 +51,11 @@ func (j *PilotLinkingJob) Run(ctx context.Context) error {
 	}
 
 	if len(vaIDs) == 0 {
-		log.Printf("[PilotLinkingJob] No VAs with active Airtable configs found")
+		log.Printf("[PilotLinkingJob] No VAs with active data provider configs found")
 		return nil
 	}
 
-	log.Printf("[PilotLinkingJob] Found %d VAs with active Airtable configs", len(vaIDs))
+	log.Printf("[PilotLinkingJob] Found %d VAs with active data provider configs", len(vaIDs))
 
 	// Link pilots for each VA
 	totalLinked := 0
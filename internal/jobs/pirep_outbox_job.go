@@ -0,0 +1,42 @@
+package jobs
+
+import (
+	"context"
+	"log"
+
+	"infinite-experiment/politburo/internal/services"
+)
+
+// outboxBatchSize is the number of outbox entries claimed per delivery round
+const outboxBatchSize = 50
+
+// PirepOutboxJob retries delivery of native PIREPs to data providers from the pirep_outbox table
+type PirepOutboxJob struct {
+	deliveryService *services.PirepDeliveryService
+}
+
+// NewPirepOutboxJob creates a new PIREP outbox job
+func NewPirepOutboxJob(deliveryService *services.PirepDeliveryService) *PirepOutboxJob {
+	return &PirepOutboxJob{deliveryService: deliveryService}
+}
+
+// Run drains all due outbox entries in batches
+func (j *PirepOutboxJob) Run(ctx context.Context) error {
+	total := 0
+	for {
+		claimed, err := j.deliveryService.ProcessDue(ctx, outboxBatchSize)
+		if err != nil {
+			return err
+		}
+		total += claimed
+
+		if claimed < outboxBatchSize || ctx.Err() != nil {
+			break
+		}
+	}
+
+	if total > 0 {
+		log.Printf("[PirepOutboxJob] Processed %d outbox entries", total)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"infinite-experiment/politburo/internal/datasync"
+	"log"
+	"time"
+)
+
+// reconciledEntityTypes are the entity types whose synced tables are reconciled
+var reconciledEntityTypes = []string{"pilot", "route", "pirep"}
+
+// ReconcileJob periodically re-fetches every pilot, route and PIREP record of each VA,
+// repairing records the incremental syncs missed and soft-deleting those removed from the provider
+type ReconcileJob struct {
+	engine  *datasync.Engine
+	tracker *Tracker
+}
+
+// NewReconcileJob creates a new reconciliation job instance
+func NewReconcileJob(engine *datasync.Engine, tracker *Tracker) *ReconcileJob {
+	return &ReconcileJob{engine: engine, tracker: tracker}
+}
+
+// Run reconciles every entity type of every VA with an active data provider
+func (j *ReconcileJob) Run(ctx context.Context) error {
+	start := time.Now()
+
+	vaIDs, err := j.engine.ActiveVAIDs(ctx)
+	if err != nil {
+		log.Printf("[ReconcileJob] Error fetching active VAs: %v", err)
+		return fmt.Errorf("failed to fetch active VAs: %w", err)
+	}
+
+	totalDeleted := 0
+	for _, vaID := range vaIDs {
+		deleted, err := j.ReconcileVA(ctx, vaID)
+		if err != nil {
+			return err
+		}
+		totalDeleted += deleted
+	}
+
+	log.Printf("[ReconcileJob] Completed reconciliation of %d VAs in %s. Total records deleted: %d",
+		len(vaIDs), time.Since(start).Truncate(time.Millisecond), totalDeleted)
+
+	return nil
+}
+
+// ReconcileVA reconciles every entity type of a specific VA (exported for manual triggering)
+// and returns the number of records deleted. Only cancellation is returned as an error.
+func (j *ReconcileJob) ReconcileVA(ctx context.Context, vaID string) (int, error) {
+	deleted := 0
+	for _, entityType := range reconciledEntityTypes {
+		if ctx.Err() != nil {
+			return deleted, ctx.Err()
+		}
+
+		started := time.Now()
+		result, err := j.engine.ReconcileEntity(ctx, vaID, entityType)
+		j.tracker.RecordSync(ctx, JobReconcile, vaID, entityType, started, result, err)
+		if err != nil {
+			log.Printf("[ReconcileJob] Error reconciling %s records for VA %s: %v", entityType, vaID, err)
+			// Continue with other entity types even if one fails
+			continue
+		}
+		deleted += result.Deleted
+	}
+
+	return deleted, nil
+}
+
+// RunVA reconciles a VA's records (the scheduled unit of work)
+func (j *ReconcileJob) RunVA(ctx context.Context, vaID string) error {
+	_, err := j.ReconcileVA(ctx, vaID)
+	return err
+}
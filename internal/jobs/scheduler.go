@@ -0,0 +1,365 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"infinite-experiment/politburo/internal/common"
+	"infinite-experiment/politburo/internal/datasync"
+	"infinite-experiment/politburo/internal/db/repositories"
+	"infinite-experiment/politburo/internal/models/gorm"
+	"log"
+	"math/rand/v2"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// schedulerPollInterval is how often the scheduler looks for due jobs
+	schedulerPollInterval = 10 * time.Second
+
+	// schedulerLockRetry is how long to wait before looking at a job again when another
+	// replica holds its lock
+	schedulerLockRetry = 30 * time.Second
+
+	// schedulerMaxJitter caps the random delay before a due run; a run is never delayed
+	// by more than a tenth of its schedule's period
+	schedulerMaxJitter = 30 * time.Second
+
+	// schedulerMaxConcurrent is the number of job runs one replica performs at once
+	schedulerMaxConcurrent = 4
+
+	// activeVAsTTL is how long the list of VAs with an active data provider is reused
+	activeVAsTTL = time.Minute
+)
+
+// ScheduledJob is a background job run by the Scheduler. Jobs with RunVA run separately
+// for each VA with an active data provider, on a schedule the VA can override through
+// ConfigKey; jobs with Run run once for everything.
+type ScheduledJob struct {
+	Name       string
+	Schedule   string // Default cron expression or "@every <duration>"
+	ConfigKey  string // VA config key overriding Schedule per VA
+	RunOnStart bool   // Run as soon as a scope is first seen instead of waiting for the schedule
+	RunVA      func(ctx context.Context, vaID string) error
+	Run        func(ctx context.Context) error
+}
+
+// JobScheduleStore holds the schedule state shared by the replicas and the advisory lock
+// of each job scope; it is implemented by repositories.JobScheduleRepo
+type JobScheduleStore interface {
+	Find(ctx context.Context, job string, scope string) (*gorm.JobSchedule, error)
+	Ensure(ctx context.Context, job string, scope string, schedule string, nextRunAt time.Time) (*gorm.JobSchedule, error)
+	Reschedule(ctx context.Context, job string, scope string, schedule string, nextRunAt time.Time) error
+	MarkStarted(ctx context.Context, job string, scope string, instance string, startedAt time.Time) error
+	MarkFinished(ctx context.Context, job string, scope string, finishedAt time.Time, nextRunAt time.Time, lastError *string) error
+	TryLock(ctx context.Context, job string, scope string) (func(), bool, error)
+}
+
+var _ JobScheduleStore = (*repositories.JobScheduleRepo)(nil)
+
+// slotState is this replica's view of one job scope
+type slotState struct {
+	running bool
+	nextRun time.Time // Zero until read from job_schedules
+}
+
+// Scheduler runs the background jobs on cron schedules. Every replica runs a scheduler;
+// the shared next run time in job_schedules and a Postgres advisory lock per job scope
+// make sure each due run happens on exactly one of them. Runs are delayed by a random
+// jitter so VAs on the same schedule don't all hit their data providers at once.
+type Scheduler struct {
+	repo     JobScheduleStore
+	tracker  *Tracker
+	engine   *datasync.Engine
+	vaConfig *common.VAConfigService
+	instance string
+
+	jobs []ScheduledJob
+	sem  chan struct{}
+	runs sync.WaitGroup // Runs in progress, waited for on shutdown
+
+	mu          sync.Mutex
+	slots       map[string]*slotState
+	schedules   map[string]*common.CronSchedule // Parsed schedules by expression
+	vaIDs       []string
+	vaIDsLoaded time.Time
+}
+
+// NewScheduler creates a job scheduler
+func NewScheduler(
+	repo JobScheduleStore,
+	tracker *Tracker,
+	engine *datasync.Engine,
+	vaConfig *common.VAConfigService,
+) *Scheduler {
+	hostname, _ := os.Hostname()
+	return &Scheduler{
+		repo:      repo,
+		tracker:   tracker,
+		engine:    engine,
+		vaConfig:  vaConfig,
+		instance:  fmt.Sprintf("%s/%d", hostname, os.Getpid()),
+		sem:       make(chan struct{}, schedulerMaxConcurrent),
+		slots:     make(map[string]*slotState),
+		schedules: make(map[string]*common.CronSchedule),
+	}
+}
+
+// Add registers a job; its default schedule must parse. Jobs must be added before Start.
+func (s *Scheduler) Add(job ScheduledJob) error {
+	if _, err := s.parse(job.Schedule); err != nil {
+		return fmt.Errorf("job %s: %w", job.Name, err)
+	}
+	s.jobs = append(s.jobs, job)
+	return nil
+}
+
+// Start dispatches due jobs until ctx is cancelled, then waits for the runs in progress.
+// Cancelling ctx cancels the runs, which stop at their next safe point.
+func (s *Scheduler) Start(ctx context.Context) {
+	log.Printf("[Scheduler] Starting on instance %s with %d jobs", s.instance, len(s.jobs))
+
+	ticker := time.NewTicker(schedulerPollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.dispatch(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			log.Printf("[Scheduler] Shutting down, waiting for runs in progress")
+			s.runs.Wait()
+			log.Printf("[Scheduler] All runs stopped")
+			return
+		}
+	}
+}
+
+// dispatch starts a run for every job scope whose next run time has passed
+func (s *Scheduler) dispatch(ctx context.Context) {
+	now := time.Now()
+
+	for _, job := range s.jobs {
+		scopes := []string{""}
+		if job.RunVA != nil {
+			vaIDs, err := s.activeVAIDs(ctx)
+			if err != nil {
+				log.Printf("[Scheduler] Error fetching active VAs: %v", err)
+				continue
+			}
+			scopes = vaIDs
+		}
+
+		for _, scope := range scopes {
+			expr := s.scheduleFor(ctx, job, scope)
+			if expr == common.ScheduleOff {
+				continue
+			}
+			schedule, err := s.parse(expr)
+			if err != nil {
+				log.Printf("[Scheduler] VA %s: Invalid %s schedule %q, using default: %v", scope, job.Name, expr, err)
+				expr = job.Schedule
+				schedule, _ = s.parse(expr)
+			}
+
+			s.mu.Lock()
+			slot, ok := s.slots[job.Name+":"+scope]
+			if !ok {
+				slot = &slotState{}
+				s.slots[job.Name+":"+scope] = slot
+			}
+			due := !slot.running && !now.Before(slot.nextRun)
+			if due {
+				slot.running = true
+			}
+			s.mu.Unlock()
+
+			if due {
+				s.runs.Add(1)
+				go func() {
+					defer s.runs.Done()
+					s.runSlot(ctx, job, scope, expr, schedule, slot)
+				}()
+			}
+		}
+	}
+}
+
+// runSlot runs a job scope if it is still due once the shared state and lock are checked
+func (s *Scheduler) runSlot(ctx context.Context, job ScheduledJob, scope string, expr string, schedule *common.CronSchedule, slot *slotState) {
+	nextCheck := time.Now().Add(schedulerLockRetry)
+	defer func() {
+		s.mu.Lock()
+		slot.running = false
+		slot.nextRun = nextCheck
+		s.mu.Unlock()
+	}()
+
+	row, err := s.syncSchedule(ctx, job, scope, expr, schedule)
+	if err != nil {
+		log.Printf("[Scheduler] Error loading %s schedule of scope %q: %v", job.Name, scope, err)
+		return
+	}
+	if time.Now().Before(row.NextRunAt) {
+		nextCheck = row.NextRunAt
+		s.tracker.SetNextRun(job.Name, row.NextRunAt)
+		return
+	}
+
+	// Spread out runs that fall due together, on this replica and across replicas
+	if jitter := jitterFor(schedule, row.NextRunAt); jitter > 0 {
+		select {
+		case <-time.After(time.Duration(rand.Int64N(int64(jitter)))):
+		case <-ctx.Done():
+			return
+		}
+	}
+
+	select {
+	case s.sem <- struct{}{}:
+		defer func() { <-s.sem }()
+	case <-ctx.Done():
+		return
+	}
+
+	unlock, locked, err := s.repo.TryLock(ctx, job.Name, scope)
+	if err != nil {
+		log.Printf("[Scheduler] Error locking %s of scope %q: %v", job.Name, scope, err)
+		return
+	}
+	if !locked {
+		// Another replica is running it and will move next_run_at on
+		return
+	}
+	defer unlock()
+
+	// Another replica may have finished the run between our read and taking the lock
+	row, err = s.repo.Find(ctx, job.Name, scope)
+	if err != nil || row == nil {
+		log.Printf("[Scheduler] Error re-reading %s schedule of scope %q: %v", job.Name, scope, err)
+		return
+	}
+	if time.Now().Before(row.NextRunAt) {
+		nextCheck = row.NextRunAt
+		return
+	}
+
+	started := time.Now()
+	if err := s.repo.MarkStarted(ctx, job.Name, scope, s.instance, started); err != nil {
+		log.Printf("[Scheduler] Warning - failed to record start of %s for scope %q: %v", job.Name, scope, err)
+	}
+
+	runErr := s.tracker.Track(ctx, job.Name, func(ctx context.Context) error {
+		if job.RunVA != nil {
+			return job.RunVA(ctx, scope)
+		}
+		return job.Run(ctx)
+	})
+	if runErr != nil {
+		log.Printf("[Scheduler] %s of scope %q failed: %v", job.Name, scope, runErr)
+	}
+
+	finished := time.Now()
+	next := schedule.Next(finished)
+	var lastError *string
+	if runErr != nil {
+		message := runErr.Error()
+		lastError = &message
+	}
+	// Recorded even if the run was cut short by shutdown, so the next replica doesn't repeat it at once
+	if err := s.repo.MarkFinished(context.WithoutCancel(ctx), job.Name, scope, finished, next, lastError); err != nil {
+		log.Printf("[Scheduler] Warning - failed to record end of %s for scope %q: %v", job.Name, scope, err)
+	}
+
+	nextCheck = next
+	s.tracker.SetNextRun(job.Name, next)
+}
+
+// syncSchedule loads the shared state of a job scope, creating it on first sight and
+// recomputing the next run when the schedule has changed
+func (s *Scheduler) syncSchedule(ctx context.Context, job ScheduledJob, scope string, expr string, schedule *common.CronSchedule) (*gorm.JobSchedule, error) {
+	now := time.Now()
+	first := schedule.Next(now)
+	if job.RunOnStart {
+		first = now
+	}
+
+	row, err := s.repo.Ensure(ctx, job.Name, scope, expr, first)
+	if err != nil {
+		return nil, err
+	}
+	if row == nil {
+		return nil, fmt.Errorf("schedule row not found after insert")
+	}
+
+	if row.Schedule != expr {
+		next := schedule.Next(now)
+		if err := s.repo.Reschedule(ctx, job.Name, scope, expr, next); err != nil {
+			return nil, err
+		}
+		log.Printf("[Scheduler] %s of scope %q rescheduled to %q, next run at %s", job.Name, scope, expr, next.Format(time.RFC3339))
+		row.Schedule = expr
+		row.NextRunAt = next
+	}
+
+	return row, nil
+}
+
+// scheduleFor returns the schedule of a job scope, applying the VA's override
+func (s *Scheduler) scheduleFor(ctx context.Context, job ScheduledJob, scope string) string {
+	if job.ConfigKey == "" || scope == "" {
+		return job.Schedule
+	}
+	if override, ok := s.vaConfig.GetConfigVal(ctx, scope, job.ConfigKey); ok && override != "" {
+		return override
+	}
+	return job.Schedule
+}
+
+// parse parses a schedule, reusing earlier results
+func (s *Scheduler) parse(expr string) (*common.CronSchedule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if schedule, ok := s.schedules[expr]; ok {
+		return schedule, nil
+	}
+	schedule, err := common.ParseCronSchedule(expr)
+	if err != nil {
+		return nil, err
+	}
+	s.schedules[expr] = schedule
+	return schedule, nil
+}
+
+// activeVAIDs returns the VAs with an active data provider, cached for activeVAsTTL
+func (s *Scheduler) activeVAIDs(ctx context.Context) ([]string, error) {
+	s.mu.Lock()
+	if time.Since(s.vaIDsLoaded) < activeVAsTTL {
+		vaIDs := s.vaIDs
+		s.mu.Unlock()
+		return vaIDs, nil
+	}
+	s.mu.Unlock()
+
+	vaIDs, err := s.engine.ActiveVAIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.vaIDs = vaIDs
+	s.vaIDsLoaded = time.Now()
+	s.mu.Unlock()
+
+	return vaIDs, nil
+}
+
+// jitterFor returns the largest random delay for a run due at the given time
+func jitterFor(schedule *common.CronSchedule, due time.Time) time.Duration {
+	next := schedule.Next(due)
+	period := schedule.Next(next).Sub(next)
+	return min(schedulerMaxJitter, period/10)
+}
@@ -0,0 +1,162 @@
+package jobs
+
+import (
+	"context"
+	"infinite-experiment/politburo/internal/models/gorm"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeScheduleStore is an in-memory JobScheduleStore whose lock can be held by another replica
+type fakeScheduleStore struct {
+	mu           sync.Mutex
+	rows         map[string]*gorm.JobSchedule
+	lockedElse   bool // The lock is held by another replica
+	locks        int
+	unlocks      int
+	started      int
+	finished     int
+	lastFinished *gorm.JobSchedule
+}
+
+func newFakeScheduleStore() *fakeScheduleStore {
+	return &fakeScheduleStore{rows: make(map[string]*gorm.JobSchedule)}
+}
+
+func (f *fakeScheduleStore) Find(ctx context.Context, job string, scope string) (*gorm.JobSchedule, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	row, ok := f.rows[job+":"+scope]
+	if !ok {
+		return nil, nil
+	}
+	copied := *row
+	return &copied, nil
+}
+
+func (f *fakeScheduleStore) Ensure(ctx context.Context, job string, scope string, schedule string, nextRunAt time.Time) (*gorm.JobSchedule, error) {
+	f.mu.Lock()
+	if _, ok := f.rows[job+":"+scope]; !ok {
+		f.rows[job+":"+scope] = &gorm.JobSchedule{Job: job, Scope: scope, Schedule: schedule, NextRunAt: nextRunAt}
+	}
+	f.mu.Unlock()
+	return f.Find(ctx, job, scope)
+}
+
+func (f *fakeScheduleStore) Reschedule(ctx context.Context, job string, scope string, schedule string, nextRunAt time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	row := f.rows[job+":"+scope]
+	row.Schedule = schedule
+	row.NextRunAt = nextRunAt
+	return nil
+}
+
+func (f *fakeScheduleStore) MarkStarted(ctx context.Context, job string, scope string, instance string, startedAt time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.started++
+	return nil
+}
+
+func (f *fakeScheduleStore) MarkFinished(ctx context.Context, job string, scope string, finishedAt time.Time, nextRunAt time.Time, lastError *string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.finished++
+	row := f.rows[job+":"+scope]
+	row.NextRunAt = nextRunAt
+	copied := *row
+	f.lastFinished = &copied
+	return nil
+}
+
+func (f *fakeScheduleStore) TryLock(ctx context.Context, job string, scope string) (func(), bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.lockedElse {
+		return nil, false, nil
+	}
+	f.locks++
+	return func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.unlocks++
+	}, true, nil
+}
+
+func TestSchedulerRunsDueSlotOnlyWithItsLock(t *testing.T) {
+	tests := []struct {
+		name       string
+		lockedElse bool
+		wantRuns   int
+	}{
+		{"lock free", false, 1},
+		{"lock held by another replica", true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newFakeScheduleStore()
+			store.lockedElse = tt.lockedElse
+
+			runs := 0
+			s := NewScheduler(store, NewTracker(nil), nil, nil)
+			// A short period keeps the jitter before the run under 100ms
+			err := s.Add(ScheduledJob{
+				Name:       "test_job",
+				Schedule:   "@every 1s",
+				RunOnStart: true,
+				Run: func(ctx context.Context) error {
+					runs++
+					return nil
+				},
+			})
+			if err != nil {
+				t.Fatalf("Add: %v", err)
+			}
+
+			before := time.Now()
+			s.dispatch(context.Background())
+			s.runs.Wait()
+
+			if runs != tt.wantRuns {
+				t.Errorf("job ran %d times, want %d", runs, tt.wantRuns)
+			}
+			if store.started != tt.wantRuns || store.finished != tt.wantRuns {
+				t.Errorf("recorded %d starts and %d ends, want %d of each", store.started, store.finished, tt.wantRuns)
+			}
+			if store.unlocks != store.locks {
+				t.Errorf("lock taken %d times but released %d times", store.locks, store.unlocks)
+			}
+
+			slot := s.slots["test_job:"]
+			if slot == nil || slot.running {
+				t.Fatalf("slot = %+v, want a slot that is not running", slot)
+			}
+			if tt.lockedElse {
+				// The other replica moves next_run_at on; look again after schedulerLockRetry
+				if slot.nextRun.Before(before.Add(schedulerLockRetry)) {
+					t.Errorf("next check at %s, want no earlier than %s", slot.nextRun, before.Add(schedulerLockRetry))
+				}
+				if row, _ := store.Find(context.Background(), "test_job", ""); row.NextRunAt.After(time.Now()) {
+					t.Errorf("next_run_at moved to %s by a replica that did not run the job", row.NextRunAt)
+				}
+			} else if store.lastFinished == nil || !store.lastFinished.NextRunAt.After(before) {
+				t.Errorf("next_run_at not moved past the run: %+v", store.lastFinished)
+			}
+
+			// The slot is not due again until its next check
+			s.dispatch(context.Background())
+			s.runs.Wait()
+			if runs != tt.wantRuns {
+				t.Errorf("job ran %d times after a second dispatch, want %d", runs, tt.wantRuns)
+			}
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package jobs
+
+import (
+	"context"
+	"infinite-experiment/politburo/internal/datasync"
+	"infinite-experiment/politburo/internal/db/repositories"
+	"infinite-experiment/politburo/internal/models/gorm"
+	"log"
+	"sync"
+	"time"
+)
+
+// Job names, as recorded in sync_runs.job and reported by the job status endpoint
+const (
+	JobPilotSync     = "pilot_sync"
+	JobRouteSync     = "route_sync"
+	JobPirepSync     = "pirep_sync"
+	JobEntitySync    = "entity_sync"
+	JobReconcile     = "reconcile"
+	JobPilotLinking  = "pilot_linking"
+	JobPirepBackfill = "pirep_backfill"
+	JobPirepOutbox   = "pirep_outbox"
+	JobPilotChanges  = "pilot_changes"
+	JobLiverySync    = "livery_sync"
+	JobAirportReload = "airport_reload"
+)
+
+// Sync run states (sync_runs.status)
+const (
+	SyncRunStatusSuccess = "success" // every fetched record was stored
+	SyncRunStatusPartial = "partial" // some records failed to store
+	SyncRunStatusFailed  = "failed"  // the run stopped on an error
+	SyncRunStatusSkipped = "skipped" // nothing to sync; see message
+)
+
+// Sync run triggers (sync_runs.trigger)
+const (
+	TriggerScheduled = "scheduled"
+	TriggerManual    = "manual"
+)
+
+type triggerKey struct{}
+
+// WithManualTrigger marks the runs made with ctx as manually triggered
+func WithManualTrigger(ctx context.Context) context.Context {
+	return context.WithValue(ctx, triggerKey{}, TriggerManual)
+}
+
+// triggerFrom returns how the runs made with ctx were triggered
+func triggerFrom(ctx context.Context) string {
+	if trigger, ok := ctx.Value(triggerKey{}).(string); ok {
+		return trigger
+	}
+	return TriggerScheduled
+}
+
+// JobStatus is the state of one background job
+type JobStatus struct {
+	Name           string
+	Description    string
+	Schedule       string // The default schedule; empty for jobs only run on demand
+	Running        bool
+	LastStartedAt  *time.Time
+	LastFinishedAt *time.Time
+	LastError      string
+	NextRunAt      *time.Time
+
+	running int // Runs in progress; a manual run can overlap a scheduled one
+}
+
+// Tracker keeps the state of every background job in memory and records each
+// entity sync of a VA in sync_runs
+type Tracker struct {
+	runRepo *repositories.SyncRunRepo
+
+	mu    sync.Mutex
+	jobs  map[string]*JobStatus
+	order []string
+}
+
+// NewTracker creates a job tracker
+func NewTracker(runRepo *repositories.SyncRunRepo) *Tracker {
+	return &Tracker{
+		runRepo: runRepo,
+		jobs:    make(map[string]*JobStatus),
+	}
+}
+
+// Register adds a job to the status report. An empty schedule means the job only runs on demand.
+func (t *Tracker) Register(name, description string, schedule string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.jobs[name]; !ok {
+		t.order = append(t.order, name)
+	}
+	t.jobs[name] = &JobStatus{Name: name, Description: description, Schedule: schedule}
+}
+
+// SetNextRun records when a job is next due, keeping the earliest upcoming time when the
+// job runs separately per VA
+func (t *Tracker) SetNextRun(name string, next time.Time) {
+	t.update(name, func(s *JobStatus) {
+		if s.NextRunAt == nil || s.NextRunAt.Before(time.Now()) || next.Before(*s.NextRunAt) {
+			s.NextRunAt = &next
+		}
+	})
+}
+
+// Track runs fn as a run of the named job, recording when it ran and how it ended.
+// A nil tracker just runs fn.
+func (t *Tracker) Track(ctx context.Context, name string, fn func(context.Context) error) error {
+	if t == nil {
+		return fn(ctx)
+	}
+
+	started := time.Now()
+	t.update(name, func(s *JobStatus) {
+		s.running++
+		s.Running = true
+		s.LastStartedAt = &started
+	})
+
+	err := fn(ctx)
+
+	finished := time.Now()
+	t.update(name, func(s *JobStatus) {
+		s.running--
+		s.Running = s.running > 0
+		s.LastFinishedAt = &finished
+		s.LastError = ""
+		if err != nil {
+			s.LastError = err.Error()
+		}
+	})
+
+	return err
+}
+
+// RecordSync stores one entity sync of a VA in sync_runs. The result may be nil when the
+// sync failed before fetching anything. A nil tracker records nothing.
+func (t *Tracker) RecordSync(ctx context.Context, job string, vaID string, entityType string, started time.Time, result *datasync.Result, syncErr error) {
+	if t == nil {
+		return
+	}
+
+	finished := time.Now()
+	durationMs := int(finished.Sub(started).Milliseconds())
+	run := &gorm.SyncRun{
+		Job:        job,
+		VAID:       vaID,
+		EntityType: entityType,
+		Trigger:    triggerFrom(ctx),
+		Status:     SyncRunStatusSuccess,
+		StartedAt:  started,
+		FinishedAt: &finished,
+		DurationMs: &durationMs,
+		Errors:     []string{},
+	}
+
+	if result != nil {
+		if result.ProviderType != "" {
+			run.ProviderType = &result.ProviderType
+		}
+		run.Incremental = result.Incremental
+		run.Pages = result.Pages
+		run.RecordsFetched = result.Fetched
+		run.RecordsInserted = result.Inserted
+		run.RecordsUpdated = result.Updated
+		run.RecordsFailed = result.Failed
+		run.RecordsDeleted = result.Deleted
+		run.RateLimitWaits = result.RateLimitWaits
+		run.RateLimitWaitMs = int(result.RateLimitWaited.Milliseconds())
+		run.ErrorCount = result.ErrorCount
+		run.Errors = append(run.Errors, result.Errors...)
+
+		if result.Failed > 0 {
+			run.Status = SyncRunStatusPartial
+		}
+		if result.Skipped != "" {
+			run.Status = SyncRunStatusSkipped
+			run.Message = &result.Skipped
+		}
+	}
+
+	if syncErr != nil {
+		message := syncErr.Error()
+		run.Status = SyncRunStatusFailed
+		run.Message = &message
+		if result == nil {
+			run.ErrorCount = 1
+			run.Errors = append(run.Errors, message)
+		}
+	}
+
+	// Recorded even if the job's context was cancelled mid-run
+	if err := t.runRepo.Create(context.WithoutCancel(ctx), run); err != nil {
+		log.Printf("[JobTracker] Warning - failed to record %s run of VA %s: %v", job, vaID, err)
+	}
+}
+
+// Status returns the state of every registered job, in registration order. Jobs that have
+// not run since the process started report the finish time of their latest recorded sync run.
+func (t *Tracker) Status(ctx context.Context) []JobStatus {
+	latest := make(map[string]gorm.SyncRun)
+	runs, err := t.runRepo.LatestByJob(ctx)
+	if err != nil {
+		log.Printf("[JobTracker] Warning - failed to load latest sync runs: %v", err)
+	}
+	for _, run := range runs {
+		latest[run.Job] = run
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	statuses := make([]JobStatus, 0, len(t.order))
+	for _, name := range t.order {
+		status := *t.jobs[name]
+		if status.LastStartedAt == nil {
+			if run, ok := latest[name]; ok {
+				startedAt := run.StartedAt
+				status.LastStartedAt = &startedAt
+				status.LastFinishedAt = run.FinishedAt
+			}
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses
+}
+
+// update applies fn to a job's state, registering unknown jobs on the fly
+func (t *Tracker) update(name string, fn func(*JobStatus)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	status, ok := t.jobs[name]
+	if !ok {
+		status = &JobStatus{Name: name}
+		t.jobs[name] = status
+		t.order = append(t.order, name)
+	}
+	fn(status)
+}
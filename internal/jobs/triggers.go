@@ -0,0 +1,246 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"infinite-experiment/politburo/internal/common"
+	"infinite-experiment/politburo/internal/db/repositories"
+	"infinite-experiment/politburo/internal/workers"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUnknownJob        = errors.New("job cannot be triggered manually")
+	ErrJobAlreadyRunning = errors.New("job is already running")
+	ErrShuttingDown      = errors.New("server is shutting down")
+)
+
+// manualRunRetention is how long finished manual runs stay available for polling
+const manualRunRetention = time.Hour
+
+// Manual run states
+const (
+	ManualRunStatusRunning = "running"
+	ManualRunStatusSuccess = "success"
+	ManualRunStatusFailed  = "failed"
+)
+
+// ManualJob is a job an admin can trigger from the API
+type ManualJob struct {
+	Name        string
+	Description string
+	Global      bool // Updates data shared by every VA rather than the triggering VA's data
+
+	// run performs the job for a VA (ignored by global jobs) and returns the number of records processed
+	run func(ctx context.Context, vaID string) (int, error)
+}
+
+// ManualRun is one manual trigger of a job, kept in memory so the caller can poll it
+type ManualRun struct {
+	ID          string
+	Job         string
+	VAID        string // The VA that triggered the run
+	Global      bool
+	TriggeredBy string
+	Status      string
+	Processed   int
+	Error       string
+	StartedAt   time.Time
+	FinishedAt  *time.Time
+}
+
+// Triggers runs jobs on demand, at most one run per job and VA at a time. Manual runs
+// take the same advisory lock as scheduled runs, so they never overlap a run of the job
+// on any replica.
+type Triggers struct {
+	ctx        context.Context // Cancelled on shutdown
+	background *sync.WaitGroup
+	tracker    *Tracker
+	locks      *repositories.JobScheduleRepo
+	jobs       map[string]ManualJob
+
+	mu     sync.Mutex
+	runs   map[string]*ManualRun // By run ID
+	active map[string]string     // Run ID of the running run by job and VA
+}
+
+// NewTriggers creates the manual triggers for the initialized jobs, the livery sync of
+// the meta cache worker and the airport loader. Runs are cancelled when ctx is cancelled
+// and are added to background so shutdown can wait for them to stop.
+func NewTriggers(
+	ctx context.Context,
+	background *sync.WaitGroup,
+	tracker *Tracker,
+	locks *repositories.JobScheduleRepo,
+	container *JobsContainer,
+	metaCache *workers.MetaCacheWorker,
+	airportLoader *common.AirportLoaderService,
+) *Triggers {
+	manualJobs := []ManualJob{
+		{Name: JobPilotSync, Description: "Sync pilots from the VA's data provider", run: container.PilotSync.SyncVAPilots},
+		{Name: JobPilotLinking, Description: "Link synced pilots to registered users", run: container.PilotSync.LinkVAPilots},
+		{Name: JobRouteSync, Description: "Sync routes from the VA's data provider", run: container.RouteSync.SyncVARoutes},
+		{Name: JobPirepSync, Description: "Sync PIREPs from the VA's data provider", run: container.PirepSync.SyncVAPireps},
+		{Name: JobEntitySync, Description: "Sync career mode and custom entity types", run: container.EntitySync.SyncVAEntities},
+		{Name: JobReconcile, Description: "Fully resync pilots, routes and PIREPs and remove deleted records", run: container.Reconcile.ReconcileVA},
+		{
+			Name:        JobPirepBackfill,
+			Description: "Backfill missing pilot and route data on the VA's synced PIREPs",
+			run: func(ctx context.Context, vaID string) (int, error) {
+				return container.PIREPBackfill.BackfillVAPireps(ctx, vaID, 100, 500)
+			},
+		},
+		{
+			Name:        JobLiverySync,
+			Description: "Refresh the world status and sync aircraft and liveries from the Infinite Flight API",
+			Global:      true,
+			run: func(ctx context.Context, _ string) (int, error) {
+				return metaCache.Sync(ctx)
+			},
+		},
+		{
+			Name:        JobAirportReload,
+			Description: "Reload the airport database",
+			Global:      true,
+			run: func(ctx context.Context, _ string) (int, error) {
+				return airportLoader.LoadAirportsFromEmbedded(ctx)
+			},
+		},
+	}
+
+	t := &Triggers{
+		ctx:        ctx,
+		background: background,
+		tracker:    tracker,
+		locks:      locks,
+		jobs:       make(map[string]ManualJob, len(manualJobs)),
+		runs:       make(map[string]*ManualRun),
+		active:     make(map[string]string),
+	}
+	for _, job := range manualJobs {
+		t.jobs[job.Name] = job
+	}
+	return t
+}
+
+// Job returns a manually triggerable job by name
+func (t *Triggers) Job(name string) (ManualJob, bool) {
+	job, ok := t.jobs[name]
+	return job, ok
+}
+
+// Start runs a job for a VA in the background and returns the run to poll. Only one run of
+// a job per VA can be in progress; global jobs allow one run across all VAs. It returns
+// ErrJobAlreadyRunning when the job is running here or, scheduled, on another replica.
+// The run keeps going after ctx is cancelled and stops at its next safe point on shutdown.
+func (t *Triggers) Start(ctx context.Context, name string, vaID string, triggeredBy string) (*ManualRun, error) {
+	job, ok := t.jobs[name]
+	if !ok {
+		return nil, ErrUnknownJob
+	}
+	if t.ctx.Err() != nil {
+		return nil, ErrShuttingDown
+	}
+
+	scope := vaID
+	if job.Global {
+		scope = ""
+	}
+	key := name + ":" + scope
+
+	// Reserve the job here while taking its lock, which may take a round trip
+	t.mu.Lock()
+	t.pruneLocked()
+	if _, running := t.active[key]; running {
+		t.mu.Unlock()
+		return nil, ErrJobAlreadyRunning
+	}
+	t.active[key] = ""
+	t.mu.Unlock()
+
+	unlock, locked, err := t.locks.TryLock(ctx, name, scope)
+	if err != nil || !locked {
+		t.mu.Lock()
+		delete(t.active, key)
+		t.mu.Unlock()
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock %s: %w", name, err)
+		}
+		return nil, ErrJobAlreadyRunning
+	}
+
+	t.mu.Lock()
+	run := &ManualRun{
+		ID:          uuid.NewString(),
+		Job:         name,
+		VAID:        vaID,
+		Global:      job.Global,
+		TriggeredBy: triggeredBy,
+		Status:      ManualRunStatusRunning,
+		StartedAt:   time.Now(),
+	}
+	t.runs[run.ID] = run
+	t.active[key] = run.ID
+	snapshot := *run
+	t.mu.Unlock()
+
+	log.Printf("[JobTriggers] %s triggered for VA %s by %s (run %s)", name, vaID, triggeredBy, run.ID)
+
+	t.background.Add(1)
+	go func() {
+		defer t.background.Done()
+
+		runCtx := WithManualTrigger(t.ctx)
+		processed := 0
+		err := t.tracker.Track(runCtx, name, func(ctx context.Context) error {
+			var err error
+			processed, err = job.run(ctx, vaID)
+			return err
+		})
+		unlock()
+
+		finished := time.Now()
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		run.Processed = processed
+		run.FinishedAt = &finished
+		run.Status = ManualRunStatusSuccess
+		if err != nil {
+			run.Status = ManualRunStatusFailed
+			run.Error = err.Error()
+			log.Printf("[JobTriggers] %s run %s failed: %v", name, run.ID, err)
+		}
+		delete(t.active, key)
+	}()
+
+	return &snapshot, nil
+}
+
+// Run returns a manual run triggered by a VA, or nil if there is none
+func (t *Triggers) Run(vaID string, id string) *ManualRun {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	run, ok := t.runs[id]
+	if !ok || run.VAID != vaID {
+		return nil
+	}
+	snapshot := *run
+	return &snapshot
+}
+
+// pruneLocked forgets finished runs older than manualRunRetention; t.mu must be held
+func (t *Triggers) pruneLocked() {
+	cutoff := time.Now().Add(-manualRunRetention)
+	for id, run := range t.runs {
+		if run.FinishedAt != nil && run.FinishedAt.Before(cutoff) {
+			delete(t.runs, id)
+		}
+	}
+}
@@ -26,6 +26,11 @@ type MetricsRegistry struct {
 	FlightsProcessedTotal prometheus.Counter
 	UsersActive           prometheus.Gauge
 	SyncJobDuration       prometheus.HistogramVec
+
+	// Data Provider Metrics
+	ProviderRateLimitWaitsTotal  prometheus.CounterVec
+	ProviderRateLimitWaitSeconds prometheus.CounterVec
+	ProviderRetriesTotal         prometheus.CounterVec
 }
 
 // NewMetricsRegistry initializes and returns a new MetricsRegistry with all metrics
@@ -123,5 +128,28 @@ func NewMetricsRegistry() *MetricsRegistry {
 			},
 			[]string{"job_name"},
 		),
+
+		// Data Provider Metrics
+		ProviderRateLimitWaitsTotal: *promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "politburo_provider_rate_limit_waits_total",
+				Help: "Total requests to data providers delayed to respect their rate limit",
+			},
+			[]string{"provider"},
+		),
+		ProviderRateLimitWaitSeconds: *promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "politburo_provider_rate_limit_wait_seconds_total",
+				Help: "Total time spent waiting on data provider rate limits in seconds",
+			},
+			[]string{"provider"},
+		),
+		ProviderRetriesTotal: *promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "politburo_provider_retries_total",
+				Help: "Total data provider requests retried by reason",
+			},
+			[]string{"provider", "reason"},
+		),
 	}
 }
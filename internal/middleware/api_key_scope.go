@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"infinite-experiment/politburo/internal/auth"
+	"infinite-experiment/politburo/internal/common"
+	"infinite-experiment/politburo/internal/constants"
+	"net/http"
+)
+
+// RequireAPIKeyScope rejects requests made with an API key whose scope does not include
+// scope. Requests authenticated another way are left to the role middlewares.
+func RequireAPIKeyScope(scope constants.APIKeyScope) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			if key := auth.GetAPIKey(r.Context()); key != nil && !key.Scope.Includes(scope) {
+				common.RespondPermissionDenied(w, scope.String()+" API key")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -1,11 +1,13 @@
 package middleware
 
 import (
+	"errors"
 	"infinite-experiment/politburo/internal/auth"
 	authCtx "infinite-experiment/politburo/internal/auth"
 	"infinite-experiment/politburo/internal/common"
 	"infinite-experiment/politburo/internal/constants"
 	"infinite-experiment/politburo/internal/db/repositories"
+	"infinite-experiment/politburo/internal/services"
 	"log"
 	"net/http"
 	"strings"
@@ -14,7 +16,7 @@ import (
 
 func AuthMiddleware(
 	userRepo *repositories.UserRepositoryGORM,
-	keysRepo *repositories.KeysRepo,
+	apiKeySvc *services.APIKeyService,
 	sessionSvc *common.SessionService,
 ) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -84,19 +86,33 @@ func AuthMiddleware(
 				serverId := r.Header.Get("X-Server-Id")
 				userId := r.Header.Get("X-Discord-Id")
 
-				keyRes, err := keysRepo.GetStatus(r.Context(), apiKey)
-				if err != nil {
+				keyRes, err := apiKeySvc.Authenticate(r.Context(), apiKey)
+				switch {
+				case errors.Is(err, services.ErrAPIKeyNotFound):
 					http.Error(w, "Unauthorized. Invalid API Key", http.StatusUnauthorized)
 					return
+				case errors.Is(err, services.ErrAPIKeyRevoked), errors.Is(err, services.ErrAPIKeyExpired):
+					http.Error(w, "Unauthorized. Inactive API Key", http.StatusUnauthorized)
+					return
+				case err != nil:
+					log.Printf("[AuthMiddleware] ERROR: Failed to look up API key: %v", err)
+					http.Error(w, "Failed to verify API Key", http.StatusInternalServerError)
+					return
 				}
 
-				if !keyRes.Status {
-					http.Error(w, "Unauthorized. Inactive API Key", http.StatusUnauthorized)
+				if keyRes.DiscordServerID != nil && serverId != *keyRes.DiscordServerID {
+					log.Printf("[AuthMiddleware] API key %s is bound to server %s, request is for %q", keyRes.Prefix, *keyRes.DiscordServerID, serverId)
+					http.Error(w, "Forbidden. API Key is not valid for this server", http.StatusForbidden)
+					return
+				}
+				if !keyRes.Scope.Includes(constants.APIKeyScopeBot) && !auth.IsReadOnlyMethod(r.Method) {
+					http.Error(w, "Forbidden. API Key is read-only", http.StatusForbidden)
 					return
 				}
 
 				claims = auth.MakeClaimsFromApi(r.Context(), userRepo, serverId, userId)
 				ctx := authCtx.SetUserClaims(r.Context(), claims)
+				ctx = authCtx.SetAPIKey(ctx, keyRes)
 				next.ServeHTTP(w, r.WithContext(ctx))
 				return
 			}
@@ -1,5 +1,7 @@
 package dtos
 
+import "time"
+
 // FormField represents a form field in a flight mode
 type FormField struct {
 	Name     string `json:"name"`
@@ -16,10 +18,37 @@ type AutoRouteConfig struct {
 }
 
 // ValidationConfig represents validation rules for a flight mode
+// Airport and route entries accept ICAO wildcards (e.g. "EG*", "K???-EGLL")
 type ValidationConfig struct {
 	AllowAnyCurrentRoute bool     `json:"allow_any_current_route"`
 	AllowedRoutes        []string `json:"allowed_routes"`
-	ValidationMode       string   `json:"validation_mode"` // exact_match, any
+	ValidationMode       string   `json:"validation_mode"` // exact_match, origin, destination, hub, any
+
+	// Airport lists for origin, destination and hub modes
+	AllowedOrigins      []string `json:"allowed_origins,omitempty"`
+	AllowedDestinations []string `json:"allowed_destinations,omitempty"`
+	Hubs                []string `json:"hubs,omitempty"`
+
+	// Rules applied on top of the route rule
+	AllowedAircraft []string     `json:"allowed_aircraft,omitempty"` // aircraft names as in aircraft_liveries
+	AllowedLiveries []string     `json:"allowed_liveries,omitempty"` // livery/airline names as in aircraft_liveries
+	MinAltitudeFt   int          `json:"min_altitude_ft,omitempty"`
+	MinDistanceNm   float64      `json:"min_distance_nm,omitempty"`
+	AllowedServers  []string     `json:"allowed_servers,omitempty"` // casual, training, expert
+	TimeWindows     []TimeWindow `json:"time_windows,omitempty"`
+
+	// Restricts selectable routes by haul band: short_haul, medium_haul, long_haul
+	DistanceBands []string `json:"distance_bands,omitempty"`
+}
+
+// TimeWindow restricts a flight mode to a period and/or daily UTC hours
+// All fields are optional; an empty window always matches
+type TimeWindow struct {
+	From      *time.Time `json:"from,omitempty"`       // RFC3339, inclusive
+	Until     *time.Time `json:"until,omitempty"`      // RFC3339, exclusive
+	Days      []string   `json:"days,omitempty"`       // mon, tue, wed, thu, fri, sat, sun
+	StartTime string     `json:"start_time,omitempty"` // HH:MM UTC
+	EndTime   string     `json:"end_time,omitempty"`   // HH:MM UTC, may wrap past midnight
 }
 
 // FlightModeConfig represents the configuration for a single flight mode
@@ -32,9 +61,42 @@ type FlightModeConfig struct {
 	Fields                 []FormField            `json:"fields"`
 	AutoRoute              *AutoRouteConfig       `json:"auto_route,omitempty"`
 	Validations            ValidationConfig       `json:"validations"`
+	MinRank                string                 `json:"min_rank,omitempty"` // Lowest rank allowed to file with this mode
 	Metadata               map[string]interface{} `json:"metadata,omitempty"`
 }
 
+// FlightModesConfig is the typed schema of a VA's flight_modes_config
+type FlightModesConfig struct {
+	SchemaVersion int                         `json:"schema_version,omitempty"`
+	FlightModes   map[string]FlightModeConfig `json:"flight_modes"`
+}
+
+// FlightModesConfigVersion represents one saved revision of a VA's flight modes configuration
+type FlightModesConfigVersion struct {
+	Version        int                    `json:"version"`
+	AuthorID       *string                `json:"author_id,omitempty"`
+	AuthorUsername string                 `json:"author_username,omitempty"`
+	Note           *string                `json:"note,omitempty"`
+	CreatedAt      time.Time              `json:"created_at"`
+	Config         map[string]interface{} `json:"config,omitempty"`
+}
+
+// FlightModesConfigDiffEntry is a single changed value between two config versions
+// Path uses dot notation, e.g. "flight_modes.standard.validations.allowed_routes[0]"
+type FlightModesConfigDiffEntry struct {
+	Path   string      `json:"path"`
+	Change string      `json:"change"` // added, removed, changed
+	Old    interface{} `json:"old,omitempty"`
+	New    interface{} `json:"new,omitempty"`
+}
+
+// FlightModesConfigDiffResponse represents the response from GET /api/v1/va/flight-modes/config/diff
+type FlightModesConfigDiffResponse struct {
+	FromVersion int                          `json:"from_version"`
+	ToVersion   int                          `json:"to_version"`
+	Changes     []FlightModesConfigDiffEntry `json:"changes"`
+}
+
 // UserInfo represents the current user's flight information
 type UserInfo struct {
 	Callsign             string `json:"callsign"`
@@ -50,9 +112,15 @@ type UserInfo struct {
 
 // RouteOption represents a selectable route option
 type RouteOption struct {
-	RouteID    string  `json:"route_id"`
-	Name       string  `json:"name"`
-	Multiplier float64 `json:"multiplier"`
+	RouteID               string         `json:"route_id"`
+	Name                  string         `json:"name"`
+	Multiplier            float64        `json:"multiplier"`
+	FlightNumber          string         `json:"flight_number,omitempty"`
+	Aircraft              string         `json:"aircraft,omitempty"`
+	DistanceNm            *float64       `json:"distance_nm,omitempty"`             // Scheduled distance, else great-circle
+	Haul                  string         `json:"haul,omitempty"`                    // short_haul, medium_haul, long_haul
+	BlockTimeMinutes      *int           `json:"block_time_minutes,omitempty"`      // Scheduled block time, else estimate for the route's aircraft
+	EstimatedBlockMinutes map[string]int `json:"estimated_block_minutes,omitempty"` // Aircraft class -> estimated minutes
 }
 
 // ModeResponse represents a single flight mode in the config response
@@ -103,9 +171,68 @@ type PirepSubmitRequest struct {
 
 // PirepSubmitResponse represents the response from POST /api/v1/pireps/submit
 type PirepSubmitResponse struct {
-	Success      bool   `json:"success"`
-	Message      string `json:"message,omitempty"`
-	PirepID      string `json:"pirep_id,omitempty"`
-	ErrorType    string `json:"error_type,omitempty"`
-	ErrorMessage string `json:"error_message,omitempty"`
+	Success          bool   `json:"success"`
+	Message          string `json:"message,omitempty"`
+	PirepID          string `json:"pirep_id,omitempty"`
+	ProviderRecordID string `json:"provider_record_id,omitempty"`
+	Status           string `json:"status,omitempty"`
+	ErrorType        string `json:"error_type,omitempty"`
+	ErrorMessage     string `json:"error_message,omitempty"`
+}
+
+// PirepReviewRequest represents the request body for POST /api/v1/pireps/{pirep_id}/review
+type PirepReviewRequest struct {
+	Decision string `json:"decision"` // approved, rejected, changes_requested
+	Notes    string `json:"notes,omitempty"`
+}
+
+// PirepResubmitRequest represents the request body for POST /api/v1/pireps/{pirep_id}/resubmit.
+// Omitted fields keep the values of the original submission.
+type PirepResubmitRequest struct {
+	FlightTime   string  `json:"flight_time,omitempty"`
+	PilotRemarks *string `json:"pilot_remarks,omitempty"`
+	FuelKg       *int    `json:"fuel_kg,omitempty"`
+	CargoKg      *int    `json:"cargo_kg,omitempty"`
+	Passengers   *int    `json:"passengers,omitempty"`
+}
+
+// PirepDetail represents a natively stored PIREP as returned by the PIREP review endpoints
+type PirepDetail struct {
+	ID                string     `json:"id"`
+	PilotCallsign     string     `json:"pilot_callsign"`
+	PilotUsername     string     `json:"pilot_username,omitempty"`
+	Mode              string     `json:"mode"`
+	ModeDisplayName   string     `json:"mode_display_name,omitempty"`
+	Route             string     `json:"route,omitempty"`
+	FlightTimeSeconds int        `json:"flight_time_seconds"`
+	Multiplier        float64    `json:"multiplier"`
+	CreditedSeconds   int        `json:"credited_seconds"`
+	Aircraft          string     `json:"aircraft,omitempty"`
+	Airline           string     `json:"airline,omitempty"`
+	FuelKg            *int       `json:"fuel_kg,omitempty"`
+	CargoKg           *int       `json:"cargo_kg,omitempty"`
+	Passengers        *int       `json:"passengers,omitempty"`
+	PilotRemarks      string     `json:"pilot_remarks,omitempty"`
+	ValidationStatus  *string    `json:"validation_status,omitempty"`
+	ValidationReason  *string    `json:"validation_reason,omitempty"`
+	HistoryFlightID   *string    `json:"history_flight_id,omitempty"`
+	FlownSeconds      *int       `json:"flown_seconds,omitempty"`
+	FlownOrigin       *string    `json:"flown_origin,omitempty"`
+	FlownDestination  *string    `json:"flown_destination,omitempty"`
+	Landings          *int       `json:"landings,omitempty"`
+	Violations        *int       `json:"violations,omitempty"`
+	Discrepancies     *string    `json:"discrepancies,omitempty"`
+	Status            string     `json:"status"`
+	ReviewedBy        *string    `json:"reviewed_by,omitempty"`
+	ReviewNotes       *string    `json:"review_notes,omitempty"`
+	ReviewedAt        *time.Time `json:"reviewed_at,omitempty"`
+	ProviderRecordID  *string    `json:"provider_record_id,omitempty"`
+	ProviderError     *string    `json:"provider_error,omitempty"`
+	SubmittedAt       time.Time  `json:"submitted_at"`
+}
+
+// PirepListResponse represents the response from GET /api/v1/pireps
+type PirepListResponse struct {
+	Pireps []PirepDetail `json:"pireps"`
+	Total  int64         `json:"total"`
 }
@@ -1,5 +1,7 @@
 package dtos
 
+import "infinite-experiment/politburo/internal/secrets"
+
 // ProviderConfigData represents the full JSONB structure stored in config_data
 type ProviderConfigData struct {
 	Version      string           `json:"version"`
@@ -12,7 +14,15 @@ type ProviderConfigData struct {
 // ProviderCreds stores authentication credentials
 type ProviderCreds struct {
 	APIKey string `json:"api_key"`
-	BaseID string `json:"base_id"`
+	BaseID string `json:"base_id"` // Airtable base ID, or the spreadsheet ID for Google Sheets
+
+	// ServiceAccountJSON is a Google service account key file; required for Google Sheets writes
+	ServiceAccountJSON string `json:"service_account_json,omitempty"`
+
+	// BaseURL is the root URL of a VA's own backend for the REST provider; APIKey, when set, is sent as a bearer token
+	BaseURL string `json:"base_url,omitempty"`
+	// SigningSecret is the shared secret the REST provider signs requests with (HMAC-SHA256)
+	SigningSecret string `json:"signing_secret,omitempty"`
 }
 
 // EntitySchema defines how to sync a specific entity type (pilot, route, etc.)
@@ -22,6 +32,34 @@ type EntitySchema struct {
 	Enabled           bool            `json:"enabled"`
 	Fields            []FieldMapping  `json:"fields"`
 	LastModifiedField string          `json:"last_modified_field,omitempty"`
+
+	// IDField is the column holding a stable record ID for providers without native record IDs
+	// (Google Sheets); the row number is used when empty
+	IDField string `json:"id_field,omitempty"`
+
+	// Endpoint maps the entity onto HTTP endpoints; required by the REST provider
+	Endpoint *RESTEndpoint `json:"endpoint,omitempty"`
+}
+
+// RESTEndpoint describes how the REST provider reads and writes one entity type.
+// Paths are relative to Credentials.BaseURL. The *_path response settings are JSONPath-style
+// expressions ($.data.items, $.meta['next']) and default to the conventional shape noted.
+// Field mappings may use the same expressions as their external name to reach nested values.
+type RESTEndpoint struct {
+	ListPath   string `json:"list_path"`             // GET, returns a page of records
+	GetPath    string `json:"get_path,omitempty"`    // GET, "{id}" is replaced with the record ID
+	CreatePath string `json:"create_path,omitempty"` // POST, creates a record (or receives it as a webhook)
+	UpdatePath string `json:"update_path,omitempty"` // PATCH, "{id}" is replaced with the record ID
+
+	RecordsPath     string `json:"records_path,omitempty"`      // Record array in list responses (default "$")
+	RecordPath      string `json:"record_path,omitempty"`       // Record in get and create responses (default "$")
+	IDPath          string `json:"id_path,omitempty"`           // Record ID within a record (default "$.id")
+	CreatedTimePath string `json:"created_time_path,omitempty"` // Record creation time within a record
+	NextCursorPath  string `json:"next_cursor_path,omitempty"`  // Next page cursor in list responses; unset means one page
+
+	CursorParam        string `json:"cursor_param,omitempty"`         // Query parameter carrying the cursor (default "cursor")
+	LimitParam         string `json:"limit_param,omitempty"`          // Query parameter carrying the page size (default "limit")
+	ModifiedSinceParam string `json:"modified_since_param,omitempty"` // Query parameter for incremental syncs; filtered locally when unset
 }
 
 // FieldMapping maps an internal field to an external provider field
@@ -91,6 +129,32 @@ func (s *EntitySchema) GetFieldMapping(internalName string) *FieldMapping {
 	return nil
 }
 
+// MapSecrets returns a copy of the config with fn applied to each secret credential
+func (c *ProviderConfigData) MapSecrets(fn func(string) (string, error)) (*ProviderConfigData, error) {
+	mapped := *c
+	for _, field := range []*string{
+		&mapped.Credentials.APIKey,
+		&mapped.Credentials.ServiceAccountJSON,
+		&mapped.Credentials.SigningSecret,
+	} {
+		value, err := fn(*field)
+		if err != nil {
+			return nil, err
+		}
+		*field = value
+	}
+	return &mapped, nil
+}
+
+// Redacted returns a copy of the config with its secret credentials redacted, for API
+// responses and logs
+func (c *ProviderConfigData) Redacted() *ProviderConfigData {
+	redacted, _ := c.MapSecrets(func(value string) (string, error) {
+		return secrets.Redact(value), nil
+	})
+	return redacted
+}
+
 // GetAirtableFieldNames returns all Airtable field names for fetching
 func (s *EntitySchema) GetAirtableFieldNames() []string {
 	names := make([]string, len(s.Fields))
@@ -0,0 +1,71 @@
+package dtos
+
+import "time"
+
+// RankRequest represents one rank of a ladder in a PUT /va/ranks request
+type RankRequest struct {
+	Name               string   `json:"name"`
+	MinHours           float64  `json:"min_hours"`
+	MinFlights         int      `json:"min_flights"`
+	RequiredCheckrides []string `json:"required_checkrides,omitempty"`
+	AllowedAircraft    []string `json:"allowed_aircraft,omitempty"` // Unlocked at this rank and above; wildcards allowed
+}
+
+// RankLadderRequest replaces a VA's rank ladder; ranks are listed lowest first
+type RankLadderRequest struct {
+	Ranks []RankRequest `json:"ranks"`
+}
+
+// Rank represents a rank of a VA's ladder
+type Rank struct {
+	ID                 string   `json:"id"`
+	Name               string   `json:"name"`
+	Position           int      `json:"position"`
+	MinHours           float64  `json:"min_hours"`
+	MinFlights         int      `json:"min_flights"`
+	RequiredCheckrides []string `json:"required_checkrides"`
+	AllowedAircraft    []string `json:"allowed_aircraft"`
+}
+
+// RankRequirement is the progress of a pilot towards one requirement of a rank
+type RankRequirement struct {
+	Type     string  `json:"type"`           // hours, flights, checkride
+	Name     string  `json:"name,omitempty"` // Checkride name
+	Required float64 `json:"required"`
+	Current  float64 `json:"current"`
+	Met      bool    `json:"met"`
+}
+
+// PilotRankProgress is a pilot's current rank and progress to the next one
+type PilotRankProgress struct {
+	UserID         string            `json:"user_id"`
+	Username       string            `json:"username,omitempty"`
+	Callsign       string            `json:"callsign,omitempty"`
+	CurrentRank    *Rank             `json:"current_rank,omitempty"`
+	RankAchievedAt *time.Time        `json:"rank_achieved_at,omitempty"`
+	NextRank       *Rank             `json:"next_rank,omitempty"`
+	CreditedHours  float64           `json:"credited_hours"`
+	Flights        int               `json:"flights"`
+	Checkrides     []string          `json:"checkrides"`
+	Requirements   []RankRequirement `json:"requirements,omitempty"` // Requirements of the next rank
+	Progress       int               `json:"progress"`               // Percentage towards the next rank, 100 at the top
+}
+
+// CheckrideRequest represents the request body for recording a passed checkride
+type CheckrideRequest struct {
+	Name  string `json:"name"`
+	Notes string `json:"notes,omitempty"`
+}
+
+// RankPromotionEvent is an automatic promotion as returned by the promotions endpoint
+type RankPromotionEvent struct {
+	ID            string    `json:"id"`
+	UserID        string    `json:"user_id"`
+	Username      string    `json:"username,omitempty"`
+	FromRank      string    `json:"from_rank,omitempty"`
+	ToRank        string    `json:"to_rank,omitempty"`
+	PirepID       *string   `json:"pirep_id,omitempty"` // The approved PIREP that earned the promotion
+	CreditedHours float64   `json:"credited_hours"`
+	Flights       int       `json:"flights"`
+	CreatedAt     time.Time `json:"created_at"`
+}
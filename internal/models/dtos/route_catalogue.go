@@ -0,0 +1,48 @@
+package dtos
+
+import "time"
+
+// RouteRequest represents the request body for creating or replacing a catalogue route
+type RouteRequest struct {
+	Route            string   `json:"route,omitempty"` // Defaults to ORIGIN-DESTINATION
+	Origin           string   `json:"origin"`
+	Destination      string   `json:"destination"`
+	FlightNumber     string   `json:"flight_number,omitempty"`
+	Aircraft         string   `json:"aircraft,omitempty"`
+	DistanceNm       *float64 `json:"distance_nm,omitempty"`
+	BlockTimeMinutes *int     `json:"block_time_minutes,omitempty"`
+	Multiplier       *float64 `json:"multiplier,omitempty"`
+	ActiveFrom       string   `json:"active_from,omitempty"`  // YYYY-MM-DD, inclusive
+	ActiveUntil      string   `json:"active_until,omitempty"` // YYYY-MM-DD, inclusive
+	IsActive         *bool    `json:"is_active,omitempty"`    // Defaults to true
+}
+
+// RouteDetail represents a catalogue route as returned by the route endpoints
+type RouteDetail struct {
+	ID               string    `json:"id"`
+	Source           string    `json:"source"` // airtable, native
+	AirtableID       *string   `json:"airtable_id,omitempty"`
+	Route            string    `json:"route"`
+	Origin           string    `json:"origin,omitempty"`
+	Destination      string    `json:"destination,omitempty"`
+	FlightNumber     *string   `json:"flight_number,omitempty"`
+	Aircraft         *string   `json:"aircraft,omitempty"`
+	DistanceNm       *float64  `json:"distance_nm,omitempty"`
+	BlockTimeMinutes *int      `json:"block_time_minutes,omitempty"`
+	Multiplier       *float64  `json:"multiplier,omitempty"`
+	ActiveFrom       *string   `json:"active_from,omitempty"`
+	ActiveUntil      *string   `json:"active_until,omitempty"`
+	IsActive         bool      `json:"is_active"`
+	UpdatedAt        time.Time `json:"updated_at"`
+
+	// Derived from airport coordinates
+	GreatCircleNm         *float64       `json:"great_circle_nm,omitempty"`
+	Haul                  *string        `json:"haul,omitempty"`
+	EstimatedBlockMinutes map[string]int `json:"estimated_block_minutes,omitempty"`
+}
+
+// RouteImportResult summarises a bulk CSV route import
+type RouteImportResult struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+}
@@ -1,10 +1,26 @@
 package entities
 
-import "time"
+import (
+	"time"
 
+	"infinite-experiment/politburo/internal/constants"
+)
+
+// ApiKey is an API key. The key itself is never stored, only its SHA-256 hash.
 type ApiKey struct {
-	ApiKey string `db:"id"`
-	Status bool   `db:"status"`
+	ID              string                `db:"id"`
+	Name            string                `db:"name"`
+	Prefix          string                `db:"prefix"` // Identifies the key in listings and logs
+	KeyHash         string                `db:"key_hash"`
+	Scope           constants.APIKeyScope `db:"scope"`
+	VAID            *string               `db:"va_id"`             // Set for keys bound to a VA
+	DiscordServerID *string               `db:"discord_server_id"` // Set for keys bound to a Discord server
+	ExpiresAt       *time.Time            `db:"expires_at"`
+	LastUsedAt      *time.Time            `db:"last_used_at"`
+	RevokedAt       *time.Time            `db:"revoked_at"`
+	ReplacedBy      *string               `db:"replaced_by"` // The key this one was rotated to
+	CreatedBy       *string               `db:"created_by"`
+	CreatedAt       time.Time             `db:"created_at"`
 }
 
 type VAConfig struct {
@@ -0,0 +1,22 @@
+package gorm
+
+import "time"
+
+// FlightModesConfigVersion is one saved revision of a VA's flight modes configuration
+type FlightModesConfigVersion struct {
+	ID        string    `gorm:"column:id;primaryKey;type:uuid;default:gen_random_uuid()"`
+	VAID      string    `gorm:"column:va_id;type:uuid;not null"`
+	Version   int       `gorm:"column:version;not null"`
+	Config    JSONB     `gorm:"column:config;type:jsonb;not null;default:'{}'"`
+	AuthorID  *string   `gorm:"column:author_id;type:uuid"`
+	Note      *string   `gorm:"column:note;type:text"`
+	CreatedAt time.Time `gorm:"column:created_at;default:now()"`
+
+	// Relationships
+	Author *User `gorm:"foreignKey:AuthorID"`
+}
+
+// TableName specifies the table name for GORM
+func (FlightModesConfigVersion) TableName() string {
+	return "flight_modes_config_versions"
+}
@@ -0,0 +1,24 @@
+package gorm
+
+import "time"
+
+// JobSchedule is the shared schedule state of a background job for one scope
+// (a VA ID, or empty for jobs that are not per VA)
+type JobSchedule struct {
+	Job      string `gorm:"column:job;primaryKey;type:varchar(50)"`
+	Scope    string `gorm:"column:scope;primaryKey;type:varchar(64)"`
+	Schedule string `gorm:"column:schedule;type:varchar(100);not null"` // The schedule next_run_at was computed from
+
+	NextRunAt      time.Time  `gorm:"column:next_run_at;not null"`
+	LastStartedAt  *time.Time `gorm:"column:last_started_at"`
+	LastFinishedAt *time.Time `gorm:"column:last_finished_at"`
+	LastError      *string    `gorm:"column:last_error;type:text"`
+	LastInstance   *string    `gorm:"column:last_instance;type:varchar(255)"` // The replica that ran it last
+
+	UpdatedAt time.Time `gorm:"column:updated_at;default:now()"`
+}
+
+// TableName specifies the table name for GORM
+func (JobSchedule) TableName() string {
+	return "job_schedules"
+}
@@ -1,5 +1,11 @@
 package gorm
 
+import (
+	"time"
+
+	gormlib "gorm.io/gorm"
+)
+
 // PilotATSynced represents a pilot record synced from Airtable
 type PilotATSynced struct {
 	ID         string `gorm:"column:id;primaryKey;type:uuid;default:gen_random_uuid()"`
@@ -7,6 +13,14 @@ type PilotATSynced struct {
 	Callsign   string `gorm:"column:callsign;type:varchar(20)"`
 	Registered bool   `gorm:"column:registered;default:false"`
 	ServerID   string `gorm:"column:server_id;type:uuid"`
+
+	// Provider values of the two-way sync fields as of the last sync, keyed by internal name
+	SyncedFields     JSONB      `gorm:"column:synced_fields;type:jsonb;default:'{}'"`
+	RemoteModifiedAt *time.Time `gorm:"column:remote_modified_at"`
+	SyncedAt         *time.Time `gorm:"column:synced_at"`
+
+	// Set when the record no longer exists in the provider (see full reconciliation)
+	DeletedAt gormlib.DeletedAt `gorm:"column:deleted_at;index"`
 }
 
 // TableName specifies the table name for GORM
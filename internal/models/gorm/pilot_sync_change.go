@@ -0,0 +1,33 @@
+package gorm
+
+import "time"
+
+// PilotSyncChange is a dashboard edit of a pilot field queued for the VA's data provider
+type PilotSyncChange struct {
+	ID               string `gorm:"column:id;primaryKey;type:uuid;default:gen_random_uuid()"`
+	VAID             string `gorm:"column:va_id;type:uuid;not null"`
+	VAUserRoleID     string `gorm:"column:va_user_role_id;type:uuid;not null"`
+	ProviderRecordID string `gorm:"column:provider_record_id;type:varchar(100);not null"`
+	Field            string `gorm:"column:field;type:varchar(50);not null"` // Pilot schema internal name
+
+	// Values as text: the provider's value at the last sync, ours, and the provider's
+	// current value when it conflicts
+	BaseValue   *string `gorm:"column:base_value;type:text"`
+	LocalValue  *string `gorm:"column:local_value;type:text"`
+	RemoteValue *string `gorm:"column:remote_value;type:text"`
+
+	// Push state
+	Status    string     `gorm:"column:status;type:varchar(20);not null;default:pending"`
+	Attempts  int        `gorm:"column:attempts;not null;default:0"`
+	LastError *string    `gorm:"column:last_error;type:text"`
+	PushedAt  *time.Time `gorm:"column:pushed_at"`
+
+	// Timestamps
+	CreatedAt time.Time `gorm:"column:created_at;default:now()"`
+	UpdatedAt time.Time `gorm:"column:updated_at;default:now()"`
+}
+
+// TableName specifies the table name for GORM
+func (PilotSyncChange) TableName() string {
+	return "pilot_sync_changes"
+}
@@ -0,0 +1,72 @@
+package gorm
+
+import (
+	"infinite-experiment/politburo/internal/constants"
+	"time"
+)
+
+// Pirep represents a natively stored PIREP (flight log) with its review lifecycle.
+// PIREPs are persisted here first and pushed to the VA's data provider afterwards.
+type Pirep struct {
+	ID            string `gorm:"column:id;primaryKey;type:uuid;default:gen_random_uuid()"`
+	VAID          string `gorm:"column:va_id;type:uuid;not null"`
+	UserID        string `gorm:"column:user_id;type:uuid;not null"`
+	PilotCallsign string `gorm:"column:pilot_callsign;type:varchar(50)"`
+
+	// Flight details
+	Mode              string  `gorm:"column:mode;type:varchar(50);not null"`
+	ModeDisplayName   string  `gorm:"column:mode_display_name;type:varchar(100)"`
+	Route             string  `gorm:"column:route;type:text"`
+	RouteID           *string `gorm:"column:route_id;type:uuid"`
+	RouteATID         *string `gorm:"column:route_at_id;type:varchar(20)"`
+	FlightTimeSeconds int     `gorm:"column:flight_time_seconds;not null;default:0"`
+	Multiplier        float64 `gorm:"column:multiplier;type:numeric(6,2);not null;default:1.0"`
+	CreditedSeconds   int     `gorm:"column:credited_seconds;not null;default:0"`
+	Aircraft          string  `gorm:"column:aircraft;type:varchar(100)"`
+	Airline           string  `gorm:"column:airline;type:varchar(100)"`
+	LiveryID          string  `gorm:"column:livery_id;type:varchar(100)"`
+	FlightID          string  `gorm:"column:flight_id;type:varchar(100)"`
+	FuelKg            *int    `gorm:"column:fuel_kg"`
+	CargoKg           *int    `gorm:"column:cargo_kg"`
+	Passengers        *int    `gorm:"column:passengers"`
+	PilotRemarks      string  `gorm:"column:pilot_remarks;type:text"`
+
+	// Submit-time live flight validation
+	ValidationPolicy *string `gorm:"column:validation_policy;type:varchar(10)"`
+	ValidationStatus *string `gorm:"column:validation_status;type:varchar(20)"`
+	ValidationReason *string `gorm:"column:validation_reason;type:text"`
+
+	// Flight as recorded in the pilot's Infinite Flight history
+	HistoryFlightID          *string `gorm:"column:history_flight_id;type:varchar(100)"`
+	DerivedFlightTimeSeconds *int    `gorm:"column:derived_flight_time_seconds"`
+	DerivedOrigin            *string `gorm:"column:derived_origin;type:varchar(10)"`
+	DerivedDestination       *string `gorm:"column:derived_destination;type:varchar(10)"`
+	Landings                 *int    `gorm:"column:landings"`
+	Violations               *int    `gorm:"column:violations"`
+	HistoryDiscrepancies     *string `gorm:"column:history_discrepancies;type:text"`
+
+	// Review lifecycle
+	Status      constants.PirepStatus `gorm:"column:status;type:pirep_status;not null;default:pending"`
+	ReviewedBy  *string               `gorm:"column:reviewed_by;type:uuid"`
+	ReviewNotes *string               `gorm:"column:review_notes;type:text"`
+	ReviewedAt  *time.Time            `gorm:"column:reviewed_at"`
+
+	// Outbound push to the data provider
+	ProviderType     *string    `gorm:"column:provider_type;type:varchar(50)"`
+	ProviderRecordID *string    `gorm:"column:provider_record_id;type:varchar(100)"`
+	ProviderSyncedAt *time.Time `gorm:"column:provider_synced_at"`
+	ProviderError    *string    `gorm:"column:provider_error;type:text"`
+
+	// Timestamps
+	SubmittedAt time.Time `gorm:"column:submitted_at;default:now()"`
+	CreatedAt   time.Time `gorm:"column:created_at;default:now()"`
+	UpdatedAt   time.Time `gorm:"column:updated_at;default:now()"`
+
+	// Relationships
+	User User `gorm:"foreignKey:UserID"`
+}
+
+// TableName specifies the table name for GORM
+func (Pirep) TableName() string {
+	return "pireps"
+}
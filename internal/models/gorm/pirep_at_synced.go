@@ -1,6 +1,10 @@
 package gorm
 
-import "time"
+import (
+	"time"
+
+	gormlib "gorm.io/gorm"
+)
 
 // PirepATSynced represents a PIREP (flight log) record synced from Airtable
 type PirepATSynced struct {
@@ -29,6 +33,9 @@ type PirepATSynced struct {
 	// Timestamps
 	CreatedAt time.Time `gorm:"column:created_at;default:now()"`
 	UpdatedAt time.Time `gorm:"column:updated_at;default:now()"`
+
+	// Set when the record no longer exists in the provider (see full reconciliation)
+	DeletedAt gormlib.DeletedAt `gorm:"column:deleted_at;index"`
 }
 
 // TableName specifies the table name for GORM
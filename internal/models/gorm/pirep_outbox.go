@@ -0,0 +1,34 @@
+package gorm
+
+import "time"
+
+// PirepOutbox is a pending delivery of a native PIREP to the VA's data provider
+type PirepOutbox struct {
+	ID             string `gorm:"column:id;primaryKey;type:uuid;default:gen_random_uuid()"`
+	PirepID        string `gorm:"column:pirep_id;type:uuid;not null"`
+	VAID           string `gorm:"column:va_id;type:uuid;not null"`
+	ProviderType   string `gorm:"column:provider_type;type:varchar(50);not null"`
+	IdempotencyKey string `gorm:"column:idempotency_key;type:varchar(100);not null;uniqueIndex"`
+
+	// Provider-ready record fields, built at submission time
+	Payload JSONB `gorm:"column:payload;type:jsonb;not null;default:'{}'"`
+
+	// Delivery state
+	Status           string     `gorm:"column:status;type:varchar(20);not null;default:pending"`
+	Attempts         int        `gorm:"column:attempts;not null;default:0"`
+	MaxAttempts      int        `gorm:"column:max_attempts;not null;default:8"`
+	NextAttemptAt    time.Time  `gorm:"column:next_attempt_at;not null"`
+	LastError        *string    `gorm:"column:last_error;type:text"`
+	OutcomeUnknown   bool       `gorm:"column:outcome_unknown;not null;default:false"` // A failed attempt may have created the record
+	ProviderRecordID *string    `gorm:"column:provider_record_id;type:varchar(100)"`
+	DeliveredAt      *time.Time `gorm:"column:delivered_at"`
+
+	// Timestamps
+	CreatedAt time.Time `gorm:"column:created_at;default:now()"`
+	UpdatedAt time.Time `gorm:"column:updated_at;default:now()"`
+}
+
+// TableName specifies the table name for GORM
+func (PirepOutbox) TableName() string {
+	return "pirep_outbox"
+}
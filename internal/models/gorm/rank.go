@@ -0,0 +1,66 @@
+package gorm
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// VARank is one step of a VA's rank ladder. Ranks are ordered by Position (lowest first);
+// a pilot holds the highest rank whose requirements they meet.
+type VARank struct {
+	ID                 string         `gorm:"column:id;primaryKey;type:uuid;default:gen_random_uuid()"`
+	VAID               string         `gorm:"column:va_id;type:uuid;not null"`
+	Name               string         `gorm:"column:name;type:varchar(100);not null"`
+	Position           int            `gorm:"column:position;not null"`
+	MinHours           float64        `gorm:"column:min_hours;type:numeric(8,1);not null"`
+	MinFlights         int            `gorm:"column:min_flights;not null"`
+	RequiredCheckrides pq.StringArray `gorm:"column:required_checkrides;type:text[];not null"`
+	AllowedAircraft    pq.StringArray `gorm:"column:allowed_aircraft;type:text[];not null"` // Aircraft unlocked at this rank (wildcards allowed)
+	CreatedAt          time.Time      `gorm:"column:created_at;default:now()"`
+	UpdatedAt          time.Time      `gorm:"column:updated_at;default:now()"`
+}
+
+// TableName specifies the table name for GORM
+func (VARank) TableName() string {
+	return "va_ranks"
+}
+
+// PilotCheckride records a checkride a pilot has passed in a VA
+type PilotCheckride struct {
+	ID         string    `gorm:"column:id;primaryKey;type:uuid;default:gen_random_uuid()"`
+	VAID       string    `gorm:"column:va_id;type:uuid;not null"`
+	UserID     string    `gorm:"column:user_id;type:uuid;not null"`
+	Name       string    `gorm:"column:name;type:varchar(100);not null"`
+	ExaminerID *string   `gorm:"column:examiner_id;type:uuid"`
+	Notes      *string   `gorm:"column:notes;type:text"`
+	PassedAt   time.Time `gorm:"column:passed_at;default:now()"`
+}
+
+// TableName specifies the table name for GORM
+func (PilotCheckride) TableName() string {
+	return "pilot_checkrides"
+}
+
+// RankPromotion is an automatic promotion event, kept as the pilot's rank history
+type RankPromotion struct {
+	ID            string    `gorm:"column:id;primaryKey;type:uuid;default:gen_random_uuid()"`
+	VAID          string    `gorm:"column:va_id;type:uuid;not null"`
+	UserID        string    `gorm:"column:user_id;type:uuid;not null"`
+	FromRankID    *string   `gorm:"column:from_rank_id;type:uuid"`
+	ToRankID      *string   `gorm:"column:to_rank_id;type:uuid"`
+	PirepID       *string   `gorm:"column:pirep_id;type:uuid"` // The approved PIREP that earned it, if any
+	CreditedHours float64   `gorm:"column:credited_hours;type:numeric(8,1);not null"`
+	Flights       int       `gorm:"column:flights;not null"`
+	CreatedAt     time.Time `gorm:"column:created_at;default:now()"`
+
+	// Relationships
+	User     *User   `gorm:"foreignKey:UserID"`
+	FromRank *VARank `gorm:"foreignKey:FromRankID"`
+	ToRank   *VARank `gorm:"foreignKey:ToRankID"`
+}
+
+// TableName specifies the table name for GORM
+func (RankPromotion) TableName() string {
+	return "rank_promotions"
+}
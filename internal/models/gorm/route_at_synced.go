@@ -3,22 +3,45 @@ package gorm
 import (
 	"database/sql"
 	"time"
+
+	gormlib "gorm.io/gorm"
 )
 
-// RouteATSynced represents a route record synced from Airtable
+// RouteATSynced represents a route in the VA's route catalogue.
+// Routes are either synced from Airtable (ATID set) or managed natively (Source "native").
 type RouteATSynced struct {
-	ID             string       `gorm:"column:id;primaryKey;type:uuid;default:gen_random_uuid()"`
-	ATID           string       `gorm:"column:at_id;type:varchar(20);not null"`
-	ServerID       string       `gorm:"column:server_id;type:uuid;not null"`
-	Origin         string       `gorm:"column:origin;type:varchar(10)"`
-	Destination    string       `gorm:"column:destination;type:varchar(10)"`
-	Route          string       `gorm:"column:route;type:text"`
+	ID             string          `gorm:"column:id;primaryKey;type:uuid;default:gen_random_uuid()"`
+	ATID           *string         `gorm:"column:at_id;type:varchar(20)"`
+	ServerID       string          `gorm:"column:server_id;type:uuid;not null"`
+	Source         string          `gorm:"column:source;type:varchar(20);not null"`
+	Origin         string          `gorm:"column:origin;type:varchar(10)"`
+	Destination    string          `gorm:"column:destination;type:varchar(10)"`
+	Route          string          `gorm:"column:route;type:text"`
 	OriginLat      sql.NullFloat64 `gorm:"column:origin_lat;type:numeric(10,6)"`
 	OriginLon      sql.NullFloat64 `gorm:"column:origin_lon;type:numeric(10,6)"`
 	DestinationLat sql.NullFloat64 `gorm:"column:destination_lat;type:numeric(10,6)"`
 	DestinationLon sql.NullFloat64 `gorm:"column:destination_lon;type:numeric(10,6)"`
-	CreatedAt      time.Time    `gorm:"column:created_at;default:now()"`
-	UpdatedAt      time.Time    `gorm:"column:updated_at;default:now()"`
+
+	// Catalogue details
+	FlightNumber     *string    `gorm:"column:flight_number;type:varchar(20)"`
+	Aircraft         *string    `gorm:"column:aircraft;type:varchar(100)"`
+	DistanceNm       *float64   `gorm:"column:distance_nm;type:numeric(8,1)"`
+	BlockTimeMinutes *int       `gorm:"column:block_time_minutes"`
+	Multiplier       *float64   `gorm:"column:multiplier;type:numeric(6,2)"`
+	ActiveFrom       *time.Time `gorm:"column:active_from;type:date"`
+	ActiveUntil      *time.Time `gorm:"column:active_until;type:date"`
+	IsActive         bool       `gorm:"column:is_active;not null"`
+
+	// Derived from airport coordinates (see common.ApplyRouteEstimates)
+	GreatCircleNm         *float64 `gorm:"column:great_circle_nm;type:numeric(8,1)"`
+	Haul                  *string  `gorm:"column:haul;type:varchar(20)"`
+	EstimatedBlockMinutes JSONB    `gorm:"column:estimated_block_minutes;type:jsonb"` // aircraft class -> minutes
+
+	CreatedAt time.Time `gorm:"column:created_at;default:now()"`
+	UpdatedAt time.Time `gorm:"column:updated_at;default:now()"`
+
+	// Set when a synced route no longer exists in the provider (see full reconciliation)
+	DeletedAt gormlib.DeletedAt `gorm:"column:deleted_at;index"`
 }
 
 // TableName specifies the table name for GORM
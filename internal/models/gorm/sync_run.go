@@ -0,0 +1,48 @@
+package gorm
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// SyncRun is one sync of an entity type for a VA by a background job
+type SyncRun struct {
+	ID           string  `gorm:"column:id;primaryKey;type:uuid;default:gen_random_uuid()"`
+	Job          string  `gorm:"column:job;type:varchar(50);not null"`
+	VAID         string  `gorm:"column:va_id;type:uuid;not null"`
+	EntityType   string  `gorm:"column:entity_type;type:varchar(50);not null"`
+	ProviderType *string `gorm:"column:provider_type;type:varchar(50)"`
+	Trigger      string  `gorm:"column:trigger;type:varchar(20);not null;default:scheduled"` // scheduled or manual
+	Status       string  `gorm:"column:status;type:varchar(20);not null"`
+	Incremental  bool    `gorm:"column:incremental;not null;default:false"`
+
+	// Timing
+	StartedAt  time.Time  `gorm:"column:started_at;not null"`
+	FinishedAt *time.Time `gorm:"column:finished_at"`
+	DurationMs *int       `gorm:"column:duration_ms"`
+
+	// Record counts
+	Pages           int `gorm:"column:pages;not null;default:0"`
+	RecordsFetched  int `gorm:"column:records_fetched;not null;default:0"`
+	RecordsInserted int `gorm:"column:records_inserted;not null;default:0"`
+	RecordsUpdated  int `gorm:"column:records_updated;not null;default:0"`
+	RecordsFailed   int `gorm:"column:records_failed;not null;default:0"`
+	RecordsDeleted  int `gorm:"column:records_deleted;not null;default:0"`
+
+	// Provider rate limiting
+	RateLimitWaits  int `gorm:"column:rate_limit_waits;not null;default:0"`
+	RateLimitWaitMs int `gorm:"column:rate_limit_wait_ms;not null;default:0"`
+
+	// Errors: the total and the first few messages
+	ErrorCount int            `gorm:"column:error_count;not null;default:0"`
+	Errors     pq.StringArray `gorm:"column:errors;type:text[];not null"`
+	Message    *string        `gorm:"column:message;type:text"` // Why the run was skipped or failed
+
+	CreatedAt time.Time `gorm:"column:created_at;default:now()"`
+}
+
+// TableName specifies the table name for GORM
+func (SyncRun) TableName() string {
+	return "sync_runs"
+}
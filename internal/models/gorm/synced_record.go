@@ -0,0 +1,26 @@
+package gorm
+
+import "time"
+
+// SyncedRecord is a raw provider record of an entity type without a dedicated table
+type SyncedRecord struct {
+	ID           string `gorm:"column:id;primaryKey;type:uuid;default:gen_random_uuid()"`
+	VAID         string `gorm:"column:va_id;type:uuid;not null"`
+	EntityType   string `gorm:"column:entity_type;type:varchar(50);not null"`
+	ProviderType string `gorm:"column:provider_type;type:varchar(50);not null"`
+	RecordID     string `gorm:"column:record_id;type:varchar(100);not null"`
+
+	// Record fields keyed by provider field name
+	Fields JSONB `gorm:"column:fields;type:jsonb;not null;default:'{}'"`
+
+	ProviderCreatedAt *time.Time `gorm:"column:provider_created_at"`
+
+	// Timestamps
+	CreatedAt time.Time `gorm:"column:created_at;default:now()"`
+	UpdatedAt time.Time `gorm:"column:updated_at;default:now()"`
+}
+
+// TableName specifies the table name for GORM
+func (SyncedRecord) TableName() string {
+	return "va_synced_records"
+}
@@ -34,6 +34,8 @@ type UserVARole struct {
 	JoinedAt        time.Time        `gorm:"column:joined_at;autoCreateTime"`
 	Callsign        string           `gorm:"column:callsign"`
 	AirtablePilotID *string          `gorm:"column:airtable_pilot_id"`
+	RankID          *string          `gorm:"column:rank_id;type:uuid"`
+	RankAchievedAt  *time.Time       `gorm:"column:rank_achieved_at"`
 	UpdatedAt       time.Time        `gorm:"column:updated_at;autoUpdateTime"`
 
 	// Relationships
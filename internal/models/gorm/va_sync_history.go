@@ -10,6 +10,12 @@ type VASyncHistory struct {
 	CreatedAt  time.Time  `gorm:"column:created_at;autoCreateTime"`
 	LastSyncAt *time.Time `gorm:"column:last_sync_at"`
 
+	// Counts of the last run, set for reconciliation events
+	RecordsFetched *int `gorm:"column:records_fetched"`
+	RecordsStored  *int `gorm:"column:records_stored"`
+	RecordsFailed  *int `gorm:"column:records_failed"`
+	RecordsDeleted *int `gorm:"column:records_deleted"`
+
 	// Relationships
 	VA VA `gorm:"foreignKey:VAID"`
 }
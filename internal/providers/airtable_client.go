@@ -0,0 +1,311 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"infinite-experiment/politburo/internal/common"
+	"infinite-experiment/politburo/internal/constants"
+	"infinite-experiment/politburo/internal/models/dtos"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	airtableAPIURL = "https://api.airtable.com/v0"
+
+	// Used when a VA's sync settings leave them unset; Airtable allows 5 requests per second per base
+	defaultAirtableRateLimit     = 5
+	defaultAirtableRetryAttempts = 3
+	defaultAirtableTimeout       = 30 * time.Second
+
+	// maxAirtableRetryAttempts caps the retry attempts a VA can configure
+	maxAirtableRetryAttempts = 10
+
+	// airtableRateLimitPenalty is how long Airtable blocks a base after a 429 without Retry-After
+	airtableRateLimitPenalty = 30 * time.Second
+
+	// airtableBaseBackoff is the first retry delay after a server or network error, doubled per attempt
+	airtableBaseBackoff = 500 * time.Millisecond
+	airtableMaxBackoff  = 30 * time.Second
+)
+
+// Retry reasons, as reported by the provider retry metric
+const (
+	retryReasonRateLimited  = "rate_limited"
+	retryReasonServerError  = "server_error"
+	retryReasonNetworkError = "network_error"
+)
+
+// airtableLimiter throttles the requests to one Airtable base across every job, worker and
+// request handler of this process. After a 429 it halves its rate and pauses for the
+// Retry-After period; each successful request then restores part of the configured rate.
+type airtableLimiter struct {
+	limiter *rate.Limiter
+
+	mu          sync.Mutex
+	ceiling     rate.Limit // The configured rate
+	pausedUntil time.Time
+}
+
+// newAirtableLimiter creates a limiter allowing perSecond requests per second
+func newAirtableLimiter(perSecond int) *airtableLimiter {
+	ceiling := rate.Limit(perSecond)
+	return &airtableLimiter{
+		limiter: rate.NewLimiter(ceiling, 1),
+		ceiling: ceiling,
+	}
+}
+
+// configure applies a changed rate from the VA's sync settings
+func (l *airtableLimiter) configure(perSecond int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	ceiling := rate.Limit(perSecond)
+	if ceiling != l.ceiling {
+		l.ceiling = ceiling
+		l.limiter.SetLimit(ceiling)
+	}
+}
+
+// wait blocks until a request may be sent and returns how long it waited
+func (l *airtableLimiter) wait(ctx context.Context) (time.Duration, error) {
+	start := time.Now()
+
+	l.mu.Lock()
+	paused := time.Until(l.pausedUntil)
+	l.mu.Unlock()
+	if paused > 0 {
+		if err := sleepContext(ctx, paused); err != nil {
+			return time.Since(start), err
+		}
+	}
+
+	reservation := l.limiter.Reserve()
+	if delay := reservation.Delay(); delay > 0 {
+		if err := sleepContext(ctx, delay); err != nil {
+			reservation.Cancel()
+			return time.Since(start), err
+		}
+	}
+
+	return time.Since(start), nil
+}
+
+// throttle slows the base down after a 429, pausing every request for the given period
+func (l *airtableLimiter) throttle(pause time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.limiter.SetLimit(max(l.limiter.Limit()/2, l.ceiling/10))
+	if until := time.Now().Add(pause); until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
+
+// recover raises a throttled rate back toward the configured rate after a success
+func (l *airtableLimiter) recover() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if current := l.limiter.Limit(); current < l.ceiling {
+		l.limiter.SetLimit(min(current+l.ceiling/10, l.ceiling))
+	}
+}
+
+// airtableSettings are a VA's sync settings with defaults filled in
+type airtableSettings struct {
+	ratePerSecond int
+	retryAttempts int
+	timeout       time.Duration
+}
+
+// settingsFor resolves the request settings of a VA's config
+func settingsFor(config *dtos.ProviderConfigData) airtableSettings {
+	settings := airtableSettings{
+		ratePerSecond: defaultAirtableRateLimit,
+		retryAttempts: defaultAirtableRetryAttempts,
+		timeout:       defaultAirtableTimeout,
+	}
+	if config.SyncSettings.RateLimitPerSecond > 0 {
+		settings.ratePerSecond = config.SyncSettings.RateLimitPerSecond
+	}
+	if config.SyncSettings.RetryAttempts > 0 {
+		settings.retryAttempts = min(config.SyncSettings.RetryAttempts, maxAirtableRetryAttempts)
+	}
+	if config.SyncSettings.TimeoutSeconds > 0 {
+		settings.timeout = time.Duration(config.SyncSettings.TimeoutSeconds) * time.Second
+	}
+	return settings
+}
+
+// limiterFor returns the shared limiter of an Airtable base
+func (p *AirtableProvider) limiterFor(baseID string, perSecond int) *airtableLimiter {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	limiter, ok := p.limiters[baseID]
+	if !ok {
+		limiter = newAirtableLimiter(perSecond)
+		p.limiters[baseID] = limiter
+		return limiter
+	}
+	limiter.configure(perSecond)
+	return limiter
+}
+
+// do sends an Airtable request through the base's rate limiter. Rate-limited requests are
+// retried after the Retry-After period; server and network errors are retried with
+// exponential backoff only when retryable, since a failed create may still have gone
+// through. newRequest builds a fresh request for every attempt. The caller closes the
+// response body and checks its status with handleHTTPError.
+func (c *airtableClient) do(ctx context.Context, retryable bool, newRequest func(ctx context.Context) (*http.Request, error)) (*http.Response, error) {
+	settings := c.settings
+	limiter := c.limiter
+	stats := common.SyncRunStatsFrom(ctx)
+
+	for attempt := 0; ; attempt++ {
+		waited, err := limiter.wait(ctx)
+		c.recordWait(stats, waited)
+		if err != nil {
+			return nil, err
+		}
+
+		attemptCtx, cancel := context.WithTimeout(ctx, settings.timeout)
+		req, err := newRequest(attemptCtx)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		resp, err := c.client.Do(req)
+		lastAttempt := attempt >= settings.retryAttempts || ctx.Err() != nil
+
+		var reason string
+		var backoff time.Duration
+		switch {
+		case err != nil:
+			cancel()
+			if !retryable || lastAttempt {
+				return nil, &ProviderError{
+					Code:    constants.ErrCodeNetworkError,
+					Message: constants.GetErrorMessage(constants.ErrCodeNetworkError),
+					Err:     err,
+				}
+			}
+			reason = retryReasonNetworkError
+			backoff = airtableBackoff(attempt)
+		case resp.StatusCode == http.StatusTooManyRequests:
+			// The pause applies to every request to the base, so the next wait covers it
+			pause, ok := parseRetryAfter(resp.Header.Get("Retry-After"))
+			if !ok {
+				pause = airtableRateLimitPenalty
+			}
+			limiter.throttle(pause)
+			reason = retryReasonRateLimited
+		case resp.StatusCode >= 500 && retryable:
+			reason = retryReasonServerError
+			backoff = airtableBackoff(attempt)
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+				backoff = max(backoff, retryAfter)
+			}
+		default:
+			limiter.recover()
+			resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+			return resp, nil
+		}
+
+		if resp != nil {
+			if lastAttempt {
+				resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+				return resp, nil
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		cancel()
+
+		c.recordRetry(reason)
+		log.Printf("[AirtableProvider] Base %s: %s on attempt %d of %d, retrying",
+			c.config.Credentials.BaseID, reason, attempt+1, settings.retryAttempts+1)
+
+		if backoff > 0 {
+			if err := sleepContext(ctx, backoff); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// recordWait reports a rate-limit wait to the sync run and the metrics
+func (c *airtableClient) recordWait(stats *common.SyncRunStats, waited time.Duration) {
+	if waited < time.Millisecond {
+		return
+	}
+	stats.AddRateLimitWait(waited)
+	if metricsReg := c.provider.metrics; metricsReg != nil {
+		metricsReg.ProviderRateLimitWaitsTotal.WithLabelValues(ProviderTypeAirtable).Inc()
+		metricsReg.ProviderRateLimitWaitSeconds.WithLabelValues(ProviderTypeAirtable).Add(waited.Seconds())
+	}
+}
+
+// recordRetry reports a retried request to the metrics
+func (c *airtableClient) recordRetry(reason string) {
+	if metricsReg := c.provider.metrics; metricsReg != nil {
+		metricsReg.ProviderRetriesTotal.WithLabelValues(ProviderTypeAirtable, reason).Inc()
+	}
+}
+
+// airtableBackoff returns the delay before retrying a failed attempt
+func airtableBackoff(attempt int) time.Duration {
+	// Shifting further would overflow; the delay is at the maximum long before
+	if attempt > 16 {
+		return airtableMaxBackoff
+	}
+	return min(airtableBaseBackoff<<max(attempt, 0), airtableMaxBackoff)
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0), true
+	}
+	return 0, false
+}
+
+// sleepContext sleeps for d or until ctx is cancelled
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// cancelOnClose releases a request's timeout context once its response body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
@@ -5,73 +5,103 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"infinite-experiment/politburo/internal/airtable"
 	"infinite-experiment/politburo/internal/common"
 	"infinite-experiment/politburo/internal/constants"
+	"infinite-experiment/politburo/internal/metrics"
 	"infinite-experiment/politburo/internal/models/dtos"
 	"io"
+	"log"
 	"net/http"
+	"sync"
 	"time"
 )
 
-// AirtableProvider implements DataProvider for Airtable
+// AirtableProvider implements DataProvider for Airtable.
+//
+// Each VA config gets its own client. Requests honour the config's SyncSettings: they are
+// throttled per base by a limiter shared by every client of that base in the process, time
+// out after TimeoutSeconds, and are retried up to RetryAttempts (at most 10) times on 429s
+// and, where safe, on server and network errors.
 type AirtableProvider struct {
-	client *http.Client
-	cache  common.CacheInterface
+	cache   common.CacheInterface
+	metrics *metrics.MetricsRegistry // Optional
+	baseURL string
+
+	mu       sync.Mutex
+	limiters map[string]*airtableLimiter // By base ID
 }
 
-// NewAirtableProvider creates a new Airtable provider
-func NewAirtableProvider(cache common.CacheInterface) *AirtableProvider {
+// NewAirtableProvider creates a new Airtable provider; metricsReg may be nil
+func NewAirtableProvider(cache common.CacheInterface, metricsReg *metrics.MetricsRegistry) *AirtableProvider {
 	return &AirtableProvider{
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-		cache: cache,
+		cache:    cache,
+		metrics:  metricsReg,
+		baseURL:  airtableAPIURL,
+		limiters: make(map[string]*airtableLimiter),
 	}
 }
 
 // GetProviderType returns the provider type identifier
 func (p *AirtableProvider) GetProviderType() string {
-	return "airtable"
+	return ProviderTypeAirtable
 }
 
-// FetchPilotRecord fetches a single pilot record by Airtable record ID
-func (p *AirtableProvider) FetchPilotRecord(ctx context.Context, pilotID string, schema *dtos.EntitySchema) (*PilotRecord, error) {
-	// Get config from context (should be set by service layer)
-	config, ok := ctx.Value("provider_config").(*dtos.ProviderConfigData)
-	if !ok {
-		return nil, fmt.Errorf("provider config not found in context")
+// NewClient creates a client for one VA's Airtable base
+func (p *AirtableProvider) NewClient(config *dtos.ProviderConfigData) ProviderClient {
+	return p.newClient(config)
+}
+
+// airtableClient talks to the Airtable base of one VA config
+type airtableClient struct {
+	provider *AirtableProvider
+	config   *dtos.ProviderConfigData
+	client   *http.Client // Timeouts are applied per request from the sync settings
+	limiter  *airtableLimiter
+	settings airtableSettings
+	cache    common.CacheInterface // Namespaced to the config
+	baseURL  string
+}
+
+func (p *AirtableProvider) newClient(config *dtos.ProviderConfigData) *airtableClient {
+	settings := settingsFor(config)
+	return &airtableClient{
+		provider: p,
+		config:   config,
+		client:   &http.Client{},
+		limiter:  p.limiterFor(config.Credentials.BaseID, settings.ratePerSecond),
+		settings: settings,
+		cache:    namespaceCache(p.cache, ProviderTypeAirtable, config),
+		baseURL:  p.baseURL,
 	}
+}
 
+// GetProviderType returns the provider type identifier
+func (c *airtableClient) GetProviderType() string {
+	return ProviderTypeAirtable
+}
+
+// FetchPilotRecord fetches a single pilot record by Airtable record ID
+func (c *airtableClient) FetchPilotRecord(ctx context.Context, pilotID string, schema *dtos.EntitySchema) (*PilotRecord, error) {
 	// Build Airtable API URL
-	url := fmt.Sprintf("https://api.airtable.com/v0/%s/%s/%s",
-		config.Credentials.BaseID,
+	url := fmt.Sprintf("%s/%s/%s/%s",
+		c.baseURL,
+		c.config.Credentials.BaseID,
 		schema.TableName,
 		pilotID,
 	)
 
-	// Create request
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// Set headers
-	req.Header.Set("Authorization", "Bearer "+config.Credentials.APIKey)
-	req.Header.Set("Content-Type", "application/json")
-
 	// Execute request
-	resp, err := p.client.Do(req)
+	resp, err := c.do(ctx, true, func(ctx context.Context) (*http.Request, error) {
+		return c.newRequest(ctx, "GET", url, nil)
+	})
 	if err != nil {
-		return nil, &ProviderError{
-			Code:    constants.ErrCodeNetworkError,
-			Message: constants.GetErrorMessage(constants.ErrCodeNetworkError),
-			Err:     err,
-		}
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	// Handle error responses
-	if err := p.handleHTTPError(resp); err != nil {
+	if err := c.handleHTTPError(resp); err != nil {
 		return nil, err
 	}
 
@@ -85,55 +115,39 @@ func (p *AirtableProvider) FetchPilotRecord(ctx context.Context, pilotID string,
 	record := &PilotRecord{
 		ProviderID: airtableResp.ID,
 		RawFields:  airtableResp.Fields,
-		Normalized: p.normalizeFields(airtableResp.Fields, schema),
+		Normalized: c.normalizeFields(airtableResp.Fields, schema),
 	}
 
 	return record, nil
 }
 
 // FetchRecords fetches multiple records with pagination
-func (p *AirtableProvider) FetchRecords(ctx context.Context, schema *dtos.EntitySchema, filters *SyncFilters) (*RecordSet, error) {
-	config, ok := ctx.Value("provider_config").(*dtos.ProviderConfigData)
-	if !ok {
-		return nil, fmt.Errorf("provider config not found in context")
-	}
-
+func (c *airtableClient) FetchRecords(ctx context.Context, schema *dtos.EntitySchema, filters *SyncFilters) (*RecordSet, error) {
 	// Build request payload
-	payload := p.buildFetchPayload(schema, filters)
+	payload := c.buildFetchPayload(schema, filters)
 	payloadBytes, err := json.Marshal(payload)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
 	// Build URL
-	url := fmt.Sprintf("https://api.airtable.com/v0/%s/%s/listRecords",
-		config.Credentials.BaseID,
+	url := fmt.Sprintf("%s/%s/%s/listRecords",
+		c.baseURL,
+		c.config.Credentials.BaseID,
 		schema.TableName,
 	)
 
-	// Create request
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(payloadBytes))
+	// Execute request; listRecords only reads, so it is safe to retry
+	resp, err := c.do(ctx, true, func(ctx context.Context) (*http.Request, error) {
+		return c.newRequest(ctx, "POST", url, payloadBytes)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// Set headers
-	req.Header.Set("Authorization", "Bearer "+config.Credentials.APIKey)
-	req.Header.Set("Content-Type", "application/json")
-
-	// Execute request
-	resp, err := p.client.Do(req)
-	if err != nil {
-		return nil, &ProviderError{
-			Code:    constants.ErrCodeNetworkError,
-			Message: constants.GetErrorMessage(constants.ErrCodeNetworkError),
-			Err:     err,
-		}
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	// Handle error responses
-	if err := p.handleHTTPError(resp); err != nil {
+	if err := c.handleHTTPError(resp); err != nil {
 		return nil, err
 	}
 
@@ -164,12 +178,7 @@ func (p *AirtableProvider) FetchRecords(ctx context.Context, schema *dtos.Entity
 }
 
 // SubmitRecord creates a new record in Airtable
-func (p *AirtableProvider) SubmitRecord(ctx context.Context, schema *dtos.EntitySchema, fields map[string]interface{}) (string, error) {
-	config, ok := ctx.Value("provider_config").(*dtos.ProviderConfigData)
-	if !ok {
-		return "", fmt.Errorf("provider config not found in context")
-	}
-
+func (c *airtableClient) SubmitRecord(ctx context.Context, schema *dtos.EntitySchema, fields map[string]interface{}) (string, error) {
 	// Build request payload
 	payload := map[string]interface{}{
 		"records": []map[string]interface{}{
@@ -185,34 +194,24 @@ func (p *AirtableProvider) SubmitRecord(ctx context.Context, schema *dtos.Entity
 	}
 
 	// Build Airtable API URL
-	url := fmt.Sprintf("https://api.airtable.com/v0/%s/%s",
-		config.Credentials.BaseID,
+	url := fmt.Sprintf("%s/%s/%s",
+		c.baseURL,
+		c.config.Credentials.BaseID,
 		schema.TableName,
 	)
 
-	// Create request
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(payloadBytes))
+	// Execute request; a create that failed on a server or network error may still have
+	// gone through, so only rate-limited attempts are retried
+	resp, err := c.do(ctx, false, func(ctx context.Context) (*http.Request, error) {
+		return c.newRequest(ctx, "POST", url, payloadBytes)
+	})
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// Set headers
-	req.Header.Set("Authorization", "Bearer "+config.Credentials.APIKey)
-	req.Header.Set("Content-Type", "application/json")
-
-	// Execute request
-	resp, err := p.client.Do(req)
-	if err != nil {
-		return "", &ProviderError{
-			Code:    constants.ErrCodeNetworkError,
-			Message: constants.GetErrorMessage(constants.ErrCodeNetworkError),
-			Err:     err,
-		}
+		return "", err
 	}
 	defer resp.Body.Close()
 
 	// Handle error responses
-	if err := p.handleHTTPError(resp); err != nil {
+	if err := c.handleHTTPError(resp); err != nil {
 		return "", err
 	}
 
@@ -234,6 +233,36 @@ func (p *AirtableProvider) SubmitRecord(ctx context.Context, schema *dtos.Entity
 	return airtableResp.Records[0].ID, nil
 }
 
+// UpdateRecord patches fields of an existing Airtable record
+func (c *airtableClient) UpdateRecord(ctx context.Context, schema *dtos.EntitySchema, recordID string, fields map[string]interface{}) error {
+	payloadBytes, err := json.Marshal(map[string]interface{}{
+		"fields": fields,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	// Build Airtable API URL
+	url := fmt.Sprintf("%s/%s/%s/%s",
+		c.baseURL,
+		c.config.Credentials.BaseID,
+		schema.TableName,
+		recordID,
+	)
+
+	// PATCH only changes the fields sent; PUT would clear the others. Sending the same
+	// fields again is harmless, so it is safe to retry.
+	resp, err := c.do(ctx, true, func(ctx context.Context) (*http.Request, error) {
+		return c.newRequest(ctx, "PATCH", url, payloadBytes)
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return c.handleHTTPError(resp)
+}
+
 // ValidateConfig validates the Airtable configuration
 func (p *AirtableProvider) ValidateConfig(ctx context.Context, config *dtos.ProviderConfigData) (*ValidationResult, error) {
 	startTime := time.Now()
@@ -246,7 +275,7 @@ func (p *AirtableProvider) ValidateConfig(ctx context.Context, config *dtos.Prov
 	}
 
 	// Phase 1: Credential Validation
-	if err := p.validateCredentials(ctx, config); err != nil {
+	if err := p.newClient(config).validateCredentials(ctx); err != nil {
 		result.IsValid = false
 		result.PhasesFailed = append(result.PhasesFailed, "credential_validation")
 		if provErr, ok := err.(*ProviderError); ok {
@@ -275,31 +304,41 @@ func (p *AirtableProvider) ValidateConfig(ctx context.Context, config *dtos.Prov
 }
 
 // validateCredentials checks if the API key and base ID are valid
-func (p *AirtableProvider) validateCredentials(ctx context.Context, config *dtos.ProviderConfigData) error {
-	url := fmt.Sprintf("https://api.airtable.com/v0/meta/bases/%s/tables", config.Credentials.BaseID)
+func (c *airtableClient) validateCredentials(ctx context.Context) error {
+	url := fmt.Sprintf("%s/meta/bases/%s/tables", c.baseURL, c.config.Credentials.BaseID)
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	resp, err := c.do(ctx, true, func(ctx context.Context) (*http.Request, error) {
+		return c.newRequest(ctx, "GET", url, nil)
+	})
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return err
 	}
+	defer resp.Body.Close()
 
-	req.Header.Set("Authorization", "Bearer "+config.Credentials.APIKey)
+	return c.handleHTTPError(resp)
+}
 
-	resp, err := p.client.Do(req)
+// newRequest builds an authenticated Airtable request with an optional JSON body
+func (c *airtableClient) newRequest(ctx context.Context, method string, url string, body []byte) (*http.Request, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
 	if err != nil {
-		return &ProviderError{
-			Code:    constants.ErrCodeNetworkError,
-			Message: constants.GetErrorMessage(constants.ErrCodeNetworkError),
-			Err:     err,
-		}
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	return p.handleHTTPError(resp)
+	req.Header.Set("Authorization", "Bearer "+c.config.Credentials.APIKey)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
 }
 
 // handleHTTPError converts HTTP errors to ProviderError
-func (p *AirtableProvider) handleHTTPError(resp *http.Response) error {
+func (c *airtableClient) handleHTTPError(resp *http.Response) error {
 	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
 		return nil
 	}
@@ -335,7 +374,7 @@ func (p *AirtableProvider) handleHTTPError(resp *http.Response) error {
 }
 
 // normalizeFields maps raw Airtable fields to internal field names
-func (p *AirtableProvider) normalizeFields(rawFields map[string]interface{}, schema *dtos.EntitySchema) map[string]interface{} {
+func (c *airtableClient) normalizeFields(rawFields map[string]interface{}, schema *dtos.EntitySchema) map[string]interface{} {
 	normalized := make(map[string]interface{})
 
 	for _, fieldMapping := range schema.Fields {
@@ -350,7 +389,7 @@ func (p *AirtableProvider) normalizeFields(rawFields map[string]interface{}, sch
 }
 
 // buildFetchPayload builds the request payload for fetching records
-func (p *AirtableProvider) buildFetchPayload(schema *dtos.EntitySchema, filters *SyncFilters) map[string]interface{} {
+func (c *airtableClient) buildFetchPayload(schema *dtos.EntitySchema, filters *SyncFilters) map[string]interface{} {
 	payload := make(map[string]interface{})
 
 	// Add fields to fetch
@@ -361,13 +400,19 @@ func (p *AirtableProvider) buildFetchPayload(schema *dtos.EntitySchema, filters
 
 	// Add filter - prioritize custom filter formula over modified since
 	if filters != nil {
-		if filters.FilterFormula != "" {
+		if !filters.FilterFormula.IsZero() {
 			// Use custom filter formula if provided
-			payload["filterByFormula"] = filters.FilterFormula
+			payload["filterByFormula"] = filters.FilterFormula.String()
+		} else if filters.MatchField != "" {
+			// Exact match on a single field: {Callsign} = "TEST012"
+			payload["filterByFormula"] = airtable.Equals(airtable.Field(filters.MatchField), filters.MatchValue).String()
 		} else if filters.ModifiedSince != nil && schema.LastModifiedField != "" {
-			// Fall back to modified since filter
-			formula := fmt.Sprintf("IS_AFTER({%s}, '%s')", schema.LastModifiedField, *filters.ModifiedSince)
-			payload["filterByFormula"] = formula
+			// Fall back to modified since filter; an unparseable time fetches everything
+			if since, err := time.Parse(time.RFC3339, *filters.ModifiedSince); err == nil {
+				payload["filterByFormula"] = airtable.IsAfter(airtable.Field(schema.LastModifiedField), since).String()
+			} else {
+				log.Printf("[AirtableProvider] Ignoring invalid modified-since time %q: %v", *filters.ModifiedSince, err)
+			}
 		}
 	}
 
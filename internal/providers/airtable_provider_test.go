@@ -0,0 +1,261 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"infinite-experiment/politburo/internal/airtable"
+	"infinite-experiment/politburo/internal/common"
+	"infinite-experiment/politburo/internal/constants"
+	"infinite-experiment/politburo/internal/models/dtos"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestAirtableClient(t *testing.T, handler http.HandlerFunc, settings dtos.SyncSettings) (*airtableClient, *dtos.EntitySchema) {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	config := &dtos.ProviderConfigData{
+		Provider:     ProviderTypeAirtable,
+		Credentials:  dtos.ProviderCreds{APIKey: "key", BaseID: "appTest"},
+		SyncSettings: settings,
+	}
+	provider := NewAirtableProvider(nil, nil)
+	provider.baseURL = server.URL
+
+	schema := &dtos.EntitySchema{EntityType: "pilot", TableName: "Pilots", Enabled: true}
+	return provider.newClient(config), schema
+}
+
+func TestAirtableProvider_FetchRecords_RetriesAfterRateLimit(t *testing.T) {
+	var calls atomic.Int32
+	client, schema := newTestAirtableClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"records":[{"id":"rec1","fields":{"Callsign":"ABC001"}}]}`))
+	}, dtos.SyncSettings{RetryAttempts: 2})
+
+	stats := &common.SyncRunStats{}
+	start := time.Now()
+	recordSet, err := client.FetchRecords(common.WithSyncRunStats(context.Background(), stats), schema, &SyncFilters{})
+	if err != nil {
+		t.Fatalf("FetchRecords: %v", err)
+	}
+	if len(recordSet.Records) != 1 || calls.Load() != 2 {
+		t.Fatalf("got %d records after %d calls, want 1 record after 2 calls", len(recordSet.Records), calls.Load())
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %s, want at least the 1s Retry-After", elapsed)
+	}
+	if waits, waited := stats.RateLimitWaits(); waits != 1 || waited < 900*time.Millisecond {
+		t.Errorf("recorded %d rate-limit waits totalling %s, want 1 of about 1s", waits, waited)
+	}
+}
+
+func TestAirtableProvider_FetchRecords_GivesUpAfterRetryAttempts(t *testing.T) {
+	var calls atomic.Int32
+	client, schema := newTestAirtableClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}, dtos.SyncSettings{RetryAttempts: 2})
+
+	_, err := client.FetchRecords(context.Background(), schema, &SyncFilters{})
+	var provErr *ProviderError
+	if !errors.As(err, &provErr) || provErr.Code != constants.ErrCodeRateLimited {
+		t.Fatalf("got error %v, want %s", err, constants.ErrCodeRateLimited)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("got %d calls, want 3 (1 + 2 retries)", calls.Load())
+	}
+}
+
+func TestAirtableProvider_SubmitRecord_DoesNotRetryServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	client, schema := newTestAirtableClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}, dtos.SyncSettings{RetryAttempts: 3})
+
+	if _, err := client.SubmitRecord(context.Background(), schema, map[string]interface{}{"Callsign": "ABC001"}); err == nil {
+		t.Fatal("SubmitRecord succeeded, want an error")
+	}
+	if calls.Load() != 1 {
+		t.Errorf("got %d calls, want 1: a failed create may have gone through", calls.Load())
+	}
+}
+
+func TestSubmitMayHaveCreated(t *testing.T) {
+	fields := map[string]interface{}{"Callsign": "ABC001"}
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		closed  bool // The server is down before the request is sent
+		want    bool
+	}{
+		{"rate limited", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}, false, false},
+		{"server error", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusServiceUnavailable) }, false, false},
+		{"rejected", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusUnprocessableEntity) }, false, false},
+		{"connection refused", nil, true, false},
+		{"response timeout", func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(1500 * time.Millisecond):
+			}
+		}, false, true},
+		{"unreadable success response", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("{")) }, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			if tt.closed {
+				server.Close()
+			} else {
+				defer server.Close()
+			}
+
+			provider := NewAirtableProvider(nil, nil)
+			provider.baseURL = server.URL
+			client := provider.newClient(&dtos.ProviderConfigData{
+				Provider:     ProviderTypeAirtable,
+				Credentials:  dtos.ProviderCreds{APIKey: "key", BaseID: "appTest"},
+				SyncSettings: dtos.SyncSettings{RetryAttempts: 1, TimeoutSeconds: 1},
+			})
+
+			_, err := client.SubmitRecord(context.Background(), &dtos.EntitySchema{EntityType: "pirep", TableName: "PIREPs"}, fields)
+			if err == nil {
+				t.Fatal("SubmitRecord succeeded, want an error")
+			}
+			if got := SubmitMayHaveCreated(err); got != tt.want {
+				t.Errorf("SubmitMayHaveCreated(%v) = %t, want %t", err, got, tt.want)
+			}
+		})
+	}
+
+	// Giving up while waiting to send never reaches the provider
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if SubmitMayHaveCreated(ctx.Err()) {
+		t.Error("a cancelled wait may have created the record")
+	}
+	if SubmitMayHaveCreated(nil) {
+		t.Error("no error may have created the record")
+	}
+}
+
+func TestAirtableProvider_UpdateRecord_RetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	client, schema := newTestAirtableClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"id":"rec1","fields":{}}`))
+	}, dtos.SyncSettings{RetryAttempts: 1})
+
+	if err := client.UpdateRecord(context.Background(), schema, "rec1", map[string]interface{}{"Callsign": "ABC002"}); err != nil {
+		t.Fatalf("UpdateRecord: %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("got %d calls, want 2", calls.Load())
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if d, ok := parseRetryAfter("30"); !ok || d != 30*time.Second {
+		t.Errorf("parseRetryAfter(30) = %s, %t", d, ok)
+	}
+	if d, ok := parseRetryAfter(time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)); !ok || d < 58*time.Second || d > time.Minute {
+		t.Errorf("parseRetryAfter(date) = %s, %t", d, ok)
+	}
+	if _, ok := parseRetryAfter("soon"); ok {
+		t.Error("parseRetryAfter(soon) parsed")
+	}
+}
+
+func TestAirtableBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{-1, airtableBaseBackoff},
+		{0, airtableBaseBackoff},
+		{1, 2 * airtableBaseBackoff},
+		{5, 16 * time.Second},
+		{6, airtableMaxBackoff},
+		{16, airtableMaxBackoff},
+		{30, airtableMaxBackoff},
+		{33, airtableMaxBackoff},
+		{64, airtableMaxBackoff},
+		{1000, airtableMaxBackoff},
+	}
+
+	for _, tt := range tests {
+		if got := airtableBackoff(tt.attempt); got != tt.want {
+			t.Errorf("airtableBackoff(%d) = %s, want %s", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestSettingsFor_CapsRetryAttempts(t *testing.T) {
+	tests := []struct {
+		configured int
+		want       int
+	}{
+		{0, defaultAirtableRetryAttempts},
+		{-1, defaultAirtableRetryAttempts},
+		{5, 5},
+		{maxAirtableRetryAttempts, maxAirtableRetryAttempts},
+		{1000, maxAirtableRetryAttempts},
+	}
+
+	for _, tt := range tests {
+		config := &dtos.ProviderConfigData{SyncSettings: dtos.SyncSettings{RetryAttempts: tt.configured}}
+		if got := settingsFor(config).retryAttempts; got != tt.want {
+			t.Errorf("retry attempts %d resolved to %d, want %d", tt.configured, got, tt.want)
+		}
+	}
+}
+
+func TestAirtableProvider_BuildFetchPayload_Filters(t *testing.T) {
+	since := "2024-05-01T12:00:00Z"
+	invalid := "yesterday"
+	schema := &dtos.EntitySchema{TableName: "Pilots", LastModifiedField: "Last Modified"}
+
+	tests := []struct {
+		name    string
+		filters *SyncFilters
+		want    interface{}
+	}{
+		{"none", &SyncFilters{}, nil},
+		{"match", &SyncFilters{MatchField: "Callsign", MatchValue: "O'Neil"}, `{Callsign} = "O'Neil"`},
+		{"match quote", &SyncFilters{MatchField: "Callsign", MatchValue: `ABC" & "1`}, `{Callsign} = "ABC\" & \"1"`},
+		{"modified since", &SyncFilters{ModifiedSince: &since}, `IS_AFTER({Last Modified}, "2024-05-01T12:00:00Z")`},
+		{"invalid modified since", &SyncFilters{ModifiedSince: &invalid}, nil},
+		{
+			"formula wins",
+			&SyncFilters{FilterFormula: airtable.Equals(airtable.Field("Active"), true), MatchField: "Callsign", ModifiedSince: &since},
+			`{Active} = TRUE()`,
+		},
+	}
+
+	client := NewAirtableProvider(nil, nil).newClient(&dtos.ProviderConfigData{Provider: ProviderTypeAirtable})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := client.buildFetchPayload(schema, tt.filters)["filterByFormula"]
+			if got != tt.want {
+				t.Errorf("filterByFormula = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,11 +2,37 @@ package providers
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"infinite-experiment/politburo/internal/airtable"
 	"infinite-experiment/politburo/internal/models/dtos"
+	"net"
+	"strconv"
 )
 
-// DataProvider defines the interface for external data sources
+// Provider type identifiers as stored in va_data_provider_configs.provider_type
+const (
+	ProviderTypeAirtable     = "airtable"
+	ProviderTypeGoogleSheets = "google_sheets"
+	ProviderTypeREST         = "rest"
+)
+
+// DataProvider defines the interface for external data sources. A provider holds what is
+// shared between VAs; everything that talks to a VA's data source goes through a
+// ProviderClient created for that VA's config, see Registry.Client.
 type DataProvider interface {
+	// NewClient creates a client bound to one VA's config
+	NewClient(config *dtos.ProviderConfigData) ProviderClient
+
+	// ValidateConfig validates that the configuration is valid and can connect
+	ValidateConfig(ctx context.Context, config *dtos.ProviderConfigData) (*ValidationResult, error)
+
+	// GetProviderType returns the provider type identifier
+	GetProviderType() string
+}
+
+// ProviderClient reads and writes the records of one VA's data source
+type ProviderClient interface {
 	// FetchPilotRecord fetches a single pilot record by their provider-specific ID
 	FetchPilotRecord(ctx context.Context, pilotID string, schema *dtos.EntitySchema) (*PilotRecord, error)
 
@@ -16,13 +42,82 @@ type DataProvider interface {
 	// SubmitRecord creates a new record in the data source
 	SubmitRecord(ctx context.Context, schema *dtos.EntitySchema, fields map[string]interface{}) (string, error)
 
-	// ValidateConfig validates that the configuration is valid and can connect
-	ValidateConfig(ctx context.Context, config *dtos.ProviderConfigData) (*ValidationResult, error)
-
 	// GetProviderType returns the provider type identifier
 	GetProviderType() string
 }
 
+// RecordUpdater is implemented by clients that can update fields of an existing record
+type RecordUpdater interface {
+	// UpdateRecord sets the given fields (keyed by external field name) on a record, leaving others untouched
+	UpdateRecord(ctx context.Context, schema *dtos.EntitySchema, recordID string, fields map[string]interface{}) error
+}
+
+// FieldString renders a scalar record field value as text for comparisons
+func FieldString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// FindRecord pages through a schema's records until one whose field equals value is found.
+// Returns nil when no record matches.
+func FindRecord(ctx context.Context, provider ProviderClient, schema *dtos.EntitySchema, field, value string) (*RecordWithID, error) {
+	filters := &SyncFilters{MatchField: field, MatchValue: value}
+	for {
+		recordSet, err := provider.FetchRecords(ctx, schema, filters)
+		if err != nil {
+			return nil, err
+		}
+		if len(recordSet.Records) > 0 {
+			return &recordSet.Records[0], nil
+		}
+		if !recordSet.HasMore {
+			return nil, nil
+		}
+		filters.Offset = recordSet.Offset
+	}
+}
+
+// SubmitMayHaveCreated reports whether a failed SubmitRecord may still have created the record,
+// i.e. the request may have reached the provider but its outcome was lost (a response timeout or a
+// connection dropped after sending). Error responses such as a 429 or 5xx, and requests that never
+// left (dial and DNS failures, cancellation while waiting to send), did not create it.
+func SubmitMayHaveCreated(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var providerErr *ProviderError
+	if !errors.As(err, &providerErr) {
+		// Bare context errors come from waiting to send; anything else (e.g. an unreadable
+		// success response) happened after the provider may have accepted the request
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	if providerErr.Err == nil {
+		// The provider answered with an error status
+		return false
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(providerErr.Err, &dnsErr) {
+		return false
+	}
+	var opErr *net.OpError
+	if errors.As(providerErr.Err, &opErr) && opErr.Op == "dial" {
+		return false
+	}
+	return true
+}
+
 // PilotRecord represents a pilot's data fetched from the provider
 type PilotRecord struct {
 	ProviderID string                 // The record ID from the provider (e.g., Airtable record ID)
@@ -47,10 +142,12 @@ type RecordWithID struct {
 
 // SyncFilters defines filters for fetching records
 type SyncFilters struct {
-	ModifiedSince *string // ISO 8601 timestamp - fetch only records modified after this
-	Offset        string  // Pagination offset
-	Limit         int     // Max records to fetch
-	FilterFormula string  // Custom filter formula (e.g., Airtable formula for field matching)
+	ModifiedSince *string          // ISO 8601 timestamp - fetch only records modified after this
+	Offset        string           // Pagination offset
+	Limit         int              // Max records to fetch
+	FilterFormula airtable.Formula // Custom Airtable filter formula; not supported by other providers
+	MatchField    string           // External field name that must equal MatchValue (provider-neutral exact match)
+	MatchValue    string           // Value MatchField must equal
 }
 
 // ValidationResult contains the results of config validation
@@ -0,0 +1,811 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"infinite-experiment/politburo/internal/common"
+	"infinite-experiment/politburo/internal/constants"
+	"infinite-experiment/politburo/internal/models/dtos"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	googleSheetsAPIURL    = "https://sheets.googleapis.com/v4"
+	googleOAuthTokenURL   = "https://oauth2.googleapis.com/token"
+	googleSheetsScope     = "https://www.googleapis.com/auth/spreadsheets"
+	defaultSheetsPageSize = 500
+)
+
+// GoogleSheetsProvider implements DataProvider for Google Sheets.
+//
+// Credentials.BaseID is the spreadsheet ID and each schema's TableName is a sheet tab.
+// Row 1 of a tab holds the column headers that field mappings refer to. Records are
+// identified by the schema's IDField column, or by their row number when it is unset.
+type GoogleSheetsProvider struct {
+	cache common.CacheInterface
+
+	BaseURL  string // Sheets API base URL, overridable for tests
+	TokenURL string // OAuth token endpoint used when the service account key has none
+}
+
+// NewGoogleSheetsProvider creates a new Google Sheets provider
+func NewGoogleSheetsProvider(cache common.CacheInterface) *GoogleSheetsProvider {
+	return &GoogleSheetsProvider{
+		cache:    cache,
+		BaseURL:  googleSheetsAPIURL,
+		TokenURL: googleOAuthTokenURL,
+	}
+}
+
+// GetProviderType returns the provider type identifier
+func (p *GoogleSheetsProvider) GetProviderType() string {
+	return ProviderTypeGoogleSheets
+}
+
+// NewClient creates a client for one VA's spreadsheet
+func (p *GoogleSheetsProvider) NewClient(config *dtos.ProviderConfigData) ProviderClient {
+	return p.newClient(config)
+}
+
+// googleSheetsClient talks to the spreadsheet of one VA config
+type googleSheetsClient struct {
+	provider *GoogleSheetsProvider
+	config   *dtos.ProviderConfigData
+	client   *http.Client
+	cache    common.CacheInterface // Namespaced to the config, holds its access tokens
+}
+
+func (p *GoogleSheetsProvider) newClient(config *dtos.ProviderConfigData) *googleSheetsClient {
+	return &googleSheetsClient{
+		provider: p,
+		config:   config,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		cache: namespaceCache(p.cache, ProviderTypeGoogleSheets, config),
+	}
+}
+
+// GetProviderType returns the provider type identifier
+func (c *googleSheetsClient) GetProviderType() string {
+	return ProviderTypeGoogleSheets
+}
+
+// FetchPilotRecord fetches a single pilot record by its ID column value or row number
+func (c *googleSheetsClient) FetchPilotRecord(ctx context.Context, pilotID string, schema *dtos.EntitySchema) (*PilotRecord, error) {
+	var record *RecordWithID
+
+	if schema.IDField != "" {
+		// Scan the tab for the row whose ID column matches
+		found, err := FindRecord(ctx, c, schema, schema.IDField, pilotID)
+		if err != nil {
+			return nil, err
+		}
+		record = found
+	} else {
+		row, err := strconv.Atoi(pilotID)
+		if err != nil || row < 2 {
+			return nil, fmt.Errorf("invalid row number for Google Sheets record: %s", pilotID)
+		}
+
+		headers, rows, err := c.fetchRows(ctx, schema.TableName, row, row)
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) > 0 {
+			record = buildSheetRecord(headers, rows[0], row, schema)
+		}
+	}
+
+	if record == nil {
+		return nil, &ProviderError{
+			Code:    constants.ErrCodePilotNotFoundInAirtable,
+			Message: fmt.Sprintf("No row found for record %s in sheet %s", pilotID, schema.TableName),
+		}
+	}
+
+	return &PilotRecord{
+		ProviderID: record.ID,
+		RawFields:  record.Fields,
+		Normalized: c.normalizeFields(record.Fields, schema),
+	}, nil
+}
+
+// FetchRecords fetches a page of rows. The offset is the sheet row the page starts at.
+// Exact-match and modified-since filters are applied to the fetched page, so a page may
+// hold fewer records than the limit while HasMore is still true.
+func (c *googleSheetsClient) FetchRecords(ctx context.Context, schema *dtos.EntitySchema, filters *SyncFilters) (*RecordSet, error) {
+	if filters == nil {
+		filters = &SyncFilters{}
+	}
+	if !filters.FilterFormula.IsZero() {
+		return nil, fmt.Errorf("filter formulas are not supported by Google Sheets; use MatchField instead")
+	}
+
+	startRow := 2
+	if filters.Offset != "" {
+		row, err := strconv.Atoi(filters.Offset)
+		if err != nil || row < 2 {
+			return nil, fmt.Errorf("invalid Google Sheets offset: %s", filters.Offset)
+		}
+		startRow = row
+	}
+
+	pageSize := filters.Limit
+	if pageSize <= 0 {
+		pageSize = defaultSheetsPageSize
+	}
+
+	headers, rows, err := c.fetchRows(ctx, schema.TableName, startRow, startRow+pageSize-1)
+	if err != nil {
+		return nil, err
+	}
+
+	var since *time.Time
+	if filters.ModifiedSince != nil && schema.LastModifiedField != "" {
+		if t, ok := parseSheetTime(*filters.ModifiedSince); ok {
+			since = &t
+		}
+	}
+
+	records := make([]RecordWithID, 0, len(rows))
+	for i, row := range rows {
+		record := buildSheetRecord(headers, row, startRow+i, schema)
+		if record == nil {
+			continue
+		}
+
+		if filters.MatchField != "" && formatSheetCell(record.Fields[filters.MatchField]) != filters.MatchValue {
+			continue
+		}
+
+		if since != nil {
+			// Rows with an unreadable timestamp are kept so they are never silently skipped
+			if modified, ok := parseSheetTime(formatSheetCell(record.Fields[schema.LastModifiedField])); ok && !modified.After(*since) {
+				continue
+			}
+		}
+
+		records = append(records, *record)
+	}
+
+	// The API trims trailing empty rows, so a short page is the last one
+	hasMore := len(rows) == pageSize
+	recordSet := &RecordSet{
+		Records:      records,
+		HasMore:      hasMore,
+		TotalFetched: len(records),
+	}
+	if hasMore {
+		recordSet.Offset = strconv.Itoa(startRow + pageSize)
+	}
+
+	return recordSet, nil
+}
+
+// SubmitRecord appends a row to the sheet, placing each field under its column header
+func (c *googleSheetsClient) SubmitRecord(ctx context.Context, schema *dtos.EntitySchema, fields map[string]interface{}) (string, error) {
+	headers, _, err := c.fetchRows(ctx, schema.TableName, 0, 0)
+	if err != nil {
+		return "", err
+	}
+	if len(headers) == 0 {
+		return "", &ProviderError{
+			Code:    constants.ErrCodeTableEmpty,
+			Message: fmt.Sprintf("Sheet %s has no header row", schema.TableName),
+		}
+	}
+
+	row := make([]interface{}, len(headers))
+	for i, header := range headers {
+		row[i] = sheetCellValue(fields[header])
+	}
+
+	payloadBytes, err := json.Marshal(map[string]interface{}{
+		"values": [][]interface{}{row},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	query := url.Values{}
+	query.Set("valueInputOption", "USER_ENTERED")
+	query.Set("insertDataOption", "INSERT_ROWS")
+	endpoint := fmt.Sprintf("%s/spreadsheets/%s/values/%s:append?%s",
+		c.provider.BaseURL,
+		url.PathEscape(c.config.Credentials.BaseID),
+		url.PathEscape(sheetRange(schema.TableName, "A1")),
+		query.Encode(),
+	)
+
+	var appendResp struct {
+		Updates struct {
+			UpdatedRange string `json:"updatedRange"`
+		} `json:"updates"`
+	}
+	if err := c.doJSON(ctx, "POST", endpoint, bytes.NewReader(payloadBytes), &appendResp); err != nil {
+		return "", err
+	}
+
+	if schema.IDField != "" {
+		if id := formatSheetCell(fields[schema.IDField]); id != "" {
+			return id, nil
+		}
+	}
+
+	rowNumber := appendedRowNumber(appendResp.Updates.UpdatedRange)
+	if rowNumber == "" {
+		return "", fmt.Errorf("no updated range returned from Google Sheets")
+	}
+
+	return rowNumber, nil
+}
+
+// ValidateConfig validates the Google Sheets configuration
+func (p *GoogleSheetsProvider) ValidateConfig(ctx context.Context, config *dtos.ProviderConfigData) (*ValidationResult, error) {
+	startTime := time.Now()
+	result := &ValidationResult{
+		IsValid:         true,
+		PhasesCompleted: []string{},
+		PhasesFailed:    []string{},
+		Errors:          []dtos.ValidationError{},
+		Warnings:        []dtos.ValidationError{},
+	}
+
+	fail := func(phase string, err error, schema *dtos.EntitySchema) {
+		result.IsValid = false
+		result.PhasesFailed = append(result.PhasesFailed, phase)
+		validationErr := dtos.ValidationError{
+			Phase:     phase,
+			Error:     err.Error(),
+			ErrorCode: constants.ErrCodeNetworkError,
+			Timestamp: time.Now().Format(time.RFC3339),
+		}
+		if provErr, ok := err.(*ProviderError); ok {
+			validationErr.Error = provErr.Message
+			validationErr.ErrorCode = provErr.Code
+		}
+		if schema != nil {
+			validationErr.EntityType = schema.EntityType
+			validationErr.TableName = schema.TableName
+		}
+		result.Errors = append(result.Errors, validationErr)
+	}
+
+	client := p.newClient(config)
+
+	// Phase 1: Credential Validation
+	titles, err := client.fetchSheetTitles(ctx)
+	if err != nil {
+		fail("credential_validation", err, nil)
+		result.DurationMs = int(time.Since(startTime).Milliseconds())
+		return result, nil
+	}
+	result.PhasesCompleted = append(result.PhasesCompleted, "credential_validation")
+
+	// Phase 2: Table Validation - every enabled schema needs a tab
+	tablesOK := true
+	for i := range config.Schemas {
+		schema := &config.Schemas[i]
+		if schema.Enabled && !titles[schema.TableName] {
+			tablesOK = false
+			fail("table_validation", &ProviderError{
+				Code:    constants.ErrCodeTableNotFound,
+				Message: fmt.Sprintf("Sheet tab %q was not found in the spreadsheet", schema.TableName),
+			}, schema)
+		}
+	}
+	if !tablesOK {
+		result.DurationMs = int(time.Since(startTime).Milliseconds())
+		return result, nil
+	}
+	result.PhasesCompleted = append(result.PhasesCompleted, "table_validation")
+
+	// Phase 3: Field Validation - mapped columns must appear in the header row
+	fieldsOK := true
+	for i := range config.Schemas {
+		schema := &config.Schemas[i]
+		if !schema.Enabled {
+			continue
+		}
+
+		headers, _, err := client.fetchRows(ctx, schema.TableName, 0, 0)
+		if err != nil {
+			fieldsOK = false
+			fail("field_validation", err, schema)
+			continue
+		}
+
+		present := make(map[string]bool, len(headers))
+		for _, header := range headers {
+			present[header] = true
+		}
+
+		for _, field := range schema.Fields {
+			if present[field.AirtableName] {
+				continue
+			}
+			fieldErr := dtos.ValidationError{
+				Phase:      "field_validation",
+				EntityType: schema.EntityType,
+				TableName:  schema.TableName,
+				Error:      fmt.Sprintf("Column %q was not found in the header row", field.AirtableName),
+				ErrorCode:  constants.ErrCodeFieldNotFound,
+				Timestamp:  time.Now().Format(time.RFC3339),
+			}
+			if field.Required {
+				fieldsOK = false
+				result.IsValid = false
+				result.Errors = append(result.Errors, fieldErr)
+			} else {
+				result.Warnings = append(result.Warnings, fieldErr)
+			}
+		}
+	}
+	if fieldsOK {
+		result.PhasesCompleted = append(result.PhasesCompleted, "field_validation")
+	} else {
+		result.PhasesFailed = append(result.PhasesFailed, "field_validation")
+	}
+
+	result.DurationMs = int(time.Since(startTime).Milliseconds())
+	return result, nil
+}
+
+// fetchSheetTitles returns the tab titles of the spreadsheet
+func (c *googleSheetsClient) fetchSheetTitles(ctx context.Context) (map[string]bool, error) {
+	endpoint := fmt.Sprintf("%s/spreadsheets/%s?fields=%s",
+		c.provider.BaseURL,
+		url.PathEscape(c.config.Credentials.BaseID),
+		url.QueryEscape("sheets.properties.title"),
+	)
+
+	var metaResp struct {
+		Sheets []struct {
+			Properties struct {
+				Title string `json:"title"`
+			} `json:"properties"`
+		} `json:"sheets"`
+	}
+	if err := c.doJSON(ctx, "GET", endpoint, nil, &metaResp); err != nil {
+		return nil, err
+	}
+
+	titles := make(map[string]bool, len(metaResp.Sheets))
+	for _, sheet := range metaResp.Sheets {
+		titles[sheet.Properties.Title] = true
+	}
+	return titles, nil
+}
+
+// fetchRows fetches the header row and rows firstRow..lastRow of a tab in one request.
+// Passing a zero firstRow fetches the header row only.
+func (c *googleSheetsClient) fetchRows(ctx context.Context, tab string, firstRow, lastRow int) ([]string, [][]interface{}, error) {
+	query := url.Values{}
+	query.Add("ranges", sheetRange(tab, "1:1"))
+	if firstRow > 0 {
+		query.Add("ranges", sheetRange(tab, fmt.Sprintf("%d:%d", firstRow, lastRow)))
+	}
+	query.Set("majorDimension", "ROWS")
+	query.Set("valueRenderOption", "UNFORMATTED_VALUE")
+	query.Set("dateTimeRenderOption", "FORMATTED_STRING")
+
+	endpoint := fmt.Sprintf("%s/spreadsheets/%s/values:batchGet?%s",
+		c.provider.BaseURL,
+		url.PathEscape(c.config.Credentials.BaseID),
+		query.Encode(),
+	)
+
+	var batchResp struct {
+		ValueRanges []struct {
+			Values [][]interface{} `json:"values"`
+		} `json:"valueRanges"`
+	}
+	if err := c.doJSON(ctx, "GET", endpoint, nil, &batchResp); err != nil {
+		return nil, nil, err
+	}
+
+	var headers []string
+	if len(batchResp.ValueRanges) > 0 && len(batchResp.ValueRanges[0].Values) > 0 {
+		for _, cell := range batchResp.ValueRanges[0].Values[0] {
+			headers = append(headers, strings.TrimSpace(formatSheetCell(cell)))
+		}
+	}
+
+	var rows [][]interface{}
+	if len(batchResp.ValueRanges) > 1 {
+		rows = batchResp.ValueRanges[1].Values
+	}
+
+	return headers, rows, nil
+}
+
+// doJSON sends an authorised request and decodes a successful JSON response into out
+func (c *googleSheetsClient) doJSON(ctx context.Context, method, endpoint string, body io.Reader, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if err := c.authorize(ctx, req); err != nil {
+		return err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return &ProviderError{
+			Code:    constants.ErrCodeNetworkError,
+			Message: "Unable to connect to Google Sheets",
+			Err:     err,
+		}
+	}
+	defer resp.Body.Close()
+
+	if err := c.handleHTTPError(resp); err != nil {
+		return err
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// authorize adds a service account bearer token, or the API key for read-only public sheets
+func (c *googleSheetsClient) authorize(ctx context.Context, req *http.Request) error {
+	creds := &c.config.Credentials
+	if creds.ServiceAccountJSON != "" {
+		token, err := c.accessToken(ctx, creds.ServiceAccountJSON)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	}
+
+	if creds.APIKey != "" {
+		query := req.URL.Query()
+		query.Set("key", creds.APIKey)
+		req.URL.RawQuery = query.Encode()
+		return nil
+	}
+
+	return &ProviderError{
+		Code:    constants.ErrCodeAuthenticationFailed,
+		Message: "Google Sheets requires a service account key or an API key",
+	}
+}
+
+// serviceAccountKey is the subset of a Google service account key file we need
+type serviceAccountKey struct {
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// accessToken exchanges a signed service account assertion for an OAuth access token,
+// caching it until shortly before it expires
+func (c *googleSheetsClient) accessToken(ctx context.Context, keyJSON string) (string, error) {
+	var key serviceAccountKey
+	if err := json.Unmarshal([]byte(keyJSON), &key); err != nil || key.ClientEmail == "" || key.PrivateKey == "" {
+		return "", &ProviderError{
+			Code:    constants.ErrCodeInvalidAPIKey,
+			Message: "The Google service account key is malformed",
+			Err:     err,
+		}
+	}
+
+	cacheKey := "gsheets_token:" + key.ClientEmail
+	if c.cache != nil {
+		if cached, found := c.cache.Get(cacheKey); found {
+			if token, ok := cached.(string); ok && token != "" {
+				return token, nil
+			}
+		}
+	}
+
+	tokenURL := key.TokenURI
+	if tokenURL == "" {
+		tokenURL = c.provider.TokenURL
+	}
+
+	assertion, err := signServiceAccountJWT(&key, tokenURL, time.Now())
+	if err != nil {
+		return "", &ProviderError{
+			Code:    constants.ErrCodeInvalidAPIKey,
+			Message: "The Google service account key could not be used for signing",
+			Err:     err,
+		}
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+	form.Set("assertion", assertion)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", &ProviderError{
+			Code:    constants.ErrCodeNetworkError,
+			Message: "Unable to connect to Google OAuth",
+			Err:     err,
+		}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return "", &ProviderError{
+			Code:    constants.ErrCodeAuthenticationFailed,
+			Message: "Authentication with Google failed",
+			Details: string(body),
+		}
+	}
+
+	var tokenResp struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return "", fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if tokenResp.AccessToken == "" {
+		return "", &ProviderError{
+			Code:    constants.ErrCodeAuthenticationFailed,
+			Message: "Authentication with Google failed",
+			Details: "no access token returned",
+		}
+	}
+
+	// Refresh a minute early so a token never expires mid-request
+	if c.cache != nil && tokenResp.ExpiresIn > 120 {
+		c.cache.Set(cacheKey, tokenResp.AccessToken, time.Duration(tokenResp.ExpiresIn-60)*time.Second)
+	}
+
+	return tokenResp.AccessToken, nil
+}
+
+// signServiceAccountJWT builds the RS256-signed assertion for the JWT bearer grant
+func signServiceAccountJWT(key *serviceAccountKey, audience string, now time.Time) (string, error) {
+	block, _ := pem.Decode([]byte(key.PrivateKey))
+	if block == nil {
+		return "", fmt.Errorf("private key is not PEM encoded")
+	}
+
+	var rsaKey *rsa.PrivateKey
+	if parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		k, ok := parsed.(*rsa.PrivateKey)
+		if !ok {
+			return "", fmt.Errorf("private key is not an RSA key")
+		}
+		rsaKey = k
+	} else if k, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		rsaKey = k
+	} else {
+		return "", fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]interface{}{
+		"iss":   key.ClientEmail,
+		"scope": googleSheetsScope,
+		"aud":   audience,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign assertion: %w", err)
+	}
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// handleHTTPError converts HTTP errors to ProviderError
+func (c *googleSheetsClient) handleHTTPError(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		return &ProviderError{
+			Code:    constants.ErrCodeInvalidAPIKey,
+			Message: "The Google Sheets credentials are invalid or have been revoked",
+			Details: string(body),
+		}
+	case http.StatusForbidden:
+		return &ProviderError{
+			Code:    constants.ErrCodeTableAccessDenied,
+			Message: "The credentials don't have access to this spreadsheet; share it with the service account",
+			Details: string(body),
+		}
+	case http.StatusNotFound:
+		return &ProviderError{
+			Code:    constants.ErrCodeInvalidBaseID,
+			Message: "The spreadsheet ID is invalid or the spreadsheet doesn't exist",
+			Details: string(body),
+		}
+	case http.StatusBadRequest:
+		// Sheets answers unknown tab names with "Unable to parse range"
+		if strings.Contains(string(body), "Unable to parse range") {
+			return &ProviderError{
+				Code:    constants.ErrCodeTableNotFound,
+				Message: "The specified sheet tab was not found in the spreadsheet",
+				Details: string(body),
+			}
+		}
+		return &ProviderError{
+			Code:    constants.ErrCodeInvalidDataFormat,
+			Message: fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(body)),
+			Details: string(body),
+		}
+	case http.StatusTooManyRequests:
+		return &ProviderError{
+			Code:    constants.ErrCodeRateLimited,
+			Message: constants.GetErrorMessage(constants.ErrCodeRateLimited),
+			Details: string(body),
+		}
+	default:
+		return &ProviderError{
+			Code:    constants.ErrCodeNetworkError,
+			Message: fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(body)),
+			Details: string(body),
+		}
+	}
+}
+
+// normalizeFields maps raw sheet columns to internal field names
+func (c *googleSheetsClient) normalizeFields(rawFields map[string]interface{}, schema *dtos.EntitySchema) map[string]interface{} {
+	normalized := make(map[string]interface{})
+
+	for _, fieldMapping := range schema.Fields {
+		if value, exists := rawFields[fieldMapping.AirtableName]; exists {
+			normalized[fieldMapping.InternalName] = value
+		} else if fieldMapping.DefaultValue != nil {
+			normalized[fieldMapping.InternalName] = *fieldMapping.DefaultValue
+		}
+	}
+
+	return normalized
+}
+
+// buildSheetRecord keys a row's cells by column header, leaving out empty cells the way
+// Airtable leaves out empty fields. Returns nil for blank rows.
+func buildSheetRecord(headers []string, row []interface{}, rowNumber int, schema *dtos.EntitySchema) *RecordWithID {
+	fields := make(map[string]interface{})
+	for i, cell := range row {
+		if i >= len(headers) || headers[i] == "" {
+			continue
+		}
+		if s, ok := cell.(string); ok && strings.TrimSpace(s) == "" {
+			continue
+		}
+		if cell == nil {
+			continue
+		}
+		fields[headers[i]] = cell
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	id := strconv.Itoa(rowNumber)
+	if schema.IDField != "" {
+		if value := formatSheetCell(fields[schema.IDField]); value != "" {
+			id = value
+		}
+	}
+
+	return &RecordWithID{
+		ID:     id,
+		Fields: fields,
+	}
+}
+
+// formatSheetCell renders an unformatted cell value as text
+func formatSheetCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// sheetCellValue converts a field value into a value a single cell can hold.
+// Lists such as Airtable-style linked record IDs are joined with commas.
+func sheetCellValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []string:
+		return strings.Join(v, ", ")
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = formatSheetCell(item)
+		}
+		return strings.Join(parts, ", ")
+	default:
+		return v
+	}
+}
+
+// sheetTimeLayouts are the timestamp formats accepted in a last-modified column
+var sheetTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"1/2/2006 15:04:05",
+	"1/2/2006 15:04",
+	"1/2/2006",
+}
+
+// parseSheetTime parses a timestamp written in any of the accepted layouts (as UTC if no zone)
+func parseSheetTime(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	for _, layout := range sheetTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// sheetRange builds an A1 range on a tab, quoting the tab name
+func sheetRange(tab, cells string) string {
+	return "'" + strings.ReplaceAll(tab, "'", "''") + "'!" + cells
+}
+
+// appendedRowNumber extracts the first row number from an updated range such as 'Pilots'!A12:F12
+func appendedRowNumber(updatedRange string) string {
+	cells := updatedRange
+	if i := strings.LastIndex(cells, "!"); i >= 0 {
+		cells = cells[i+1:]
+	}
+	if i := strings.Index(cells, ":"); i >= 0 {
+		cells = cells[:i]
+	}
+	digits := strings.TrimLeft(cells, "ABCDEFGHIJKLMNOPQRSTUVWXYZ$")
+	if _, err := strconv.Atoi(digits); err != nil {
+		return ""
+	}
+	return digits
+}
@@ -0,0 +1,227 @@
+package providers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"infinite-experiment/politburo/internal/models/dtos"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// fakeSheet serves a minimal subset of the Sheets API for one tab
+type fakeSheet struct {
+	t        *testing.T
+	rows     [][]interface{} // rows[0] is the header row
+	appended [][]interface{}
+	token    string // bearer token required on API calls, empty to require an API key
+}
+
+func (f *fakeSheet) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/token" {
+		if err := r.ParseForm(); err != nil || r.Form.Get("assertion") == "" {
+			f.t.Errorf("Expected a JWT assertion in token request")
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"access_token": f.token, "expires_in": 3600})
+		return
+	}
+
+	if f.token != "" && r.Header.Get("Authorization") != "Bearer "+f.token {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if f.token == "" && r.URL.Query().Get("key") != "test-key" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/values:batchGet"):
+		var valueRanges []map[string]interface{}
+		for _, rng := range r.URL.Query()["ranges"] {
+			parts := strings.SplitN(rng[strings.Index(rng, "!")+1:], ":", 2)
+			first, _ := strconv.Atoi(parts[0])
+			last, _ := strconv.Atoi(parts[1])
+			var values [][]interface{}
+			for i := first; i <= last && i <= len(f.rows); i++ {
+				values = append(values, f.rows[i-1])
+			}
+			valueRanges = append(valueRanges, map[string]interface{}{"values": values})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"valueRanges": valueRanges})
+
+	case strings.HasSuffix(r.URL.Path, ":append"):
+		var body struct {
+			Values [][]interface{} `json:"values"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		f.appended = append(f.appended, body.Values...)
+		f.rows = append(f.rows, body.Values...)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"updates": map[string]interface{}{"updatedRange": fmt.Sprintf("'Pilots'!A%d:D%d", len(f.rows), len(f.rows))},
+		})
+
+	case strings.HasPrefix(r.URL.Path, "/spreadsheets/"):
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"sheets": []map[string]interface{}{{"properties": map[string]string{"title": "Pilots"}}},
+		})
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func pilotsSchema() *dtos.EntitySchema {
+	return &dtos.EntitySchema{
+		EntityType:        "pilot",
+		TableName:         "Pilots",
+		Enabled:           true,
+		LastModifiedField: "Modified",
+		Fields: []dtos.FieldMapping{
+			{InternalName: "callsign", AirtableName: "Callsign", DataType: "string", Required: true},
+			{InternalName: "flight_hours", AirtableName: "Hours", DataType: "float"},
+		},
+	}
+}
+
+func newFakeSheetProvider(t *testing.T, fake *fakeSheet) (*GoogleSheetsProvider, *googleSheetsClient, *dtos.ProviderConfigData, func()) {
+	server := httptest.NewServer(fake)
+	provider := NewGoogleSheetsProvider(nil)
+	provider.BaseURL = server.URL
+	provider.TokenURL = server.URL + "/token"
+
+	config := &dtos.ProviderConfigData{
+		Provider:    ProviderTypeGoogleSheets,
+		Credentials: dtos.ProviderCreds{APIKey: "test-key", BaseID: "sheet-123"},
+		Schemas:     []dtos.EntitySchema{*pilotsSchema()},
+	}
+	return provider, provider.newClient(config), config, server.Close
+}
+
+func TestGoogleSheetsProvider_FetchRecords_PaginatesAndFilters(t *testing.T) {
+	fake := &fakeSheet{t: t, rows: [][]interface{}{
+		{"Callsign", "Hours", "Modified"},
+		{"VA001", float64(12.5), "2024-01-01 10:00:00"},
+		{"VA002", float64(3), "2024-03-01 10:00:00"},
+		{"", "", ""},
+		{"VA004", float64(40), "2024-05-01 10:00:00"},
+	}}
+	_, client, _, closeServer := newFakeSheetProvider(t, fake)
+	defer closeServer()
+
+	ctx := context.Background()
+	since := "2024-02-01T00:00:00Z"
+	filters := &SyncFilters{Limit: 2, ModifiedSince: &since}
+
+	var ids []string
+	pages := 0
+	for {
+		recordSet, err := client.FetchRecords(ctx, pilotsSchema(), filters)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		pages++
+		for _, rec := range recordSet.Records {
+			ids = append(ids, rec.ID)
+		}
+		if !recordSet.HasMore {
+			break
+		}
+		filters.Offset = recordSet.Offset
+	}
+
+	if pages != 3 {
+		t.Errorf("Expected 3 pages, got %d", pages)
+	}
+	// Row 2 is older than the cutoff and row 4 is blank
+	if strings.Join(ids, ",") != "3,5" {
+		t.Errorf("Expected row IDs 3,5, got %v", ids)
+	}
+
+	matched, err := client.FetchRecords(ctx, pilotsSchema(), &SyncFilters{MatchField: "Callsign", MatchValue: "VA004"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(matched.Records) != 1 || matched.Records[0].Fields["Hours"] != float64(40) {
+		t.Errorf("Expected VA004 with 40 hours, got %+v", matched.Records)
+	}
+}
+
+func TestGoogleSheetsProvider_SubmitRecord_WithServiceAccount(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	der, _ := x509.MarshalPKCS8PrivateKey(key)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	fake := &fakeSheet{t: t, token: "sa-token", rows: [][]interface{}{
+		{"Callsign", "Hours", "Modified", "Notes"},
+	}}
+	_, client, config, closeServer := newFakeSheetProvider(t, fake)
+	defer closeServer()
+
+	saJSON, _ := json.Marshal(map[string]string{
+		"client_email": "bot@example.iam.gserviceaccount.com",
+		"private_key":  string(keyPEM),
+	})
+	config.Credentials.APIKey = ""
+	config.Credentials.ServiceAccountJSON = string(saJSON)
+
+	id, err := client.SubmitRecord(context.Background(), pilotsSchema(), map[string]interface{}{
+		"Notes":    "first flight",
+		"Callsign": "VA009",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if id != "2" {
+		t.Errorf("Expected row ID 2, got %s", id)
+	}
+
+	if len(fake.appended) != 1 {
+		t.Fatalf("Expected 1 appended row, got %d", len(fake.appended))
+	}
+	row := fake.appended[0]
+	if row[0] != "VA009" || row[1] != "" || row[3] != "first flight" {
+		t.Errorf("Expected values ordered by header, got %v", row)
+	}
+}
+
+func TestGoogleSheetsProvider_ValidateConfig(t *testing.T) {
+	fake := &fakeSheet{t: t, rows: [][]interface{}{
+		{"Callsign", "Modified"},
+	}}
+	provider, _, config, closeServer := newFakeSheetProvider(t, fake)
+	defer closeServer()
+
+	ctx := context.Background()
+
+	result, err := provider.ValidateConfig(ctx, config)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// Hours is optional, so its missing column is only a warning
+	if !result.IsValid || len(result.Warnings) != 1 {
+		t.Errorf("Expected valid config with 1 warning, got %+v", result)
+	}
+
+	config.Schemas = append(config.Schemas, dtos.EntitySchema{EntityType: "route", TableName: "Routes", Enabled: true})
+	result, _ = provider.ValidateConfig(ctx, config)
+	if result.IsValid || len(result.Errors) != 1 || result.Errors[0].TableName != "Routes" {
+		t.Errorf("Expected missing Routes tab error, got %+v", result)
+	}
+
+	config.Credentials.APIKey = "wrong-key"
+	result, _ = provider.ValidateConfig(ctx, config)
+	if result.IsValid || len(result.PhasesFailed) != 1 || result.PhasesFailed[0] != "credential_validation" {
+		t.Errorf("Expected credential validation failure, got %+v", result)
+	}
+}
@@ -0,0 +1,152 @@
+package providers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"infinite-experiment/politburo/internal/common"
+	"infinite-experiment/politburo/internal/metrics"
+	"infinite-experiment/politburo/internal/models/dtos"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Registry holds the available data provider implementations keyed by GetProviderType(),
+// and the clients created for VA configs
+type Registry struct {
+	mu        sync.RWMutex
+	providers map[string]DataProvider
+	clients   map[string]ProviderClient // By provider type and config fingerprint
+}
+
+// NewRegistry creates a registry holding the given providers
+func NewRegistry(providers ...DataProvider) *Registry {
+	r := &Registry{
+		providers: make(map[string]DataProvider),
+		clients:   make(map[string]ProviderClient),
+	}
+	for _, p := range providers {
+		r.Register(p)
+	}
+	return r
+}
+
+// NewDefaultRegistry creates a registry with every built-in provider; metricsReg may be nil
+func NewDefaultRegistry(cache common.CacheInterface, metricsReg *metrics.MetricsRegistry) *Registry {
+	return NewRegistry(
+		NewAirtableProvider(cache, metricsReg),
+		NewGoogleSheetsProvider(cache),
+		NewRESTProvider(),
+	)
+}
+
+// Register adds a provider, replacing any provider of the same type
+func (r *Registry) Register(p DataProvider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers[p.GetProviderType()] = p
+}
+
+// Get returns the provider for a provider type
+func (r *Registry) Get(providerType string) (DataProvider, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, ok := r.providers[providerType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported provider type: %s", providerType)
+	}
+	return p, nil
+}
+
+// Client returns the client for a VA config of a provider type. Clients are reused while
+// the config is unchanged, so connections and rate limits carry over between calls; an
+// edited config gets a new client. The client keeps config, so it must not be modified.
+func (r *Registry) Client(providerType string, config *dtos.ProviderConfigData) (ProviderClient, error) {
+	if config == nil {
+		return nil, fmt.Errorf("no %s config", providerType)
+	}
+	provider, err := r.Get(providerType)
+	if err != nil {
+		return nil, err
+	}
+
+	key := providerType + ":" + configFingerprint(config)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if client, ok := r.clients[key]; ok {
+		return client, nil
+	}
+	client := provider.NewClient(config)
+	r.clients[key] = client
+	return client, nil
+}
+
+// Has reports whether a provider is registered for a provider type
+func (r *Registry) Has(providerType string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.providers[providerType]
+	return ok
+}
+
+// Types returns the registered provider types, sorted
+func (r *Registry) Types() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	types := make([]string, 0, len(r.providers))
+	for t := range r.providers {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// configFingerprint identifies the contents of a config
+func configFingerprint(config *dtos.ProviderConfigData) string {
+	data, _ := json.Marshal(config)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// namespacedCache prefixes every key so clients of different configs can't read each
+// other's entries in a shared cache
+type namespacedCache struct {
+	common.CacheInterface
+	prefix string
+}
+
+// namespaceCache scopes cache to the credentials of a config; a nil cache stays nil
+func namespaceCache(cache common.CacheInterface, providerType string, config *dtos.ProviderConfigData) common.CacheInterface {
+	if cache == nil {
+		return nil
+	}
+	data, _ := json.Marshal(config.Credentials)
+	sum := sha256.Sum256(data)
+	return &namespacedCache{
+		CacheInterface: cache,
+		prefix:         "provider:" + providerType + ":" + hex.EncodeToString(sum[:8]) + ":",
+	}
+}
+
+func (c *namespacedCache) Set(key string, value interface{}, duration time.Duration) {
+	c.CacheInterface.Set(c.prefix+key, value, duration)
+}
+
+func (c *namespacedCache) Get(key string) (interface{}, bool) {
+	return c.CacheInterface.Get(c.prefix + key)
+}
+
+func (c *namespacedCache) Delete(key string) {
+	c.CacheInterface.Delete(c.prefix + key)
+}
+
+func (c *namespacedCache) GetOrSet(key string, duration time.Duration, loader func() (any, error)) (interface{}, error) {
+	return c.CacheInterface.GetOrSet(c.prefix+key, duration, loader)
+}
@@ -0,0 +1,34 @@
+package providers
+
+import (
+	"infinite-experiment/politburo/internal/models/dtos"
+	"testing"
+)
+
+func TestRegistry_Client(t *testing.T) {
+	registry := NewRegistry(NewAirtableProvider(nil, nil), NewRESTProvider())
+	config := func(apiKey string) *dtos.ProviderConfigData {
+		return &dtos.ProviderConfigData{
+			Provider:    ProviderTypeAirtable,
+			Credentials: dtos.ProviderCreds{APIKey: apiKey, BaseID: "appTest"},
+		}
+	}
+
+	first, err := registry.Client(ProviderTypeAirtable, config("key"))
+	if err != nil {
+		t.Fatalf("Client: %v", err)
+	}
+	if again, _ := registry.Client(ProviderTypeAirtable, config("key")); again != first {
+		t.Error("Expected the same client for an unchanged config")
+	}
+	if rotated, _ := registry.Client(ProviderTypeAirtable, config("new-key")); rotated == first {
+		t.Error("Expected a new client for an edited config")
+	}
+
+	if _, err := registry.Client("unknown", config("key")); err == nil {
+		t.Error("Expected an error for an unknown provider type")
+	}
+	if _, err := registry.Client(ProviderTypeAirtable, nil); err == nil {
+		t.Error("Expected an error for a missing config")
+	}
+}
@@ -0,0 +1,760 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"infinite-experiment/politburo/internal/constants"
+	"infinite-experiment/politburo/internal/models/dtos"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// RESTSignatureHeader carries "sha256=<hex HMAC>" of the signed request, see SignRESTRequest
+	RESTSignatureHeader = "X-Politburo-Signature"
+	// RESTTimestampHeader carries the Unix time the request was signed at
+	RESTTimestampHeader = "X-Politburo-Timestamp"
+
+	defaultRESTRecordsPath = "$"
+	defaultRESTIDPath      = "$.id"
+	defaultRESTCursorParam = "cursor"
+	defaultRESTLimitParam  = "limit"
+)
+
+// RESTProvider implements DataProvider for VAs that run their own backend.
+//
+// Each schema's Endpoint maps the entity onto HTTP endpoints under Credentials.BaseURL.
+// List endpoints are paged with an opaque cursor read from the response. Field mappings
+// name either a top-level key of a record or a JSONPath-style expression into it. When a
+// signing secret is configured every request carries an HMAC-SHA256 signature so the VA's
+// backend can verify it came from us.
+type RESTProvider struct{}
+
+// NewRESTProvider creates a new REST provider
+func NewRESTProvider() *RESTProvider {
+	return &RESTProvider{}
+}
+
+// GetProviderType returns the provider type identifier
+func (p *RESTProvider) GetProviderType() string {
+	return ProviderTypeREST
+}
+
+// NewClient creates a client for one VA's backend
+func (p *RESTProvider) NewClient(config *dtos.ProviderConfigData) ProviderClient {
+	return p.newClient(config)
+}
+
+// restClient talks to the backend of one VA config
+type restClient struct {
+	config *dtos.ProviderConfigData
+	client *http.Client
+}
+
+func (p *RESTProvider) newClient(config *dtos.ProviderConfigData) *restClient {
+	return &restClient{
+		config: config,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// GetProviderType returns the provider type identifier
+func (c *restClient) GetProviderType() string {
+	return ProviderTypeREST
+}
+
+// FetchPilotRecord fetches a single pilot record from the schema's get endpoint
+func (c *restClient) FetchPilotRecord(ctx context.Context, pilotID string, schema *dtos.EntitySchema) (*PilotRecord, error) {
+	endpoint, err := restEndpoint(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	var record *RecordWithID
+	if endpoint.GetPath != "" {
+		getPath := strings.ReplaceAll(endpoint.GetPath, "{id}", url.PathEscape(pilotID))
+
+		var doc interface{}
+		if err := c.doJSON(ctx, "GET", getPath, nil, nil, &doc); err != nil {
+			if provErr, ok := err.(*ProviderError); ok && provErr.Code == constants.ErrCodeTableNotFound {
+				return nil, &ProviderError{
+					Code:    constants.ErrCodePilotNotFoundInAirtable,
+					Message: fmt.Sprintf("No record %s found at %s", pilotID, getPath),
+				}
+			}
+			return nil, err
+		}
+
+		raw, _ := jsonPathGet(doc, restPathOrDefault(endpoint.RecordPath, defaultRESTRecordsPath))
+		record = buildRESTRecord(raw, schema, endpoint)
+	} else {
+		// Without a get endpoint, page through the list until the ID matches
+		found, err := FindRecord(ctx, c, schema, restPathOrDefault(endpoint.IDPath, defaultRESTIDPath), pilotID)
+		if err != nil {
+			return nil, err
+		}
+		record = found
+	}
+
+	if record == nil {
+		return nil, &ProviderError{
+			Code:    constants.ErrCodePilotNotFoundInAirtable,
+			Message: fmt.Sprintf("No record %s found for %s", pilotID, schema.EntityType),
+		}
+	}
+
+	return &PilotRecord{
+		ProviderID: record.ID,
+		RawFields:  record.Fields,
+		Normalized: c.normalizeFields(record.Fields, schema),
+	}, nil
+}
+
+// FetchRecords fetches one page of records from the schema's list endpoint. The offset is
+// the cursor returned by the previous page. Exact-match filters, and modified-since filters
+// when the endpoint has no parameter for them, are applied to the fetched page.
+func (c *restClient) FetchRecords(ctx context.Context, schema *dtos.EntitySchema, filters *SyncFilters) (*RecordSet, error) {
+	endpoint, err := restEndpoint(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	if filters == nil {
+		filters = &SyncFilters{}
+	}
+	if !filters.FilterFormula.IsZero() {
+		return nil, fmt.Errorf("filter formulas are not supported by the REST provider; use MatchField instead")
+	}
+
+	query := url.Values{}
+	if filters.Limit > 0 {
+		query.Set(restPathOrDefault(endpoint.LimitParam, defaultRESTLimitParam), strconv.Itoa(filters.Limit))
+	}
+	if filters.Offset != "" {
+		query.Set(restPathOrDefault(endpoint.CursorParam, defaultRESTCursorParam), filters.Offset)
+	}
+
+	var since *time.Time
+	if filters.ModifiedSince != nil && schema.LastModifiedField != "" {
+		if endpoint.ModifiedSinceParam != "" {
+			query.Set(endpoint.ModifiedSinceParam, *filters.ModifiedSince)
+		} else if t, err := time.Parse(time.RFC3339, *filters.ModifiedSince); err == nil {
+			since = &t
+		}
+	}
+
+	var doc interface{}
+	if err := c.doJSON(ctx, "GET", endpoint.ListPath, query, nil, &doc); err != nil {
+		return nil, err
+	}
+
+	items, err := restRecordList(doc, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]RecordWithID, 0, len(items))
+	for _, item := range items {
+		record := buildRESTRecord(item, schema, endpoint)
+		if record == nil {
+			continue
+		}
+
+		if filters.MatchField != "" && restValueString(restField(record.Fields, filters.MatchField)) != filters.MatchValue {
+			continue
+		}
+
+		if since != nil {
+			// Records with an unreadable timestamp are kept so they are never silently skipped
+			if modified, err := time.Parse(time.RFC3339, restValueString(record.Fields[schema.LastModifiedField])); err == nil && !modified.After(*since) {
+				continue
+			}
+		}
+
+		records = append(records, *record)
+	}
+
+	recordSet := &RecordSet{
+		Records:      records,
+		TotalFetched: len(records),
+	}
+
+	if endpoint.NextCursorPath != "" {
+		next, _ := jsonPathGet(doc, endpoint.NextCursorPath)
+		cursor := restValueString(next)
+		// A backend that hands back the same cursor would otherwise page forever
+		if cursor != "" && cursor != filters.Offset && len(items) > 0 {
+			recordSet.Offset = cursor
+			recordSet.HasMore = true
+		}
+	}
+
+	return recordSet, nil
+}
+
+// SubmitRecord posts a record to the schema's create endpoint. Fields named by a JSONPath
+// expression are nested in the request body. Endpoints that only acknowledge the request
+// (webhooks answering 202 with no body) yield an empty record ID.
+func (c *restClient) SubmitRecord(ctx context.Context, schema *dtos.EntitySchema, fields map[string]interface{}) (string, error) {
+	endpoint, err := restEndpoint(schema)
+	if err != nil {
+		return "", err
+	}
+	if endpoint.CreatePath == "" {
+		return "", fmt.Errorf("no create_path configured for %s", schema.EntityType)
+	}
+
+	body := make(map[string]interface{})
+	for name, value := range fields {
+		if err := jsonPathSet(body, name, value); err != nil {
+			return "", fmt.Errorf("invalid field %q: %w", name, err)
+		}
+	}
+
+	payloadBytes, err := json.Marshal(body)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	var doc interface{}
+	if err := c.doJSON(ctx, "POST", endpoint.CreatePath, nil, payloadBytes, &doc); err != nil {
+		return "", err
+	}
+
+	raw, _ := jsonPathGet(doc, restPathOrDefault(endpoint.RecordPath, defaultRESTRecordsPath))
+	id, _ := jsonPathGet(raw, restPathOrDefault(endpoint.IDPath, defaultRESTIDPath))
+	return restValueString(id), nil
+}
+
+// UpdateRecord sends the changed fields to the schema's update endpoint with PATCH.
+// Fields named by a JSONPath expression are nested in the request body.
+func (c *restClient) UpdateRecord(ctx context.Context, schema *dtos.EntitySchema, recordID string, fields map[string]interface{}) error {
+	endpoint, err := restEndpoint(schema)
+	if err != nil {
+		return err
+	}
+	if endpoint.UpdatePath == "" {
+		return fmt.Errorf("no update_path configured for %s", schema.EntityType)
+	}
+
+	body := make(map[string]interface{})
+	for name, value := range fields {
+		if err := jsonPathSet(body, name, value); err != nil {
+			return fmt.Errorf("invalid field %q: %w", name, err)
+		}
+	}
+
+	payloadBytes, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	updatePath := strings.ReplaceAll(endpoint.UpdatePath, "{id}", url.PathEscape(recordID))
+	var doc interface{}
+	return c.doJSON(ctx, "PATCH", updatePath, nil, payloadBytes, &doc)
+}
+
+// ValidateConfig validates the REST configuration
+func (p *RESTProvider) ValidateConfig(ctx context.Context, config *dtos.ProviderConfigData) (*ValidationResult, error) {
+	startTime := time.Now()
+	result := &ValidationResult{
+		IsValid:         true,
+		PhasesCompleted: []string{},
+		PhasesFailed:    []string{},
+		Errors:          []dtos.ValidationError{},
+		Warnings:        []dtos.ValidationError{},
+	}
+
+	fail := func(phase string, err error, schema *dtos.EntitySchema) {
+		result.IsValid = false
+		result.PhasesFailed = append(result.PhasesFailed, phase)
+		validationErr := dtos.ValidationError{
+			Phase:     phase,
+			Error:     err.Error(),
+			ErrorCode: constants.ErrCodeNetworkError,
+			Timestamp: time.Now().Format(time.RFC3339),
+		}
+		if provErr, ok := err.(*ProviderError); ok {
+			validationErr.Error = provErr.Message
+			validationErr.ErrorCode = provErr.Code
+		}
+		if schema != nil {
+			validationErr.EntityType = schema.EntityType
+			validationErr.TableName = schema.TableName
+		}
+		result.Errors = append(result.Errors, validationErr)
+	}
+
+	// Phase 1: Credential Validation - the base URL must be an absolute http(s) URL
+	base, err := url.Parse(config.Credentials.BaseURL)
+	if err != nil || (base.Scheme != "http" && base.Scheme != "https") || base.Host == "" {
+		fail("credential_validation", &ProviderError{
+			Code:    constants.ErrCodeInvalidBaseID,
+			Message: fmt.Sprintf("base_url %q is not an absolute http(s) URL", config.Credentials.BaseURL),
+		}, nil)
+		result.DurationMs = int(time.Since(startTime).Milliseconds())
+		return result, nil
+	}
+	result.PhasesCompleted = append(result.PhasesCompleted, "credential_validation")
+
+	// Phase 2: Table Validation - every enabled schema's list endpoint must answer with records
+	client := p.newClient(config)
+	samples := make(map[int]*RecordWithID)
+	tablesOK := true
+	for i := range config.Schemas {
+		schema := &config.Schemas[i]
+		if !schema.Enabled {
+			continue
+		}
+
+		recordSet, err := client.FetchRecords(ctx, schema, &SyncFilters{Limit: 1})
+		if err != nil {
+			tablesOK = false
+			fail("table_validation", err, schema)
+			continue
+		}
+		if len(recordSet.Records) > 0 {
+			samples[i] = &recordSet.Records[0]
+		}
+	}
+	if !tablesOK {
+		result.DurationMs = int(time.Since(startTime).Milliseconds())
+		return result, nil
+	}
+	result.PhasesCompleted = append(result.PhasesCompleted, "table_validation")
+
+	// Phase 3: Field Validation - mapped fields must be present on a sample record.
+	// Endpoints without records yet can't be checked, so they pass.
+	fieldsOK := true
+	for i, sample := range samples {
+		schema := &config.Schemas[i]
+		for _, field := range schema.Fields {
+			if _, ok := sample.Fields[field.AirtableName]; ok {
+				continue
+			}
+			fieldErr := dtos.ValidationError{
+				Phase:      "field_validation",
+				EntityType: schema.EntityType,
+				TableName:  schema.TableName,
+				Error:      fmt.Sprintf("Field %q was not found on a sample record", field.AirtableName),
+				ErrorCode:  constants.ErrCodeFieldNotFound,
+				Timestamp:  time.Now().Format(time.RFC3339),
+			}
+			if field.Required {
+				fieldsOK = false
+				result.IsValid = false
+				result.Errors = append(result.Errors, fieldErr)
+			} else {
+				result.Warnings = append(result.Warnings, fieldErr)
+			}
+		}
+	}
+	if fieldsOK {
+		result.PhasesCompleted = append(result.PhasesCompleted, "field_validation")
+	} else {
+		result.PhasesFailed = append(result.PhasesFailed, "field_validation")
+	}
+
+	result.DurationMs = int(time.Since(startTime).Milliseconds())
+	return result, nil
+}
+
+// SignRESTRequest returns the hex HMAC-SHA256 of a request as sent in RESTSignatureHeader.
+// The signed message is the timestamp, method and request URI (path and query) each
+// followed by a newline, then the raw body. VA backends verify requests by recomputing it.
+func SignRESTRequest(secret string, timestamp int64, method, requestURI string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d\n%s\n%s\n", timestamp, method, requestURI)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// doJSON sends a signed request to a path under the base URL and decodes a successful
+// JSON response into out. An empty response body leaves out untouched.
+func (c *restClient) doJSON(ctx context.Context, method, endpointPath string, query url.Values, body []byte, out interface{}) error {
+	endpoint, err := restURL(c.config.Credentials.BaseURL, endpointPath, query)
+	if err != nil {
+		return err
+	}
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.config.Credentials.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.config.Credentials.APIKey)
+	}
+	if c.config.Credentials.SigningSecret != "" {
+		timestamp := time.Now().Unix()
+		req.Header.Set(RESTTimestampHeader, strconv.FormatInt(timestamp, 10))
+		req.Header.Set(RESTSignatureHeader, "sha256="+SignRESTRequest(c.config.Credentials.SigningSecret, timestamp, method, req.URL.RequestURI(), body))
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return &ProviderError{
+			Code:    constants.ErrCodeNetworkError,
+			Message: "Unable to connect to the VA's backend",
+			Err:     err,
+		}
+	}
+	defer resp.Body.Close()
+
+	if err := c.handleHTTPError(resp); err != nil {
+		return err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if len(bytes.TrimSpace(respBody)) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return &ProviderError{
+			Code:    constants.ErrCodeInvalidDataFormat,
+			Message: "The VA's backend did not answer with JSON",
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+// handleHTTPError converts HTTP errors to ProviderError
+func (c *restClient) handleHTTPError(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		return &ProviderError{
+			Code:    constants.ErrCodeInvalidAPIKey,
+			Message: "The VA's backend rejected the API key or request signature",
+			Details: string(body),
+		}
+	case http.StatusForbidden:
+		return &ProviderError{
+			Code:    constants.ErrCodeTableAccessDenied,
+			Message: "The VA's backend denied access to this endpoint",
+			Details: string(body),
+		}
+	case http.StatusNotFound:
+		return &ProviderError{
+			Code:    constants.ErrCodeTableNotFound,
+			Message: "The endpoint was not found on the VA's backend",
+			Details: string(body),
+		}
+	case http.StatusTooManyRequests:
+		return &ProviderError{
+			Code:    constants.ErrCodeRateLimited,
+			Message: constants.GetErrorMessage(constants.ErrCodeRateLimited),
+			Details: string(body),
+		}
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return &ProviderError{
+			Code:    constants.ErrCodeInvalidDataFormat,
+			Message: fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(body)),
+			Details: string(body),
+		}
+	default:
+		return &ProviderError{
+			Code:    constants.ErrCodeNetworkError,
+			Message: fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(body)),
+			Details: string(body),
+		}
+	}
+}
+
+// normalizeFields maps raw record fields to internal field names
+func (c *restClient) normalizeFields(rawFields map[string]interface{}, schema *dtos.EntitySchema) map[string]interface{} {
+	normalized := make(map[string]interface{})
+
+	for _, fieldMapping := range schema.Fields {
+		if value, exists := rawFields[fieldMapping.AirtableName]; exists {
+			normalized[fieldMapping.InternalName] = value
+		} else if fieldMapping.DefaultValue != nil {
+			normalized[fieldMapping.InternalName] = *fieldMapping.DefaultValue
+		}
+	}
+
+	return normalized
+}
+
+// restEndpoint returns the schema's endpoint config, or an error when the list path is missing
+func restEndpoint(schema *dtos.EntitySchema) (*dtos.RESTEndpoint, error) {
+	if schema.Endpoint == nil || schema.Endpoint.ListPath == "" {
+		return nil, &ProviderError{
+			Code:    constants.ErrCodeConfigMalformed,
+			Message: fmt.Sprintf("No endpoint list_path configured for %s", schema.EntityType),
+		}
+	}
+	return schema.Endpoint, nil
+}
+
+// restURL joins an endpoint path onto the base URL, keeping any query string in the path
+func restURL(baseURL, endpointPath string, query url.Values) (string, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil || base.Host == "" {
+		return "", &ProviderError{
+			Code:    constants.ErrCodeInvalidBaseID,
+			Message: fmt.Sprintf("base_url %q is not a valid URL", baseURL),
+			Err:     err,
+		}
+	}
+
+	ref, err := url.Parse(endpointPath)
+	if err != nil {
+		return "", fmt.Errorf("invalid endpoint path %q: %w", endpointPath, err)
+	}
+
+	base.Path = path.Join("/", base.Path, ref.Path)
+	values := ref.Query()
+	for key, vals := range query {
+		values[key] = vals
+	}
+	base.RawQuery = values.Encode()
+
+	return base.String(), nil
+}
+
+// restRecordList extracts the record array from a list response
+func restRecordList(doc interface{}, endpoint *dtos.RESTEndpoint) ([]interface{}, error) {
+	recordsPath := restPathOrDefault(endpoint.RecordsPath, defaultRESTRecordsPath)
+	raw, ok := jsonPathGet(doc, recordsPath)
+	if !ok || raw == nil {
+		return nil, nil
+	}
+
+	items, ok := raw.([]interface{})
+	if !ok {
+		return nil, &ProviderError{
+			Code:    constants.ErrCodeInvalidDataFormat,
+			Message: fmt.Sprintf("%s in the list response is not an array", recordsPath),
+		}
+	}
+	return items, nil
+}
+
+// buildRESTRecord keys a record's fields by top-level key, adding the values of mapped fields
+// named by a JSONPath expression under that expression. Returns nil for non-objects and
+// records without an ID.
+func buildRESTRecord(raw interface{}, schema *dtos.EntitySchema, endpoint *dtos.RESTEndpoint) *RecordWithID {
+	obj, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	fields := make(map[string]interface{}, len(obj))
+	for key, value := range obj {
+		if value != nil {
+			fields[key] = value
+		}
+	}
+
+	paths := make([]string, 0, len(schema.Fields)+1)
+	for _, field := range schema.Fields {
+		paths = append(paths, field.AirtableName)
+	}
+	paths = append(paths, schema.LastModifiedField)
+	for _, name := range paths {
+		if !isJSONPath(name) {
+			continue
+		}
+		if value, ok := jsonPathGet(obj, name); ok && value != nil {
+			fields[name] = value
+		}
+	}
+
+	idValue, _ := jsonPathGet(obj, restPathOrDefault(endpoint.IDPath, defaultRESTIDPath))
+	id := restValueString(idValue)
+	if id == "" {
+		return nil
+	}
+
+	record := &RecordWithID{
+		ID:     id,
+		Fields: fields,
+	}
+	if endpoint.CreatedTimePath != "" {
+		created, _ := jsonPathGet(obj, endpoint.CreatedTimePath)
+		record.CreatedTime = restValueString(created)
+	}
+
+	return record
+}
+
+// restField looks up a record field by key, resolving JSONPath expressions against the fields
+func restField(fields map[string]interface{}, name string) interface{} {
+	if value, ok := fields[name]; ok {
+		return value
+	}
+	if isJSONPath(name) {
+		value, _ := jsonPathGet(fields, name)
+		return value
+	}
+	return nil
+}
+
+// restValueString renders a scalar JSON value as text
+func restValueString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// restPathOrDefault returns value, or def when value is empty
+func restPathOrDefault(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
+
+// isJSONPath reports whether a field name is a JSONPath-style expression rather than a plain key
+func isJSONPath(name string) bool {
+	return strings.HasPrefix(name, "$")
+}
+
+// parseJSONPath splits a JSONPath-style expression into object keys (strings) and array
+// indexes (ints). Supported syntax: $ (root), .key, ['key'] and [n]. A plain name is a single key.
+func parseJSONPath(expr string) ([]interface{}, error) {
+	if !isJSONPath(expr) {
+		return []interface{}{expr}, nil
+	}
+
+	var segments []interface{}
+	rest := expr[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("empty key in %q", expr)
+			}
+			segments = append(segments, rest[:end])
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("unterminated bracket in %q", expr)
+			}
+			inner := rest[1:end]
+			rest = rest[end+1:]
+			if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+				segments = append(segments, inner[1:len(inner)-1])
+				continue
+			}
+			index, err := strconv.Atoi(inner)
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("invalid index %q in %q", inner, expr)
+			}
+			segments = append(segments, index)
+		default:
+			return nil, fmt.Errorf("unexpected %q in %q", rest[0], expr)
+		}
+	}
+
+	return segments, nil
+}
+
+// jsonPathGet resolves a JSONPath-style expression against a decoded JSON document
+func jsonPathGet(doc interface{}, expr string) (interface{}, bool) {
+	segments, err := parseJSONPath(expr)
+	if err != nil {
+		return nil, false
+	}
+
+	current := doc
+	for _, segment := range segments {
+		switch s := segment.(type) {
+		case string:
+			obj, ok := current.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if current, ok = obj[s]; !ok {
+				return nil, false
+			}
+		case int:
+			arr, ok := current.([]interface{})
+			if !ok || s >= len(arr) {
+				return nil, false
+			}
+			current = arr[s]
+		}
+	}
+
+	return current, true
+}
+
+// jsonPathSet sets a value at a JSONPath-style expression, creating intermediate objects.
+// Only object keys can be written; array indexes are rejected.
+func jsonPathSet(doc map[string]interface{}, expr string, value interface{}) error {
+	segments, err := parseJSONPath(expr)
+	if err != nil {
+		return err
+	}
+	if len(segments) == 0 {
+		return fmt.Errorf("cannot replace the document root")
+	}
+
+	current := doc
+	for i, segment := range segments {
+		key, ok := segment.(string)
+		if !ok {
+			return fmt.Errorf("array indexes cannot be written in %q", expr)
+		}
+		if i == len(segments)-1 {
+			current[key] = value
+			break
+		}
+
+		next, ok := current[key].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			current[key] = next
+		}
+		current = next
+	}
+
+	return nil
+}
@@ -0,0 +1,232 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"infinite-experiment/politburo/internal/models/dtos"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// fakeCrewCentre serves a cursor-paged pilots endpoint and a create endpoint, rejecting
+// requests whose HMAC signature doesn't verify
+type fakeCrewCentre struct {
+	t       *testing.T
+	secret  string
+	pilots  []map[string]interface{}
+	created []map[string]interface{}
+	updated map[string]map[string]interface{}
+}
+
+func (f *fakeCrewCentre) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	timestamp, _ := strconv.ParseInt(r.Header.Get(RESTTimestampHeader), 10, 64)
+	expected := "sha256=" + SignRESTRequest(f.secret, timestamp, r.Method, r.URL.RequestURI(), body)
+	if r.Header.Get(RESTSignatureHeader) != expected {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	switch {
+	case r.Method == "GET" && r.URL.Path == "/api/pilots":
+		start, _ := strconv.Atoi(r.URL.Query().Get("after"))
+		limit, err := strconv.Atoi(r.URL.Query().Get("per_page"))
+		if err != nil {
+			limit = 2 // server default page size
+		}
+		end := start + limit
+		if end > len(f.pilots) {
+			end = len(f.pilots)
+		}
+		next := ""
+		if end < len(f.pilots) {
+			next = strconv.Itoa(end)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": f.pilots[start:end],
+			"meta": map[string]interface{}{"next": next},
+		})
+
+	case r.Method == "POST" && r.URL.Path == "/api/pireps":
+		var record map[string]interface{}
+		json.Unmarshal(body, &record)
+		f.created = append(f.created, record)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"pirep": map[string]interface{}{"uuid": "p-" + strconv.Itoa(len(f.created))},
+		})
+
+	case r.Method == "PATCH" && strings.HasPrefix(r.URL.Path, "/api/pilots/"):
+		var fields map[string]interface{}
+		json.Unmarshal(body, &fields)
+		if f.updated == nil {
+			f.updated = make(map[string]map[string]interface{})
+		}
+		f.updated[strings.TrimPrefix(r.URL.Path, "/api/pilots/")] = fields
+		w.Write([]byte("{}"))
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newFakeCrewCentreClient(t *testing.T, fake *fakeCrewCentre) (*restClient, *dtos.ProviderConfigData, func()) {
+	server := httptest.NewServer(fake)
+	config := &dtos.ProviderConfigData{
+		Provider:    ProviderTypeREST,
+		Credentials: dtos.ProviderCreds{BaseURL: server.URL + "/api", SigningSecret: fake.secret},
+		Schemas: []dtos.EntitySchema{
+			{
+				EntityType: "pilot",
+				Enabled:    true,
+				Endpoint: &dtos.RESTEndpoint{
+					ListPath:       "/pilots",
+					UpdatePath:     "/pilots/{id}",
+					RecordsPath:    "$.data",
+					NextCursorPath: "$.meta.next",
+					CursorParam:    "after",
+					LimitParam:     "per_page",
+				},
+				Fields: []dtos.FieldMapping{
+					{InternalName: "callsign", AirtableName: "callsign", Required: true},
+					{InternalName: "flight_hours", AirtableName: "$.stats.hours"},
+				},
+			},
+			{
+				EntityType: "pirep",
+				Enabled:    true,
+				Endpoint: &dtos.RESTEndpoint{
+					ListPath:   "/pireps",
+					CreatePath: "/pireps",
+					RecordPath: "$.pirep",
+					IDPath:     "$.uuid",
+				},
+			},
+		},
+	}
+	return NewRESTProvider().newClient(config), config, server.Close
+}
+
+func TestRESTProvider_FetchRecords_FollowsCursorAndMapsPaths(t *testing.T) {
+	fake := &fakeCrewCentre{t: t, secret: "shh", pilots: []map[string]interface{}{
+		{"id": "1", "callsign": "VA001", "stats": map[string]interface{}{"hours": 12.5}},
+		{"id": "2", "callsign": "VA002", "stats": map[string]interface{}{"hours": 3}},
+		{"callsign": "NOID"},
+	}}
+	client, config, closeServer := newFakeCrewCentreClient(t, fake)
+	defer closeServer()
+
+	ctx := context.Background()
+	schema := config.GetSchemaByType("pilot")
+	filters := &SyncFilters{Limit: 2}
+
+	var records []RecordWithID
+	pages := 0
+	for {
+		recordSet, err := client.FetchRecords(ctx, schema, filters)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		pages++
+		records = append(records, recordSet.Records...)
+		if !recordSet.HasMore {
+			break
+		}
+		filters.Offset = recordSet.Offset
+	}
+
+	if pages != 2 {
+		t.Errorf("Expected 2 pages, got %d", pages)
+	}
+	// The record without an ID is skipped
+	if len(records) != 2 || records[0].ID != "1" || records[1].ID != "2" {
+		t.Fatalf("Expected records 1 and 2, got %+v", records)
+	}
+	if records[0].Fields["$.stats.hours"] != 12.5 || records[0].Fields["callsign"] != "VA001" {
+		t.Errorf("Expected mapped fields, got %v", records[0].Fields)
+	}
+
+	found, err := FindRecord(ctx, client, schema, "callsign", "VA002")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if found == nil || found.ID != "2" {
+		t.Errorf("Expected to find record 2, got %+v", found)
+	}
+
+	config.Credentials.SigningSecret = "wrong"
+	if _, err := client.FetchRecords(ctx, schema, &SyncFilters{}); err == nil {
+		t.Errorf("Expected an error for a bad signature")
+	}
+}
+
+func TestRESTProvider_SubmitRecord_NestsPathFields(t *testing.T) {
+	fake := &fakeCrewCentre{t: t, secret: "shh"}
+	client, config, closeServer := newFakeCrewCentreClient(t, fake)
+	defer closeServer()
+
+	ctx := context.Background()
+	id, err := client.SubmitRecord(ctx, config.GetSchemaByType("pirep"), map[string]interface{}{
+		"callsign":           "VA001",
+		"$.flight.route":     "KJFK-EGLL",
+		"$.flight['time h']": 7.5,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if id != "p-1" {
+		t.Errorf("Expected record ID p-1, got %s", id)
+	}
+
+	if len(fake.created) != 1 {
+		t.Fatalf("Expected 1 created record, got %d", len(fake.created))
+	}
+	flight, _ := fake.created[0]["flight"].(map[string]interface{})
+	if fake.created[0]["callsign"] != "VA001" || flight["route"] != "KJFK-EGLL" || flight["time h"] != 7.5 {
+		t.Errorf("Expected nested body, got %v", fake.created[0])
+	}
+}
+
+func TestRESTProvider_UpdateRecord_PatchesRecord(t *testing.T) {
+	fake := &fakeCrewCentre{t: t, secret: "shh"}
+	client, config, closeServer := newFakeCrewCentreClient(t, fake)
+	defer closeServer()
+
+	ctx := context.Background()
+	err := client.UpdateRecord(ctx, config.GetSchemaByType("pilot"), "7", map[string]interface{}{
+		"callsign":        "VA123",
+		"$.status.active": false,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	status, _ := fake.updated["7"]["status"].(map[string]interface{})
+	if fake.updated["7"]["callsign"] != "VA123" || status["active"] != false {
+		t.Errorf("Expected nested patch body, got %v", fake.updated["7"])
+	}
+
+	if err := client.UpdateRecord(ctx, config.GetSchemaByType("pirep"), "p-1", map[string]interface{}{"x": 1}); err == nil {
+		t.Errorf("Expected an error for a schema without update_path")
+	}
+}
+
+func TestParseJSONPath(t *testing.T) {
+	segments, err := parseJSONPath("$.data[2]['first name'].x")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	got, _ := json.Marshal(segments)
+	if string(got) != `["data",2,"first name","x"]` {
+		t.Errorf("Unexpected segments %s", got)
+	}
+
+	for _, bad := range []string{"$..a", "$[x]", "$['a'", "$a"} {
+		if _, err := parseJSONPath(bad); err == nil {
+			t.Errorf("Expected an error for %q", bad)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package routes
 import (
 	"infinite-experiment/politburo/internal/api"
 	"infinite-experiment/politburo/internal/common"
+	"infinite-experiment/politburo/internal/constants"
 	"infinite-experiment/politburo/internal/db/repositories"
 	"infinite-experiment/politburo/internal/middleware"
 	"infinite-experiment/politburo/internal/metrics"
@@ -13,7 +14,7 @@ import (
 
 // RegisterAPIRoutes registers all API v1 routes and handlers
 // This keeps API route registration separate from the main router setup
-func RegisterAPIRoutes(r chi.Router, metricsReg *metrics.MetricsRegistry, userRepoGorm *repositories.UserRepositoryGORM, keyRepo *repositories.KeysRepo,
+func RegisterAPIRoutes(r chi.Router, metricsReg *metrics.MetricsRegistry, userRepoGorm *repositories.UserRepositoryGORM, apiKeySvc *services.APIKeyService,
 	handlers *api.Handlers, legacyCacheSvc *common.CacheService, cfgSvc *common.VAConfigService, vaMgmtSvc *services.VAManagementService,
 	atApiSvc *common.AirtableApiService, syncSvc *services.AtSyncService, flightSvc *services.FlightsService, jobsHandler *api.JobsHandler, deps *api.Dependencies, airportLoader *common.AirportLoaderService, sessionSvc *common.SessionService) {
 
@@ -27,7 +28,7 @@ func RegisterAPIRoutes(r chi.Router, metricsReg *metrics.MetricsRegistry, userRe
 	// API v1 routes
 	r.Route("/api/v1", func(v1 chi.Router) {
 		v1.Use(middleware.MetricsMiddleware(metricsReg))
-		v1.Use(middleware.AuthMiddleware(userRepoGorm, keyRepo, sessionSvc)) // global: all routes must be authenticated (using GORM or session cookie)
+		v1.Use(middleware.AuthMiddleware(userRepoGorm, apiKeySvc, sessionSvc)) // global: all routes must be authenticated (using GORM or session cookie)
 		v1.Get("/user/details", handlers.GetUserDetails())
 		v1.Get("/admin/verify-god", handlers.VerifyGodMode())
 
@@ -36,7 +37,14 @@ func RegisterAPIRoutes(r chi.Router, metricsReg *metrics.MetricsRegistry, userRe
 			// God-only group (admin + staff + member + registered)
 			registered.Group(func(god chi.Router) {
 				god.Use(middleware.IsGodMiddleware())
+				god.Use(middleware.RequireAPIKeyScope(constants.APIKeyScopeAdmin))
 				god.Delete("/users/delete", handlers.DeleteAllUsers())
+
+				// API key management
+				god.Get("/admin/api-keys", handlers.ListAPIKeys())
+				god.Post("/admin/api-keys", handlers.CreateAPIKey())
+				god.Post("/admin/api-keys/{key_id}/rotate", handlers.RotateAPIKey())
+				god.Post("/admin/api-keys/{key_id}/revoke", handlers.RevokeAPIKey())
 			})
 			registered.Use(middleware.IsRegisteredMiddleware())
 
@@ -52,9 +60,14 @@ func RegisterAPIRoutes(r chi.Router, metricsReg *metrics.MetricsRegistry, userRe
 				// Pilot stats endpoint - comprehensive stats including game stats (future) and provider data
 				member.Get("/pilot/stats", handlers.GetPilotStats())
 
+				// Rank ladder and the caller's progress through it
+				member.Get("/va/ranks", handlers.ListRanks())
+				member.Get("/pilot/rank", handlers.GetMyRankProgress())
+
 				// PIREP filing endpoints
 				member.Get("/pireps/config", handlers.GetPirepConfig())
 				member.Post("/pireps/submit", handlers.SubmitPirep())
+				member.Post("/pireps/{pirep_id}/resubmit", handlers.ResubmitPirep())
 
 				member.Get("/va/live", api.VaFlightsHandler(flightSvc))
 				member.Get("/live/sessions", api.LiveServers(flightSvc))
@@ -65,6 +78,20 @@ func RegisterAPIRoutes(r chi.Router, metricsReg *metrics.MetricsRegistry, userRe
 					staff.Get("/user/{user_id}/flights", api.UserFlightsHandler(flightSvc, cfgSvc))
 					staff.Post("/va/userSync", api.SyncUser(vaMgmtSvc))
 
+					// PIREP review queue
+					staff.Get("/pireps", handlers.ListPireps())
+					staff.Post("/pireps/{pirep_id}/review", handlers.ReviewPirep())
+
+					// Route catalogue (read-only for staff)
+					staff.Get("/va/routes", handlers.ListRoutes())
+					staff.Get("/va/routes/export", handlers.ExportRoutes())
+
+					// Pilot ranks, promotions and checkrides
+					staff.Get("/va/ranks/progress", handlers.ListRankProgress())
+					staff.Get("/va/ranks/promotions", handlers.ListRankPromotions())
+					staff.Get("/va/pilots/{user_id}/rank", handlers.GetPilotRankProgress())
+					staff.Post("/va/pilots/{user_id}/checkrides", handlers.RecordCheckride())
+
 					// Admin-only group (staff + member + registered)
 					staff.Group(func(admin chi.Router) {
 						admin.Use(middleware.IsAdminMiddleware())
@@ -80,10 +107,33 @@ func RegisterAPIRoutes(r chi.Router, metricsReg *metrics.MetricsRegistry, userRe
 
 						// Flight mode configuration management
 						admin.Post("/va/flight-modes/config", handlers.SetFlightModesConfig())
+						admin.Get("/va/flight-modes/config/versions", handlers.ListFlightModesConfigVersions())
+						admin.Get("/va/flight-modes/config/versions/{version}", handlers.GetFlightModesConfigVersion())
+						admin.Post("/va/flight-modes/config/versions/{version}/rollback", handlers.RollbackFlightModesConfig())
+						admin.Get("/va/flight-modes/config/diff", handlers.DiffFlightModesConfig())
+
+						// Route catalogue management
+						admin.Post("/va/routes", handlers.CreateRoute())
+						admin.Post("/va/routes/import", handlers.ImportRoutes())
+						admin.Put("/va/routes/{route_id}", handlers.UpdateRoute())
+						admin.Delete("/va/routes/{route_id}", handlers.DeleteRoute())
+
+						// Rank ladder management
+						admin.Put("/va/ranks", handlers.SetRankLadder())
+
+						// PIREP delivery outbox (dead letters)
+						admin.Get("/admin/pireps/outbox/dead", handlers.ListPirepDeadLetters())
+						admin.Post("/admin/pireps/outbox/{entry_id}/replay", handlers.ReplayPirepDeadLetter())
+
+						// Pilot sync conflicts (dashboard and provider both changed a field)
+						admin.Get("/admin/pilots/sync/conflicts", handlers.ListPilotSyncConflicts())
+						admin.Post("/admin/pilots/sync/conflicts/{change_id}/resolve", handlers.ResolvePilotSyncConflict())
 
 						// Background jobs management
-						admin.Post("/admin/jobs/sync-pilots", jobsHandler.TriggerPilotSync())
+						admin.Post("/admin/jobs/{job}/trigger", jobsHandler.TriggerJob())
+						admin.Get("/admin/jobs/triggers/{run_id}", jobsHandler.GetTriggeredRun())
 						admin.Get("/admin/jobs/status", jobsHandler.GetJobStatus())
+						admin.Get("/admin/jobs/runs", handlers.ListSyncRuns())
 
 						// Airport data management
 						admin.Post("/admin/data/sync-airports", api.SyncAirportsHandler(airportLoader))
@@ -3,6 +3,7 @@ package routes
 import (
 	"context"
 	"net/http"
+	"sync"
 	"time"
 
 	"infinite-experiment/politburo/internal/api"
@@ -19,7 +20,9 @@ import (
 	"github.com/go-chi/cors"
 )
 
-func RegisterRoutes(upSince time.Time) http.Handler {
+// RegisterRoutes builds the API and UI router and starts the background jobs and workers.
+// The jobs and workers run until ctx is cancelled and are added to background.
+func RegisterRoutes(ctx context.Context, upSince time.Time, background *sync.WaitGroup) http.Handler {
 
 	// initialize Chi router
 	r := chi.NewRouter()
@@ -54,7 +57,6 @@ func RegisterRoutes(upSince time.Time) http.Handler {
 
 	// Legacy: Keep individual references for old handlers that haven't been migrated yet
 	userRepoGorm := deps.Repo.UserGorm
-	keyRepo := &deps.Repo.Keys
 	legacyCacheSvc := deps.Services.LegacyCache
 	cfgSvc := &deps.Services.Conf
 	vaMgmtSvc := &deps.Services.VaMgmt
@@ -74,12 +76,30 @@ func RegisterRoutes(upSince time.Time) http.Handler {
 	// This will be passed to middleware when creating handlers
 
 	// Register UI routes (separate from API)
-	RegisterUIRoutes(r, metricsReg, sessionSvc, urlSigner, userRepoGorm, vaUserRoleRepo, vaGormRepo, flightSvc, deps.Services.Cache, &deps.Services.Live)
+	RegisterUIRoutes(r, metricsReg, sessionSvc, urlSigner, userRepoGorm, vaUserRoleRepo, vaGormRepo, flightSvc, deps.Services.Cache, &deps.Services.Live, deps.Services.PirepReview, deps.Services.RouteCatalogue, deps.Services.Rank, deps.Services.PilotSync, deps.Repo.SyncRun)
+
+	// Tracks job state and records each sync run
+	jobTracker := jobs.NewTracker(deps.Repo.SyncRun)
 
 	// Setup workers and jobs first
+	workersContainer := workers.InitWorkers(
+		ctx,
+		background,
+		db.PgDB,
+		&deps.Services.Cache,
+		&deps.Services.Live,
+		deps.Services.AircraftLivery,
+		&deps.Services.RedisQueue,
+		deps.Repo.AircraftLivery,
+		deps.Repo.DataProviderCfg,
+		deps.Repo.PirepATSynced,
+		deps.Repo.VASyncHistory,
+	)
+
 	// Setup scheduled jobs (both pilot and route sync run every hour)
 	jobsContainer := jobs.InitializeJobs(
-		context.Background(),
+		ctx,
+		background,
 		db.PgDB,
 		deps.Services.Cache, // Use CacheInterface (supports Redis or in-memory)
 		deps.Repo.DataProviderCfg,
@@ -87,31 +107,28 @@ func RegisterRoutes(upSince time.Time) http.Handler {
 		deps.Repo.PilotATSynced,
 		deps.Repo.RouteATSynced,
 		deps.Repo.PirepATSynced,
+		deps.Repo.SyncedRecord,
+		deps.Repo.JobSchedule,
+		deps.Repo.PilotSyncChange,
 		deps.Repo.AirportsRepo,
 		cfgSvc,
 		&deps.Services.RedisQueue,
+		deps.Services.DataProviders,
+		deps.Services.PirepDelivery,
+		deps.Services.PilotSync,
+		&workersContainer.CacheFiller,
+		jobTracker,
 	)
 
-	workers.InitWorkers(
-		db.PgDB,
-		&deps.Services.Cache,
-		&deps.Services.Live,
-		deps.Services.AircraftLivery,
-		&deps.Services.RedisQueue,
-		deps.Repo.AircraftLivery,
-		deps.Repo.DataProviderCfg,
-		deps.Repo.PirepATSynced,
-		deps.Repo.VASyncHistory,
-	)
-
-	// Initialize jobs handler for manual triggering
-	jobsHandler := api.NewJobsHandler(jobsContainer.PilotSync)
-
 	// Initialize airport loader service
 	airportLoader := common.NewAirportLoaderService(db.PgDB)
 
+	// Initialize jobs handler for manual triggering
+	jobTriggers := jobs.NewTriggers(ctx, background, jobTracker, deps.Repo.JobSchedule, jobsContainer, &workersContainer.CacheFiller, airportLoader)
+	jobsHandler := api.NewJobsHandler(jobTriggers, jobTracker, deps.Repo.VAGorm)
+
 	// Register API routes (after jobsHandler is initialized)
-	RegisterAPIRoutes(r, metricsReg, userRepoGorm, keyRepo, handlers, legacyCacheSvc, cfgSvc, vaMgmtSvc, atApiSvc, syncSvc, flightSvc, jobsHandler, deps, airportLoader, sessionSvc)
+	RegisterAPIRoutes(r, metricsReg, userRepoGorm, deps.Services.APIKeys, handlers, legacyCacheSvc, cfgSvc, vaMgmtSvc, atApiSvc, syncSvc, flightSvc, jobsHandler, deps, airportLoader, sessionSvc)
 
 	return r
 }
@@ -27,11 +27,16 @@ func RegisterUIRoutes(
 	flightSvc *services.FlightsService,
 	cache common.CacheInterface,
 	liveAPI *common.LiveAPIService,
+	pirepReviewSvc *services.PirepReviewService,
+	routeCatalogueSvc *services.RouteCatalogueService,
+	rankSvc *services.RankService,
+	pilotSyncSvc *services.PilotSyncService,
+	syncRunRepo *repositories.SyncRunRepo,
 ) {
 	authHandler := vizbuUI.NewAuthHandler(sessionSvc, urlSigner, userRepo, vaRoleRepo, vaRepo)
 
 	// Initialize pilot management service
-	pilotMgmtSvc := services.NewPilotManagementService(vaRoleRepo)
+	pilotMgmtSvc := services.NewPilotManagementService(vaRoleRepo, pilotSyncSvc)
 
 	// Import middleware
 	authMiddleware := middleware.AuthMiddleware(userRepo, nil, sessionSvc) // keysRepo is nil for UI routes
@@ -98,6 +103,33 @@ func RegisterUIRoutes(
 				vizbuUI.UpdatePilotCallsignHandler(w, r, pilotMgmtSvc)
 			})
 
+			// PIREP review queue (staff + admin can review)
+			staff.Get("/pireps", vizbuUI.PirepsHandler)
+			staff.Get("/pireps/list", func(w http.ResponseWriter, r *http.Request) {
+				vizbuUI.PirepsListHandler(w, r, pirepReviewSvc)
+			})
+			staff.Post("/pireps/{pirep_id}/review", func(w http.ResponseWriter, r *http.Request) {
+				vizbuUI.ReviewPirepHandler(w, r, pirepReviewSvc)
+			})
+
+			// Route catalogue (staff + admin can view and export)
+			staff.Get("/routes", vizbuUI.RoutesHandler)
+			staff.Get("/routes/list", func(w http.ResponseWriter, r *http.Request) {
+				vizbuUI.RoutesListHandler(w, r, routeCatalogueSvc)
+			})
+			staff.Get("/routes/export", func(w http.ResponseWriter, r *http.Request) {
+				vizbuUI.ExportRoutesHandler(w, r, routeCatalogueSvc)
+			})
+
+			// Pilot ranks (staff + admin can view progress and record checkrides)
+			staff.Get("/ranks", vizbuUI.RanksHandler)
+			staff.Get("/ranks/list", func(w http.ResponseWriter, r *http.Request) {
+				vizbuUI.RanksListHandler(w, r, rankSvc)
+			})
+			staff.Post("/ranks/{user_id}/checkrides", func(w http.ResponseWriter, r *http.Request) {
+				vizbuUI.RecordCheckrideHandler(w, r, rankSvc)
+			})
+
 			// Admin-only routes (admin only)
 			staff.Group(func(admin chi.Router) {
 				admin.Use(middleware.IsAdminMiddleware())
@@ -109,6 +141,26 @@ func RegisterUIRoutes(
 				admin.Delete("/pilots/{pilot_id}", func(w http.ResponseWriter, r *http.Request) {
 					vizbuUI.RemovePilotHandler(w, r, pilotMgmtSvc)
 				})
+
+				// Route catalogue management (admin only)
+				admin.Post("/routes", func(w http.ResponseWriter, r *http.Request) {
+					vizbuUI.CreateRouteHandler(w, r, routeCatalogueSvc)
+				})
+				admin.Post("/routes/import", func(w http.ResponseWriter, r *http.Request) {
+					vizbuUI.ImportRoutesHandler(w, r, routeCatalogueSvc)
+				})
+				admin.Post("/routes/{route_id}/active", func(w http.ResponseWriter, r *http.Request) {
+					vizbuUI.SetRouteActiveHandler(w, r, routeCatalogueSvc)
+				})
+				admin.Delete("/routes/{route_id}", func(w http.ResponseWriter, r *http.Request) {
+					vizbuUI.DeleteRouteHandler(w, r, routeCatalogueSvc)
+				})
+
+				// Data provider sync history (admin only)
+				admin.Get("/sync", vizbuUI.SyncHandler)
+				admin.Get("/sync/list", func(w http.ResponseWriter, r *http.Request) {
+					vizbuUI.SyncRunsListHandler(w, r, syncRunRepo)
+				})
 			})
 		})
 	})
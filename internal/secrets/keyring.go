@@ -0,0 +1,285 @@
+// Package secrets encrypts credentials at rest, such as the API keys in a VA's data provider
+// config, with envelope encryption.
+//
+// Every value is encrypted with its own random data key, and the data key is encrypted
+// ("wrapped") with a key-encryption key from the SECRETS_KEYS environment variable. A sealed
+// value names the key that wrapped it, so after adding a new primary key the old one stays
+// usable for reading until every value has been re-wrapped with Rewrap.
+//
+//	SECRETS_KEYS=2025-06:<base64 32-byte key>,2024-01:<base64 32-byte key>
+//
+// The first key is the primary one used for sealing; the others are only used for opening.
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// sealedPrefix marks a sealed value: enc:v1:<key ID>:<wrapped data key>:<ciphertext>
+const sealedPrefix = "enc:v1:"
+
+// KeySize is the size in bytes of key-encryption and data keys (AES-256)
+const KeySize = 32
+
+var (
+	// ErrNoKey is returned when sealing, or opening a sealed value, without a keyring
+	ErrNoKey = errors.New("no secrets encryption key configured, set SECRETS_KEYS")
+
+	// ErrUnknownKey is returned when a sealed value was wrapped by a key not in the keyring
+	ErrUnknownKey = errors.New("sealed with an unknown key")
+
+	// ErrSealedInput is returned when a value to be sealed already looks sealed. Only Rewrap
+	// takes sealed values; anything else could store ciphertext that never opens.
+	ErrSealedInput = errors.New("value starts with " + sealedPrefix + ", which is reserved for encrypted values")
+)
+
+// Keyring holds the key-encryption keys. A nil Keyring passes plain values through
+// Open but cannot seal anything.
+type Keyring struct {
+	primary string
+	keys    map[string][]byte // By key ID
+}
+
+// NewKeyring creates a keyring sealing with the key primary
+func NewKeyring(primary string, keys map[string][]byte) (*Keyring, error) {
+	if _, ok := keys[primary]; !ok {
+		return nil, fmt.Errorf("primary key %q is not in the keyring", primary)
+	}
+	for id, key := range keys {
+		if id == "" || strings.ContainsAny(id, ":,") {
+			return nil, fmt.Errorf("invalid key ID %q", id)
+		}
+		if len(key) != KeySize {
+			return nil, fmt.Errorf("key %q is %d bytes, want %d", id, len(key), KeySize)
+		}
+	}
+	return &Keyring{primary: primary, keys: keys}, nil
+}
+
+// ParseKeyring parses a comma-separated list of <key ID>:<base64 key>; the first key is primary
+func ParseKeyring(spec string) (*Keyring, error) {
+	var primary string
+	keys := make(map[string][]byte)
+	for _, entry := range strings.Split(spec, ",") {
+		id, encoded, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok {
+			return nil, fmt.Errorf("key entry %q is not <id>:<base64 key>", entry)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("key %q is not valid base64: %w", id, err)
+		}
+		if _, dup := keys[id]; dup {
+			return nil, fmt.Errorf("key %q is listed twice", id)
+		}
+		if primary == "" {
+			primary = id
+		}
+		keys[id] = key
+	}
+	return NewKeyring(primary, keys)
+}
+
+// KeyringFromEnv loads the keyring from SECRETS_KEYS. It returns nil, nil when the variable
+// is not set.
+func KeyringFromEnv() (*Keyring, error) {
+	spec := os.Getenv("SECRETS_KEYS")
+	if spec == "" {
+		return nil, nil
+	}
+	keyring, err := ParseKeyring(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SECRETS_KEYS: %w", err)
+	}
+	return keyring, nil
+}
+
+// GenerateKey returns a new random key, base64 encoded for SECRETS_KEYS
+func GenerateKey() (string, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// PrimaryKeyID returns the ID of the key new values are sealed with
+func (k *Keyring) PrimaryKeyID() string {
+	if k == nil {
+		return ""
+	}
+	return k.primary
+}
+
+// IsSealed reports whether a value was produced by Seal
+func IsSealed(value string) bool {
+	return strings.HasPrefix(value, sealedPrefix)
+}
+
+// RequirePlain returns value unchanged, or ErrSealedInput when it looks sealed. It checks
+// values supplied by clients before they are sealed.
+func RequirePlain(value string) (string, error) {
+	if IsSealed(value) {
+		return "", ErrSealedInput
+	}
+	return value, nil
+}
+
+// Seal encrypts a value with a new data key wrapped by the primary key. Empty values are
+// returned unchanged and already sealed ones are rejected with ErrSealedInput; use Rewrap to
+// bring sealed values up to date.
+func (k *Keyring) Seal(plaintext string) (string, error) {
+	if plaintext == "" {
+		return plaintext, nil
+	}
+	if _, err := RequirePlain(plaintext); err != nil {
+		return "", err
+	}
+	if k == nil {
+		return "", ErrNoKey
+	}
+
+	dataKey := make([]byte, KeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", fmt.Errorf("failed to generate data key: %w", err)
+	}
+	ciphertext, err := encrypt(dataKey, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	wrapped, err := encrypt(k.keys[k.primary], dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	return format(k.primary, wrapped, ciphertext), nil
+}
+
+// Open decrypts a sealed value. Values that are not sealed, such as rows written before
+// encryption was enabled, are returned unchanged.
+func (k *Keyring) Open(value string) (string, error) {
+	if !IsSealed(value) {
+		return value, nil
+	}
+
+	keyID, wrapped, ciphertext, err := parse(value)
+	if err != nil {
+		return "", err
+	}
+	dataKey, err := k.unwrap(keyID, wrapped)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := decrypt(dataKey, ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// Rewrap brings a value up to date with the primary key: plain values are sealed and the
+// data key of a value sealed with an older key is re-wrapped, leaving its ciphertext as is.
+// It reports whether the value changed.
+func (k *Keyring) Rewrap(value string) (string, bool, error) {
+	if value == "" {
+		return value, false, nil
+	}
+	if !IsSealed(value) {
+		sealed, err := k.Seal(value)
+		return sealed, err == nil, err
+	}
+
+	keyID, wrapped, ciphertext, err := parse(value)
+	if err != nil {
+		return "", false, err
+	}
+	if k != nil && keyID == k.primary {
+		return value, false, nil
+	}
+	dataKey, err := k.unwrap(keyID, wrapped)
+	if err != nil {
+		return "", false, err
+	}
+	rewrapped, err := encrypt(k.keys[k.primary], dataKey)
+	if err != nil {
+		return "", false, err
+	}
+	return format(k.primary, rewrapped, ciphertext), true, nil
+}
+
+// unwrap decrypts a data key with the key-encryption key keyID
+func (k *Keyring) unwrap(keyID string, wrapped []byte) ([]byte, error) {
+	if k == nil {
+		return nil, ErrNoKey
+	}
+	key, ok := k.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownKey, keyID)
+	}
+	dataKey, err := decrypt(key, wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key with key %q: %w", keyID, err)
+	}
+	return dataKey, nil
+}
+
+func format(keyID string, wrapped, ciphertext []byte) string {
+	return sealedPrefix + keyID + ":" +
+		base64.RawURLEncoding.EncodeToString(wrapped) + ":" +
+		base64.RawURLEncoding.EncodeToString(ciphertext)
+}
+
+func parse(value string) (keyID string, wrapped, ciphertext []byte, err error) {
+	parts := strings.Split(strings.TrimPrefix(value, sealedPrefix), ":")
+	if len(parts) != 3 {
+		return "", nil, nil, errors.New("malformed sealed value")
+	}
+	if wrapped, err = base64.RawURLEncoding.DecodeString(parts[1]); err != nil {
+		return "", nil, nil, fmt.Errorf("malformed sealed value: %w", err)
+	}
+	if ciphertext, err = base64.RawURLEncoding.DecodeString(parts[2]); err != nil {
+		return "", nil, nil, fmt.Errorf("malformed sealed value: %w", err)
+	}
+	return parts[0], wrapped, ciphertext, nil
+}
+
+// encrypt seals plaintext with AES-256-GCM, prefixing the random nonce
+func encrypt(key, plaintext []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// decrypt opens the output of encrypt
+func decrypt(key, sealed []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,130 @@
+package secrets
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testKeyring(t *testing.T, spec ...string) *Keyring {
+	t.Helper()
+	keyring, err := ParseKeyring(strings.Join(spec, ","))
+	if err != nil {
+		t.Fatalf("ParseKeyring: %v", err)
+	}
+	return keyring
+}
+
+func testKey(t *testing.T, id string) string {
+	t.Helper()
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	return id + ":" + key
+}
+
+func TestKeyring_SealOpen(t *testing.T) {
+	keyring := testKeyring(t, testKey(t, "k1"))
+
+	sealed, err := keyring.Seal("patSecret.123")
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if !IsSealed(sealed) || strings.Contains(sealed, "patSecret") {
+		t.Fatalf("sealed value %q is not encrypted", sealed)
+	}
+	if again, _ := keyring.Seal("patSecret.123"); again == sealed {
+		t.Error("sealing twice gave the same ciphertext")
+	}
+	if resealed, err := keyring.Seal(sealed); !errors.Is(err, ErrSealedInput) || resealed != "" {
+		t.Errorf("Seal(sealed) = %q, %v, want ErrSealedInput", resealed, err)
+	}
+
+	opened, err := keyring.Open(sealed)
+	if err != nil || opened != "patSecret.123" {
+		t.Fatalf("Open = %q, %v", opened, err)
+	}
+	if plain, err := keyring.Open("legacy-plaintext"); err != nil || plain != "legacy-plaintext" {
+		t.Errorf("Open(plain) = %q, %v", plain, err)
+	}
+
+	tampered := sealed[:len(sealed)-2] + "AA"
+	if _, err := keyring.Open(tampered); err == nil {
+		t.Error("opened a tampered value")
+	}
+}
+
+func TestRequirePlain(t *testing.T) {
+	if value, err := RequirePlain("patSecret.123"); err != nil || value != "patSecret.123" {
+		t.Errorf("RequirePlain(plain) = %q, %v", value, err)
+	}
+	// Client input that merely looks sealed is refused, not stored to fail on Open later
+	if _, err := RequirePlain(sealedPrefix + "k1:bm90:YSBrZXk"); !errors.Is(err, ErrSealedInput) {
+		t.Errorf("RequirePlain(sealed) = %v, want ErrSealedInput", err)
+	}
+}
+
+func TestKeyring_Rotation(t *testing.T) {
+	oldKey, newKey := testKey(t, "old"), testKey(t, "new")
+	oldKeyring := testKeyring(t, oldKey)
+	rotated := testKeyring(t, newKey, oldKey)
+
+	sealed, _ := oldKeyring.Seal("secret")
+
+	// The old key still opens values until they are re-wrapped
+	if opened, err := rotated.Open(sealed); err != nil || opened != "secret" {
+		t.Fatalf("Open with rotated keyring = %q, %v", opened, err)
+	}
+
+	rewrapped, changed, err := rotated.Rewrap(sealed)
+	if err != nil || !changed || !strings.HasPrefix(rewrapped, sealedPrefix+"new:") {
+		t.Fatalf("Rewrap = %q, %t, %v", rewrapped, changed, err)
+	}
+	if _, changed, _ := rotated.Rewrap(rewrapped); changed {
+		t.Error("re-wrapping a current value changed it")
+	}
+	if _, changed, _ := rotated.Rewrap("plain"); !changed {
+		t.Error("re-wrapping a plain value did not seal it")
+	}
+
+	// Once re-wrapped the old key can be dropped
+	if opened, err := testKeyring(t, newKey).Open(rewrapped); err != nil || opened != "secret" {
+		t.Errorf("Open without the old key = %q, %v", opened, err)
+	}
+	if _, err := testKeyring(t, newKey).Open(sealed); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("got %v, want ErrUnknownKey", err)
+	}
+}
+
+func TestKeyring_Nil(t *testing.T) {
+	var keyring *Keyring
+	if _, err := keyring.Seal("secret"); !errors.Is(err, ErrNoKey) {
+		t.Errorf("Seal = %v, want ErrNoKey", err)
+	}
+	if plain, err := keyring.Open("plain"); err != nil || plain != "plain" {
+		t.Errorf("Open(plain) = %q, %v", plain, err)
+	}
+}
+
+func TestParseKeyring_Invalid(t *testing.T) {
+	for _, spec := range []string{"", "k1", "k1:not-base64!", "k1:c2hvcnQ=", testKey(t, "a:b")} {
+		if _, err := ParseKeyring(spec); err == nil {
+			t.Errorf("ParseKeyring(%q) succeeded", spec)
+		}
+	}
+}
+
+func TestRedact(t *testing.T) {
+	tests := map[string]string{
+		"":                           "",
+		"short":                      Redacted,
+		"patABCDEFGHIJKLMNOP.wxyz":   Redacted + "wxyz",
+		"enc:v1:k1:AAAAAAAA:BBBBBBB": Redacted,
+	}
+	for value, want := range tests {
+		if got := Redact(value); got != want {
+			t.Errorf("Redact(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package secrets
+
+// Redacted replaces a secret in API responses and logs
+const Redacted = "****"
+
+// Redact hides a secret, keeping the last four characters of long values so that an admin
+// can tell which key is configured. Empty values stay empty.
+func Redact(value string) string {
+	switch {
+	case value == "":
+		return ""
+	case IsSealed(value) || len(value) < 16:
+		return Redacted
+	default:
+		return Redacted + value[len(value)-4:]
+	}
+}
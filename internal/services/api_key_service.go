@@ -0,0 +1,226 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"infinite-experiment/politburo/internal/auth"
+	"infinite-experiment/politburo/internal/constants"
+	"infinite-experiment/politburo/internal/db/repositories"
+	"infinite-experiment/politburo/internal/models/entities"
+)
+
+var (
+	// ErrAPIKeyNotFound is returned for an unknown API key or key ID
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	// ErrAPIKeyRevoked is returned for a revoked API key
+	ErrAPIKeyRevoked = errors.New("api key has been revoked")
+	// ErrAPIKeyExpired is returned for an API key past its expiry, including rotated keys
+	// past their grace period
+	ErrAPIKeyExpired = errors.New("api key has expired")
+	// ErrInvalidAPIKeyScope is returned when creating a key with an unknown scope
+	ErrInvalidAPIKeyScope = errors.New("scope must be read_only, bot or admin")
+	// ErrAPIKeyVANotFound is returned when binding a key to an unknown VA
+	ErrAPIKeyVANotFound = errors.New("virtual airline not found")
+	// ErrAPIKeyBindingMismatch is returned when a key is bound to a VA and a different Discord server
+	ErrAPIKeyBindingMismatch = errors.New("discord server does not belong to the VA")
+)
+
+// CreateAPIKeyInput describes a new API key
+type CreateAPIKeyInput struct {
+	Name            string
+	Scope           constants.APIKeyScope
+	VAID            string     // Optional: bind the key to this VA and its Discord server
+	DiscordServerID string     // Optional: bind the key to this Discord server
+	ExpiresAt       *time.Time // Optional
+	CreatedBy       string     // Discord ID of the admin, or "cli"
+}
+
+// IssuedAPIKey is a newly created key. Key is the only copy of the secret and is shown once.
+type IssuedAPIKey struct {
+	APIKey *entities.ApiKey
+	Key    string
+}
+
+// APIKeyService issues, rotates, revokes and authenticates API keys
+type APIKeyService struct {
+	keysRepo *repositories.KeysRepo
+	vaRepo   *repositories.VAGormRepository
+}
+
+// NewAPIKeyService creates a new APIKeyService
+func NewAPIKeyService(keysRepo *repositories.KeysRepo, vaRepo *repositories.VAGormRepository) *APIKeyService {
+	return &APIKeyService{
+		keysRepo: keysRepo,
+		vaRepo:   vaRepo,
+	}
+}
+
+// Authenticate returns the active key matching a key presented by a client and records its use
+func (s *APIKeyService) Authenticate(ctx context.Context, key string) (*entities.ApiKey, error) {
+	hash := auth.HashAPIKey(key)
+	apiKey, err := s.keysRepo.GetByHash(ctx, hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Clients may send a legacy key in upper case; it was stored by its lowercase hash
+		if legacyHash, ok := auth.LegacyAPIKeyHash(key); ok && legacyHash != hash {
+			apiKey, err = s.keysRepo.GetByHash(ctx, legacyHash)
+		}
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if apiKey.RevokedAt != nil {
+		return nil, ErrAPIKeyRevoked
+	}
+	if apiKey.ExpiresAt != nil && !time.Now().UTC().Before(*apiKey.ExpiresAt) {
+		return nil, ErrAPIKeyExpired
+	}
+
+	if err := s.keysRepo.TouchLastUsed(ctx, apiKey.ID); err != nil {
+		log.Printf("[APIKeyService] Failed to record use of key %s: %v", apiKey.Prefix, err)
+	}
+
+	return apiKey, nil
+}
+
+// Create issues a new API key
+func (s *APIKeyService) Create(ctx context.Context, input CreateAPIKeyInput) (*IssuedAPIKey, error) {
+	if !input.Scope.IsValid() {
+		return nil, ErrInvalidAPIKeyScope
+	}
+
+	apiKey := &entities.ApiKey{
+		Name:      input.Name,
+		Scope:     input.Scope,
+		ExpiresAt: input.ExpiresAt,
+		CreatedBy: optionalString(input.CreatedBy),
+	}
+	if err := s.bind(ctx, apiKey, input.VAID, input.DiscordServerID); err != nil {
+		return nil, err
+	}
+
+	issued, err := s.issue(apiKey)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.keysRepo.Create(ctx, issued.APIKey); err != nil {
+		return nil, fmt.Errorf("failed to save api key: %w", err)
+	}
+
+	log.Printf("[APIKeyService] Created key %s (%s, scope=%s)", apiKey.Prefix, apiKey.Name, apiKey.Scope)
+	return issued, nil
+}
+
+// List returns the keys, newest first
+func (s *APIKeyService) List(ctx context.Context, includeRevoked bool) ([]entities.ApiKey, error) {
+	return s.keysRepo.List(ctx, includeRevoked)
+}
+
+// Rotate issues a replacement for a key with the same name, scope, binding and lifetime. The
+// old key keeps working for the grace period, so that clients can switch over.
+func (s *APIKeyService) Rotate(ctx context.Context, id string, grace time.Duration, createdBy string) (*IssuedAPIKey, error) {
+	old, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if old.RevokedAt != nil {
+		return nil, ErrAPIKeyRevoked
+	}
+	now := time.Now().UTC()
+	if old.ReplacedBy != nil || (old.ExpiresAt != nil && !now.Before(*old.ExpiresAt)) {
+		return nil, ErrAPIKeyExpired
+	}
+
+	apiKey := &entities.ApiKey{
+		Name:            old.Name,
+		Scope:           old.Scope,
+		VAID:            old.VAID,
+		DiscordServerID: old.DiscordServerID,
+		CreatedBy:       optionalString(createdBy),
+	}
+	if old.ExpiresAt != nil {
+		expiresAt := now.Add(old.ExpiresAt.Sub(old.CreatedAt))
+		apiKey.ExpiresAt = &expiresAt
+	}
+
+	issued, err := s.issue(apiKey)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.keysRepo.Replace(ctx, old.ID, issued.APIKey, now.Add(grace)); err != nil {
+		return nil, fmt.Errorf("failed to rotate api key: %w", err)
+	}
+
+	log.Printf("[APIKeyService] Rotated key %s to %s (grace period %s)", old.Prefix, apiKey.Prefix, grace)
+	return issued, nil
+}
+
+// Revoke revokes a key immediately
+func (s *APIKeyService) Revoke(ctx context.Context, id string) error {
+	apiKey, err := s.get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	revoked, err := s.keysRepo.Revoke(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	if !revoked {
+		return ErrAPIKeyRevoked
+	}
+
+	log.Printf("[APIKeyService] Revoked key %s (%s)", apiKey.Prefix, apiKey.Name)
+	return nil
+}
+
+func (s *APIKeyService) get(ctx context.Context, id string) (*entities.ApiKey, error) {
+	apiKey, err := s.keysRepo.GetByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAPIKeyNotFound
+	}
+	return apiKey, err
+}
+
+// bind binds a key to a VA and/or a Discord server. A key bound to a VA is bound to the
+// VA's Discord server, which is what requests are checked against.
+func (s *APIKeyService) bind(ctx context.Context, apiKey *entities.ApiKey, vaID string, discordServerID string) error {
+	if vaID != "" {
+		va, err := s.vaRepo.GetByID(ctx, vaID)
+		if err != nil {
+			return err
+		}
+		if va == nil {
+			return ErrAPIKeyVANotFound
+		}
+		if discordServerID != "" && discordServerID != va.DiscordID {
+			return ErrAPIKeyBindingMismatch
+		}
+		apiKey.VAID = &va.ID
+		discordServerID = va.DiscordID
+	}
+	apiKey.DiscordServerID = optionalString(discordServerID)
+	return nil
+}
+
+// issue generates the secret of a new key
+func (s *APIKeyService) issue(apiKey *entities.ApiKey) (*IssuedAPIKey, error) {
+	key, prefix, err := auth.GenerateAPIKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate api key: %w", err)
+	}
+	apiKey.Prefix = prefix
+	apiKey.KeyHash = auth.HashAPIKey(key)
+	// Stamped here rather than by the database so that it is UTC like expires_at, which a
+	// rotated key's lifetime is measured against
+	apiKey.CreatedAt = time.Now().UTC()
+	return &IssuedAPIKey{APIKey: apiKey, Key: key}, nil
+}
@@ -0,0 +1,26 @@
+package services
+
+import (
+	"infinite-experiment/politburo/internal/models/entities"
+	"testing"
+	"time"
+)
+
+func TestAPIKeyService_IssueStampsCreationInUTC(t *testing.T) {
+	service := NewAPIKeyService(nil, nil)
+
+	before := time.Now()
+	issued, err := service.issue(&entities.ApiKey{Name: "bot"})
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+
+	// created_at is stored as wall time like expires_at, so it must not carry the local zone
+	createdAt := issued.APIKey.CreatedAt
+	if createdAt.Location() != time.UTC {
+		t.Errorf("created_at in %s, want UTC", createdAt.Location())
+	}
+	if createdAt.Before(before.Add(-time.Second)) || createdAt.After(time.Now().Add(time.Second)) {
+		t.Errorf("created_at = %s, want now", createdAt)
+	}
+}
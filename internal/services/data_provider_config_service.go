@@ -9,21 +9,30 @@ import (
 	"infinite-experiment/politburo/internal/db/repositories"
 	"infinite-experiment/politburo/internal/models"
 	"infinite-experiment/politburo/internal/models/dtos"
+	"infinite-experiment/politburo/internal/providers"
+	"infinite-experiment/politburo/internal/secrets"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/lib/pq"
 )
 
+// DataProviderConfigService manages VA data provider configs. Cached configs keep their
+// credentials sealed, since the cache may be shared (Redis).
 type DataProviderConfigService struct {
 	configRepo *repositories.DataProviderConfigRepo
 	cache      common.CacheInterface
+	registry   *providers.Registry
+	keyring    *secrets.Keyring
 }
 
-func NewDataProviderConfigService(configRepo *repositories.DataProviderConfigRepo, cache common.CacheInterface) *DataProviderConfigService {
+func NewDataProviderConfigService(configRepo *repositories.DataProviderConfigRepo, cache common.CacheInterface, registry *providers.Registry, keyring *secrets.Keyring) *DataProviderConfigService {
 	return &DataProviderConfigService{
 		configRepo: configRepo,
 		cache:      cache,
+		registry:   registry,
+		keyring:    keyring,
 	}
 }
 
@@ -48,7 +57,7 @@ func (s *DataProviderConfigService) SaveOrUpdateConfig(ctx context.Context, vaID
 	}
 
 	// Check if config already exists for this VA and provider type
-	existingConfig, err := s.configRepo.GetActiveConfig(ctx, vaID, req.ProviderType)
+	existingConfig, err := s.configRepo.GetConfig(ctx, vaID, req.ProviderType)
 	if err != nil {
 		return nil, &ConfigError{
 			Code:    constants.ErrCodeNetworkError,
@@ -114,6 +123,23 @@ func (s *DataProviderConfigService) SaveOrUpdateConfig(ctx context.Context, vaID
 		config = newConfig
 	}
 
+	// A VA syncs with one provider at a time, so activating this config deactivates the others
+	if config.IsActive {
+		deactivated, err := s.configRepo.DeactivateOtherConfigs(ctx, vaID, config.ID)
+		if err != nil {
+			return nil, &ConfigError{
+				Code:    constants.ErrCodeNetworkError,
+				Message: "Failed to deactivate other configs",
+				Err:     err,
+			}
+		}
+		for _, providerType := range deactivated {
+			s.cache.Delete(fmt.Sprintf("provider_config:%s:%s", vaID, providerType))
+			log.Printf("[DataProviderConfigService] Deactivated %s config for VA=%s", providerType, vaID)
+		}
+	}
+	s.cache.Delete(fmt.Sprintf("provider_config:%s:active", vaID))
+
 	// Build response
 	response := &DataProviderConfigResponse{
 		ID:               config.ID,
@@ -129,7 +155,7 @@ func (s *DataProviderConfigService) SaveOrUpdateConfig(ctx context.Context, vaID
 	// Add parsed config data
 	parsedConfig, err := repositories.ParseConfigData(config.ConfigData)
 	if err == nil {
-		response.ConfigData = parsedConfig
+		response.ConfigData = parsedConfig.Redacted()
 	}
 
 	return response, nil
@@ -149,14 +175,40 @@ func (s *DataProviderConfigService) validateConfigRequest(req *dtos.SaveProvider
 		return fmt.Errorf("provider is required in config_data")
 	}
 
-	// Validate credentials for Airtable
-	if req.ProviderType == "airtable" {
+	// Clients send plain credentials; a value that looks sealed would never open
+	if _, err := req.ConfigData.MapSecrets(secrets.RequirePlain); err != nil {
+		return fmt.Errorf("credentials: %w", err)
+	}
+
+	// Validate credentials for the provider type
+	switch req.ProviderType {
+	case providers.ProviderTypeAirtable:
 		if req.ConfigData.Credentials.APIKey == "" {
 			return fmt.Errorf("api_key is required for Airtable")
 		}
 		if req.ConfigData.Credentials.BaseID == "" {
 			return fmt.Errorf("base_id is required for Airtable")
 		}
+	case providers.ProviderTypeGoogleSheets:
+		if req.ConfigData.Credentials.BaseID == "" {
+			return fmt.Errorf("base_id (spreadsheet ID) is required for Google Sheets")
+		}
+		if req.ConfigData.Credentials.ServiceAccountJSON == "" && req.ConfigData.Credentials.APIKey == "" {
+			return fmt.Errorf("service_account_json or api_key is required for Google Sheets")
+		}
+	case providers.ProviderTypeREST:
+		if req.ConfigData.Credentials.BaseURL == "" {
+			return fmt.Errorf("base_url is required for the REST provider")
+		}
+		for _, schema := range req.ConfigData.Schemas {
+			if schema.Enabled && (schema.Endpoint == nil || schema.Endpoint.ListPath == "") {
+				return fmt.Errorf("schema '%s' requires endpoint.list_path for the REST provider", schema.EntityType)
+			}
+		}
+	default:
+		if !s.registry.Has(req.ProviderType) {
+			return fmt.Errorf("unsupported provider_type '%s' (allowed: %s)", req.ProviderType, strings.Join(s.registry.Types(), ", "))
+		}
 	}
 
 	// Validate at least one schema is provided
@@ -252,10 +304,14 @@ func (s *DataProviderConfigService) GetActiveConfigCached(ctx context.Context, v
 
 	// Check cache first
 	if cached, found := s.cache.Get(cacheKey); found {
-		if configData, ok := cached.(*dtos.ProviderConfigData); ok {
-			configJSON, _ := json.MarshalIndent(configData, "", "  ")
-			log.Printf("[DataProviderConfigService] Cache hit for provider config: VA=%s, Provider=%s\nConfig:\n%s", vaID, providerType, string(configJSON))
-			return configData, nil
+		if sealed, ok := cached.(*dtos.ProviderConfigData); ok {
+			configData, err := sealed.MapSecrets(s.keyring.Open)
+			if err == nil {
+				configJSON, _ := json.MarshalIndent(configData.Redacted(), "", "  ")
+				log.Printf("[DataProviderConfigService] Cache hit for provider config: VA=%s, Provider=%s\nConfig:\n%s", vaID, providerType, string(configJSON))
+				return configData, nil
+			}
+			log.Printf("[DataProviderConfigService] Error decrypting cached provider config: %v", err)
 		}
 	}
 
@@ -278,9 +334,67 @@ func (s *DataProviderConfigService) GetActiveConfigCached(ctx context.Context, v
 	}
 
 	// Cache for 24 hours
-	s.cache.Set(cacheKey, configData, 24*time.Hour)
-	configJSON, _ := json.MarshalIndent(configData, "", "  ")
+	s.cacheSealed(cacheKey, configData, func(sealed *dtos.ProviderConfigData) interface{} { return sealed })
+	configJSON, _ := json.MarshalIndent(configData.Redacted(), "", "  ")
 	log.Printf("[DataProviderConfigService] Cached provider config: VA=%s, Provider=%s, TTL=24h\nConfig:\n%s", vaID, providerType, string(configJSON))
 
 	return configData, nil
 }
+
+// ActiveProviderConfig is a VA's active provider config together with its provider type
+type ActiveProviderConfig struct {
+	ProviderType string
+	ConfigData   *dtos.ProviderConfigData
+}
+
+// GetActiveProviderConfigCached fetches the VA's active config, whatever its provider type,
+// with caching (24-hour TTL). Returns nil when the VA has no active config.
+func (s *DataProviderConfigService) GetActiveProviderConfigCached(ctx context.Context, vaID string) (*ActiveProviderConfig, error) {
+	cacheKey := fmt.Sprintf("provider_config:%s:active", vaID)
+
+	if cached, found := s.cache.Get(cacheKey); found {
+		if sealed, ok := cached.(*ActiveProviderConfig); ok {
+			configData, err := sealed.ConfigData.MapSecrets(s.keyring.Open)
+			if err == nil {
+				return &ActiveProviderConfig{ProviderType: sealed.ProviderType, ConfigData: configData}, nil
+			}
+			log.Printf("[DataProviderConfigService] Error decrypting cached provider config: %v", err)
+		}
+	}
+
+	providerConfig, err := s.configRepo.GetActiveConfigForVA(ctx, vaID)
+	if err != nil || providerConfig == nil {
+		if err != nil {
+			log.Printf("[DataProviderConfigService] Error fetching active provider config: %v", err)
+		}
+		return nil, err
+	}
+
+	configData, err := repositories.ParseConfigData(providerConfig.ConfigData)
+	if err != nil {
+		log.Printf("[DataProviderConfigService] Error parsing provider config: %v", err)
+		return nil, err
+	}
+
+	active := &ActiveProviderConfig{
+		ProviderType: providerConfig.ProviderType,
+		ConfigData:   configData,
+	}
+	s.cacheSealed(cacheKey, configData, func(sealed *dtos.ProviderConfigData) interface{} {
+		return &ActiveProviderConfig{ProviderType: active.ProviderType, ConfigData: sealed}
+	})
+	log.Printf("[DataProviderConfigService] Cached active provider config: VA=%s, Provider=%s, TTL=24h", vaID, providerConfig.ProviderType)
+
+	return active, nil
+}
+
+// cacheSealed caches the value built from a copy of configData with its credentials sealed
+// for 24 hours. Nothing is cached when they can't be sealed.
+func (s *DataProviderConfigService) cacheSealed(cacheKey string, configData *dtos.ProviderConfigData, value func(sealed *dtos.ProviderConfigData) interface{}) {
+	sealed, err := configData.MapSecrets(s.keyring.Seal)
+	if err != nil {
+		log.Printf("[DataProviderConfigService] Not caching %s: %v", cacheKey, err)
+		return
+	}
+	s.cache.Set(cacheKey, value(sealed), 24*time.Hour)
+}
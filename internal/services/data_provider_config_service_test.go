@@ -0,0 +1,30 @@
+package services
+
+import (
+	"errors"
+	"infinite-experiment/politburo/internal/models/dtos"
+	"infinite-experiment/politburo/internal/providers"
+	"infinite-experiment/politburo/internal/secrets"
+	"testing"
+)
+
+func TestDataProviderConfigService_ValidateConfigRequest_RejectsSealedCredentials(t *testing.T) {
+	service := &DataProviderConfigService{}
+	request := func(apiKey string) *dtos.SaveProviderConfigRequest {
+		return &dtos.SaveProviderConfigRequest{
+			ProviderType: providers.ProviderTypeAirtable,
+			ConfigData: dtos.ProviderConfigData{
+				Version:     "1.0",
+				Provider:    providers.ProviderTypeAirtable,
+				Credentials: dtos.ProviderCreds{APIKey: apiKey, BaseID: "appTest"},
+			},
+		}
+	}
+
+	if err := service.validateConfigRequest(request("enc:v1:k1:bm90:YSBrZXk")); !errors.Is(err, secrets.ErrSealedInput) {
+		t.Errorf("sealed-looking api_key: got %v, want ErrSealedInput", err)
+	}
+	if err := service.validateConfigRequest(request("patSecret.123")); errors.Is(err, secrets.ErrSealedInput) {
+		t.Errorf("plain api_key refused as sealed: %v", err)
+	}
+}
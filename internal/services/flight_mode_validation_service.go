@@ -3,15 +3,22 @@ package services
 import (
 	"context"
 	"fmt"
+	"log"
+	"path"
+	"strings"
+	"time"
 
 	"infinite-experiment/politburo/internal/common"
+	"infinite-experiment/politburo/internal/constants"
+	"infinite-experiment/politburo/internal/db/repositories"
 	"infinite-experiment/politburo/internal/models/dtos"
 )
 
 // FlightModeValidationService validates if a current flight is eligible for a specific mode
 type FlightModeValidationService struct {
-	liveAPI *common.LiveAPIService
-	cache   common.CacheInterface
+	liveAPI     *common.LiveAPIService
+	cache       common.CacheInterface
+	airportRepo *repositories.AirportRepository
 }
 
 // ValidationResult represents the result of a flight mode validation
@@ -20,55 +27,359 @@ type ValidationResult struct {
 	ErrorMsg string
 }
 
+// FlightSnapshot is the flight being checked against a mode's validation rules
+type FlightSnapshot struct {
+	Origin      string
+	Destination string
+	Aircraft    string
+	Livery      string
+	AltitudeFt  int
+	SessionID   string
+	At          time.Time
+}
+
+// Route returns the flight's route in "ORIG-DEST" form
+func (f *FlightSnapshot) Route() string {
+	return fmt.Sprintf("%s-%s", f.Origin, f.Destination)
+}
+
+// NewFlightSnapshot builds a snapshot of a live flight as of now
+func NewFlightSnapshot(lf *dtos.LiveFlight) *FlightSnapshot {
+	return &FlightSnapshot{
+		Origin:      lf.Origin,
+		Destination: lf.Destination,
+		Aircraft:    lf.Aircraft,
+		Livery:      lf.Livery,
+		AltitudeFt:  lf.AltitudeFt,
+		SessionID:   lf.SessionID,
+		At:          time.Now().UTC(),
+	}
+}
+
+// NewCompletedFlightSnapshot builds a snapshot of a flight from the pilot's flight history, as of
+// when it was flown. The altitude is the highest the flight reached.
+func NewCompletedFlightSnapshot(cf *CompletedFlight) *FlightSnapshot {
+	return &FlightSnapshot{
+		Origin:      cf.Origin,
+		Destination: cf.Destination,
+		Aircraft:    cf.Aircraft,
+		Livery:      cf.Livery,
+		AltitudeFt:  cf.MaxAltitudeFt,
+		SessionID:   cf.SessionID,
+		At:          cf.Created.UTC(),
+	}
+}
+
 // NewFlightModeValidationService creates a new flight mode validation service
 func NewFlightModeValidationService(
 	liveAPI *common.LiveAPIService,
 	cache common.CacheInterface,
+	airportRepo *repositories.AirportRepository,
 ) *FlightModeValidationService {
 	return &FlightModeValidationService{
-		liveAPI: liveAPI,
-		cache:   cache,
+		liveAPI:     liveAPI,
+		cache:       cache,
+		airportRepo: airportRepo,
 	}
 }
 
 // ValidateFlightForMode validates if a current flight qualifies for a specific mode
+// The route rule is checked first, then each additional rule that is configured
 func (s *FlightModeValidationService) ValidateFlightForMode(
 	ctx context.Context,
-	currentRoute string,
+	flight *FlightSnapshot,
 	config *dtos.ValidationConfig,
 ) *ValidationResult {
-	// If allow_any_current_route is true, always valid
-	if config.AllowAnyCurrentRoute {
-		return &ValidationResult{Valid: true}
+	if flight == nil {
+		flight = &FlightSnapshot{At: time.Now().UTC()}
+	}
+
+	checks := []func() *ValidationResult{
+		func() *ValidationResult { return s.validateRoute(flight, config) },
+		func() *ValidationResult { return s.validateAircraft(flight, config.AllowedAircraft) },
+		func() *ValidationResult { return s.validateLivery(flight, config.AllowedLiveries) },
+		func() *ValidationResult { return s.validateAltitude(flight, config.MinAltitudeFt) },
+		func() *ValidationResult { return s.validateDistance(ctx, flight, config.MinDistanceNm) },
+		func() *ValidationResult { return s.validateServer(flight, config.AllowedServers) },
+		func() *ValidationResult { return s.validateTimeWindows(flight, config.TimeWindows) },
+	}
+
+	for _, check := range checks {
+		if result := check(); !result.Valid {
+			return result
+		}
 	}
 
-	// If allowed_routes is empty, always valid
-	if len(config.AllowedRoutes) == 0 {
+	return &ValidationResult{Valid: true}
+}
+
+// validateRoute applies the mode's route rule
+func (s *FlightModeValidationService) validateRoute(flight *FlightSnapshot, config *dtos.ValidationConfig) *ValidationResult {
+	// If allow_any_current_route is true, any route is valid
+	if config.AllowAnyCurrentRoute {
 		return &ValidationResult{Valid: true}
 	}
 
 	// Check validation mode
 	switch config.ValidationMode {
-	case "exact_match":
-		return s.validateExactMatch(currentRoute, config.AllowedRoutes)
 	case "any":
 		return &ValidationResult{Valid: true}
+	case "origin":
+		return s.validateAirport("Origin", flight.Origin, config.AllowedOrigins)
+	case "destination":
+		return s.validateAirport("Destination", flight.Destination, config.AllowedDestinations)
+	case "hub":
+		if len(config.Hubs) == 0 || matchesAnyPattern(config.Hubs, flight.Origin) || matchesAnyPattern(config.Hubs, flight.Destination) {
+			return &ValidationResult{Valid: true}
+		}
+		return &ValidationResult{
+			Valid:    false,
+			ErrorMsg: fmt.Sprintf("Current route %s does not start or end at a hub for this mode", flight.Route()),
+		}
 	default:
-		// Default to exact match if unknown mode
-		return s.validateExactMatch(currentRoute, config.AllowedRoutes)
+		// exact_match, and the default for unknown modes
+		// If allowed_routes is empty, always valid
+		if len(config.AllowedRoutes) == 0 {
+			return &ValidationResult{Valid: true}
+		}
+		return s.validateExactMatch(flight.Route(), config.AllowedRoutes)
 	}
 }
 
 // validateExactMatch checks if current route is in allowed routes
 func (s *FlightModeValidationService) validateExactMatch(currentRoute string, allowedRoutes []string) *ValidationResult {
-	for _, route := range allowedRoutes {
-		if route == currentRoute {
+	if matchesAnyPattern(allowedRoutes, currentRoute) {
+		return &ValidationResult{Valid: true}
+	}
+
+	return &ValidationResult{
+		Valid:    false,
+		ErrorMsg: fmt.Sprintf("Current route %s not in allowed routes for this mode", currentRoute),
+	}
+}
+
+// validateAirport checks a single airport against an allow-list (empty list allows any)
+func (s *FlightModeValidationService) validateAirport(label string, icao string, allowed []string) *ValidationResult {
+	if len(allowed) == 0 || matchesAnyPattern(allowed, icao) {
+		return &ValidationResult{Valid: true}
+	}
+	if icao == "" {
+		icao = "unknown"
+	}
+	return &ValidationResult{
+		Valid:    false,
+		ErrorMsg: fmt.Sprintf("%s %s not allowed for this mode", label, icao),
+	}
+}
+
+// validateAircraft checks the flight's aircraft against the allow-list
+func (s *FlightModeValidationService) validateAircraft(flight *FlightSnapshot, allowed []string) *ValidationResult {
+	if len(allowed) == 0 || containsFold(allowed, flight.Aircraft) {
+		return &ValidationResult{Valid: true}
+	}
+	return &ValidationResult{
+		Valid:    false,
+		ErrorMsg: fmt.Sprintf("Aircraft %s not allowed for this mode", orUnknown(flight.Aircraft)),
+	}
+}
+
+// validateLivery checks the flight's livery against the allow-list
+func (s *FlightModeValidationService) validateLivery(flight *FlightSnapshot, allowed []string) *ValidationResult {
+	if len(allowed) == 0 || containsFold(allowed, flight.Livery) {
+		return &ValidationResult{Valid: true}
+	}
+	return &ValidationResult{
+		Valid:    false,
+		ErrorMsg: fmt.Sprintf("Livery %s not allowed for this mode", orUnknown(flight.Livery)),
+	}
+}
+
+// validateAltitude checks the flight has reached the minimum altitude
+func (s *FlightModeValidationService) validateAltitude(flight *FlightSnapshot, minAltitudeFt int) *ValidationResult {
+	if minAltitudeFt <= 0 || flight.AltitudeFt >= minAltitudeFt {
+		return &ValidationResult{Valid: true}
+	}
+	return &ValidationResult{
+		Valid:    false,
+		ErrorMsg: fmt.Sprintf("Altitude %d ft is below the %d ft minimum for this mode", flight.AltitudeFt, minAltitudeFt),
+	}
+}
+
+// validateDistance checks the great-circle distance between origin and destination
+func (s *FlightModeValidationService) validateDistance(ctx context.Context, flight *FlightSnapshot, minDistanceNm float64) *ValidationResult {
+	if minDistanceNm <= 0 {
+		return &ValidationResult{Valid: true}
+	}
+
+	distance, err := s.routeDistanceNm(ctx, flight.Origin, flight.Destination)
+	if err != nil {
+		return &ValidationResult{
+			Valid:    false,
+			ErrorMsg: fmt.Sprintf("Could not determine distance for route %s", flight.Route()),
+		}
+	}
+	if distance < minDistanceNm {
+		return &ValidationResult{
+			Valid:    false,
+			ErrorMsg: fmt.Sprintf("Route %s is %.0f nm, below the %.0f nm minimum for this mode", flight.Route(), distance, minDistanceNm),
+		}
+	}
+	return &ValidationResult{Valid: true}
+}
+
+// validateServer checks the flight is on one of the allowed servers (matched by name, e.g. "expert")
+func (s *FlightModeValidationService) validateServer(flight *FlightSnapshot, allowed []string) *ValidationResult {
+	if len(allowed) == 0 {
+		return &ValidationResult{Valid: true}
+	}
+
+	serverName := s.serverName(flight.SessionID)
+	for _, a := range allowed {
+		if serverName != "" && strings.Contains(strings.ToLower(serverName), strings.ToLower(a)) {
 			return &ValidationResult{Valid: true}
 		}
 	}
+	return &ValidationResult{
+		Valid:    false,
+		ErrorMsg: fmt.Sprintf("Server %s not allowed for this mode", orUnknown(serverName)),
+	}
+}
 
+// validateTimeWindows checks the flight falls inside at least one configured window
+func (s *FlightModeValidationService) validateTimeWindows(flight *FlightSnapshot, windows []dtos.TimeWindow) *ValidationResult {
+	if len(windows) == 0 {
+		return &ValidationResult{Valid: true}
+	}
+	for _, w := range windows {
+		if inTimeWindow(w, flight.At) {
+			return &ValidationResult{Valid: true}
+		}
+	}
 	return &ValidationResult{
 		Valid:    false,
-		ErrorMsg: fmt.Sprintf("Current route %s not in allowed routes for this mode", currentRoute),
+		ErrorMsg: "This mode is not available at this time",
+	}
+}
+
+// routeDistanceNm looks up both airports and returns the great-circle distance between them
+func (s *FlightModeValidationService) routeDistanceNm(ctx context.Context, origin, destination string) (float64, error) {
+	if s.airportRepo == nil || origin == "" || destination == "" {
+		return 0, fmt.Errorf("airports unavailable")
+	}
+
+	org, err := s.airportRepo.FindByICAO(ctx, origin)
+	if err != nil || org == nil {
+		return 0, fmt.Errorf("airport not found: %s", origin)
+	}
+	dest, err := s.airportRepo.FindByICAO(ctx, destination)
+	if err != nil || dest == nil {
+		return 0, fmt.Errorf("airport not found: %s", destination)
+	}
+
+	return common.GreatCircleDistanceNm(org.Latitude, org.Longitude, dest.Latitude, dest.Longitude), nil
+}
+
+// serverName resolves a session ID to its server name (cache-first, then Live API)
+func (s *FlightModeValidationService) serverName(sessionID string) string {
+	if sessionID == "" {
+		return ""
+	}
+
+	const cacheKey = string(constants.CacheKeyServers)
+	var sessions *[]dtos.Session
+	if s.cache != nil {
+		if val, found := s.cache.Get(cacheKey); found {
+			sessions, _ = val.(*[]dtos.Session)
+		}
+	}
+	if sessions == nil && s.liveAPI != nil {
+		data, err := s.liveAPI.GetSessions()
+		if err != nil {
+			log.Printf("[FlightModeValidationService] Could not fetch sessions: %v", err)
+			return ""
+		}
+		sessions = &data.Result
+		if s.cache != nil {
+			s.cache.Set(cacheKey, sessions, 5*time.Minute)
+		}
+	}
+	if sessions == nil {
+		return ""
+	}
+
+	for _, session := range *sessions {
+		if session.ID == sessionID {
+			return session.Name
+		}
+	}
+	return ""
+}
+
+// inTimeWindow reports whether t (UTC) falls inside the window
+func inTimeWindow(w dtos.TimeWindow, t time.Time) bool {
+	t = t.UTC()
+
+	if w.From != nil && t.Before(*w.From) {
+		return false
+	}
+	if w.Until != nil && !t.Before(*w.Until) {
+		return false
+	}
+
+	if len(w.Days) > 0 {
+		day := strings.ToLower(t.Weekday().String()[:3])
+		if !containsFold(w.Days, day) {
+			return false
+		}
+	}
+
+	if w.StartTime != "" && w.EndTime != "" {
+		start, errStart := time.Parse("15:04", w.StartTime)
+		end, errEnd := time.Parse("15:04", w.EndTime)
+		if errStart != nil || errEnd != nil {
+			return false
+		}
+		now := t.Hour()*60 + t.Minute()
+		from := start.Hour()*60 + start.Minute()
+		to := end.Hour()*60 + end.Minute()
+		if from <= to {
+			return now >= from && now < to
+		}
+		// Window wraps past midnight (e.g. 22:00-06:00)
+		return now >= from || now < to
+	}
+
+	return true
+}
+
+// matchesAnyPattern reports whether value matches any ICAO wildcard pattern (* and ?), case-insensitively
+func matchesAnyPattern(patterns []string, value string) bool {
+	if value == "" {
+		return false
+	}
+	value = strings.ToUpper(value)
+	for _, p := range patterns {
+		if ok, err := path.Match(strings.ToUpper(strings.TrimSpace(p)), value); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// containsFold reports whether list contains value, case-insensitively
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(strings.TrimSpace(item), value) {
+			return true
+		}
+	}
+	return false
+}
+
+// orUnknown substitutes "unknown" for an empty value in error messages
+func orUnknown(v string) string {
+	if v == "" {
+		return "unknown"
 	}
+	return v
 }
@@ -0,0 +1,231 @@
+package services
+
+import (
+	"context"
+	"infinite-experiment/politburo/internal/common"
+	"infinite-experiment/politburo/internal/constants"
+	"infinite-experiment/politburo/internal/db/repositories"
+	"infinite-experiment/politburo/internal/models/dtos"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestFlightModeValidationService creates a service with two airports 187 nm apart and
+// the expert and casual servers cached
+func newTestFlightModeValidationService(t *testing.T) *FlightModeValidationService {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	// The model's Postgres defaults don't migrate to SQLite
+	if err := db.Exec(`CREATE TABLE airports (id TEXT PRIMARY KEY, icao TEXT, name TEXT, latitude REAL, longitude REAL)`).Error; err != nil {
+		t.Fatalf("Failed to create airports: %v", err)
+	}
+	if err := db.Exec(`INSERT INTO airports VALUES
+		('1', 'EGLL', 'London Heathrow', 51.4706, -0.461941),
+		('2', 'LFPG', 'Paris Charles de Gaulle', 49.012798, 2.55)`).Error; err != nil {
+		t.Fatalf("Failed to insert airports: %v", err)
+	}
+
+	cache := common.NewCacheService(60, 60)
+	t.Cleanup(func() { cache.Close() })
+	cache.Set(string(constants.CacheKeyServers), &[]dtos.Session{
+		{ID: "session-expert", Name: "Expert Server"},
+		{ID: "session-casual", Name: "Casual Server"},
+	}, time.Hour)
+
+	return NewFlightModeValidationService(nil, cache, repositories.NewAirportRepository(db))
+}
+
+func TestFlightModeValidationService_ValidateFlightForMode(t *testing.T) {
+	service := newTestFlightModeValidationService(t)
+	flight := FlightSnapshot{
+		Origin:      "EGLL",
+		Destination: "LFPG",
+		Aircraft:    "Airbus A320",
+		Livery:      "British Airways",
+		AltitudeFt:  35000,
+		SessionID:   "session-expert",
+		At:          time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC), // A Monday
+	}
+
+	tests := []struct {
+		name    string
+		flight  func(f *FlightSnapshot)
+		config  dtos.ValidationConfig
+		wantErr string // Empty when the flight is valid
+	}{
+		{name: "no rules", config: dtos.ValidationConfig{}},
+
+		// Routes
+		{name: "exact route", config: dtos.ValidationConfig{AllowedRoutes: []string{"EGLL-LFPG"}}},
+		{name: "exact route is case-insensitive", config: dtos.ValidationConfig{AllowedRoutes: []string{"egll-lfpg"}}},
+		{name: "route wildcard", config: dtos.ValidationConfig{AllowedRoutes: []string{"EG*-LF??"}}},
+		{name: "route not allowed", config: dtos.ValidationConfig{AllowedRoutes: []string{"EGLL-KJFK"}}, wantErr: "Current route EGLL-LFPG not in allowed routes"},
+		{name: "route wildcard does not cross the dash", config: dtos.ValidationConfig{AllowedRoutes: []string{"EGLL-LF?"}}, wantErr: "not in allowed routes"},
+		{name: "any current route", config: dtos.ValidationConfig{AllowAnyCurrentRoute: true, AllowedRoutes: []string{"EGLL-KJFK"}}},
+		{name: "origin wildcard", config: dtos.ValidationConfig{ValidationMode: "origin", AllowedOrigins: []string{"EG*"}}},
+		{name: "origin not allowed", config: dtos.ValidationConfig{ValidationMode: "origin", AllowedOrigins: []string{"K*"}}, wantErr: "Origin EGLL not allowed"},
+		{name: "unknown origin", flight: func(f *FlightSnapshot) { f.Origin = "" }, config: dtos.ValidationConfig{ValidationMode: "origin", AllowedOrigins: []string{"*"}}, wantErr: "Origin unknown not allowed"},
+		{name: "destination", config: dtos.ValidationConfig{ValidationMode: "destination", AllowedDestinations: []string{"lfpg"}}},
+		{name: "hub at destination", config: dtos.ValidationConfig{ValidationMode: "hub", Hubs: []string{"KJFK", "LFPG"}}},
+		{name: "no hub", config: dtos.ValidationConfig{ValidationMode: "hub", Hubs: []string{"KJFK"}}, wantErr: "does not start or end at a hub"},
+		{name: "malformed pattern matches nothing", config: dtos.ValidationConfig{ValidationMode: "origin", AllowedOrigins: []string{"EG[LL"}}, wantErr: "Origin EGLL not allowed"},
+		{name: "malformed pattern does not hide valid ones", config: dtos.ValidationConfig{ValidationMode: "origin", AllowedOrigins: []string{"EG[LL", "EGLL"}}},
+
+		// Aircraft and livery
+		{name: "aircraft is case-insensitive", config: dtos.ValidationConfig{AllowedAircraft: []string{"airbus a320"}}},
+		{name: "aircraft ignores surrounding spaces", config: dtos.ValidationConfig{AllowedAircraft: []string{" Airbus A320 "}}},
+		{name: "aircraft not allowed", config: dtos.ValidationConfig{AllowedAircraft: []string{"Boeing 737-800"}}, wantErr: "Aircraft Airbus A320 not allowed"},
+		{name: "aircraft is not a pattern", config: dtos.ValidationConfig{AllowedAircraft: []string{"Airbus*"}}, wantErr: "Aircraft Airbus A320 not allowed"},
+		{name: "unknown aircraft", flight: func(f *FlightSnapshot) { f.Aircraft = "" }, config: dtos.ValidationConfig{AllowedAircraft: []string{"Airbus A320"}}, wantErr: "Aircraft unknown not allowed"},
+		{name: "livery is case-insensitive", config: dtos.ValidationConfig{AllowedLiveries: []string{"BRITISH AIRWAYS"}}},
+		{name: "livery not allowed", config: dtos.ValidationConfig{AllowedLiveries: []string{"Air France"}}, wantErr: "Livery British Airways not allowed"},
+
+		// Altitude and distance
+		{name: "altitude above minimum", config: dtos.ValidationConfig{MinAltitudeFt: 30000}},
+		{name: "altitude at minimum", config: dtos.ValidationConfig{MinAltitudeFt: 35000}},
+		{name: "altitude below minimum", config: dtos.ValidationConfig{MinAltitudeFt: 35001}, wantErr: "Altitude 35000 ft is below the 35001 ft minimum"},
+		{name: "distance above minimum", config: dtos.ValidationConfig{MinDistanceNm: 150}},
+		{name: "distance below minimum", config: dtos.ValidationConfig{MinDistanceNm: 200}, wantErr: "Route EGLL-LFPG is 187 nm, below the 200 nm minimum"},
+		{name: "distance of unknown airport", flight: func(f *FlightSnapshot) { f.Destination = "ZZZZ" }, config: dtos.ValidationConfig{MinDistanceNm: 1}, wantErr: "Could not determine distance for route EGLL-ZZZZ"},
+		{name: "distance without destination", flight: func(f *FlightSnapshot) { f.Destination = "" }, config: dtos.ValidationConfig{MinDistanceNm: 1}, wantErr: "Could not determine distance"},
+
+		// Servers
+		{name: "server name contains allowed name", config: dtos.ValidationConfig{AllowedServers: []string{"expert"}}},
+		{name: "server is case-insensitive", config: dtos.ValidationConfig{AllowedServers: []string{"EXPERT"}}},
+		{name: "server not allowed", config: dtos.ValidationConfig{AllowedServers: []string{"training"}}, wantErr: "Server Expert Server not allowed"},
+		{name: "unknown session", flight: func(f *FlightSnapshot) { f.SessionID = "session-gone" }, config: dtos.ValidationConfig{AllowedServers: []string{"expert"}}, wantErr: "Server unknown not allowed"},
+
+		// Time windows
+		{name: "inside a time window", config: dtos.ValidationConfig{TimeWindows: []dtos.TimeWindow{{StartTime: "10:00", EndTime: "14:00"}}}},
+		{name: "outside every time window", config: dtos.ValidationConfig{TimeWindows: []dtos.TimeWindow{{StartTime: "20:00", EndTime: "22:00"}, {Days: []string{"sat", "sun"}}}}, wantErr: "not available at this time"},
+		{name: "inside one of several time windows", config: dtos.ValidationConfig{TimeWindows: []dtos.TimeWindow{{StartTime: "20:00", EndTime: "22:00"}, {Days: []string{"mon"}}}}},
+
+		// The first failing rule is reported
+		{name: "route reported before aircraft", config: dtos.ValidationConfig{AllowedRoutes: []string{"EGLL-KJFK"}, AllowedAircraft: []string{"Boeing 737-800"}}, wantErr: "Current route"},
+		{name: "aircraft reported before altitude", config: dtos.ValidationConfig{AllowedAircraft: []string{"Boeing 737-800"}, MinAltitudeFt: 40000}, wantErr: "Aircraft"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := flight
+			if tt.flight != nil {
+				tt.flight(&f)
+			}
+
+			result := service.ValidateFlightForMode(context.Background(), &f, &tt.config)
+			if tt.wantErr == "" {
+				if !result.Valid {
+					t.Errorf("flight rejected: %s", result.ErrorMsg)
+				}
+				return
+			}
+			if result.Valid {
+				t.Fatalf("flight accepted, want %q", tt.wantErr)
+			}
+			if !strings.Contains(result.ErrorMsg, tt.wantErr) {
+				t.Errorf("error %q, want it to contain %q", result.ErrorMsg, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInTimeWindow(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		// 3 June 2024 is a Monday
+		return time.Date(2024, 6, day, hour, minute, 0, 0, time.UTC)
+	}
+	from := at(3, 0, 0)
+	until := at(10, 0, 0)
+
+	tests := []struct {
+		name   string
+		window dtos.TimeWindow
+		at     time.Time
+		want   bool
+	}{
+		{"empty window", dtos.TimeWindow{}, at(3, 12, 0), true},
+
+		// Daily hours
+		{"inside hours", dtos.TimeWindow{StartTime: "09:00", EndTime: "17:00"}, at(3, 12, 0), true},
+		{"start is inclusive", dtos.TimeWindow{StartTime: "09:00", EndTime: "17:00"}, at(3, 9, 0), true},
+		{"end is exclusive", dtos.TimeWindow{StartTime: "09:00", EndTime: "17:00"}, at(3, 17, 0), false},
+		{"before hours", dtos.TimeWindow{StartTime: "09:00", EndTime: "17:00"}, at(3, 8, 59), false},
+		{"hours are UTC", dtos.TimeWindow{StartTime: "09:00", EndTime: "17:00"}, time.Date(2024, 6, 3, 18, 30, 0, 0, time.FixedZone("EDT", -4*60*60)), false},
+		{"start time only", dtos.TimeWindow{StartTime: "09:00"}, at(3, 3, 0), true},
+		{"malformed hours", dtos.TimeWindow{StartTime: "9am", EndTime: "17:00"}, at(3, 12, 0), false},
+		{"out of range hours", dtos.TimeWindow{StartTime: "09:00", EndTime: "25:00"}, at(3, 12, 0), false},
+
+		// Windows wrapping midnight
+		{"wrap: before midnight", dtos.TimeWindow{StartTime: "22:00", EndTime: "06:00"}, at(3, 23, 30), true},
+		{"wrap: at start", dtos.TimeWindow{StartTime: "22:00", EndTime: "06:00"}, at(3, 22, 0), true},
+		{"wrap: at midnight", dtos.TimeWindow{StartTime: "22:00", EndTime: "06:00"}, at(4, 0, 0), true},
+		{"wrap: after midnight", dtos.TimeWindow{StartTime: "22:00", EndTime: "06:00"}, at(4, 5, 59), true},
+		{"wrap: at end", dtos.TimeWindow{StartTime: "22:00", EndTime: "06:00"}, at(4, 6, 0), false},
+		{"wrap: during the day", dtos.TimeWindow{StartTime: "22:00", EndTime: "06:00"}, at(3, 12, 0), false},
+		{"wrap: just before start", dtos.TimeWindow{StartTime: "22:00", EndTime: "06:00"}, at(3, 21, 59), false},
+		{"wrap: days apply to the hour, not the evening it started", dtos.TimeWindow{Days: []string{"mon"}, StartTime: "22:00", EndTime: "06:00"}, at(4, 1, 0), false},
+
+		// Days
+		{"on an allowed day", dtos.TimeWindow{Days: []string{"mon", "tue"}}, at(3, 12, 0), true},
+		{"days are case-insensitive", dtos.TimeWindow{Days: []string{"MON"}}, at(3, 12, 0), true},
+		{"on another day", dtos.TimeWindow{Days: []string{"sat", "sun"}}, at(3, 12, 0), false},
+		{"day and hours", dtos.TimeWindow{Days: []string{"mon"}, StartTime: "09:00", EndTime: "17:00"}, at(3, 12, 0), true},
+		{"right hours on the wrong day", dtos.TimeWindow{Days: []string{"tue"}, StartTime: "09:00", EndTime: "17:00"}, at(3, 12, 0), false},
+
+		// Period
+		{"inside period", dtos.TimeWindow{From: &from, Until: &until}, at(5, 12, 0), true},
+		{"from is inclusive", dtos.TimeWindow{From: &from, Until: &until}, from, true},
+		{"until is exclusive", dtos.TimeWindow{From: &from, Until: &until}, until, false},
+		{"before period", dtos.TimeWindow{From: &from}, at(2, 23, 59), false},
+		{"period and hours", dtos.TimeWindow{From: &from, Until: &until, StartTime: "09:00", EndTime: "17:00"}, at(11, 12, 0), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := inTimeWindow(tt.window, tt.at); got != tt.want {
+				t.Errorf("inTimeWindow(%+v, %s) = %t, want %t", tt.window, tt.at.Format(time.RFC3339), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchesAnyPattern(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		value    string
+		want     bool
+	}{
+		{"exact", []string{"EGLL"}, "EGLL", true},
+		{"case-insensitive", []string{"egll"}, "Egll", true},
+		{"surrounding spaces", []string{" EGLL "}, "EGLL", true},
+		{"star prefix", []string{"EG*"}, "EGKK", true},
+		{"star matches nothing", []string{"EGLL*"}, "EGLL", true},
+		{"question mark", []string{"EGL?"}, "EGLL", true},
+		{"question mark needs a character", []string{"EGLL?"}, "EGLL", false},
+		{"character class", []string{"EG[KL]L"}, "EGLL", true},
+		{"no match", []string{"LF*", "K*"}, "EGLL", false},
+		{"second pattern", []string{"LF*", "EG*"}, "EGLL", true},
+		{"star does not cross a slash", []string{"EG*"}, "EG/LL", false},
+		{"malformed pattern", []string{"EG[LL"}, "EGLL", false},
+		{"malformed escape", []string{`EGLL\`}, "EGLL", false},
+		{"malformed then valid", []string{"[", "EGLL"}, "EGLL", true},
+		{"empty value", []string{"*"}, "", false},
+		{"no patterns", nil, "EGLL", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesAnyPattern(tt.patterns, tt.value); got != tt.want {
+				t.Errorf("matchesAnyPattern(%q, %q) = %t, want %t", tt.patterns, tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,131 +1,351 @@
 package services
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
 
+	"infinite-experiment/politburo/internal/common"
 	"infinite-experiment/politburo/internal/db/repositories"
+	"infinite-experiment/politburo/internal/models/dtos"
 	gormModels "infinite-experiment/politburo/internal/models/gorm"
 )
 
+// FlightModesSchemaVersion is the flight_modes_config schema version this build understands
+const FlightModesSchemaVersion = 1
+
+var (
+	// ErrConfigVersionNotFound is returned when a flight modes config version does not exist for the VA
+	ErrConfigVersionNotFound = errors.New("flight modes config version not found")
+
+	validFieldTypes      = map[string]struct{}{"text": {}, "textarea": {}, "number": {}, "date": {}}
+	validValidationModes = map[string]struct{}{"any": {}, "exact_match": {}, "origin": {}, "destination": {}, "hub": {}}
+	validServers         = map[string]struct{}{"casual": {}, "training": {}, "expert": {}}
+	validWeekdays        = map[string]struct{}{"mon": {}, "tue": {}, "wed": {}, "thu": {}, "fri": {}, "sat": {}, "sun": {}}
+)
+
+// FlightModesConfigError lists every problem found in a flight modes configuration
+type FlightModesConfigError struct {
+	Problems []string
+}
+
+func (e *FlightModesConfigError) Error() string {
+	return "invalid flight modes configuration: " + strings.Join(e.Problems, "; ")
+}
+
 // FlightModesConfigService handles flight modes configuration management
 type FlightModesConfigService struct {
-	vaGormRepo *repositories.VAGormRepository
+	vaGormRepo  *repositories.VAGormRepository
+	versionRepo *repositories.FlightModesConfigVersionRepo
+	routeRepo   *repositories.RouteATSyncedRepo
+	rankRepo    *repositories.RankRepo
 }
 
 // NewFlightModesConfigService creates a new flight modes config service
-func NewFlightModesConfigService(vaGormRepo *repositories.VAGormRepository) *FlightModesConfigService {
+func NewFlightModesConfigService(
+	vaGormRepo *repositories.VAGormRepository,
+	versionRepo *repositories.FlightModesConfigVersionRepo,
+	routeRepo *repositories.RouteATSyncedRepo,
+	rankRepo *repositories.RankRepo,
+) *FlightModesConfigService {
 	return &FlightModesConfigService{
-		vaGormRepo: vaGormRepo,
+		vaGormRepo:  vaGormRepo,
+		versionRepo: versionRepo,
+		routeRepo:   routeRepo,
+		rankRepo:    rankRepo,
 	}
 }
 
-// ValidateAndSaveConfig validates the flight modes configuration and saves it to the database
-// Validates against the complete schema from PIREP_LOGGING_IMPLEMENTATION_PLAN.md
-func (s *FlightModesConfigService) ValidateAndSaveConfig(ctx context.Context, vaID string, configPayload map[string]interface{}) error {
-	// Validate basic structure - must have flight_modes key
-	if _, ok := configPayload["flight_modes"]; !ok {
-		return fmt.Errorf("configuration must contain 'flight_modes' key")
+// ParseFlightModesConfig strictly decodes a raw flight modes configuration.
+// Unknown keys, type mismatches and duplicate mode IDs are rejected.
+func ParseFlightModesConfig(raw []byte) (*dtos.FlightModesConfig, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+
+	var config dtos.FlightModesConfig
+	if err := dec.Decode(&config); err != nil {
+		return nil, &FlightModesConfigError{Problems: []string{describeDecodeError(err)}}
 	}
 
-	flightModes, ok := configPayload["flight_modes"].(map[string]interface{})
-	if !ok {
-		return fmt.Errorf("'flight_modes' must be an object/map")
+	if dups := duplicateModeIDs(raw); len(dups) > 0 {
+		return nil, &FlightModesConfigError{Problems: []string{
+			fmt.Sprintf("duplicate mode IDs: %s", strings.Join(dups, ", ")),
+		}}
 	}
 
-	// Validate each mode has required keys and proper structure
-	for modeID, modeData := range flightModes {
-		modeConfig, ok := modeData.(map[string]interface{})
-		if !ok {
-			return fmt.Errorf("mode '%s' must be an object/map", modeID)
-		}
+	return &config, nil
+}
 
-		// Required keys for a mode
-		if _, hasEnabled := modeConfig["enabled"]; !hasEnabled {
-			return fmt.Errorf("mode '%s' must have 'enabled' field", modeID)
-		}
+// LoadFlightModesConfig decodes a VA's stored configuration into the typed schema.
+// Stored configs are decoded leniently so older configs keep working until re-saved.
+func LoadFlightModesConfig(va *gormModels.VA) (*dtos.FlightModesConfig, error) {
+	if len(va.FlightModesConfig) == 0 {
+		return nil, fmt.Errorf("no flight modes configured")
+	}
 
-		if _, hasDisplayName := modeConfig["display_name"]; !hasDisplayName {
-			return fmt.Errorf("mode '%s' must have 'display_name' field", modeID)
-		}
+	raw, err := json.Marshal(va.FlightModesConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode flight modes config: %w", err)
+	}
 
-		// Validate requires_route_selection
-		if _, hasRouteSelection := modeConfig["requires_route_selection"]; !hasRouteSelection {
-			return fmt.Errorf("mode '%s' must have 'requires_route_selection' field", modeID)
-		}
+	var config dtos.FlightModesConfig
+	if err := json.Unmarshal(raw, &config); err != nil {
+		return nil, fmt.Errorf("invalid flight modes structure: %w", err)
+	}
+	return &config, nil
+}
+
+// Validate checks a parsed configuration against the schema and the VA's data.
+// Returns nil or a *FlightModesConfigError listing every problem found.
+func (s *FlightModesConfigService) Validate(ctx context.Context, vaID string, config *dtos.FlightModesConfig) error {
+	var problems []string
+	addf := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if config.SchemaVersion > FlightModesSchemaVersion {
+		addf("schema_version %d is not supported (latest is %d)", config.SchemaVersion, FlightModesSchemaVersion)
+	}
+	if config.FlightModes == nil {
+		addf("configuration must contain 'flight_modes' object")
+	}
+
+	modeIDs := make([]string, 0, len(config.FlightModes))
+	for id := range config.FlightModes {
+		modeIDs = append(modeIDs, id)
+	}
+	sort.Strings(modeIDs)
+
+	// Rank names for min_rank, loaded on first use
+	var rankNames []string
+	ranksLoaded := false
 
-		// Validate fields array
-		fields, ok := modeConfig["fields"].([]interface{})
-		if !ok {
-			return fmt.Errorf("mode '%s': 'fields' must be an array", modeID)
+	for _, modeID := range modeIDs {
+		mode := config.FlightModes[modeID]
+
+		if strings.TrimSpace(modeID) == "" {
+			addf("mode IDs must not be empty")
+		}
+		if strings.TrimSpace(mode.DisplayName) == "" {
+			addf("mode '%s': 'display_name' is required", modeID)
 		}
 
-		for idx, fieldData := range fields {
-			field, ok := fieldData.(map[string]interface{})
-			if !ok {
-				return fmt.Errorf("mode '%s': field[%d] must be an object", modeID, idx)
+		fieldNames := make(map[string]struct{})
+		for idx, field := range mode.Fields {
+			if field.Name == "" {
+				addf("mode '%s': field[%d] must have 'name'", modeID, idx)
+			} else if _, dup := fieldNames[field.Name]; dup {
+				addf("mode '%s': field '%s' is defined more than once", modeID, field.Name)
 			}
+			fieldNames[field.Name] = struct{}{}
 
-			// Validate field properties
-			if _, hasName := field["name"]; !hasName {
-				return fmt.Errorf("mode '%s': field[%d] must have 'name'", modeID, idx)
+			if _, ok := validFieldTypes[field.Type]; !ok {
+				addf("mode '%s': field '%s' has unknown type '%s' (text, textarea, number, date)", modeID, field.Name, field.Type)
 			}
-
-			if _, hasType := field["type"]; !hasType {
-				return fmt.Errorf("mode '%s': field[%d] must have 'type' (text, textarea, number)", modeID, idx)
+			if field.Label == "" {
+				addf("mode '%s': field '%s' must have 'label'", modeID, field.Name)
 			}
+		}
 
-			if _, hasLabel := field["label"]; !hasLabel {
-				return fmt.Errorf("mode '%s': field[%d] must have 'label'", modeID, idx)
+		if mode.AutoRoute != nil {
+			if mode.RequiresRouteSelection {
+				addf("mode '%s': 'auto_route' cannot be combined with 'requires_route_selection'", modeID)
 			}
-
-			if _, hasRequired := field["required"]; !hasRequired {
-				return fmt.Errorf("mode '%s': field[%d] must have 'required'", modeID, idx)
+			if strings.TrimSpace(mode.AutoRoute.RouteName) == "" {
+				addf("mode '%s': auto_route must have 'route_name'", modeID)
+			} else if s.routeRepo != nil {
+				route, err := s.routeRepo.FindByName(ctx, vaID, strings.TrimSpace(mode.AutoRoute.RouteName))
+				if err != nil {
+					return fmt.Errorf("failed to look up auto_route for mode '%s': %w", modeID, err)
+				}
+				if route == nil {
+					addf("mode '%s': auto_route '%s' does not match any route of this VA", modeID, mode.AutoRoute.RouteName)
+				}
+			}
+			if mode.AutoRoute.Multiplier <= 0 {
+				addf("mode '%s': auto_route 'multiplier' must be greater than 0", modeID)
 			}
 		}
 
-		// Validate validations object
-		validations, ok := modeConfig["validations"].(map[string]interface{})
-		if !ok {
-			return fmt.Errorf("mode '%s': 'validations' must be an object", modeID)
+		if minRank := strings.TrimSpace(mode.MinRank); minRank != "" && s.rankRepo != nil {
+			if !ranksLoaded {
+				ranks, err := s.rankRepo.ListByVA(ctx, vaID)
+				if err != nil {
+					return fmt.Errorf("failed to look up ranks for mode '%s': %w", modeID, err)
+				}
+				for _, rank := range ranks {
+					rankNames = append(rankNames, rank.Name)
+				}
+				ranksLoaded = true
+			}
+			if !containsFold(rankNames, minRank) {
+				addf("mode '%s': min_rank '%s' does not match any rank of this VA", modeID, mode.MinRank)
+			}
 		}
 
-		if _, hasAllowAny := validations["allow_any_current_route"]; !hasAllowAny {
-			return fmt.Errorf("mode '%s': validations must have 'allow_any_current_route'", modeID)
+		v := mode.Validations
+		if _, ok := validValidationModes[v.ValidationMode]; !ok {
+			addf("mode '%s': unknown validation_mode '%s' (any, exact_match, origin, destination, hub)", modeID, v.ValidationMode)
 		}
-
-		if _, hasValidationMode := validations["validation_mode"]; !hasValidationMode {
-			return fmt.Errorf("mode '%s': validations must have 'validation_mode' (any, exact_match)", modeID)
+		for _, server := range v.AllowedServers {
+			if _, ok := validServers[strings.ToLower(server)]; !ok {
+				addf("mode '%s': unknown server '%s' (casual, training, expert)", modeID, server)
+			}
 		}
-
-		// Validate metadata exists (can be empty but must exist)
-		if _, hasMetadata := modeConfig["metadata"]; !hasMetadata {
-			return fmt.Errorf("mode '%s' must have 'metadata' object", modeID)
+		if v.MinAltitudeFt < 0 {
+			addf("mode '%s': min_altitude_ft must not be negative", modeID)
 		}
-
-		// auto_route is optional, but if present must be valid
-		if autoRoute, hasAutoRoute := modeConfig["auto_route"]; hasAutoRoute && autoRoute != nil {
-			if autoRouteObj, ok := autoRoute.(map[string]interface{}); ok {
-				if _, hasRouteName := autoRouteObj["route_name"]; !hasRouteName {
-					return fmt.Errorf("mode '%s': auto_route must have 'route_name'", modeID)
+		if v.MinDistanceNm < 0 {
+			addf("mode '%s': min_distance_nm must not be negative", modeID)
+		}
+		for _, band := range v.DistanceBands {
+			if !containsFold(common.RouteHauls, band) {
+				addf("mode '%s': unknown distance band '%s' (short_haul, medium_haul, long_haul)", modeID, band)
+			}
+		}
+		for idx, w := range v.TimeWindows {
+			for _, day := range w.Days {
+				if _, ok := validWeekdays[strings.ToLower(day)]; !ok {
+					addf("mode '%s': time_windows[%d] has unknown day '%s'", modeID, idx, day)
 				}
-
-				if _, hasMultiplier := autoRouteObj["multiplier"]; !hasMultiplier {
-					return fmt.Errorf("mode '%s': auto_route must have 'multiplier'", modeID)
+			}
+			if (w.StartTime == "") != (w.EndTime == "") {
+				addf("mode '%s': time_windows[%d] needs both 'start_time' and 'end_time'", modeID, idx)
+			}
+			for _, t := range []string{w.StartTime, w.EndTime} {
+				if _, err := time.Parse("15:04", t); t != "" && err != nil {
+					addf("mode '%s': time_windows[%d] time '%s' must be HH:MM", modeID, idx, t)
 				}
 			}
+			if w.From != nil && w.Until != nil && !w.Until.After(*w.From) {
+				addf("mode '%s': time_windows[%d] 'until' must be after 'from'", modeID, idx)
+			}
 		}
 	}
 
-	// If validation passes, save to database
-	// Convert map[string]interface{} to gormModels.JSONB
-	jsonbConfig := gormModels.JSONB(configPayload)
+	if len(problems) > 0 {
+		return &FlightModesConfigError{Problems: problems}
+	}
+	return nil
+}
 
-	if err := s.vaGormRepo.UpdateFlightModesConfig(ctx, vaID, jsonbConfig); err != nil {
-		return fmt.Errorf("failed to save flight modes configuration: %w", err)
+// ValidateAndSaveConfig validates a raw flight modes configuration and saves it as a new version
+func (s *FlightModesConfigService) ValidateAndSaveConfig(
+	ctx context.Context,
+	vaID string,
+	authorID string,
+	raw []byte,
+) (*gormModels.FlightModesConfigVersion, error) {
+	config, err := ParseFlightModesConfig(raw)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Validate(ctx, vaID, config); err != nil {
+		return nil, err
 	}
 
-	return nil
+	if config.SchemaVersion == 0 {
+		config.SchemaVersion = FlightModesSchemaVersion
+	}
+	return s.saveVersion(ctx, vaID, authorID, config, nil)
+}
+
+// ListVersions returns the saved versions of a VA's configuration, newest first
+func (s *FlightModesConfigService) ListVersions(ctx context.Context, vaID string) ([]gormModels.FlightModesConfigVersion, error) {
+	return s.versionRepo.List(ctx, vaID)
+}
+
+// GetVersion returns a single saved version of a VA's configuration
+func (s *FlightModesConfigService) GetVersion(ctx context.Context, vaID string, version int) (*gormModels.FlightModesConfigVersion, error) {
+	v, err := s.versionRepo.FindByVersion(ctx, vaID, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch config version: %w", err)
+	}
+	if v == nil {
+		return nil, ErrConfigVersionNotFound
+	}
+	return v, nil
+}
+
+// Diff compares two saved versions. A toVersion of 0 means the latest version.
+func (s *FlightModesConfigService) Diff(ctx context.Context, vaID string, fromVersion, toVersion int) (*dtos.FlightModesConfigDiffResponse, error) {
+	from, err := s.GetVersion(ctx, vaID, fromVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	var to *gormModels.FlightModesConfigVersion
+	if toVersion == 0 {
+		to, err = s.versionRepo.FindLatest(ctx, vaID)
+		if err == nil && to == nil {
+			err = ErrConfigVersionNotFound
+		}
+	} else {
+		to, err = s.GetVersion(ctx, vaID, toVersion)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.FlightModesConfigDiffResponse{
+		FromVersion: from.Version,
+		ToVersion:   to.Version,
+		Changes:     diffConfigs(from.Config, to.Config),
+	}, nil
+}
+
+// Rollback re-applies a prior version by saving its configuration as a new version
+func (s *FlightModesConfigService) Rollback(ctx context.Context, vaID string, authorID string, version int) (*gormModels.FlightModesConfigVersion, error) {
+	target, err := s.GetVersion(ctx, vaID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := LoadFlightModesConfig(&gormModels.VA{FlightModesConfig: target.Config})
+	if err != nil {
+		return nil, err
+	}
+
+	note := fmt.Sprintf("Rollback to version %d", version)
+	return s.saveVersion(ctx, vaID, authorID, config, &note)
+}
+
+// saveVersion stores config as the VA's newest version and applies it
+func (s *FlightModesConfigService) saveVersion(
+	ctx context.Context,
+	vaID string,
+	authorID string,
+	config *dtos.FlightModesConfig,
+	note *string,
+) (*gormModels.FlightModesConfigVersion, error) {
+	raw, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode flight modes configuration: %w", err)
+	}
+	var jsonbConfig gormModels.JSONB
+	if err := json.Unmarshal(raw, &jsonbConfig); err != nil {
+		return nil, fmt.Errorf("failed to encode flight modes configuration: %w", err)
+	}
+
+	version := &gormModels.FlightModesConfigVersion{
+		VAID:   vaID,
+		Config: jsonbConfig,
+		Note:   note,
+	}
+	if authorID != "" {
+		version.AuthorID = &authorID
+	}
+
+	if err := s.versionRepo.CreateAndApply(ctx, version); err != nil {
+		return nil, fmt.Errorf("failed to save flight modes configuration: %w", err)
+	}
+	return version, nil
 }
 
 // GetConfig retrieves the flight modes configuration for a VA
@@ -145,3 +365,111 @@ func (s *FlightModesConfigService) GetConfig(ctx context.Context, vaID string) (
 
 	return va.FlightModesConfig, nil
 }
+
+// describeDecodeError turns a JSON decoding error into a message that names the offending key
+func describeDecodeError(err error) string {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return fmt.Sprintf("'%s' must be of type %s, got %s", typeErr.Field, typeErr.Type.String(), typeErr.Value)
+	}
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return fmt.Sprintf("malformed JSON at offset %d: %v", syntaxErr.Offset, err)
+	}
+	// DisallowUnknownFields reports `json: unknown field "x"`
+	return strings.TrimPrefix(err.Error(), "json: ")
+}
+
+// duplicateModeIDs scans the raw flight_modes object for repeated keys, which
+// encoding/json would otherwise silently collapse. Keys are compared case-insensitively.
+func duplicateModeIDs(raw []byte) []string {
+	var top map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &top); err != nil {
+		return nil
+	}
+	modes, ok := top["flight_modes"]
+	if !ok {
+		return nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(modes))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil
+	}
+
+	seen := make(map[string]struct{})
+	var dups []string
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return dups
+		}
+		key, _ := tok.(string)
+		norm := strings.ToLower(strings.TrimSpace(key))
+		if _, exists := seen[norm]; exists {
+			dups = append(dups, key)
+		}
+		seen[norm] = struct{}{}
+
+		var skip json.RawMessage
+		if err := dec.Decode(&skip); err != nil {
+			return dups
+		}
+	}
+	return dups
+}
+
+// diffConfigs compares two JSON documents leaf by leaf, sorted by path
+func diffConfigs(from, to map[string]interface{}) []dtos.FlightModesConfigDiffEntry {
+	oldLeaves := make(map[string]interface{})
+	newLeaves := make(map[string]interface{})
+	flattenJSON("", map[string]interface{}(from), oldLeaves)
+	flattenJSON("", map[string]interface{}(to), newLeaves)
+
+	changes := []dtos.FlightModesConfigDiffEntry{}
+	for path, oldVal := range oldLeaves {
+		newVal, ok := newLeaves[path]
+		switch {
+		case !ok:
+			changes = append(changes, dtos.FlightModesConfigDiffEntry{Path: path, Change: "removed", Old: oldVal})
+		case !reflect.DeepEqual(oldVal, newVal):
+			changes = append(changes, dtos.FlightModesConfigDiffEntry{Path: path, Change: "changed", Old: oldVal, New: newVal})
+		}
+	}
+	for path, newVal := range newLeaves {
+		if _, ok := oldLeaves[path]; !ok {
+			changes = append(changes, dtos.FlightModesConfigDiffEntry{Path: path, Change: "added", New: newVal})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+// flattenJSON collects the leaf values of a decoded JSON document keyed by dot/index path
+func flattenJSON(prefix string, value interface{}, out map[string]interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 && prefix != "" {
+			out[prefix] = v
+			return
+		}
+		for key, child := range v {
+			path := key
+			if prefix != "" {
+				path = prefix + "." + key
+			}
+			flattenJSON(path, child, out)
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			out[prefix] = v
+			return
+		}
+		for i, child := range v {
+			flattenJSON(fmt.Sprintf("%s[%d]", prefix, i), child, out)
+		}
+	default:
+		out[prefix] = v
+	}
+}
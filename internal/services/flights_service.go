@@ -598,3 +598,100 @@ func (svc *FlightsService) FindUserCurrentFlight(
 
 	return nil, fmt.Errorf("current flight not found for callsign: %s", userCallsign)
 }
+
+// CompletedFlight is a pilot's flight as recorded in their Infinite Flight flight history
+type CompletedFlight struct {
+	FlightID         string
+	SessionID        string
+	Callsign         string
+	Origin           string
+	Destination      string
+	Aircraft         string
+	Livery           string
+	LiveryID         string
+	BlockTimeSeconds int
+	MaxAltitudeFt    int // Highest position report in the track, 0 when the track is unavailable
+	Landings         int
+	Violations       int
+	Created          time.Time
+}
+
+// FindCompletedFlight looks up a pilot's flight in their Live API flight history.
+// If flightID is set, that flight is returned; otherwise the most recent flight started within lookback.
+// Block time is taken from the flight's track when available, falling back to the recorded total time.
+func (svc *FlightsService) FindCompletedFlight(
+	ctx context.Context,
+	ifUserID string,
+	flightID string,
+	lookback time.Duration,
+) (*CompletedFlight, error) {
+	// Skip the cache: the flight the pilot just finished is usually not in it yet
+	flts, _, err := svc.ApiService.GetUserFlights(ifUserID, 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch flight history: %w", err)
+	}
+	if flts == nil || len(flts.Flights) == 0 {
+		return nil, fmt.Errorf("no flights in history")
+	}
+
+	var rec *dtos.UserFlightEntry
+	for i := range flts.Flights {
+		f := &flts.Flights[i]
+		if flightID != "" && f.ID == flightID {
+			rec = f
+			break
+		}
+		if flightID == "" && time.Since(f.Created) <= lookback {
+			rec = f
+			break
+		}
+	}
+	if rec == nil {
+		return nil, fmt.Errorf("no matching flight in history")
+	}
+
+	flight := &CompletedFlight{
+		FlightID:         rec.ID,
+		Callsign:         rec.Callsign,
+		Origin:           rec.OriginAirport,
+		Destination:      rec.DestinationAirport,
+		LiveryID:         rec.LiveryID,
+		BlockTimeSeconds: int(rec.TotalTime * 60), // TotalTime is in minutes
+		Landings:         rec.LandingCount,
+		Violations:       len(rec.Violations),
+		Created:          rec.Created,
+	}
+
+	if liveryData := svc.LiverySvc.GetAircraftLivery(ctx, rec.LiveryID); liveryData != nil {
+		flight.Aircraft = liveryData.AircraftName
+		flight.Livery = liveryData.LiveryName
+	}
+
+	// Map server name to session ID for the route API call
+	if sessions, err := svc.GetLiveServers(); err == nil && sessions != nil {
+		for _, session := range *sessions {
+			if session.Name == rec.Server {
+				flight.SessionID = session.ID
+				break
+			}
+		}
+	}
+
+	// Track spans first to last position report, which is closer to block time than airborne time
+	if flight.SessionID != "" {
+		track, _, err := svc.ApiService.GetFlightRoute(rec.ID, flight.SessionID)
+		if err != nil {
+			log.Printf("[FindCompletedFlight] Could not fetch track for flight %s: %v", rec.ID, err)
+		} else if track != nil && len(track.Result) > 1 {
+			first, last := track.Result[0].Date, track.Result[len(track.Result)-1].Date
+			if d := last.Sub(first); d > 0 {
+				flight.BlockTimeSeconds = int(d.Seconds())
+			}
+			for _, position := range track.Result {
+				flight.MaxAltitudeFt = max(flight.MaxAltitudeFt, int(position.Altitude))
+			}
+		}
+	}
+
+	return flight, nil
+}
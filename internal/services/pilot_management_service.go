@@ -3,22 +3,27 @@ package services
 import (
 	"context"
 	"fmt"
+	"log"
 	"regexp"
 	"strings"
 
 	"infinite-experiment/politburo/internal/constants"
 	"infinite-experiment/politburo/internal/db/repositories"
+	models "infinite-experiment/politburo/internal/models/gorm"
 )
 
 // PilotManagementService handles pilot management operations
 type PilotManagementService struct {
 	vaRoleRepo *repositories.VAUserRoleRepository
+	pilotSync  *PilotSyncService
 }
 
-// NewPilotManagementService creates a new pilot management service
-func NewPilotManagementService(vaRoleRepo *repositories.VAUserRoleRepository) *PilotManagementService {
+// NewPilotManagementService creates a new pilot management service.
+// Changes are queued for the VA's data provider through pilotSync when it is non-nil.
+func NewPilotManagementService(vaRoleRepo *repositories.VAUserRoleRepository, pilotSync *PilotSyncService) *PilotManagementService {
 	return &PilotManagementService{
 		vaRoleRepo: vaRoleRepo,
+		pilotSync:  pilotSync,
 	}
 }
 
@@ -99,11 +104,13 @@ func (s *PilotManagementService) UpdatePilotRole(
 	}
 
 	// Update the role
+	oldRole := string(pilot.Role)
 	pilot.Role = constants.VARole(newRole)
 	if err := s.vaRoleRepo.Update(ctx, pilot); err != nil {
 		return fmt.Errorf("failed to update pilot role: %w", err)
 	}
 
+	s.recordSyncChange(ctx, pilot, constants.PilotSyncFieldRole, oldRole, newRole)
 	return nil
 }
 
@@ -152,11 +159,13 @@ func (s *PilotManagementService) UpdatePilotCallsign(
 	}
 
 	// Update the callsign
+	oldCallsign := pilot.Callsign
 	pilot.Callsign = newCallsign
 	if err := s.vaRoleRepo.Update(ctx, pilot); err != nil {
 		return fmt.Errorf("failed to update pilot callsign: %w", err)
 	}
 
+	s.recordSyncChange(ctx, pilot, constants.PilotSyncFieldCallsign, oldCallsign, newCallsign)
 	return nil
 }
 
@@ -188,9 +197,21 @@ func (s *PilotManagementService) RemovePilot(
 		return fmt.Errorf("failed to remove pilot: %w", err)
 	}
 
+	s.recordSyncChange(ctx, pilot, constants.PilotSyncFieldActive, fmt.Sprint(pilot.IsActive), "false")
 	return nil
 }
 
+// recordSyncChange queues a pilot change for the data provider. The local change has already
+// been saved, so a failure to queue it is logged rather than returned.
+func (s *PilotManagementService) recordSyncChange(ctx context.Context, pilot *models.UserVARole, field, oldValue, newValue string) {
+	if s.pilotSync == nil {
+		return
+	}
+	if err := s.pilotSync.RecordChange(ctx, pilot, field, oldValue, newValue); err != nil {
+		log.Printf("[PilotManagementService] Failed to queue %s change of pilot %s for sync: %v", field, pilot.ID, err)
+	}
+}
+
 // SearchResult represents a pilot search result
 type SearchResult struct {
 	Username string
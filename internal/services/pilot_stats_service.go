@@ -19,16 +19,16 @@ import (
 )
 
 type PilotStatsService struct {
-	db               *sqlx.DB
-	gormDB           *gorm.DB
-	cache            *common.CacheService
-	configRepo       *repositories.DataProviderConfigRepo
-	userRepo         *repositories.UserRepository
-	vaConfigService  *common.VAConfigService
-	pirepRepo        *repositories.PirepATSyncedRepo
-	routeRepo        *repositories.RouteATSyncedRepo
-	airtableProvider *providers.AirtableProvider
-	liveAPIProvider  *providers.LiveAPIProvider
+	db              *sqlx.DB
+	gormDB          *gorm.DB
+	cache           *common.CacheService
+	configRepo      *repositories.DataProviderConfigRepo
+	userRepo        *repositories.UserRepository
+	vaConfigService *common.VAConfigService
+	pirepRepo       *repositories.PirepATSyncedRepo
+	routeRepo       *repositories.RouteATSyncedRepo
+	liveAPIProvider *providers.LiveAPIProvider
+	dataProviders   *providers.Registry
 }
 
 func NewPilotStatsService(
@@ -40,18 +40,19 @@ func NewPilotStatsService(
 	vaConfigService *common.VAConfigService,
 	pirepRepo *repositories.PirepATSyncedRepo,
 	routeRepo *repositories.RouteATSyncedRepo,
+	dataProviders *providers.Registry,
 ) *PilotStatsService {
 	return &PilotStatsService{
-		db:               db,
-		gormDB:           gormDB,
-		cache:            cache,
-		configRepo:       configRepo,
-		userRepo:         userRepo,
-		vaConfigService:  vaConfigService,
-		pirepRepo:        pirepRepo,
-		routeRepo:        routeRepo,
-		airtableProvider: providers.NewAirtableProvider(cache),
-		liveAPIProvider:  providers.NewLiveAPIProvider(),
+		db:              db,
+		gormDB:          gormDB,
+		cache:           cache,
+		configRepo:      configRepo,
+		userRepo:        userRepo,
+		vaConfigService: vaConfigService,
+		pirepRepo:       pirepRepo,
+		routeRepo:       routeRepo,
+		liveAPIProvider: providers.NewLiveAPIProvider(),
+		dataProviders:   dataProviders,
 	}
 }
 
@@ -82,12 +83,13 @@ func (s *PilotStatsService) getUserMembership(ctx context.Context, userDiscordID
 	return &membership, nil
 }
 
-// getActiveAirtableConfig fetches and parses the active Airtable config for a VA
-func (s *PilotStatsService) getActiveAirtableConfig(ctx context.Context, vaID string) (*models.DataProviderConfig, *dtos.ProviderConfigData, error) {
+// getActiveProviderConfig fetches and parses the VA's active data provider config
+// and returns the provider client for it
+func (s *PilotStatsService) getActiveProviderConfig(ctx context.Context, vaID string) (*models.DataProviderConfig, *dtos.ProviderConfigData, providers.ProviderClient, error) {
 	// Get config entity from database
-	config, err := s.configRepo.GetActiveConfig(ctx, vaID, "airtable")
+	config, err := s.configRepo.GetActiveConfigForVA(ctx, vaID)
 	if err != nil {
-		return nil, nil, &PilotStatsError{
+		return nil, nil, nil, &PilotStatsError{
 			Code:    constants.ErrCodeConfigNotFound,
 			Message: constants.GetErrorMessage(constants.ErrCodeConfigNotFound),
 			Err:     err,
@@ -95,7 +97,7 @@ func (s *PilotStatsService) getActiveAirtableConfig(ctx context.Context, vaID st
 	}
 
 	if config == nil {
-		return nil, nil, &PilotStatsError{
+		return nil, nil, nil, &PilotStatsError{
 			Code:    constants.ErrCodeVAAirtableNotEnabled,
 			Message: constants.GetErrorMessage(constants.ErrCodeVAAirtableNotEnabled),
 		}
@@ -104,14 +106,23 @@ func (s *PilotStatsService) getActiveAirtableConfig(ctx context.Context, vaID st
 	// Parse JSONB config_data
 	configData, err := repositories.ParseConfigData(config.ConfigData)
 	if err != nil {
-		return nil, nil, &PilotStatsError{
+		return nil, nil, nil, &PilotStatsError{
 			Code:    constants.ErrCodeConfigMalformed,
 			Message: constants.GetErrorMessage(constants.ErrCodeConfigMalformed),
 			Err:     err,
 		}
 	}
 
-	return config, configData, nil
+	provider, err := s.dataProviders.Client(config.ProviderType, configData)
+	if err != nil {
+		return nil, nil, nil, &PilotStatsError{
+			Code:    constants.ErrCodeConfigMalformed,
+			Message: constants.GetErrorMessage(constants.ErrCodeConfigMalformed),
+			Err:     err,
+		}
+	}
+
+	return config, configData, provider, nil
 }
 
 // MembershipWithAirtable extends membership data with Airtable ID
@@ -156,8 +167,8 @@ func (s *PilotStatsService) GetPilotStatusByCallsign(ctx context.Context, userDi
 	fullCallsign := callsignPrefix + membership.Callsign
 	fmt.Printf("Searching for pilot with callsign: %s (prefix: %s, base: %s)\n", fullCallsign, callsignPrefix, membership.Callsign)
 
-	// Step 5: Get active Airtable config for VA
-	config, configData, err := s.getActiveAirtableConfig(ctx, vaID)
+	// Step 5: Get active provider config for VA
+	config, configData, provider, err := s.getActiveProviderConfig(ctx, vaID)
 	if err != nil {
 		return nil, err
 	}
@@ -187,19 +198,8 @@ func (s *PilotStatsService) GetPilotStatusByCallsign(ctx context.Context, userDi
 		}
 	}
 
-	// Step 8: Build Airtable filter formula
-	// Airtable formula: {Callsign} = 'TEST012'
-	filterFormula := fmt.Sprintf("{%s} = '%s'", callsignFieldName, fullCallsign)
-	fmt.Printf("Airtable filter formula: %s\n", filterFormula)
-
-	// Step 9: Fetch from Airtable using filter
-	ctx = context.WithValue(ctx, "provider_config", configData)
-	filters := &providers.SyncFilters{
-		FilterFormula: filterFormula,
-		Limit:         1, // We only expect one record
-	}
-
-	recordSet, err := s.airtableProvider.FetchRecords(ctx, pilotSchema, filters)
+	// Step 8-9: Fetch the record whose callsign field matches, e.g. {Callsign} = 'TEST012'
+	record, err := providers.FindRecord(ctx, provider, pilotSchema, callsignFieldName, fullCallsign)
 	if err != nil {
 		if provErr, ok := err.(*providers.ProviderError); ok {
 			return nil, &PilotStatsError{
@@ -216,7 +216,7 @@ func (s *PilotStatsService) GetPilotStatusByCallsign(ctx context.Context, userDi
 	}
 
 	// Step 10: Check if we found a record
-	if len(recordSet.Records) == 0 {
+	if record == nil {
 		return nil, &PilotStatsError{
 			Code:    constants.ErrCodePilotNotFoundInAirtable,
 			Message: fmt.Sprintf("No pilot found with callsign: %s", fullCallsign),
@@ -224,8 +224,7 @@ func (s *PilotStatsService) GetPilotStatusByCallsign(ctx context.Context, userDi
 	}
 
 	// Step 11: Log the raw response data
-	record := recordSet.Records[0]
-	fmt.Printf("\n=== PILOT STATUS RESPONSE FROM AIRTABLE ===\n")
+	fmt.Printf("\n=== PILOT STATUS RESPONSE FROM %s ===\n", provider.GetProviderType())
 	fmt.Printf("Record ID: %s\n", record.ID)
 
 	// Pretty print the fields as JSON
@@ -301,7 +300,7 @@ func (s *PilotStatsService) fetchIFGameStats(ctx context.Context, ifcID string)
 	// Get the first result (should be only one)
 	userStats := userStatsResp.Result[0]
 
-	log.Printf("[fetchIFGameStats] Successfully fetched game stats for user %s", userStats.DiscourseUsername)
+	log.Printf("[fetchIFGameStats] Successfully fetched game stats for user %s", ifcID)
 
 	// Transform to IFGameStats DTO
 	// Note: FlightTime from Live API is in minutes, convert to seconds for consistency
@@ -388,7 +387,7 @@ func (s *PilotStatsService) GetPilotStats(ctx context.Context, userDiscordID, va
 // Returns: (providerData, rawFields, cached, error)
 func (s *PilotStatsService) fetchProviderData(ctx context.Context, userDiscordID, vaID string) (*responses.ProviderPilotData, map[string]interface{}, bool, error) {
 	// Get active config
-	_, configData, err := s.getActiveAirtableConfig(ctx, vaID)
+	_, configData, provider, err := s.getActiveProviderConfig(ctx, vaID)
 	if err != nil {
 		return nil, nil, false, err
 	}
@@ -427,10 +426,9 @@ func (s *PilotStatsService) fetchProviderData(ctx context.Context, userDiscordID
 		}
 	}
 
-	log.Printf("[fetchProviderData] Fetching from Airtable for pilot %s in VA %s", airtablePilotID, vaID)
+	log.Printf("[fetchProviderData] Fetching from %s for pilot %s in VA %s", provider.GetProviderType(), airtablePilotID, vaID)
 	// Fetch from provider
-	ctx = context.WithValue(ctx, "provider_config", configData)
-	pilotRecord, err := s.airtableProvider.FetchPilotRecord(ctx, airtablePilotID, pilotSchema)
+	pilotRecord, err := provider.FetchPilotRecord(ctx, airtablePilotID, pilotSchema)
 	if err != nil {
 		// Check if it's a provider error
 		if provErr, ok := err.(*providers.ProviderError); ok {
@@ -563,7 +561,7 @@ func (s *PilotStatsService) transformToStandardizedFields(
 // fetchCareerModeData fetches career mode data using callsign matching
 func (s *PilotStatsService) fetchCareerModeData(ctx context.Context, userDiscordID, vaID string) (*responses.CareerModeData, bool, error) {
 	// Get active config
-	_, configData, err := s.getActiveAirtableConfig(ctx, vaID)
+	_, configData, provider, err := s.getActiveProviderConfig(ctx, vaID)
 	if err != nil {
 		return nil, false, err
 	}
@@ -606,18 +604,9 @@ func (s *PilotStatsService) fetchCareerModeData(ctx context.Context, userDiscord
 		}
 	}
 
-	// Build Airtable filter formula
-	filterFormula := fmt.Sprintf("{%s} = '%s'", callsignFieldName, fullCallsign)
-	log.Printf("[fetchCareerModeData] Filter formula: %s", filterFormula)
-
-	// Fetch from Airtable using filter
-	ctx = context.WithValue(ctx, "provider_config", configData)
-	filters := &providers.SyncFilters{
-		FilterFormula: filterFormula,
-		Limit:         1,
-	}
-
-	recordSet, err := s.airtableProvider.FetchRecords(ctx, careerModeSchema, filters)
+	// Fetch the record whose callsign field matches
+	log.Printf("[fetchCareerModeData] Matching %s = %s", callsignFieldName, fullCallsign)
+	record, err := providers.FindRecord(ctx, provider, careerModeSchema, callsignFieldName, fullCallsign)
 	if err != nil {
 		if provErr, ok := err.(*providers.ProviderError); ok {
 			return nil, false, &PilotStatsError{
@@ -634,17 +623,15 @@ func (s *PilotStatsService) fetchCareerModeData(ctx context.Context, userDiscord
 	}
 
 	// Check if we found a record
-	if len(recordSet.Records) == 0 {
+	if record == nil {
 		return nil, false, &PilotStatsError{
 			Code:    constants.ErrCodePilotNotFoundInAirtable,
 			Message: fmt.Sprintf("No career mode record found for callsign: %s", fullCallsign),
 		}
 	}
 
-	record := recordSet.Records[0]
-
-	// Log raw fields from Airtable
-	log.Printf("[fetchCareerModeData] Raw fields from Airtable:")
+	// Log raw fields from the provider
+	log.Printf("[fetchCareerModeData] Raw fields from %s:", provider.GetProviderType())
 	rawJSON, _ := json.MarshalIndent(record.Fields, "", "  ")
 	log.Printf("%s", string(rawJSON))
 
@@ -0,0 +1,325 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"infinite-experiment/politburo/internal/constants"
+	"infinite-experiment/politburo/internal/datasync"
+	"infinite-experiment/politburo/internal/db/repositories"
+	gormModels "infinite-experiment/politburo/internal/models/gorm"
+	"infinite-experiment/politburo/internal/providers"
+)
+
+// pilotChangeMaxAttempts is the number of push attempts before a change is marked failed
+const pilotChangeMaxAttempts = 5
+
+// Conflict resolutions accepted by ResolveConflict
+const (
+	PilotConflictKeepLocal  = "local"
+	PilotConflictKeepRemote = "remote"
+)
+
+var (
+	// ErrPilotChangeNotFound is returned when a conflicting change does not exist in the VA
+	ErrPilotChangeNotFound = errors.New("pilot change not found")
+	// ErrInvalidConflictResolution is returned for a resolution other than local or remote
+	ErrInvalidConflictResolution = errors.New("resolution must be local or remote")
+)
+
+// PilotSyncService pushes pilot changes made in the dashboard to the VA's data provider.
+//
+// Each change remembers the provider's value it was made against (the base, taken from the
+// last inbound sync). A change is only pushed while the provider still holds the base value;
+// if both sides changed the field it is held as a conflict for an admin to resolve.
+type PilotSyncService struct {
+	changeRepo                *repositories.PilotSyncChangeRepo
+	pilotATSyncedRepo         *repositories.PilotATSyncedRepo
+	vaRoleRepo                *repositories.VAUserRoleRepository
+	registry                  *providers.Registry
+	dataProviderConfigService *DataProviderConfigService
+}
+
+// NewPilotSyncService creates a new PilotSyncService
+func NewPilotSyncService(
+	changeRepo *repositories.PilotSyncChangeRepo,
+	pilotATSyncedRepo *repositories.PilotATSyncedRepo,
+	vaRoleRepo *repositories.VAUserRoleRepository,
+	registry *providers.Registry,
+	dataProviderConfigService *DataProviderConfigService,
+) *PilotSyncService {
+	return &PilotSyncService{
+		changeRepo:                changeRepo,
+		pilotATSyncedRepo:         pilotATSyncedRepo,
+		vaRoleRepo:                vaRoleRepo,
+		registry:                  registry,
+		dataProviderConfigService: dataProviderConfigService,
+	}
+}
+
+// RecordChange queues a dashboard change of a two-way pilot field for the provider.
+// Pilots not linked to a provider record are skipped. A further change of a field that has
+// not been pushed yet replaces the queued value.
+func (s *PilotSyncService) RecordChange(ctx context.Context, pilot *gormModels.UserVARole, field string, oldValue, newValue string) error {
+	if pilot.AirtablePilotID == nil || *pilot.AirtablePilotID == "" {
+		return nil
+	}
+
+	oldValue = datasync.NormalizePilotSyncValue(field, oldValue)
+	newValue = datasync.NormalizePilotSyncValue(field, newValue)
+
+	open, err := s.changeRepo.FindOpen(ctx, pilot.ID, field)
+	if err != nil {
+		return fmt.Errorf("failed to fetch open change: %w", err)
+	}
+	if open != nil {
+		return s.changeRepo.UpdateLocalValue(ctx, open.ID, newValue)
+	}
+
+	if oldValue == newValue {
+		return nil
+	}
+
+	// Compare against what the provider held at the last sync; before the first sync the
+	// local value is the best guess
+	base := oldValue
+	synced, err := s.pilotATSyncedRepo.FindByATID(ctx, pilot.VAID, *pilot.AirtablePilotID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch synced pilot record: %w", err)
+	}
+	if synced != nil {
+		if value, ok := synced.SyncedFields[field].(string); ok {
+			base = value
+		}
+	}
+
+	return s.changeRepo.Create(ctx, &gormModels.PilotSyncChange{
+		VAID:             pilot.VAID,
+		VAUserRoleID:     pilot.ID,
+		ProviderRecordID: *pilot.AirtablePilotID,
+		Field:            field,
+		BaseValue:        &base,
+		LocalValue:       &newValue,
+		Status:           constants.PilotChangeStatusPending,
+	})
+}
+
+// Push writes a pending change to the provider, or marks it as a conflict when the
+// provider's value has changed since the change was made
+func (s *PilotSyncService) Push(ctx context.Context, change *gormModels.PilotSyncChange) error {
+	active, err := s.dataProviderConfigService.GetActiveProviderConfigCached(ctx, change.VAID)
+	if err != nil || active == nil {
+		return s.recordFailure(ctx, change, fmt.Errorf("no active data provider configuration"), false)
+	}
+
+	schema := active.ConfigData.GetSchemaByType("pilot")
+	if schema == nil {
+		return s.recordFailure(ctx, change, fmt.Errorf("pilot schema not configured in provider settings"), true)
+	}
+	mapping := schema.GetFieldMapping(change.Field)
+	if mapping == nil {
+		return s.recordFailure(ctx, change, fmt.Errorf("%s is not mapped in the pilot schema", change.Field), true)
+	}
+
+	provider, err := s.registry.Client(active.ProviderType, active.ConfigData)
+	if err != nil {
+		return s.recordFailure(ctx, change, err, true)
+	}
+	updater, ok := provider.(providers.RecordUpdater)
+	if !ok {
+		return s.recordFailure(ctx, change, fmt.Errorf("%s provider does not support record updates", active.ProviderType), true)
+	}
+
+	record, err := provider.FetchPilotRecord(ctx, change.ProviderRecordID, schema)
+	if err != nil {
+		return s.recordFailure(ctx, change, fmt.Errorf("failed to fetch provider record: %w", err), false)
+	}
+
+	remote := datasync.NormalizePilotSyncValue(change.Field, record.RawFields[mapping.AirtableName])
+	if datasync.PilotChangeConflicts(change, remote) {
+		if err := s.changeRepo.MarkConflict(ctx, change.ID, remote); err != nil {
+			return fmt.Errorf("failed to mark change as conflicting: %w", err)
+		}
+		log.Printf("[PilotSyncService] Change %s: %s of record %s changed on both sides", change.ID, change.Field, change.ProviderRecordID)
+		return nil
+	}
+
+	local := ""
+	if change.LocalValue != nil {
+		local = *change.LocalValue
+	}
+
+	if remote != local {
+		fields := map[string]interface{}{
+			mapping.AirtableName: pilotSyncPayloadValue(change.Field, local),
+		}
+		if err := updater.UpdateRecord(ctx, schema, change.ProviderRecordID, fields); err != nil {
+			return s.recordFailure(ctx, change, err, false)
+		}
+	}
+
+	if err := s.changeRepo.MarkPushed(ctx, change.ID); err != nil {
+		log.Printf("[PilotSyncService] Failed to mark change %s pushed: %v", change.ID, err)
+	}
+	// The provider now holds our value; the next inbound sync must not see it as a remote change
+	if err := s.pilotATSyncedRepo.SetSyncedField(ctx, change.VAID, change.ProviderRecordID, change.Field, local); err != nil {
+		log.Printf("[PilotSyncService] Failed to update synced %s of record %s: %v", change.Field, change.ProviderRecordID, err)
+	}
+
+	log.Printf("[PilotSyncService] Change %s pushed (%s of record %s)", change.ID, change.Field, change.ProviderRecordID)
+	return nil
+}
+
+// PushPending pushes up to batchSize pending changes.
+// Returns the number of changes processed.
+func (s *PilotSyncService) PushPending(ctx context.Context, batchSize int) (int, error) {
+	changes, err := s.changeRepo.ListPending(ctx, batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list pending changes: %w", err)
+	}
+
+	for i := range changes {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := s.Push(ctx, &changes[i]); err != nil {
+			log.Printf("[PilotSyncService] Change %s attempt %d failed: %v", changes[i].ID, changes[i].Attempts, err)
+		}
+	}
+
+	return len(changes), nil
+}
+
+// PilotSyncConflict is a conflicting change with when each side last changed the record
+type PilotSyncConflict struct {
+	Change           gormModels.PilotSyncChange
+	LocalModifiedAt  time.Time
+	RemoteModifiedAt *time.Time // Nil when the pilot schema has no last-modified field
+}
+
+// ListConflicts returns a VA's changes that were also changed in the provider
+func (s *PilotSyncService) ListConflicts(ctx context.Context, vaID string, limit int) ([]PilotSyncConflict, error) {
+	changes, err := s.changeRepo.ListByStatus(ctx, vaID, constants.PilotChangeStatusConflict, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conflicts: %w", err)
+	}
+
+	conflicts := make([]PilotSyncConflict, 0, len(changes))
+	for _, change := range changes {
+		conflict := PilotSyncConflict{Change: change, LocalModifiedAt: change.UpdatedAt}
+		synced, err := s.pilotATSyncedRepo.FindByATID(ctx, vaID, change.ProviderRecordID)
+		if err != nil {
+			log.Printf("[PilotSyncService] Failed to fetch synced record %s: %v", change.ProviderRecordID, err)
+		} else if synced != nil {
+			conflict.RemoteModifiedAt = synced.RemoteModifiedAt
+		}
+		conflicts = append(conflicts, conflict)
+	}
+
+	return conflicts, nil
+}
+
+// ResolveConflict settles a conflicting change. Keeping local pushes the dashboard value over
+// the provider's; keeping remote applies the provider's value to the pilot instead.
+func (s *PilotSyncService) ResolveConflict(ctx context.Context, vaID string, id string, keep string) error {
+	change, err := s.changeRepo.FindByID(ctx, vaID, id)
+	if err != nil {
+		return fmt.Errorf("failed to fetch change: %w", err)
+	}
+	if change == nil || change.Status != constants.PilotChangeStatusConflict {
+		return ErrPilotChangeNotFound
+	}
+
+	remote := ""
+	if change.RemoteValue != nil {
+		remote = *change.RemoteValue
+	}
+
+	switch keep {
+	case PilotConflictKeepLocal:
+		requeued, err := s.changeRepo.Requeue(ctx, vaID, id, remote)
+		if err != nil {
+			return fmt.Errorf("failed to requeue change: %w", err)
+		}
+		if !requeued {
+			return ErrPilotChangeNotFound
+		}
+		change.Status = constants.PilotChangeStatusPending
+		change.BaseValue = &remote
+		change.Attempts = 0
+		return s.Push(ctx, change)
+
+	case PilotConflictKeepRemote:
+		if err := s.applyRemoteValue(ctx, change, remote); err != nil {
+			return err
+		}
+		resolved, err := s.changeRepo.MarkResolved(ctx, vaID, id)
+		if err != nil {
+			return fmt.Errorf("failed to resolve change: %w", err)
+		}
+		if !resolved {
+			return ErrPilotChangeNotFound
+		}
+		return nil
+
+	default:
+		return ErrInvalidConflictResolution
+	}
+}
+
+// applyRemoteValue sets the provider's value of a changed field on the pilot
+func (s *PilotSyncService) applyRemoteValue(ctx context.Context, change *gormModels.PilotSyncChange, remote string) error {
+	pilot, err := s.vaRoleRepo.GetByID(ctx, change.VAUserRoleID)
+	if err != nil {
+		return fmt.Errorf("pilot not found: %w", err)
+	}
+
+	switch change.Field {
+	case constants.PilotSyncFieldCallsign:
+		pilot.Callsign = remote
+	case constants.PilotSyncFieldRole:
+		switch constants.VARole(remote) {
+		case constants.RolePilot, constants.RoleAirlineManager, constants.RoleAdmin:
+			pilot.Role = constants.VARole(remote)
+		default:
+			return fmt.Errorf("provider role %q is not a valid role", remote)
+		}
+	case constants.PilotSyncFieldActive:
+		pilot.IsActive = remote == "true"
+	default:
+		return fmt.Errorf("unknown pilot field: %s", change.Field)
+	}
+
+	if err := s.vaRoleRepo.Update(ctx, pilot); err != nil {
+		return fmt.Errorf("failed to update pilot: %w", err)
+	}
+	return nil
+}
+
+// recordFailure counts a failed push, marking the change failed once its attempts run out
+// (or straight away when retrying cannot help), and returns cause
+func (s *PilotSyncService) recordFailure(ctx context.Context, change *gormModels.PilotSyncChange, cause error, permanent bool) error {
+	attempts := change.Attempts + 1
+	failed := permanent || attempts >= pilotChangeMaxAttempts
+
+	if err := s.changeRepo.MarkFailed(ctx, change.ID, attempts, cause.Error(), failed); err != nil {
+		log.Printf("[PilotSyncService] Failed to record failure for change %s: %v", change.ID, err)
+	}
+	if failed {
+		log.Printf("[PilotSyncService] Change %s failed after %d attempts: %v", change.ID, attempts, cause)
+	}
+
+	change.Attempts = attempts
+	return cause
+}
+
+// pilotSyncPayloadValue converts a normalised field value to the type written to the provider
+func pilotSyncPayloadValue(field string, value string) interface{} {
+	if field == constants.PilotSyncFieldActive {
+		return value == "true"
+	}
+	return value
+}
@@ -0,0 +1,285 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"infinite-experiment/politburo/internal/constants"
+	"infinite-experiment/politburo/internal/db/repositories"
+	"infinite-experiment/politburo/internal/models/dtos"
+	gormModels "infinite-experiment/politburo/internal/models/gorm"
+	"infinite-experiment/politburo/internal/providers"
+)
+
+const (
+	// DefaultOutboxMaxAttempts is the number of delivery attempts before an entry is dead-lettered
+	DefaultOutboxMaxAttempts = 8
+
+	// outboxBaseBackoff is the delay after the first failed attempt; it doubles on every further failure
+	outboxBaseBackoff = 30 * time.Second
+	// outboxMaxBackoff caps the delay between attempts
+	outboxMaxBackoff = 6 * time.Hour
+	// outboxDeliveryLease hides an entry from other workers while a delivery is in flight. It is
+	// renewed every outboxLeaseRenewal for as long as the delivery runs, since the provider
+	// client's retries can take longer than the lease.
+	outboxDeliveryLease = 2 * time.Minute
+	outboxLeaseRenewal  = outboxDeliveryLease / 2
+
+	// idempotencyKeyField is the schema internal name under which the outbox idempotency key
+	// is written to the provider record, so retries can detect an earlier successful create
+	idempotencyKeyField = "idempotency_key"
+)
+
+// ErrOutboxEntryNotFound is returned when a dead letter does not exist in the VA
+var ErrOutboxEntryNotFound = errors.New("outbox entry not found")
+
+// errUnsafeRetry dead-letters an entry at once: an earlier attempt may have reached the provider
+// and created the record, and without an idempotency key mapped there is no way to check
+var errUnsafeRetry = errors.New("earlier attempt may have created the record and the PIREP schema has no " +
+	idempotencyKeyField + " field to check; verify in the provider before replaying")
+
+// PirepDeliveryService delivers native PIREPs to the VA's data provider through the pirep_outbox table
+type PirepDeliveryService struct {
+	outboxRepo                *repositories.PirepOutboxRepo
+	pirepRepo                 *repositories.PirepRepo
+	registry                  *providers.Registry
+	dataProviderConfigService *DataProviderConfigService
+}
+
+// NewPirepDeliveryService creates a new PirepDeliveryService
+func NewPirepDeliveryService(
+	outboxRepo *repositories.PirepOutboxRepo,
+	pirepRepo *repositories.PirepRepo,
+	registry *providers.Registry,
+	dataProviderConfigService *DataProviderConfigService,
+) *PirepDeliveryService {
+	return &PirepDeliveryService{
+		outboxRepo:                outboxRepo,
+		pirepRepo:                 pirepRepo,
+		registry:                  registry,
+		dataProviderConfigService: dataProviderConfigService,
+	}
+}
+
+// NewOutboxEntry builds an outbox entry for a PIREP. The entry starts leased to the caller,
+// which is expected to attempt delivery immediately; the outbox job picks it up if that fails.
+func (s *PirepDeliveryService) NewOutboxEntry(vaID, pirepID, providerType string, payload map[string]interface{}) *gormModels.PirepOutbox {
+	return &gormModels.PirepOutbox{
+		PirepID:        pirepID,
+		VAID:           vaID,
+		ProviderType:   providerType,
+		IdempotencyKey: pirepID,
+		Payload:        gormModels.JSONB(payload),
+		Status:         constants.OutboxStatusPending,
+		MaxAttempts:    DefaultOutboxMaxAttempts,
+		NextAttemptAt:  time.Now().Add(outboxDeliveryLease),
+	}
+}
+
+// Deliver attempts to push a single outbox entry to the provider.
+// On failure the entry is rescheduled with exponential backoff (or dead-lettered) and the error returned.
+func (s *PirepDeliveryService) Deliver(ctx context.Context, entry *gormModels.PirepOutbox) (string, error) {
+	if entry.ProviderRecordID != nil && *entry.ProviderRecordID != "" {
+		return *entry.ProviderRecordID, nil
+	}
+
+	// Keep other workers off the entry until the outcome is recorded
+	releaseLease := s.holdLease(ctx, entry.ID)
+	recordID, err := s.submit(ctx, entry)
+	releaseLease()
+
+	if err != nil {
+		return "", s.recordFailure(ctx, entry, err)
+	}
+
+	s.markDelivered(ctx, entry, recordID)
+	log.Printf("[PirepDeliveryService] Outbox %s delivered (PIREP %s -> %s)", entry.ID, entry.PirepID, recordID)
+	return recordID, nil
+}
+
+// submit creates the entry's provider record, unless an earlier attempt already did
+func (s *PirepDeliveryService) submit(ctx context.Context, entry *gormModels.PirepOutbox) (string, error) {
+	configData, err := s.dataProviderConfigService.GetActiveConfigCached(ctx, entry.VAID, entry.ProviderType)
+	if err != nil || configData == nil {
+		return "", fmt.Errorf("%s provider configuration not available", entry.ProviderType)
+	}
+
+	pirepSchema := configData.GetSchemaByType("pirep")
+	if pirepSchema == nil {
+		return "", fmt.Errorf("PIREP schema not configured in provider settings")
+	}
+
+	// Deliver to the provider the entry was queued for, even if the VA has switched since
+	provider, err := s.registry.Client(entry.ProviderType, configData)
+	if err != nil {
+		return "", err
+	}
+
+	// A previous attempt may have created the record before failing (e.g. a timeout on the response);
+	// look it up by idempotency key before creating it again. Replayed dead letters have their
+	// attempts reset but keep their last error. Without the key mapped, retrying is only unsafe
+	// when an earlier attempt may actually have reached the provider.
+	if entry.Attempts > 0 || entry.LastError != nil {
+		if mapping := pirepSchema.GetFieldMapping(idempotencyKeyField); mapping != nil {
+			recordID, err := s.findExistingRecord(ctx, provider, pirepSchema, mapping.AirtableName, entry.IdempotencyKey)
+			if err != nil {
+				return "", err
+			}
+			if recordID != "" {
+				log.Printf("[PirepDeliveryService] Outbox %s already delivered as %s, skipping re-submit", entry.ID, recordID)
+				return recordID, nil
+			}
+		} else if entry.OutcomeUnknown {
+			return "", errUnsafeRetry
+		}
+	}
+
+	recordID, err := provider.SubmitRecord(ctx, pirepSchema, map[string]interface{}(entry.Payload))
+	if err != nil && providers.SubmitMayHaveCreated(err) {
+		entry.OutcomeUnknown = true
+	}
+	return recordID, err
+}
+
+// holdLease renews the entry's delivery lease until the returned function is called, which
+// waits for any renewal in flight so that it can't overwrite the recorded outcome
+func (s *PirepDeliveryService) holdLease(ctx context.Context, id string) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(outboxLeaseRenewal)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.outboxRepo.ExtendLease(ctx, id, time.Now().Add(outboxDeliveryLease)); err != nil {
+					log.Printf("[PirepDeliveryService] Failed to renew lease of outbox %s: %v", id, err)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// ProcessDue claims due outbox entries and attempts to deliver each one.
+// Returns the number of entries claimed so callers can keep draining while batches are full.
+func (s *PirepDeliveryService) ProcessDue(ctx context.Context, batchSize int) (int, error) {
+	entries, err := s.outboxRepo.ClaimDue(ctx, batchSize, outboxDeliveryLease)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim outbox entries: %w", err)
+	}
+
+	for i := range entries {
+		if ctx.Err() != nil {
+			break
+		}
+		if _, err := s.Deliver(ctx, &entries[i]); err != nil {
+			log.Printf("[PirepDeliveryService] Outbox %s attempt %d failed: %v", entries[i].ID, entries[i].Attempts+1, err)
+		}
+	}
+
+	return len(entries), nil
+}
+
+// ListDeadLetters returns dead-lettered outbox entries for a VA
+func (s *PirepDeliveryService) ListDeadLetters(ctx context.Context, vaID string, limit int) ([]gormModels.PirepOutbox, error) {
+	entries, err := s.outboxRepo.ListByStatus(ctx, vaID, constants.OutboxStatusDead, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead letters: %w", err)
+	}
+	return entries, nil
+}
+
+// ReplayDeadLetter resets a dead-lettered entry's attempt budget and delivers it immediately
+func (s *PirepDeliveryService) ReplayDeadLetter(ctx context.Context, vaID string, id string) (string, error) {
+	requeued, err := s.outboxRepo.Requeue(ctx, vaID, id, time.Now().Add(outboxDeliveryLease))
+	if err != nil {
+		return "", fmt.Errorf("failed to requeue outbox entry: %w", err)
+	}
+	if !requeued {
+		return "", ErrOutboxEntryNotFound
+	}
+
+	entry, err := s.outboxRepo.FindByID(ctx, vaID, id)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch outbox entry: %w", err)
+	}
+	if entry == nil {
+		return "", ErrOutboxEntryNotFound
+	}
+
+	return s.Deliver(ctx, entry)
+}
+
+// findExistingRecord looks up a provider record whose keyField holds the idempotency key.
+// Returns "" when no record matches.
+func (s *PirepDeliveryService) findExistingRecord(ctx context.Context, provider providers.ProviderClient, schema *dtos.EntitySchema, keyField string, key string) (string, error) {
+	record, err := providers.FindRecord(ctx, provider, schema, keyField, key)
+	if err != nil {
+		return "", fmt.Errorf("idempotency lookup failed: %w", err)
+	}
+	if record == nil {
+		return "", nil
+	}
+	return record.ID, nil
+}
+
+// markDelivered records a successful delivery on both the outbox entry and the PIREP
+func (s *PirepDeliveryService) markDelivered(ctx context.Context, entry *gormModels.PirepOutbox, recordID string) {
+	if err := s.outboxRepo.MarkDelivered(ctx, entry.ID, recordID); err != nil {
+		log.Printf("[PirepDeliveryService] Failed to mark outbox %s delivered: %v", entry.ID, err)
+	}
+	if err := s.pirepRepo.MarkProviderSynced(ctx, entry.PirepID, entry.ProviderType, recordID); err != nil {
+		log.Printf("[PirepDeliveryService] Failed to record provider sync for PIREP %s: %v", entry.PirepID, err)
+	}
+}
+
+// recordFailure reschedules the entry with backoff (or dead-letters it) and returns cause
+func (s *PirepDeliveryService) recordFailure(ctx context.Context, entry *gormModels.PirepOutbox, cause error) error {
+	attempts := entry.Attempts + 1
+	dead := attempts >= entry.MaxAttempts || errors.Is(cause, errUnsafeRetry)
+	nextAttemptAt := time.Now().Add(outboxBackoff(attempts))
+
+	if err := s.outboxRepo.MarkFailed(ctx, entry.ID, attempts, cause.Error(), entry.OutcomeUnknown, nextAttemptAt, dead); err != nil {
+		log.Printf("[PirepDeliveryService] Failed to record outbox failure for %s: %v", entry.ID, err)
+	}
+	if err := s.pirepRepo.MarkProviderFailed(ctx, entry.PirepID, entry.ProviderType, cause.Error()); err != nil {
+		log.Printf("[PirepDeliveryService] Failed to record provider error for PIREP %s: %v", entry.PirepID, err)
+	}
+
+	if dead {
+		log.Printf("[PirepDeliveryService] Outbox %s dead-lettered after %d attempts: %v", entry.ID, attempts, cause)
+	}
+
+	entry.Attempts = attempts
+	return cause
+}
+
+// outboxBackoff returns the delay before the next attempt after the given number of failures
+func outboxBackoff(attempts int) time.Duration {
+	if attempts < 1 {
+		return 0
+	}
+	delay := outboxBaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= outboxMaxBackoff {
+			return outboxMaxBackoff
+		}
+	}
+	return delay
+}
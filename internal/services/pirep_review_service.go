@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"infinite-experiment/politburo/internal/constants"
+	"infinite-experiment/politburo/internal/db/repositories"
+	"infinite-experiment/politburo/internal/models/dtos"
+	gormModels "infinite-experiment/politburo/internal/models/gorm"
+)
+
+var (
+	// ErrPirepNotFound is returned when a PIREP does not exist in the VA
+	ErrPirepNotFound = errors.New("pirep not found")
+	// ErrPirepAlreadyReviewed is returned when reviewing a PIREP that is no longer pending
+	ErrPirepAlreadyReviewed = errors.New("pirep has already been reviewed")
+	// ErrInvalidReviewDecision is returned for unknown review decisions
+	ErrInvalidReviewDecision = errors.New("invalid review decision")
+	// ErrReviewNotesRequired is returned when rejecting or requesting changes without a reason
+	ErrReviewNotesRequired = errors.New("review notes are required when rejecting or requesting changes")
+	// ErrPirepNotResubmittable is returned when resubmitting a PIREP that staff have not asked changes for
+	ErrPirepNotResubmittable = errors.New("pirep is not awaiting changes")
+	// ErrInvalidFlightTime is returned for a resubmitted flight time that is not HH:MM
+	ErrInvalidFlightTime = errors.New("flight time must be HH:MM")
+)
+
+// PirepReviewService handles the PIREP review lifecycle (listing, approving/rejecting and resubmitting)
+type PirepReviewService struct {
+	pirepRepo *repositories.PirepRepo
+	rankSvc   *RankService
+}
+
+// NewPirepReviewService creates a new PirepReviewService
+func NewPirepReviewService(pirepRepo *repositories.PirepRepo, rankSvc *RankService) *PirepReviewService {
+	return &PirepReviewService{pirepRepo: pirepRepo, rankSvc: rankSvc}
+}
+
+// ListPireps returns PIREPs for a VA matching the filter
+func (s *PirepReviewService) ListPireps(ctx context.Context, vaID string, filter repositories.PirepFilter) ([]gormModels.Pirep, int64, error) {
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return nil, 0, fmt.Errorf("invalid status filter: %s", filter.Status)
+	}
+
+	pireps, total, err := s.pirepRepo.List(ctx, vaID, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list pireps: %w", err)
+	}
+	return pireps, total, nil
+}
+
+// GetPirep returns a single PIREP belonging to the VA
+func (s *PirepReviewService) GetPirep(ctx context.Context, vaID, pirepID string) (*gormModels.Pirep, error) {
+	pirep, err := s.pirepRepo.FindByID(ctx, vaID, pirepID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch pirep: %w", err)
+	}
+	if pirep == nil {
+		return nil, ErrPirepNotFound
+	}
+	return pirep, nil
+}
+
+// ReviewPirep moves a pending PIREP to approved, rejected or changes_requested, recording the reviewer and notes
+func (s *PirepReviewService) ReviewPirep(
+	ctx context.Context,
+	vaID string,
+	pirepID string,
+	reviewerID string,
+	decision constants.PirepStatus,
+	notes string,
+) (*gormModels.Pirep, error) {
+	switch decision {
+	case constants.PirepStatusApproved, constants.PirepStatusRejected, constants.PirepStatusChangesRequested:
+	default:
+		return nil, ErrInvalidReviewDecision
+	}
+
+	notes = strings.TrimSpace(notes)
+	if decision != constants.PirepStatusApproved && notes == "" {
+		return nil, ErrReviewNotesRequired
+	}
+
+	pirep, err := s.GetPirep(ctx, vaID, pirepID)
+	if err != nil {
+		return nil, err
+	}
+	if !pirep.Status.IsReviewable() {
+		return nil, ErrPirepAlreadyReviewed
+	}
+
+	var notesPtr *string
+	if notes != "" {
+		notesPtr = &notes
+	}
+
+	// The status condition makes concurrent reviews safe: only the first one applies
+	updated, err := s.pirepRepo.UpdateReview(ctx, vaID, pirepID, decision, reviewerID, notesPtr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update pirep review: %w", err)
+	}
+	if !updated {
+		return nil, ErrPirepAlreadyReviewed
+	}
+
+	log.Printf("[PirepReviewService] PIREP %s %s by %s", pirepID, decision, reviewerID)
+
+	// Approved time counts towards the pilot's rank; a failed promotion must not fail the review
+	if decision == constants.PirepStatusApproved && s.rankSvc != nil {
+		if _, err := s.rankSvc.EvaluateApproval(ctx, vaID, pirep.UserID, pirepID); err != nil {
+			log.Printf("[PirepReviewService] Failed to evaluate rank for user %s: %v", pirep.UserID, err)
+		}
+	}
+
+	return s.GetPirep(ctx, vaID, pirepID)
+}
+
+// ResubmitPirep applies the pilot's corrections to a PIREP staff requested changes on and puts it back
+// in the review queue. The review notes are kept so staff can see what they asked for. The data
+// provider keeps the originally pushed record.
+func (s *PirepReviewService) ResubmitPirep(
+	ctx context.Context,
+	vaID string,
+	pirepID string,
+	userID string,
+	req *dtos.PirepResubmitRequest,
+) (*gormModels.Pirep, error) {
+	pirep, err := s.GetPirep(ctx, vaID, pirepID)
+	if err != nil {
+		return nil, err
+	}
+	// Another pilot's PIREP is reported as missing rather than revealed
+	if pirep.UserID != userID {
+		return nil, ErrPirepNotFound
+	}
+	if pirep.Status != constants.PirepStatusChangesRequested {
+		return nil, ErrPirepNotResubmittable
+	}
+
+	updates := map[string]interface{}{}
+	if flightTime := strings.TrimSpace(req.FlightTime); flightTime != "" {
+		seconds := parseFlightTime(flightTime)
+		if seconds <= 0 {
+			return nil, ErrInvalidFlightTime
+		}
+		updates["flight_time_seconds"] = seconds
+		updates["credited_seconds"] = int(float64(seconds) * pirep.Multiplier)
+	}
+	if req.PilotRemarks != nil {
+		updates["pilot_remarks"] = strings.TrimSpace(*req.PilotRemarks)
+	}
+	if req.FuelKg != nil {
+		updates["fuel_kg"] = *req.FuelKg
+	}
+	if req.CargoKg != nil {
+		updates["cargo_kg"] = *req.CargoKg
+	}
+	if req.Passengers != nil {
+		updates["passengers"] = *req.Passengers
+	}
+
+	// The status condition makes a resubmit racing a staff review safe: only the first one applies
+	updated, err := s.pirepRepo.Resubmit(ctx, vaID, pirepID, userID, updates)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resubmit pirep: %w", err)
+	}
+	if !updated {
+		return nil, ErrPirepNotResubmittable
+	}
+
+	log.Printf("[PirepReviewService] PIREP %s resubmitted by %s", pirepID, userID)
+
+	return s.GetPirep(ctx, vaID, pirepID)
+}
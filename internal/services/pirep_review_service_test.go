@@ -0,0 +1,256 @@
+package services
+
+import (
+	"context"
+	"infinite-experiment/politburo/internal/constants"
+	"infinite-experiment/politburo/internal/db/repositories"
+	"infinite-experiment/politburo/internal/models/dtos"
+	gormModels "infinite-experiment/politburo/internal/models/gorm"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const (
+	testVAID     = "va-1"
+	testUserID   = "user-1"
+	testCadetID  = "rank-cadet"
+	testFirstOff = "rank-first-officer"
+)
+
+// newTestReviewDB creates the tables PIREP review and promotions use, with one pilot
+// holding the Cadet rank and a First Officer rank at 10 hours
+func newTestReviewDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+
+	// The models' Postgres types and defaults don't migrate to SQLite
+	statements := []string{
+		`CREATE TABLE users (id TEXT PRIMARY KEY, username TEXT)`,
+		`CREATE TABLE virtual_airlines (id TEXT PRIMARY KEY, name TEXT)`,
+		`CREATE TABLE va_user_roles (id TEXT PRIMARY KEY, user_id TEXT, va_id TEXT, role TEXT, is_active BOOLEAN DEFAULT TRUE,
+			callsign TEXT, rank_id TEXT, rank_achieved_at DATETIME, joined_at DATETIME, updated_at DATETIME)`,
+		`CREATE TABLE va_ranks (id TEXT PRIMARY KEY, va_id TEXT, name TEXT, position INTEGER, min_hours REAL, min_flights INTEGER,
+			required_checkrides TEXT DEFAULT '{}', allowed_aircraft TEXT DEFAULT '{}', created_at DATETIME, updated_at DATETIME)`,
+		`CREATE TABLE pilot_checkrides (id TEXT PRIMARY KEY, va_id TEXT, user_id TEXT, name TEXT, examiner_id TEXT, notes TEXT, passed_at DATETIME)`,
+		`CREATE TABLE rank_promotions (id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(16)))), va_id TEXT, user_id TEXT,
+			from_rank_id TEXT, to_rank_id TEXT, pirep_id TEXT, credited_hours REAL, flights INTEGER, created_at DATETIME)`,
+		`CREATE UNIQUE INDEX idx_rank_promotions_pirep ON rank_promotions (pirep_id) WHERE pirep_id IS NOT NULL`,
+		`CREATE TABLE pireps (id TEXT PRIMARY KEY, va_id TEXT, user_id TEXT, mode TEXT, status TEXT, credited_seconds INTEGER,
+			flight_time_seconds INTEGER, multiplier REAL DEFAULT 1.0, pilot_remarks TEXT, fuel_kg INTEGER, cargo_kg INTEGER, passengers INTEGER,
+			reviewed_by TEXT, review_notes TEXT, reviewed_at DATETIME, submitted_at DATETIME, created_at DATETIME, updated_at DATETIME)`,
+
+		`INSERT INTO users (id, username) VALUES ('user-1', 'pilot'), ('staff-1', 'staff')`,
+		`INSERT INTO virtual_airlines (id, name) VALUES ('va-1', 'Test VA')`,
+		`INSERT INTO va_ranks (id, va_id, name, position, min_hours, min_flights) VALUES
+			('rank-cadet', 'va-1', 'Cadet', 1, 0, 0),
+			('rank-first-officer', 'va-1', 'First Officer', 2, 10, 0)`,
+		`INSERT INTO va_user_roles (id, user_id, va_id, role, callsign, rank_id) VALUES ('role-1', 'user-1', 'va-1', 'pilot', 'TST001', 'rank-cadet')`,
+	}
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			t.Fatalf("Failed to set up test database: %v\n%s", err, statement)
+		}
+	}
+	return db
+}
+
+// addTestPirep adds a PIREP of the test pilot crediting the given hours
+func addTestPirep(t *testing.T, db *gorm.DB, id string, status constants.PirepStatus, hours float64) {
+	err := db.Exec(`INSERT INTO pireps (id, va_id, user_id, mode, status, credited_seconds) VALUES (?, ?, ?, 'free', ?, ?)`,
+		id, testVAID, testUserID, status, int(hours*3600)).Error
+	if err != nil {
+		t.Fatalf("Failed to add pirep %s: %v", id, err)
+	}
+}
+
+// testPilotRank returns the test pilot's rank ID
+func testPilotRank(t *testing.T, db *gorm.DB) string {
+	var role gormModels.UserVARole
+	if err := db.Where("id = ?", "role-1").First(&role).Error; err != nil {
+		t.Fatalf("Failed to load role: %v", err)
+	}
+	if role.RankID == nil {
+		return ""
+	}
+	return *role.RankID
+}
+
+// testPromotions returns the test VA's promotion events
+func testPromotions(t *testing.T, db *gorm.DB) []gormModels.RankPromotion {
+	var promotions []gormModels.RankPromotion
+	if err := db.Where("va_id = ?", testVAID).Find(&promotions).Error; err != nil {
+		t.Fatalf("Failed to load promotions: %v", err)
+	}
+	return promotions
+}
+
+func TestPirepReviewService_ApprovalCrossingThresholdPromotesOnce(t *testing.T) {
+	ctx := context.Background()
+	db := newTestReviewDB(t)
+	pirepRepo := repositories.NewPirepRepo(db)
+	rankRepo := repositories.NewRankRepo(db)
+	rankSvc := NewRankService(rankRepo, pirepRepo, repositories.NewVAUserRoleRepository(db))
+	reviewSvc := NewPirepReviewService(pirepRepo, rankSvc)
+
+	addTestPirep(t, db, "pirep-1", constants.PirepStatusApproved, 8)
+	addTestPirep(t, db, "pirep-2", constants.PirepStatusPending, 1)
+	addTestPirep(t, db, "pirep-3", constants.PirepStatusPending, 1.5)
+	addTestPirep(t, db, "pirep-4", constants.PirepStatusPending, 2)
+
+	// 9 hours: still short of First Officer
+	if _, err := reviewSvc.ReviewPirep(ctx, testVAID, "pirep-2", "staff-1", constants.PirepStatusApproved, ""); err != nil {
+		t.Fatalf("ReviewPirep(pirep-2): %v", err)
+	}
+	if rank := testPilotRank(t, db); rank != testCadetID {
+		t.Fatalf("rank after 9 hours = %q, want %q", rank, testCadetID)
+	}
+	if promotions := testPromotions(t, db); len(promotions) != 0 {
+		t.Fatalf("got %d promotions after 9 hours, want none", len(promotions))
+	}
+
+	// A rejection doesn't count towards the rank
+	if _, err := reviewSvc.ReviewPirep(ctx, testVAID, "pirep-4", "staff-1", constants.PirepStatusRejected, "Wrong aircraft"); err != nil {
+		t.Fatalf("ReviewPirep(pirep-4): %v", err)
+	}
+	if rank := testPilotRank(t, db); rank != testCadetID {
+		t.Fatalf("rank after a rejection = %q, want %q", rank, testCadetID)
+	}
+
+	// 10.5 hours: the approval crosses the threshold
+	if _, err := reviewSvc.ReviewPirep(ctx, testVAID, "pirep-3", "staff-1", constants.PirepStatusApproved, ""); err != nil {
+		t.Fatalf("ReviewPirep(pirep-3): %v", err)
+	}
+	if rank := testPilotRank(t, db); rank != testFirstOff {
+		t.Fatalf("rank after 10.5 hours = %q, want %q", rank, testFirstOff)
+	}
+	promotions := testPromotions(t, db)
+	if len(promotions) != 1 {
+		t.Fatalf("got %d promotions, want 1", len(promotions))
+	}
+	promotion := promotions[0]
+	if promotion.PirepID == nil || *promotion.PirepID != "pirep-3" {
+		t.Errorf("promotion earned by %v, want pirep-3", promotion.PirepID)
+	}
+	if promotion.FromRankID == nil || *promotion.FromRankID != testCadetID || promotion.ToRankID == nil || *promotion.ToRankID != testFirstOff {
+		t.Errorf("promotion from %v to %v, want %s to %s", promotion.FromRankID, promotion.ToRankID, testCadetID, testFirstOff)
+	}
+	if promotion.CreditedHours != 10.5 || promotion.Flights != 3 {
+		t.Errorf("promotion at %.1fh and %d flights, want 10.5h and 3 flights", promotion.CreditedHours, promotion.Flights)
+	}
+
+	// Reviewing the approved PIREP again is refused
+	if _, err := reviewSvc.ReviewPirep(ctx, testVAID, "pirep-3", "staff-1", constants.PirepStatusApproved, ""); err != ErrPirepAlreadyReviewed {
+		t.Errorf("second review of pirep-3: got %v, want ErrPirepAlreadyReviewed", err)
+	}
+
+	// Evaluating the same approval again, even from the old rank, doesn't promote twice
+	if err := db.Exec(`UPDATE va_user_roles SET rank_id = ? WHERE id = 'role-1'`, testCadetID).Error; err != nil {
+		t.Fatal(err)
+	}
+	again, err := rankSvc.EvaluateApproval(ctx, testVAID, testUserID, "pirep-3")
+	if err != nil {
+		t.Fatalf("EvaluateApproval(pirep-3) again: %v", err)
+	}
+	if again != nil {
+		t.Errorf("second evaluation of pirep-3 promoted to %v", again.ToRankID)
+	}
+	if rank := testPilotRank(t, db); rank != testCadetID {
+		t.Errorf("rank after second evaluation = %q, want it left at %q", rank, testCadetID)
+	}
+	if promotions := testPromotions(t, db); len(promotions) != 1 {
+		t.Errorf("got %d promotions after second evaluation, want 1", len(promotions))
+	}
+}
+
+func TestRankRepo_PromoteSkipsStaleRank(t *testing.T) {
+	ctx := context.Background()
+	db := newTestReviewDB(t)
+	rankRepo := repositories.NewRankRepo(db)
+
+	// Another evaluation already moved the pilot on from the rank this promotion started from
+	toRank := testFirstOff
+	stale := "rank-gone"
+	promoted, err := rankRepo.Promote(ctx, &gormModels.RankPromotion{
+		VAID:       testVAID,
+		UserID:     testUserID,
+		FromRankID: &stale,
+		ToRankID:   &toRank,
+	})
+	if err != nil {
+		t.Fatalf("Promote: %v", err)
+	}
+	if promoted {
+		t.Error("promoted from a rank the pilot no longer holds")
+	}
+	if rank := testPilotRank(t, db); rank != testCadetID {
+		t.Errorf("rank = %q, want it left at %q", rank, testCadetID)
+	}
+	if promotions := testPromotions(t, db); len(promotions) != 0 {
+		t.Errorf("got %d promotions, want the skipped one rolled back", len(promotions))
+	}
+}
+
+func TestPirepReviewService_ResubmitAfterChangesRequested(t *testing.T) {
+	ctx := context.Background()
+	db := newTestReviewDB(t)
+	reviewSvc := NewPirepReviewService(repositories.NewPirepRepo(db), nil)
+
+	addTestPirep(t, db, "pirep-1", constants.PirepStatusPending, 1)
+	if err := db.Exec(`UPDATE pireps SET multiplier = 1.5 WHERE id = 'pirep-1'`).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	remarks := "Fuel corrected"
+	fuel := 5200
+	req := &dtos.PirepResubmitRequest{FlightTime: "02:00", PilotRemarks: &remarks, FuelKg: &fuel}
+
+	// Nothing to resubmit while the PIREP is pending
+	if _, err := reviewSvc.ResubmitPirep(ctx, testVAID, "pirep-1", testUserID, req); err != ErrPirepNotResubmittable {
+		t.Fatalf("resubmit while pending: got %v, want ErrPirepNotResubmittable", err)
+	}
+
+	if _, err := reviewSvc.ReviewPirep(ctx, testVAID, "pirep-1", "staff-1", constants.PirepStatusChangesRequested, "Fuel is missing"); err != nil {
+		t.Fatalf("ReviewPirep: %v", err)
+	}
+
+	// Only the pilot who filed it can resubmit, and a bad flight time is refused
+	if _, err := reviewSvc.ResubmitPirep(ctx, testVAID, "pirep-1", "staff-1", req); err != ErrPirepNotFound {
+		t.Errorf("resubmit by another user: got %v, want ErrPirepNotFound", err)
+	}
+	if _, err := reviewSvc.ResubmitPirep(ctx, testVAID, "pirep-1", testUserID, &dtos.PirepResubmitRequest{FlightTime: "two hours"}); err != ErrInvalidFlightTime {
+		t.Errorf("resubmit with a bad flight time: got %v, want ErrInvalidFlightTime", err)
+	}
+
+	pirep, err := reviewSvc.ResubmitPirep(ctx, testVAID, "pirep-1", testUserID, req)
+	if err != nil {
+		t.Fatalf("ResubmitPirep: %v", err)
+	}
+	if pirep.Status != constants.PirepStatusPending {
+		t.Errorf("status = %s, want %s", pirep.Status, constants.PirepStatusPending)
+	}
+	if pirep.FlightTimeSeconds != 7200 || pirep.CreditedSeconds != 10800 {
+		t.Errorf("flight time %ds credited %ds, want 7200s credited 10800s at the 1.5 multiplier", pirep.FlightTimeSeconds, pirep.CreditedSeconds)
+	}
+	if pirep.PilotRemarks != remarks || pirep.FuelKg == nil || *pirep.FuelKg != fuel {
+		t.Errorf("remarks %q fuel %v, want %q and %d", pirep.PilotRemarks, pirep.FuelKg, remarks, fuel)
+	}
+	if pirep.ReviewedBy != nil || pirep.ReviewedAt != nil {
+		t.Errorf("review kept as %v at %v, want it cleared", pirep.ReviewedBy, pirep.ReviewedAt)
+	}
+	if pirep.ReviewNotes == nil || *pirep.ReviewNotes != "Fuel is missing" {
+		t.Errorf("review notes = %v, want the change request kept", pirep.ReviewNotes)
+	}
+
+	// Back in the queue, it can be resubmitted again only after another change request
+	if _, err := reviewSvc.ResubmitPirep(ctx, testVAID, "pirep-1", testUserID, req); err != ErrPirepNotResubmittable {
+		t.Errorf("second resubmit: got %v, want ErrPirepNotResubmittable", err)
+	}
+	if _, err := reviewSvc.ReviewPirep(ctx, testVAID, "pirep-1", "staff-1", constants.PirepStatusApproved, ""); err != nil {
+		t.Errorf("approving the resubmitted PIREP: %v", err)
+	}
+}
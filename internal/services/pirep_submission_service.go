@@ -11,10 +11,12 @@ import (
 
 	"infinite-experiment/politburo/internal/auth"
 	"infinite-experiment/politburo/internal/common"
+	"infinite-experiment/politburo/internal/constants"
 	"infinite-experiment/politburo/internal/db/repositories"
 	"infinite-experiment/politburo/internal/models/dtos"
 	gormModels "infinite-experiment/politburo/internal/models/gorm"
-	"infinite-experiment/politburo/internal/providers"
+
+	"github.com/google/uuid"
 )
 
 // PirepSubmissionService handles PIREP submission logic
@@ -24,12 +26,14 @@ type PirepSubmissionService struct {
 	routeRepo                   *repositories.RouteATSyncedRepo
 	liveryMappingRepo           *repositories.LiveryAirtableMappingRepository
 	dataProviderConfigRepo      *repositories.DataProviderConfigRepo
-	airtableProvider            providers.DataProvider
 	validator                   *FlightModeValidationService
 	cache                       common.CacheInterface
 	flightsService              *FlightsService
 	configService               *common.VAConfigService
 	dataProviderConfigService   *DataProviderConfigService
+	pirepRepo                   *repositories.PirepRepo
+	deliveryService             *PirepDeliveryService
+	rankService                 *RankService
 }
 
 // NewPirepSubmissionService creates a new PirepSubmissionService with dependencies
@@ -39,12 +43,14 @@ func NewPirepSubmissionService(
 	routeRepo *repositories.RouteATSyncedRepo,
 	liveryMappingRepo *repositories.LiveryAirtableMappingRepository,
 	dataProviderConfigRepo *repositories.DataProviderConfigRepo,
-	airtableProvider providers.DataProvider,
 	validator *FlightModeValidationService,
 	cache common.CacheInterface,
 	flightsService *FlightsService,
 	configService *common.VAConfigService,
 	dataProviderConfigService *DataProviderConfigService,
+	pirepRepo *repositories.PirepRepo,
+	deliveryService *PirepDeliveryService,
+	rankService *RankService,
 ) *PirepSubmissionService {
 	return &PirepSubmissionService{
 		userRepo:                  userRepo,
@@ -52,15 +58,22 @@ func NewPirepSubmissionService(
 		routeRepo:                 routeRepo,
 		liveryMappingRepo:         liveryMappingRepo,
 		dataProviderConfigRepo:    dataProviderConfigRepo,
-		airtableProvider:          airtableProvider,
 		validator:                 validator,
 		cache:                     cache,
 		flightsService:            flightsService,
 		configService:             configService,
 		dataProviderConfigService: dataProviderConfigService,
+		pirepRepo:                 pirepRepo,
+		deliveryService:           deliveryService,
+		rankService:               rankService,
 	}
 }
 
+const (
+	flightHistoryLookback      = 12 * time.Hour   // How far back to look for the flight being filed
+	defaultFlightTimeTolerance = 15 * time.Minute // Filed vs flown time gap before a PIREP is flagged
+)
+
 // FlightData represents the current flight information for enrichment
 type FlightData struct {
 	FlightID string
@@ -102,46 +115,7 @@ func (s *PirepSubmissionService) SubmitPirep(
 		}, nil
 	}
 
-	// STEP 3: RE-VALIDATE MODE (defensive check)
-	// Note: For now, we skip this as it would require Live API call
-	// This can be enhanced later to fetch current flight and re-validate
-
-	// STEP 4: RESOLVE ROUTE
-	var route *gormModels.RouteATSynced
-	if modeConfig.AutoRoute != nil {
-		// Auto-route mode: lookup by route name
-		var err error
-		route, err = s.resolveAutoRoute(ctx, vaConfig.ID, modeConfig.AutoRoute.RouteName)
-		if err != nil {
-			return &dtos.PirepSubmitResponse{
-				Success:      false,
-				ErrorType:    "validation_error",
-				ErrorMessage: fmt.Sprintf("Auto-route not found: %s", modeConfig.AutoRoute.RouteName),
-			}, nil
-		}
-	} else {
-		// Manual route selection: use provided route string (e.g., "LFPG-EGLL")
-		if request.RouteID == "" {
-			return &dtos.PirepSubmitResponse{
-				Success:      false,
-				ErrorType:    "validation_error",
-				ErrorMessage: "Route selection required but no route_id provided",
-			}, nil
-		}
-
-		// Resolve route by name (the route_id is actually the route string)
-		var err error
-		route, err = s.routeRepo.FindByName(ctx, vaConfig.ID, request.RouteID)
-		if err != nil || route == nil {
-			return &dtos.PirepSubmitResponse{
-				Success:      false,
-				ErrorType:    "validation_error",
-				ErrorMessage: fmt.Sprintf("Route not found in system: %s", request.RouteID),
-			}, nil
-		}
-	}
-
-	// STEP 5: RESOLVE PILOT
+	// STEP 3: RESOLVE PILOT
 	userDiscordID := userClaims.DiscordUserID()
 	user, err := s.getUserWithVAAffiliations(ctx, userDiscordID)
 	if err != nil || user == nil {
@@ -169,16 +143,23 @@ func (s *PirepSubmissionService) SubmitPirep(
 		}, nil
 	}
 
-	// Verify airtable_pilot_id is set
-	if userVARole.AirtablePilotID == nil || *userVARole.AirtablePilotID == "" {
-		return &dtos.PirepSubmitResponse{
-			Success:      false,
-			ErrorType:    "validation_error",
-			ErrorMessage: "User's pilot record not linked to Airtable",
-		}, nil
+	// Rank gates are enforced regardless of the mode's validation policy
+	var standing *RankStanding
+	if s.rankService != nil {
+		standing, err = s.rankService.LoadStanding(ctx, vaConfig.ID, user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load pilot rank: %w", err)
+		}
+		if ok, reason := standing.AllowsMode(modeConfig.MinRank); !ok {
+			return &dtos.PirepSubmitResponse{
+				Success:      false,
+				ErrorType:    "validation_error",
+				ErrorMessage: reason,
+			}, nil
+		}
 	}
 
-	// STEP 5.5: FETCH CURRENT FLIGHT DATA (for enrichment)
+	// STEP 4: FETCH CURRENT FLIGHT DATA (for enrichment)
 	// Get user's callsign and current flight from Live API
 	flightData := &FlightData{}
 
@@ -208,7 +189,107 @@ func (s *PirepSubmissionService) SubmitPirep(
 		}
 	}
 
-	// STEP 6: RESOLVE LIVERY MAPPING (aircraft/airline standardization)
+	// STEP 5: DERIVE FLIGHT FROM INFINITE FLIGHT HISTORY
+	// Prefills what the pilot left out and flags what doesn't match what they actually flew
+	completed := s.findCompletedFlight(ctx, user, flightData.FlightID)
+	if completed != nil {
+		if flightData.LiveryID == "" {
+			flightData.FlightID = completed.FlightID
+			flightData.LiveryID = completed.LiveryID
+			flightData.Aircraft = completed.Aircraft
+			flightData.Livery = completed.Livery
+		}
+		if flightData.Route == "" && completed.Origin != "" && completed.Destination != "" {
+			flightData.Route = fmt.Sprintf("%s-%s", completed.Origin, completed.Destination)
+		}
+		if request.FlightTime == "" && completed.BlockTimeSeconds > 0 {
+			request.FlightTime = formatFlightTime(completed.BlockTimeSeconds)
+			log.Printf("[PirepSubmissionService] Flight time prefilled from flight history: %s", request.FlightTime)
+		}
+	}
+
+	// STEP 6: RE-VALIDATE MODE AGAINST THE FLIGHT
+	// The config endpoint only advises the client; enforce the mode's validations here per VA policy.
+	// Pilots usually file after landing, when the flight is only in their history.
+	validation := s.revalidateFlight(ctx, vaConfig.ID, modeConfig, validationSnapshot(currentFlight, completed))
+	if validation.Status == constants.PirepValidationFailed {
+		log.Printf("[PirepSubmissionService] Flight validation failed for %s (mode=%s, policy=%s): %s",
+			userVARole.Callsign, request.Mode, validation.Policy, validation.Reason)
+		if validation.Blocks() {
+			return &dtos.PirepSubmitResponse{
+				Success:      false,
+				ErrorType:    "validation_error",
+				ErrorMessage: validation.Reason,
+			}, nil
+		}
+	}
+
+	if standing != nil {
+		if ok, reason := standing.AllowsAircraft(flightData.Aircraft); !ok {
+			return &dtos.PirepSubmitResponse{
+				Success:      false,
+				ErrorType:    "validation_error",
+				ErrorMessage: reason,
+			}, nil
+		}
+	}
+	if request.FlightTime == "" {
+		return &dtos.PirepSubmitResponse{
+			Success:      false,
+			ErrorType:    "validation_error",
+			ErrorMessage: "flight_time is required (no completed flight found in your Infinite Flight history)",
+		}, nil
+	}
+
+	// STEP 7: RESOLVE ROUTE
+	var route *gormModels.RouteATSynced
+	if modeConfig.AutoRoute != nil {
+		// Auto-route mode: lookup by route name
+		var err error
+		route, err = s.resolveAutoRoute(ctx, vaConfig.ID, modeConfig.AutoRoute.RouteName)
+		if err != nil {
+			return &dtos.PirepSubmitResponse{
+				Success:      false,
+				ErrorType:    "validation_error",
+				ErrorMessage: fmt.Sprintf("Auto-route not found: %s", modeConfig.AutoRoute.RouteName),
+			}, nil
+		}
+	} else {
+		// Manual route selection: use provided route string (e.g., "LFPG-EGLL")
+		// Fall back to the route actually flown when the pilot didn't pick one
+		if request.RouteID == "" && completed != nil && completed.Origin != "" && completed.Destination != "" {
+			request.RouteID = fmt.Sprintf("%s-%s", completed.Origin, completed.Destination)
+			log.Printf("[PirepSubmissionService] Route prefilled from flight history: %s", request.RouteID)
+		}
+		if request.RouteID == "" {
+			return &dtos.PirepSubmitResponse{
+				Success:      false,
+				ErrorType:    "validation_error",
+				ErrorMessage: "Route selection required but no route_id provided",
+			}, nil
+		}
+
+		// Resolve route by name (the route_id is actually the route string)
+		var err error
+		route, err = s.routeRepo.FindActiveByName(ctx, vaConfig.ID, request.RouteID, time.Now())
+		if err != nil || route == nil {
+			return &dtos.PirepSubmitResponse{
+				Success:      false,
+				ErrorType:    "validation_error",
+				ErrorMessage: fmt.Sprintf("Route not found in system: %s", request.RouteID),
+			}, nil
+		}
+
+		if !RouteInDistanceBands(route, modeConfig.Validations.DistanceBands) {
+			return &dtos.PirepSubmitResponse{
+				Success:      false,
+				ErrorType:    "validation_error",
+				ErrorMessage: fmt.Sprintf("Route %s is not in this mode's distance bands (%s)", route.Route, strings.Join(modeConfig.Validations.DistanceBands, ", ")),
+			}, nil
+		}
+	}
+
+	// STEP 8: RESOLVE LIVERY MAPPING (aircraft/airline standardization)
 	// Livery mappings standardize aircraft and airline names from Infinite Flight API to Airtable values
 	// Flow: livery_id -> aircraft_livery table (get aircraft_name) -> livery_airtable_mappings (get target_value)
 	// Uses Redis cache with 24-hour TTL for frequent mappings
@@ -231,26 +312,270 @@ func (s *PirepSubmissionService) SubmitPirep(
 		}
 	}
 
-	// STEP 6.5: LOAD PROVIDER CONFIG AND GET PIREP SCHEMA (with caching)
-	configData, err := s.dataProviderConfigService.GetActiveConfigCached(ctx, vaConfig.ID, "airtable")
-	if err != nil || configData == nil {
-		return &dtos.PirepSubmitResponse{
-			Success:      false,
-			ErrorType:    "validation_error",
-			ErrorMessage: "Airtable provider configuration not available",
-		}, nil
+	// STEP 9: PERSIST NATIVE PIREP (together with its outbox entry if the VA has a data provider)
+	// The native store is the source of truth; provider delivery goes through the outbox
+	flightTimeSeconds := parseFlightTime(request.FlightTime)
+	multiplier := s.getMultiplier(modeConfig, route)
+
+	pirep := &gormModels.Pirep{
+		ID:                uuid.NewString(),
+		VAID:              vaConfig.ID,
+		UserID:            user.ID,
+		PilotCallsign:     userVARole.Callsign,
+		Mode:              request.Mode,
+		ModeDisplayName:   modeConfig.DisplayName,
+		FlightTimeSeconds: flightTimeSeconds,
+		Multiplier:        multiplier,
+		CreditedSeconds:   int(float64(flightTimeSeconds) * multiplier),
+		Aircraft:          aircraft,
+		Airline:           airline,
+		LiveryID:          flightData.LiveryID,
+		FlightID:          flightData.FlightID,
+		FuelKg:            request.FuelKg,
+		CargoKg:           request.CargoKg,
+		Passengers:        request.Passengers,
+		PilotRemarks:      request.PilotRemarks,
+		ValidationPolicy:  &validation.Policy,
+		ValidationStatus:  &validation.Status,
+		Status:            constants.PirepStatusPending,
+	}
+	if validation.Reason != "" {
+		pirep.ValidationReason = &validation.Reason
+	}
+	if completed != nil {
+		s.applyFlightHistory(ctx, pirep, completed, route)
+	}
+	if route != nil {
+		pirep.Route = route.Route
+		pirep.RouteID = &route.ID
+		pirep.RouteATID = route.ATID
 	}
 
-	pirepSchema := configData.GetSchemaByType("pirep")
-	if pirepSchema == nil {
-		return &dtos.PirepSubmitResponse{
-			Success:      false,
-			ErrorType:    "validation_error",
-			ErrorMessage: "PIREP schema not configured in provider settings",
-		}, nil
+	outbox, providerType, err := s.buildOutboxEntry(ctx, pirep, request, modeConfig, user, userVARole, route, aircraft, airline, flightData)
+	if err != nil {
+		// The VA has a provider but this PIREP can't be delivered to it; keep it natively and say why
+		log.Printf("[PirepSubmissionService] PIREP %s will not be delivered to provider: %v", pirep.ID, err)
+		errMsg := err.Error()
+		pirep.ProviderType = &providerType
+		pirep.ProviderError = &errMsg
+	}
+
+	if err := s.pirepRepo.CreateWithOutbox(ctx, pirep, outbox); err != nil {
+		return nil, fmt.Errorf("failed to store PIREP: %w", err)
+	}
+	log.Printf("[PirepSubmissionService] PIREP stored natively: %s", pirep.ID)
+
+	response := &dtos.PirepSubmitResponse{
+		Success: true,
+		Message: "PIREP filed successfully",
+		PirepID: pirep.ID,
+		Status:  pirep.Status.String(),
+	}
+
+	// STEP 10: DELIVER TO DATA PROVIDER
+	// First attempt is made inline; on failure the outbox job retries with backoff
+	if outbox != nil {
+		recordID, err := s.deliveryService.Deliver(ctx, outbox)
+		if err != nil {
+			log.Printf("[PirepSubmissionService] Provider delivery failed for PIREP %s, queued for retry: %v", pirep.ID, err)
+			response.Message = "PIREP filed successfully (data provider sync pending)"
+		} else {
+			response.ProviderRecordID = recordID
+		}
+	} else if pirep.ProviderError != nil {
+		response.Message = "PIREP filed successfully (not synced to data provider: " + *pirep.ProviderError + ")"
+	}
+
+	log.Printf("[PirepSubmissionService] PIREP filed successfully: %s", pirep.ID)
+	return response, nil
+}
+
+// flightValidation is the outcome of re-validating the live flight at submit time
+type flightValidation struct {
+	Policy string
+	Status string
+	Reason string
+}
+
+// Blocks reports whether the outcome rejects the PIREP, i.e. it failed under the strict policy
+func (v *flightValidation) Blocks() bool {
+	return v.Status == constants.PirepValidationFailed && v.Policy == constants.PirepValidationPolicyStrict
+}
+
+// validationSnapshot returns the flight to validate: the live flight while the pilot is still
+// flying, otherwise the flight from their history. Returns nil when neither is found.
+func validationSnapshot(current *dtos.LiveFlight, completed *CompletedFlight) *FlightSnapshot {
+	switch {
+	case current != nil:
+		return NewFlightSnapshot(current)
+	case completed != nil:
+		return NewCompletedFlightSnapshot(completed)
+	default:
+		return nil
+	}
+}
+
+// revalidateFlight checks the pilot's flight against the mode's validation rules.
+// The outcome is recorded on the PIREP; whether a failure blocks submission is up to the caller.
+func (s *PirepSubmissionService) revalidateFlight(
+	ctx context.Context,
+	vaID string,
+	modeConfig *dtos.FlightModeConfig,
+	flight *FlightSnapshot,
+) *flightValidation {
+	policy := s.getValidationPolicy(ctx, vaID)
+	if policy == constants.PirepValidationPolicyOff || s.validator == nil {
+		return &flightValidation{Policy: policy, Status: constants.PirepValidationSkipped}
+	}
+
+	// Unrestricted modes pass even when the pilot isn't found on the Live API
+	result := s.validator.ValidateFlightForMode(ctx, flight, &modeConfig.Validations)
+	if result.Valid {
+		return &flightValidation{Policy: policy, Status: constants.PirepValidationPassed}
+	}
+
+	reason := result.ErrorMsg
+	if flight == nil {
+		reason = "No live or recently completed flight found for your callsign; this mode requires a flight on an allowed route"
+	}
+	return &flightValidation{Policy: policy, Status: constants.PirepValidationFailed, Reason: reason}
+}
+
+// getValidationPolicy retrieves the VA's submit-time validation policy, defaulting to warn
+func (s *PirepSubmissionService) getValidationPolicy(ctx context.Context, vaID string) string {
+	if s.configService == nil {
+		return constants.PirepValidationPolicyWarn
+	}
+	policy, _ := s.configService.GetConfigVal(ctx, vaID, common.ConfigKeyPirepValidationPolicy)
+	switch policy {
+	case constants.PirepValidationPolicyStrict, constants.PirepValidationPolicyOff:
+		return policy
+	default:
+		return constants.PirepValidationPolicyWarn
+	}
+}
+
+// findCompletedFlight looks up the pilot's flight in their Infinite Flight history.
+// Returns nil when the pilot's IF account is unknown or no recent flight is found.
+func (s *PirepSubmissionService) findCompletedFlight(ctx context.Context, user *gormModels.User, flightID string) *CompletedFlight {
+	if s.flightsService == nil {
+		return nil
+	}
+
+	ifUserID := ""
+	if user.IFApiID != nil {
+		ifUserID = *user.IFApiID
+	} else if user.IFCommunityID != "" {
+		if lookup, err := s.flightsService.getUserByIfcIDCached(user.IFCommunityID); err == nil && len(lookup.Result) > 0 {
+			ifUserID = lookup.Result[0].UserID
+		}
+	}
+	if ifUserID == "" {
+		log.Printf("[PirepSubmissionService] No Infinite Flight account linked for user %s, skipping flight history", user.ID)
+		return nil
+	}
+
+	completed, err := s.flightsService.FindCompletedFlight(ctx, ifUserID, flightID, flightHistoryLookback)
+	if err != nil {
+		log.Printf("[PirepSubmissionService] Could not derive flight from history: %v", err)
+		return nil
+	}
+	return completed
+}
+
+// applyFlightHistory records the derived flight on the PIREP and flags discrepancies
+// between what the pilot filed and what their flight history shows
+func (s *PirepSubmissionService) applyFlightHistory(
+	ctx context.Context,
+	pirep *gormModels.Pirep,
+	completed *CompletedFlight,
+	route *gormModels.RouteATSynced,
+) {
+	pirep.HistoryFlightID = &completed.FlightID
+	pirep.DerivedFlightTimeSeconds = &completed.BlockTimeSeconds
+	pirep.Landings = &completed.Landings
+	pirep.Violations = &completed.Violations
+	if completed.Origin != "" {
+		pirep.DerivedOrigin = &completed.Origin
+	}
+	if completed.Destination != "" {
+		pirep.DerivedDestination = &completed.Destination
+	}
+
+	var discrepancies []string
+
+	tolerance := s.getFlightTimeTolerance(ctx, pirep.VAID)
+	diff := pirep.FlightTimeSeconds - completed.BlockTimeSeconds
+	if diff < 0 {
+		diff = -diff
+	}
+	if completed.BlockTimeSeconds > 0 && diff > int(tolerance.Seconds()) {
+		discrepancies = append(discrepancies, fmt.Sprintf("Filed flight time %s differs from flown %s",
+			formatFlightTime(pirep.FlightTimeSeconds), formatFlightTime(completed.BlockTimeSeconds)))
+	}
+
+	if route != nil && route.Origin != "" && route.Destination != "" && completed.Origin != "" && completed.Destination != "" {
+		if !strings.EqualFold(route.Origin, completed.Origin) || !strings.EqualFold(route.Destination, completed.Destination) {
+			discrepancies = append(discrepancies, fmt.Sprintf("Filed route %s-%s but flew %s-%s",
+				route.Origin, route.Destination, completed.Origin, completed.Destination))
+		}
+	}
+
+	if len(discrepancies) > 0 {
+		joined := strings.Join(discrepancies, "; ")
+		pirep.HistoryDiscrepancies = &joined
+		log.Printf("[PirepSubmissionService] PIREP %s flagged against flight history: %s", pirep.ID, joined)
+	}
+}
+
+// getFlightTimeTolerance retrieves the VA's allowed gap between filed and flown time
+func (s *PirepSubmissionService) getFlightTimeTolerance(ctx context.Context, vaID string) time.Duration {
+	if s.configService == nil {
+		return defaultFlightTimeTolerance
+	}
+	val, ok := s.configService.GetConfigVal(ctx, vaID, common.ConfigKeyPirepFlightTimeToleranceMin)
+	if !ok || val == "" {
+		return defaultFlightTimeTolerance
+	}
+	minutes, err := strconv.Atoi(val)
+	if err != nil || minutes < 0 {
+		return defaultFlightTimeTolerance
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// buildOutboxEntry builds the provider payload for a PIREP and wraps it in an outbox entry
+// addressed to the VA's active provider, whose type is also returned.
+// Returns nil and no error when the VA has no provider (or no PIREP schema) configured.
+func (s *PirepSubmissionService) buildOutboxEntry(
+	ctx context.Context,
+	pirep *gormModels.Pirep,
+	request *dtos.PirepSubmitRequest,
+	modeConfig *dtos.FlightModeConfig,
+	user *gormModels.User,
+	userVARole *gormModels.UserVARole,
+	route *gormModels.RouteATSynced,
+	aircraft string,
+	airline string,
+	flightData *FlightData,
+) (*gormModels.PirepOutbox, string, error) {
+	// Load provider config and PIREP schema (with caching)
+	active, err := s.dataProviderConfigService.GetActiveProviderConfigCached(ctx, pirep.VAID)
+	if err != nil || active == nil {
+		log.Printf("[PirepSubmissionService] No active provider for VA %s, PIREP %s kept natively only", pirep.VAID, pirep.ID)
+		return nil, "", nil
+	}
+
+	pirepSchema := active.ConfigData.GetSchemaByType("pirep")
+	if pirepSchema == nil || !pirepSchema.Enabled {
+		log.Printf("[PirepSubmissionService] PIREP schema not configured for VA %s, skipping provider push", pirep.VAID)
+		return nil, "", nil
+	}
+
+	if userVARole.AirtablePilotID == nil || *userVARole.AirtablePilotID == "" {
+		return nil, active.ProviderType, fmt.Errorf("pilot record not linked to %s", active.ProviderType)
 	}
 
-	// STEP 7: BUILD PIREP OBJECT FOR AIRTABLE
 	pirepObj := s.buildPirepObject(
 		request,
 		modeConfig,
@@ -261,64 +586,32 @@ func (s *PirepSubmissionService) SubmitPirep(
 		airline,
 		pirepSchema,
 		flightData,
+		pirep.ID,
 	)
 
-	// STEP 8: SUBMIT TO AIRTABLE
-	// Log the complete PIREP object before submission
+	// Log the complete PIREP object before queuing
 	pirepJSON, _ := json.MarshalIndent(pirepObj, "", "  ")
-	log.Printf("[PirepSubmissionService] Submitting PIREP to Airtable:\n%s\n", string(pirepJSON))
-
-	// Set provider config in context for provider to use
-	ctx = context.WithValue(ctx, "provider_config", configData)
-
-	pirepID, err := s.submitToAirtable(ctx, pirepObj, pirepSchema)
-	if err != nil {
-		log.Printf("[PirepSubmissionService] Airtable submission error: %v", err)
-		return &dtos.PirepSubmitResponse{
-			Success:      false,
-			ErrorType:    "airtable_error",
-			ErrorMessage: "Failed to file PIREP with Airtable",
-		}, nil
-	}
+	log.Printf("[PirepSubmissionService] Queuing PIREP for %s:\n%s\n", active.ProviderType, string(pirepJSON))
 
-	log.Printf("[PirepSubmissionService] PIREP filed successfully: %s", pirepID)
-	return &dtos.PirepSubmitResponse{
-		Success: true,
-		Message: "PIREP filed successfully",
-		PirepID: pirepID,
-	}, nil
+	return s.deliveryService.NewOutboxEntry(pirep.VAID, pirep.ID, active.ProviderType, pirepObj), active.ProviderType, nil
 }
 
 // getModeConfig extracts and validates a flight mode configuration
 func (s *PirepSubmissionService) getModeConfig(va *gormModels.VA, modeID string) (*dtos.FlightModeConfig, error) {
-	if va.FlightModesConfig == nil || len(va.FlightModesConfig) == 0 {
-		return nil, fmt.Errorf("no flight modes configured")
-	}
-
-	flightModes, ok := va.FlightModesConfig["flight_modes"].(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("invalid flight modes structure")
+	config, err := LoadFlightModesConfig(va)
+	if err != nil {
+		return nil, err
 	}
 
-	modeData, ok := flightModes[modeID].(map[string]interface{})
+	mode, ok := config.FlightModes[modeID]
 	if !ok {
 		return nil, fmt.Errorf("mode not found: %s", modeID)
 	}
-
-	// Check if enabled
-	enabled, _ := modeData["enabled"].(bool)
-	if !enabled {
+	if !mode.Enabled {
 		return nil, fmt.Errorf("mode not enabled: %s", modeID)
 	}
 
-	// Convert to FlightModeConfig struct
-	modeConfigJSON, _ := json.Marshal(modeData)
-	var config dtos.FlightModeConfig
-	if err := json.Unmarshal(modeConfigJSON, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse mode config: %w", err)
-	}
-
-	return &config, nil
+	return &mode, nil
 }
 
 // validateRequiredFields checks that all required fields in the request are present
@@ -330,16 +623,12 @@ func (s *PirepSubmissionService) validateRequiredFields(
 		return fmt.Errorf("mode is required")
 	}
 
-	if request.FlightTime == "" {
-		return fmt.Errorf("flight_time is required")
-	}
-
 	// Check for mode-specific required fields
 	for _, field := range modeConfig.Fields {
 		if field.Required {
 			switch field.Name {
 			case "flight_time":
-				// Already checked above
+				// May be derived from flight history; checked once the pilot's flight is known
 			case "fuel_kg":
 				if request.FuelKg == nil {
 					return fmt.Errorf("missing required field: fuel_kg")
@@ -359,12 +648,12 @@ func (s *PirepSubmissionService) validateRequiredFields(
 	return nil
 }
 
-// resolveAutoRoute finds an auto-route by name (normalized: trimmed, case-insensitive)
+// resolveAutoRoute finds an active catalogue route by name (normalized: trimmed, case-insensitive)
 func (s *PirepSubmissionService) resolveAutoRoute(ctx context.Context, vaID string, routeName string) (*gormModels.RouteATSynced, error) {
 	// Normalize: trim spaces
 	normalizedRouteName := strings.TrimSpace(routeName)
 
-	route, err := s.routeRepo.FindByName(ctx, vaID, normalizedRouteName)
+	route, err := s.routeRepo.FindActiveByName(ctx, vaID, normalizedRouteName, time.Now())
 	if err != nil || route == nil {
 		return nil, fmt.Errorf("auto-route not found: %s", routeName)
 	}
@@ -391,6 +680,7 @@ func (s *PirepSubmissionService) buildPirepObject(
 	airline string,
 	pirepSchema *dtos.EntitySchema,
 	flightData *FlightData,
+	idempotencyKey string,
 ) map[string]interface{} {
 	pirepObj := make(map[string]interface{})
 
@@ -430,9 +720,9 @@ func (s *PirepSubmissionService) buildPirepObject(
 		pirepObj[airlineField] = airline
 	}
 
-	if route != nil {
+	if route != nil && route.ATID != nil {
 		if routeField := getFieldName("route_at_id"); routeField != "" {
-			pirepObj[routeField] = []string{route.ATID}
+			pirepObj[routeField] = []string{*route.ATID}
 		}
 	}
 
@@ -442,8 +732,8 @@ func (s *PirepSubmissionService) buildPirepObject(
 
 	// Flight time with multiplier
 	if flightTimeField := getFieldName("flight_time"); flightTimeField != "" {
-		flightTimeSeconds := s.parseFlightTime(request.FlightTime)
-		multiplier := s.getMultiplier(modeConfig)
+		flightTimeSeconds := parseFlightTime(request.FlightTime)
+		multiplier := s.getMultiplier(modeConfig, route)
 		pirepObj[flightTimeField] = int(float64(flightTimeSeconds) * multiplier)
 	}
 
@@ -476,7 +766,7 @@ func (s *PirepSubmissionService) buildPirepObject(
 
 	// Append bot metadata if configured
 	if botMetadataFieldName != "" && botMetadataFieldName == remarksField && flightData != nil {
-		botMetadata := s.buildBotMetadataSection(request, modeConfig, flightData)
+		botMetadata := s.buildBotMetadataSection(request, modeConfig, route, flightData)
 		if botMetadata != "" {
 			if remarksValue != "" {
 				remarksValue = remarksValue + "\n\n" + botMetadata
@@ -490,6 +780,11 @@ func (s *PirepSubmissionService) buildPirepObject(
 		pirepObj[remarksField] = remarksValue
 	}
 
+	// Idempotency key lets outbox retries find a record an earlier attempt already created
+	if keyField := getFieldName(idempotencyKeyField); keyField != "" && idempotencyKey != "" {
+		pirepObj[keyField] = idempotencyKey
+	}
+
 	return pirepObj
 }
 
@@ -526,8 +821,13 @@ func (s *PirepSubmissionService) resolveLiveryMapping(ctx context.Context, vaID
 	return mappings, nil
 }
 
+// formatFlightTime converts seconds to "HH:MM" format
+func formatFlightTime(seconds int) string {
+	return fmt.Sprintf("%02d:%02d", seconds/3600, (seconds%3600)/60)
+}
+
 // parseFlightTime converts "HH:MM" format to seconds
-func (s *PirepSubmissionService) parseFlightTime(flightTime string) int {
+func parseFlightTime(flightTime string) int {
 	parts := strings.Split(flightTime, ":")
 	if len(parts) != 2 {
 		return 0
@@ -538,23 +838,22 @@ func (s *PirepSubmissionService) parseFlightTime(flightTime string) int {
 	return (hours * 3600) + (minutes * 60)
 }
 
-// getMultiplier extracts the multiplier from mode config metadata
-func (s *PirepSubmissionService) getMultiplier(modeConfig *dtos.FlightModeConfig) float64 {
-	if modeConfig.Metadata != nil {
-		if m, ok := modeConfig.Metadata["multiplier"].(float64); ok {
-			return m
-		}
+// getMultiplier returns the route's catalogue multiplier, falling back to the mode config
+func (s *PirepSubmissionService) getMultiplier(modeConfig *dtos.FlightModeConfig, route *gormModels.RouteATSynced) float64 {
+	modeMultiplier := 1.0
+	if m, ok := modeConfig.Metadata["multiplier"].(float64); ok {
+		modeMultiplier = m
+	} else if modeConfig.AutoRoute != nil {
+		modeMultiplier = modeConfig.AutoRoute.Multiplier
 	}
-	if modeConfig.AutoRoute != nil {
-		return modeConfig.AutoRoute.Multiplier
-	}
-	return 1.0
+	return RouteMultiplier(route, modeMultiplier)
 }
 
 // buildBotMetadataSection constructs the bot enriched metadata section for pilot remarks
 func (s *PirepSubmissionService) buildBotMetadataSection(
 	request *dtos.PirepSubmitRequest,
 	modeConfig *dtos.FlightModeConfig,
+	route *gormModels.RouteATSynced,
 	flightData *FlightData,
 ) string {
 	var metadata []string
@@ -566,7 +865,7 @@ func (s *PirepSubmissionService) buildBotMetadataSection(
 	}
 
 	// Add multiplier
-	multiplier := s.getMultiplier(modeConfig)
+	multiplier := s.getMultiplier(modeConfig, route)
 	metadata = append(metadata, fmt.Sprintf("Multiplier: %.1f", multiplier))
 
 	// Add route
@@ -615,25 +914,6 @@ func (s *PirepSubmissionService) getCallsignSuffix(ctx context.Context, vaID str
 	return suffix
 }
 
-// submitToAirtable creates a new PIREP record in Airtable using the provider
-func (s *PirepSubmissionService) submitToAirtable(
-	ctx context.Context,
-	pirepObj map[string]interface{},
-	pirepSchema *dtos.EntitySchema,
-) (string, error) {
-	log.Printf("[PirepSubmissionService] Submitting PIREP to Airtable via provider")
-	log.Printf("[PirepSubmissionService] Table: %s, Fields: %v", pirepSchema.TableName, pirepObj)
-
-	// Use the DataProvider to submit the record
-	recordID, err := s.airtableProvider.SubmitRecord(ctx, pirepSchema, pirepObj)
-	if err != nil {
-		return "", fmt.Errorf("failed to submit record via provider: %w", err)
-	}
-
-	log.Printf("[PirepSubmissionService] Record submitted successfully: %s", recordID)
-	return recordID, nil
-}
-
 // min returns the minimum of two integers
 func min(a, b int) int {
 	if a < b {
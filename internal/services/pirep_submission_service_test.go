@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"infinite-experiment/politburo/internal/common"
+	"infinite-experiment/politburo/internal/constants"
+	"infinite-experiment/politburo/internal/models/dtos"
+	"testing"
+	"time"
+)
+
+// newTestSubmissionService creates a submission service whose VA uses the given validation policy
+func newTestSubmissionService(t *testing.T, policy string) *PirepSubmissionService {
+	validator := newTestFlightModeValidationService(t)
+
+	cache := common.NewCacheService(60, 60)
+	t.Cleanup(func() { cache.Close() })
+	cache.Set(string(constants.CachePrefixVAConfig)+testVAID, map[string]string{
+		common.ConfigKeyPirepValidationPolicy: policy,
+	}, time.Hour)
+
+	return &PirepSubmissionService{
+		validator:     validator,
+		configService: common.NewVAConfigService(nil, cache, nil),
+	}
+}
+
+func TestPirepSubmissionService_RevalidateFlight(t *testing.T) {
+	// A mode flown between EGLL and LFPG above 10,000 ft
+	mode := &dtos.FlightModeConfig{Validations: dtos.ValidationConfig{
+		ValidationMode: "exact_match",
+		AllowedRoutes:  []string{"EGLL-LFPG"},
+		MinAltitudeFt:  10000,
+	}}
+
+	live := &dtos.LiveFlight{Origin: "EGLL", Destination: "LFPG", Aircraft: "Airbus A320", AltitudeFt: 35000, SessionID: "session-expert"}
+	landed := &CompletedFlight{Origin: "EGLL", Destination: "LFPG", Aircraft: "Airbus A320", MaxAltitudeFt: 35000,
+		SessionID: "session-expert", Created: time.Now().Add(-2 * time.Hour)}
+	diverted := &CompletedFlight{Origin: "EGLL", Destination: "EHAM", Aircraft: "Airbus A320", MaxAltitudeFt: 35000,
+		SessionID: "session-expert", Created: time.Now().Add(-2 * time.Hour)}
+
+	tests := []struct {
+		name       string
+		policy     string
+		live       *dtos.LiveFlight
+		completed  *CompletedFlight
+		wantStatus string
+		wantBlocks bool
+	}{
+		{"strict, still flying", constants.PirepValidationPolicyStrict, live, nil, constants.PirepValidationPassed, false},
+		{"strict, filed after landing", constants.PirepValidationPolicyStrict, nil, landed, constants.PirepValidationPassed, false},
+		{"strict, landed off the allowed routes", constants.PirepValidationPolicyStrict, nil, diverted, constants.PirepValidationFailed, true},
+		{"strict, no flight found", constants.PirepValidationPolicyStrict, nil, nil, constants.PirepValidationFailed, true},
+		{"strict, live flight wins over history", constants.PirepValidationPolicyStrict, live, diverted, constants.PirepValidationPassed, false},
+
+		{"warn, filed after landing", constants.PirepValidationPolicyWarn, nil, landed, constants.PirepValidationPassed, false},
+		{"warn, landed off the allowed routes", constants.PirepValidationPolicyWarn, nil, diverted, constants.PirepValidationFailed, false},
+		{"warn, no flight found", constants.PirepValidationPolicyWarn, nil, nil, constants.PirepValidationFailed, false},
+		{"unknown policy warns", "sometimes", nil, diverted, constants.PirepValidationFailed, false},
+
+		{"off, landed off the allowed routes", constants.PirepValidationPolicyOff, nil, diverted, constants.PirepValidationSkipped, false},
+		{"off, no flight found", constants.PirepValidationPolicyOff, nil, nil, constants.PirepValidationSkipped, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestSubmissionService(t, tt.policy)
+
+			validation := service.revalidateFlight(context.Background(), testVAID, mode, validationSnapshot(tt.live, tt.completed))
+			if validation.Status != tt.wantStatus {
+				t.Errorf("status = %s (%s), want %s", validation.Status, validation.Reason, tt.wantStatus)
+			}
+			if validation.Blocks() != tt.wantBlocks {
+				t.Errorf("blocks = %t under policy %s, want %t", validation.Blocks(), validation.Policy, tt.wantBlocks)
+			}
+			if validation.Status == constants.PirepValidationFailed && validation.Reason == "" {
+				t.Error("failed without a reason")
+			}
+		})
+	}
+}
+
+func TestNewCompletedFlightSnapshot_UsesTheHighestAltitude(t *testing.T) {
+	service := newTestFlightModeValidationService(t)
+	config := &dtos.ValidationConfig{AllowAnyCurrentRoute: true, MinAltitudeFt: 10000}
+
+	// The snapshot is checked against the highest point of the flight, not where it ended
+	flown := &CompletedFlight{Origin: "EGLL", Destination: "LFPG", MaxAltitudeFt: 35000, Created: time.Now()}
+	if result := service.ValidateFlightForMode(context.Background(), NewCompletedFlightSnapshot(flown), config); !result.Valid {
+		t.Errorf("flight that reached 35000 ft failed: %s", result.ErrorMsg)
+	}
+
+	low := &CompletedFlight{Origin: "EGLL", Destination: "LFPG", MaxAltitudeFt: 5000, Created: time.Now()}
+	if result := service.ValidateFlightForMode(context.Background(), NewCompletedFlightSnapshot(low), config); result.Valid {
+		t.Error("flight that stayed at 5000 ft passed a 10000 ft minimum")
+	}
+}
@@ -0,0 +1,654 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"path"
+	"sort"
+	"strings"
+
+	"infinite-experiment/politburo/internal/db/repositories"
+	"infinite-experiment/politburo/internal/models/dtos"
+	gormModels "infinite-experiment/politburo/internal/models/gorm"
+)
+
+var (
+	// ErrPilotNotInVA is returned when the pilot is not a member of the VA
+	ErrPilotNotInVA = errors.New("pilot is not a member of this VA")
+	// ErrCheckrideNameRequired is returned when recording a checkride without a name
+	ErrCheckrideNameRequired = errors.New("checkride name is required")
+	// ErrCheckrideAlreadyRecorded is returned when the pilot has already passed the checkride
+	ErrCheckrideAlreadyRecorded = errors.New("checkride has already been recorded for this pilot")
+)
+
+// RankLadderError lists every problem found in a rank ladder
+type RankLadderError struct {
+	Problems []string
+}
+
+func (e *RankLadderError) Error() string {
+	return "invalid rank ladder: " + strings.Join(e.Problems, "; ")
+}
+
+// maxRankNameLength matches va_ranks.name and pilot_checkrides.name
+const maxRankNameLength = 100
+
+// RankService manages a VA's native rank ladder and promotes pilots automatically
+// from their approved PIREP totals and passed checkrides
+type RankService struct {
+	rankRepo  *repositories.RankRepo
+	pirepRepo *repositories.PirepRepo
+	roleRepo  *repositories.VAUserRoleRepository
+}
+
+// NewRankService creates a new RankService
+func NewRankService(
+	rankRepo *repositories.RankRepo,
+	pirepRepo *repositories.PirepRepo,
+	roleRepo *repositories.VAUserRoleRepository,
+) *RankService {
+	return &RankService{
+		rankRepo:  rankRepo,
+		pirepRepo: pirepRepo,
+		roleRepo:  roleRepo,
+	}
+}
+
+// ListRanks returns a VA's rank ladder, lowest rank first
+func (s *RankService) ListRanks(ctx context.Context, vaID string) ([]gormModels.VARank, error) {
+	ranks, err := s.rankRepo.ListByVA(ctx, vaID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch ranks: %w", err)
+	}
+	return ranks, nil
+}
+
+// SetLadder validates and replaces a VA's rank ladder, then promotes every pilot who now
+// qualifies for a higher rank. An empty ladder removes all ranks.
+func (s *RankService) SetLadder(ctx context.Context, vaID string, req []dtos.RankRequest) ([]gormModels.VARank, error) {
+	ranks, err := buildLadder(req)
+	if err != nil {
+		return nil, err
+	}
+
+	saved, err := s.rankRepo.ReplaceLadder(ctx, vaID, ranks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save ranks: %w", err)
+	}
+
+	log.Printf("[RankService] Saved %d ranks for VA %s", len(saved), vaID)
+
+	// Promotions are best-effort here; they are retried on the next approval or checkride
+	if promoted, err := s.EvaluateVA(ctx, vaID); err != nil {
+		log.Printf("[RankService] Failed to evaluate pilots of VA %s after ladder change: %v", vaID, err)
+	} else if promoted > 0 {
+		log.Printf("[RankService] Promoted %d pilots of VA %s after ladder change", promoted, vaID)
+	}
+
+	return saved, nil
+}
+
+// EvaluatePilot promotes a pilot to the highest rank they qualify for.
+// Pilots are never demoted; returns nil when no promotion was due.
+func (s *RankService) EvaluatePilot(ctx context.Context, vaID, userID string) (*gormModels.RankPromotion, error) {
+	return s.evaluatePilot(ctx, vaID, userID, nil)
+}
+
+// EvaluateApproval promotes a pilot after one of their PIREPs was approved. Each PIREP
+// earns at most one promotion, so evaluating the same approval again promotes no further.
+func (s *RankService) EvaluateApproval(ctx context.Context, vaID, userID, pirepID string) (*gormModels.RankPromotion, error) {
+	return s.evaluatePilot(ctx, vaID, userID, &pirepID)
+}
+
+// evaluatePilot promotes a pilot, recording the PIREP that earned the promotion if given
+func (s *RankService) evaluatePilot(ctx context.Context, vaID, userID string, pirepID *string) (*gormModels.RankPromotion, error) {
+	ladder, err := s.ListRanks(ctx, vaID)
+	if err != nil {
+		return nil, err
+	}
+	if len(ladder) == 0 {
+		return nil, nil
+	}
+
+	role, err := s.roleRepo.GetByUserAndVA(ctx, userID, vaID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPilotNotInVA, err)
+	}
+
+	totals, err := s.pirepRepo.ApprovedTotals(ctx, vaID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch pirep totals: %w", err)
+	}
+
+	checkrides, err := s.rankRepo.ListCheckrides(ctx, vaID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch checkrides: %w", err)
+	}
+
+	return s.promote(ctx, ladder, role, totals, checkrides, pirepID)
+}
+
+// EvaluateVA promotes every active pilot of a VA who qualifies for a higher rank.
+// Returns the number of pilots promoted.
+func (s *RankService) EvaluateVA(ctx context.Context, vaID string) (int, error) {
+	ladder, err := s.ListRanks(ctx, vaID)
+	if err != nil {
+		return 0, err
+	}
+	if len(ladder) == 0 {
+		return 0, nil
+	}
+
+	roles, totals, checkrides, err := s.loadVAStandings(ctx, vaID)
+	if err != nil {
+		return 0, err
+	}
+
+	promoted := 0
+	for i := range roles {
+		promotion, err := s.promote(ctx, ladder, &roles[i], totals[roles[i].UserID], checkrides[roles[i].UserID], nil)
+		if err != nil {
+			return promoted, err
+		}
+		if promotion != nil {
+			promoted++
+		}
+	}
+	return promoted, nil
+}
+
+// GetProgress returns a pilot's current rank and progress towards the next one
+func (s *RankService) GetProgress(ctx context.Context, vaID, userID string) (*dtos.PilotRankProgress, error) {
+	ladder, err := s.ListRanks(ctx, vaID)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := s.roleRepo.GetByUserAndVA(ctx, userID, vaID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPilotNotInVA, err)
+	}
+
+	totals, err := s.pirepRepo.ApprovedTotals(ctx, vaID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch pirep totals: %w", err)
+	}
+
+	checkrides, err := s.rankRepo.ListCheckrides(ctx, vaID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch checkrides: %w", err)
+	}
+
+	progress := buildRankProgress(ladder, role, totals, checkrides)
+	return &progress, nil
+}
+
+// ListProgress returns the rank progress of every active pilot of a VA,
+// highest rank first and then by credited hours
+func (s *RankService) ListProgress(ctx context.Context, vaID string) ([]dtos.PilotRankProgress, error) {
+	ladder, err := s.ListRanks(ctx, vaID)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, totals, checkrides, err := s.loadVAStandings(ctx, vaID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dtos.PilotRankProgress, 0, len(roles))
+	for i := range roles {
+		result = append(result, buildRankProgress(ladder, &roles[i], totals[roles[i].UserID], checkrides[roles[i].UserID]))
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		pi, pj := rankPosition(result[i].CurrentRank), rankPosition(result[j].CurrentRank)
+		if pi != pj {
+			return pi > pj
+		}
+		return result[i].CreditedHours > result[j].CreditedHours
+	})
+	return result, nil
+}
+
+// RecordCheckride records a passed checkride for a pilot and promotes them if it completes
+// the requirements of a higher rank
+func (s *RankService) RecordCheckride(
+	ctx context.Context,
+	vaID string,
+	userID string,
+	examinerID string,
+	req *dtos.CheckrideRequest,
+) (*gormModels.PilotCheckride, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > maxRankNameLength {
+		return nil, ErrCheckrideNameRequired
+	}
+
+	if _, err := s.roleRepo.GetByUserAndVA(ctx, userID, vaID); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPilotNotInVA, err)
+	}
+
+	existing, err := s.rankRepo.FindCheckride(ctx, vaID, userID, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for existing checkride: %w", err)
+	}
+	if existing != nil {
+		return nil, ErrCheckrideAlreadyRecorded
+	}
+
+	checkride := &gormModels.PilotCheckride{
+		VAID:   vaID,
+		UserID: userID,
+		Name:   name,
+	}
+	if examinerID != "" {
+		checkride.ExaminerID = &examinerID
+	}
+	if notes := strings.TrimSpace(req.Notes); notes != "" {
+		checkride.Notes = &notes
+	}
+
+	if err := s.rankRepo.CreateCheckride(ctx, checkride); err != nil {
+		return nil, fmt.Errorf("failed to record checkride: %w", err)
+	}
+
+	log.Printf("[RankService] Checkride '%s' recorded for user %s in VA %s", name, userID, vaID)
+
+	if _, err := s.EvaluatePilot(ctx, vaID, userID); err != nil {
+		log.Printf("[RankService] Failed to evaluate user %s after checkride: %v", userID, err)
+	}
+
+	return checkride, nil
+}
+
+// ListPromotions returns a VA's most recent promotion events, newest first
+func (s *RankService) ListPromotions(ctx context.Context, vaID string, limit int) ([]gormModels.RankPromotion, error) {
+	promotions, err := s.rankRepo.ListPromotions(ctx, vaID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch promotions: %w", err)
+	}
+	return promotions, nil
+}
+
+// LoadStanding returns the pilot's position on the VA's ladder, used to gate flight modes and aircraft
+func (s *RankService) LoadStanding(ctx context.Context, vaID, userID string) (*RankStanding, error) {
+	ladder, err := s.ListRanks(ctx, vaID)
+	if err != nil {
+		return nil, err
+	}
+	if len(ladder) == 0 {
+		return &RankStanding{current: -1}, nil
+	}
+
+	role, err := s.roleRepo.GetByUserAndVA(ctx, userID, vaID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPilotNotInVA, err)
+	}
+
+	return &RankStanding{ladder: ladder, current: rankIndex(ladder, role.RankID)}, nil
+}
+
+// promote moves the pilot up to the highest rank they qualify for and records the promotion
+// event. Returns nil when the PIREP already earned a promotion or another evaluation got there first.
+func (s *RankService) promote(
+	ctx context.Context,
+	ladder []gormModels.VARank,
+	role *gormModels.UserVARole,
+	totals repositories.PilotPirepTotals,
+	checkrides []gormModels.PilotCheckride,
+	pirepID *string,
+) (*gormModels.RankPromotion, error) {
+	hours := creditedHours(totals.CreditedSeconds)
+	current := rankIndex(ladder, role.RankID)
+	achieved := achievedRankIndex(ladder, hours, totals.Flights, passedCheckrides(checkrides))
+	if achieved <= current {
+		return nil, nil
+	}
+
+	promotion := &gormModels.RankPromotion{
+		VAID:          role.VAID,
+		UserID:        role.UserID,
+		FromRankID:    role.RankID,
+		ToRankID:      &ladder[achieved].ID,
+		PirepID:       pirepID,
+		CreditedHours: hours,
+		Flights:       totals.Flights,
+	}
+	promoted, err := s.rankRepo.Promote(ctx, promotion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to promote user %s: %w", role.UserID, err)
+	}
+	if !promoted {
+		log.Printf("[RankService] Skipped promotion of user %s in VA %s: already promoted", role.UserID, role.VAID)
+		return nil, nil
+	}
+
+	role.RankID = promotion.ToRankID
+	log.Printf("[RankService] Promoted user %s in VA %s to %s (%.1fh, %d flights)",
+		role.UserID, role.VAID, ladder[achieved].Name, hours, totals.Flights)
+
+	return promotion, nil
+}
+
+// loadVAStandings fetches everything needed to evaluate every active pilot of a VA
+func (s *RankService) loadVAStandings(ctx context.Context, vaID string) (
+	[]gormModels.UserVARole,
+	map[string]repositories.PilotPirepTotals,
+	map[string][]gormModels.PilotCheckride,
+	error,
+) {
+	roles, err := s.roleRepo.GetAllByVAID(ctx, vaID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	totals, err := s.pirepRepo.ApprovedTotalsByVA(ctx, vaID)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to fetch pirep totals: %w", err)
+	}
+
+	checkrides, err := s.rankRepo.ListCheckridesByVA(ctx, vaID)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to fetch checkrides: %w", err)
+	}
+
+	return roles, totals, checkrides, nil
+}
+
+// RankStanding is a pilot's position on a VA's rank ladder
+type RankStanding struct {
+	ladder  []gormModels.VARank
+	current int // Index into ladder, -1 when unranked
+}
+
+// AllowsMode reports whether the pilot's rank meets a flight mode's min_rank.
+// Modes without a min_rank, or naming a rank no longer on the ladder, are not gated.
+func (st *RankStanding) AllowsMode(minRank string) (bool, string) {
+	minRank = strings.TrimSpace(minRank)
+	if minRank == "" {
+		return true, ""
+	}
+
+	required := -1
+	for i := range st.ladder {
+		if strings.EqualFold(st.ladder[i].Name, minRank) {
+			required = i
+			break
+		}
+	}
+	if required < 0 || st.current >= required {
+		return true, ""
+	}
+
+	return false, fmt.Sprintf("Requires rank %s or above", st.ladder[required].Name)
+}
+
+// AllowsAircraft reports whether the pilot's rank has unlocked an aircraft.
+// Each rank unlocks its allowed_aircraft for itself and every rank above it; when no rank
+// lists any aircraft the ladder does not gate aircraft at all.
+func (st *RankStanding) AllowsAircraft(aircraft string) (bool, string) {
+	if aircraft == "" {
+		return true, ""
+	}
+
+	gated := false
+	for i := range st.ladder {
+		if len(st.ladder[i].AllowedAircraft) == 0 {
+			continue
+		}
+		gated = true
+		if i <= st.current && matchesAnyPattern(st.ladder[i].AllowedAircraft, aircraft) {
+			return true, ""
+		}
+	}
+	if !gated {
+		return true, ""
+	}
+
+	for i := st.current + 1; i < len(st.ladder); i++ {
+		if matchesAnyPattern(st.ladder[i].AllowedAircraft, aircraft) {
+			return false, fmt.Sprintf("The %s requires rank %s or above", aircraft, st.ladder[i].Name)
+		}
+	}
+	return false, fmt.Sprintf("The %s is not available at any rank", aircraft)
+}
+
+// RankDetail maps a rank onto its API representation
+func RankDetail(rank *gormModels.VARank) dtos.Rank {
+	return dtos.Rank{
+		ID:                 rank.ID,
+		Name:               rank.Name,
+		Position:           rank.Position,
+		MinHours:           rank.MinHours,
+		MinFlights:         rank.MinFlights,
+		RequiredCheckrides: nonNilStrings(rank.RequiredCheckrides),
+		AllowedAircraft:    nonNilStrings(rank.AllowedAircraft),
+	}
+}
+
+// buildLadder validates a ladder request and converts it into ranks, lowest first.
+// Hour and flight requirements must not decrease up the ladder.
+func buildLadder(req []dtos.RankRequest) ([]gormModels.VARank, error) {
+	var problems []string
+	addf := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	seen := make(map[string]bool, len(req))
+	ranks := make([]gormModels.VARank, 0, len(req))
+	for i, r := range req {
+		name := strings.TrimSpace(r.Name)
+		switch {
+		case name == "":
+			addf("rank %d: 'name' is required", i+1)
+		case len(name) > maxRankNameLength:
+			addf("rank '%s': name must be at most %d characters", name, maxRankNameLength)
+		case seen[strings.ToLower(name)]:
+			addf("rank '%s' is listed more than once", name)
+		}
+		seen[strings.ToLower(name)] = true
+
+		if r.MinHours < 0 {
+			addf("rank '%s': min_hours must not be negative", name)
+		}
+		if r.MinFlights < 0 {
+			addf("rank '%s': min_flights must not be negative", name)
+		}
+		if i > 0 {
+			prev := req[i-1]
+			if r.MinHours < prev.MinHours {
+				addf("rank '%s' requires fewer hours than '%s' below it", name, strings.TrimSpace(prev.Name))
+			}
+			if r.MinFlights < prev.MinFlights {
+				addf("rank '%s' requires fewer flights than '%s' below it", name, strings.TrimSpace(prev.Name))
+			}
+		}
+
+		checkrides := cleanNames(r.RequiredCheckrides)
+		for _, c := range checkrides {
+			if len(c) > maxRankNameLength {
+				addf("rank '%s': checkride '%s' must be at most %d characters", name, c, maxRankNameLength)
+			}
+		}
+
+		aircraft := cleanNames(r.AllowedAircraft)
+		for _, a := range aircraft {
+			if _, err := path.Match(a, ""); err != nil {
+				addf("rank '%s': invalid aircraft pattern '%s'", name, a)
+			}
+		}
+
+		ranks = append(ranks, gormModels.VARank{
+			Name:               name,
+			Position:           i + 1,
+			MinHours:           math.Round(r.MinHours*10) / 10,
+			MinFlights:         r.MinFlights,
+			RequiredCheckrides: checkrides,
+			AllowedAircraft:    aircraft,
+		})
+	}
+
+	if len(problems) > 0 {
+		return nil, &RankLadderError{Problems: problems}
+	}
+	return ranks, nil
+}
+
+// buildRankProgress computes a pilot's progress towards the rank above their current one
+func buildRankProgress(
+	ladder []gormModels.VARank,
+	role *gormModels.UserVARole,
+	totals repositories.PilotPirepTotals,
+	checkrides []gormModels.PilotCheckride,
+) dtos.PilotRankProgress {
+	hours := creditedHours(totals.CreditedSeconds)
+	passed := passedCheckrides(checkrides)
+
+	progress := dtos.PilotRankProgress{
+		UserID:         role.UserID,
+		Callsign:       role.Callsign,
+		RankAchievedAt: role.RankAchievedAt,
+		CreditedHours:  hours,
+		Flights:        totals.Flights,
+		Checkrides:     make([]string, 0, len(checkrides)),
+	}
+	if role.User.UserName != nil {
+		progress.Username = *role.User.UserName
+	}
+	for _, c := range checkrides {
+		progress.Checkrides = append(progress.Checkrides, c.Name)
+	}
+
+	current := rankIndex(ladder, role.RankID)
+	if current >= 0 {
+		rank := RankDetail(&ladder[current])
+		progress.CurrentRank = &rank
+	}
+	if current+1 >= len(ladder) {
+		if len(ladder) > 0 {
+			progress.Progress = 100
+		}
+		return progress
+	}
+
+	next := &ladder[current+1]
+	nextRank := RankDetail(next)
+	progress.NextRank = &nextRank
+
+	if next.MinHours > 0 {
+		progress.Requirements = append(progress.Requirements, dtos.RankRequirement{
+			Type: "hours", Required: next.MinHours, Current: hours, Met: hours >= next.MinHours,
+		})
+	}
+	if next.MinFlights > 0 {
+		progress.Requirements = append(progress.Requirements, dtos.RankRequirement{
+			Type: "flights", Required: float64(next.MinFlights), Current: float64(totals.Flights), Met: totals.Flights >= next.MinFlights,
+		})
+	}
+	for _, name := range next.RequiredCheckrides {
+		req := dtos.RankRequirement{Type: "checkride", Name: name, Required: 1}
+		if passed[strings.ToLower(name)] {
+			req.Current, req.Met = 1, true
+		}
+		progress.Requirements = append(progress.Requirements, req)
+	}
+
+	// Average of each requirement's completion; a rank without requirements is complete
+	if len(progress.Requirements) == 0 {
+		progress.Progress = 100
+		return progress
+	}
+	sum := 0.0
+	for _, req := range progress.Requirements {
+		sum += math.Min(req.Current/req.Required, 1)
+	}
+	progress.Progress = int(math.Floor(sum / float64(len(progress.Requirements)) * 100))
+
+	return progress
+}
+
+// achievedRankIndex returns the index of the highest rank whose requirements are all met, or -1
+func achievedRankIndex(ladder []gormModels.VARank, hours float64, flights int, passed map[string]bool) int {
+	achieved := -1
+	for i := range ladder {
+		if meetsRank(&ladder[i], hours, flights, passed) {
+			achieved = i
+		}
+	}
+	return achieved
+}
+
+// meetsRank reports whether the totals and passed checkrides satisfy a rank's requirements
+func meetsRank(rank *gormModels.VARank, hours float64, flights int, passed map[string]bool) bool {
+	if hours < rank.MinHours || flights < rank.MinFlights {
+		return false
+	}
+	for _, name := range rank.RequiredCheckrides {
+		if !passed[strings.ToLower(name)] {
+			return false
+		}
+	}
+	return true
+}
+
+// rankIndex returns the ladder index of a rank ID, or -1 when unranked or the rank is gone
+func rankIndex(ladder []gormModels.VARank, rankID *string) int {
+	if rankID == nil {
+		return -1
+	}
+	for i := range ladder {
+		if ladder[i].ID == *rankID {
+			return i
+		}
+	}
+	return -1
+}
+
+// rankPosition returns a rank's position for sorting, 0 when unranked
+func rankPosition(rank *dtos.Rank) int {
+	if rank == nil {
+		return 0
+	}
+	return rank.Position
+}
+
+// passedCheckrides indexes checkride names case-insensitively
+func passedCheckrides(checkrides []gormModels.PilotCheckride) map[string]bool {
+	passed := make(map[string]bool, len(checkrides))
+	for _, c := range checkrides {
+		passed[strings.ToLower(c.Name)] = true
+	}
+	return passed
+}
+
+// creditedHours converts credited seconds to hours rounded to one decimal
+func creditedHours(seconds int64) float64 {
+	return math.Round(float64(seconds)/360) / 10
+}
+
+// cleanNames trims a list of names, dropping blanks and case-insensitive duplicates
+func cleanNames(names []string) []string {
+	cleaned := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, n := range names {
+		n = strings.TrimSpace(n)
+		if n == "" || seen[strings.ToLower(n)] {
+			continue
+		}
+		seen[strings.ToLower(n)] = true
+		cleaned = append(cleaned, n)
+	}
+	return cleaned
+}
+
+// nonNilStrings returns an empty slice instead of nil so lists encode as []
+func nonNilStrings(list []string) []string {
+	if list == nil {
+		return []string{}
+	}
+	return list
+}
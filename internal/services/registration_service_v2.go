@@ -6,20 +6,25 @@ import (
 	"infinite-experiment/politburo/internal/constants"
 	"infinite-experiment/politburo/internal/models/dtos"
 	gormModels "infinite-experiment/politburo/internal/models/gorm"
-	"infinite-experiment/politburo/internal/providers"
 	"log"
 
 	"gorm.io/gorm"
 )
 
+// RegistrationLiveAPI is the part of the Live API that registration validates users against
+type RegistrationLiveAPI interface {
+	GetUserByIfcId(ctx context.Context, ifcId string) (*dtos.UserStatsResponse, int, error)
+	GetUserFlights(ctx context.Context, userID string, page int) (*dtos.UserFlightsResponse, int, error)
+}
+
 // RegistrationServiceV2 handles user registration using GORM and provider pattern
 type RegistrationServiceV2 struct {
 	db              *gorm.DB
-	liveAPIProvider *providers.LiveAPIProvider
+	liveAPIProvider RegistrationLiveAPI
 }
 
 // NewRegistrationServiceV2 creates a new V2 registration service
-func NewRegistrationServiceV2(db *gorm.DB, liveAPIProvider *providers.LiveAPIProvider) *RegistrationServiceV2 {
+func NewRegistrationServiceV2(db *gorm.DB, liveAPIProvider RegistrationLiveAPI) *RegistrationServiceV2 {
 	return &RegistrationServiceV2{
 		db:              db,
 		liveAPIProvider: liveAPIProvider,
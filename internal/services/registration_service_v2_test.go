@@ -9,12 +9,13 @@ import (
 
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 )
 
 // Mock LiveAPIProvider
 type mockLiveAPIProvider struct {
-	getUserByIfcIdFunc  func(ctx context.Context, ifcId string) (*dtos.UserStatsResponse, int, error)
-	getUserFlightsFunc  func(ctx context.Context, userID string, page int) (*dtos.UserFlightsResponse, int, error)
+	getUserByIfcIdFunc func(ctx context.Context, ifcId string) (*dtos.UserStatsResponse, int, error)
+	getUserFlightsFunc func(ctx context.Context, userID string, page int) (*dtos.UserFlightsResponse, int, error)
 }
 
 func (m *mockLiveAPIProvider) GetUserByIfcId(ctx context.Context, ifcId string) (*dtos.UserStatsResponse, int, error) {
@@ -27,13 +28,16 @@ func (m *mockLiveAPIProvider) GetUserFlights(ctx context.Context, userID string,
 
 // Setup test database
 func setupTestDB(t *testing.T) *gorm.DB {
-	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
 	if err != nil {
 		t.Fatalf("Failed to open test database: %v", err)
 	}
 
-	// Auto migrate
-	if err := db.AutoMigrate(&gormModels.User{}); err != nil {
+	// The models' Postgres types and defaults don't migrate to SQLite
+	err = db.Exec(`CREATE TABLE users (id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(16)))), discord_id TEXT UNIQUE,
+		if_community_id TEXT, if_api_id TEXT, is_active BOOLEAN DEFAULT FALSE, username TEXT, otp TEXT,
+		created_at DATETIME, updated_at DATETIME)`).Error
+	if err != nil {
 		t.Fatalf("Failed to migrate: %v", err)
 	}
 
@@ -67,7 +71,7 @@ func TestRegistrationServiceV2_InitUserRegistration_Success(t *testing.T) {
 	service := NewRegistrationServiceV2(db, mockProvider)
 
 	ctx := context.Background()
-	response, err := service.InitUserRegistration(ctx, "discord-123", "testuser", "KJFK-KLAX")
+	response, err := service.InitUserRegistration(ctx, "discord-123", "server-123", "testuser", "KJFK-KLAX", nil)
 
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
@@ -115,7 +119,7 @@ func TestRegistrationServiceV2_InitUserRegistration_DuplicateUser(t *testing.T)
 	service := NewRegistrationServiceV2(db, mockProvider)
 
 	ctx := context.Background()
-	response, err := service.InitUserRegistration(ctx, "discord-123", "testuser", "KJFK-KLAX")
+	response, err := service.InitUserRegistration(ctx, "discord-123", "server-123", "testuser", "KJFK-KLAX", nil)
 
 	if err == nil {
 		t.Error("Expected error for duplicate user")
@@ -153,7 +157,7 @@ func TestRegistrationServiceV2_InitUserRegistration_UserNotFoundInAPI(t *testing
 	service := NewRegistrationServiceV2(db, mockProvider)
 
 	ctx := context.Background()
-	response, err := service.InitUserRegistration(ctx, "discord-123", "nonexistent", "KJFK-KLAX")
+	response, err := service.InitUserRegistration(ctx, "discord-123", "server-123", "nonexistent", "KJFK-KLAX", nil)
 
 	if err == nil {
 		t.Error("Expected error for user not found")
@@ -191,7 +195,7 @@ func TestRegistrationServiceV2_InitUserRegistration_FlightMismatch(t *testing.T)
 	service := NewRegistrationServiceV2(db, mockProvider)
 
 	ctx := context.Background()
-	response, err := service.InitUserRegistration(ctx, "discord-123", "testuser", "KJFK-KLAX")
+	response, err := service.InitUserRegistration(ctx, "discord-123", "server-123", "testuser", "KJFK-KLAX", nil)
 
 	if err == nil {
 		t.Error("Expected error for flight mismatch")
@@ -224,7 +228,7 @@ func TestRegistrationServiceV2_InitUserRegistration_NoRecentFlights(t *testing.T
 	service := NewRegistrationServiceV2(db, mockProvider)
 
 	ctx := context.Background()
-	response, err := service.InitUserRegistration(ctx, "discord-123", "testuser", "KJFK-KLAX")
+	response, err := service.InitUserRegistration(ctx, "discord-123", "server-123", "testuser", "KJFK-KLAX", nil)
 
 	if err == nil {
 		t.Error("Expected error for no flights")
@@ -247,7 +251,7 @@ func TestRegistrationServiceV2_InitUserRegistration_APIError(t *testing.T) {
 	service := NewRegistrationServiceV2(db, mockProvider)
 
 	ctx := context.Background()
-	response, err := service.InitUserRegistration(ctx, "discord-123", "testuser", "KJFK-KLAX")
+	response, err := service.InitUserRegistration(ctx, "discord-123", "server-123", "testuser", "KJFK-KLAX", nil)
 
 	if err == nil {
 		t.Error("Expected error for API failure")
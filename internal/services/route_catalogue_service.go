@@ -0,0 +1,579 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"infinite-experiment/politburo/internal/common"
+	"infinite-experiment/politburo/internal/constants"
+	"infinite-experiment/politburo/internal/db/repositories"
+	"infinite-experiment/politburo/internal/models/dtos"
+	gormModels "infinite-experiment/politburo/internal/models/gorm"
+)
+
+var (
+	ErrRouteNotFound          = errors.New("route not found")
+	ErrRouteManagedByProvider = errors.New("route is synced from the VA's data provider")
+)
+
+// RouteCatalogueError lists every problem found in a route or a CSV import
+type RouteCatalogueError struct {
+	Problems []string
+}
+
+func (e *RouteCatalogueError) Error() string {
+	return "invalid route: " + strings.Join(e.Problems, "; ")
+}
+
+// routeDateLayout is the format of active_from/active_until in requests and CSV files
+const routeDateLayout = "2006-01-02"
+
+// routeCSVColumns is the CSV layout used for export; import accepts the same columns in any order
+var routeCSVColumns = []string{
+	"route", "origin", "destination", "flight_number", "aircraft",
+	"distance_nm", "block_time_minutes", "multiplier", "active_from", "active_until", "is_active",
+}
+
+var icaoPattern = regexp.MustCompile(`^[A-Z0-9]{4}$`)
+
+// RouteCatalogueService manages a VA's route catalogue.
+// Routes synced from Airtable and native routes live side by side; only native routes
+// can be created, renamed or deleted here, while both can carry catalogue details.
+type RouteCatalogueService struct {
+	routeRepo   *repositories.RouteATSyncedRepo
+	airportRepo *repositories.AirportRepository
+}
+
+// NewRouteCatalogueService creates a new route catalogue service
+func NewRouteCatalogueService(
+	routeRepo *repositories.RouteATSyncedRepo,
+	airportRepo *repositories.AirportRepository,
+) *RouteCatalogueService {
+	return &RouteCatalogueService{
+		routeRepo:   routeRepo,
+		airportRepo: airportRepo,
+	}
+}
+
+// ListRoutes returns every route in the VA's catalogue, including inactive ones
+func (s *RouteCatalogueService) ListRoutes(ctx context.Context, vaID string) ([]gormModels.RouteATSynced, error) {
+	return s.routeRepo.GetAllByVA(ctx, vaID)
+}
+
+// GetRoute returns a single route from the VA's catalogue
+func (s *RouteCatalogueService) GetRoute(ctx context.Context, vaID string, routeID string) (*gormModels.RouteATSynced, error) {
+	route, err := s.routeRepo.FindByID(ctx, vaID, routeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch route: %w", err)
+	}
+	if route == nil {
+		return nil, ErrRouteNotFound
+	}
+	return route, nil
+}
+
+// CreateRoute adds a native route to the VA's catalogue
+func (s *RouteCatalogueService) CreateRoute(ctx context.Context, vaID string, req *dtos.RouteRequest) (*gormModels.RouteATSynced, error) {
+	route := &gormModels.RouteATSynced{
+		ServerID: vaID,
+		Source:   constants.RouteSourceNative,
+	}
+	if problems := s.applyRequest(ctx, route, req); len(problems) > 0 {
+		return nil, &RouteCatalogueError{Problems: problems}
+	}
+
+	existing, err := s.routeRepo.FindByName(ctx, vaID, route.Route)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for duplicate route: %w", err)
+	}
+	if existing != nil {
+		return nil, &RouteCatalogueError{Problems: []string{fmt.Sprintf("route %s already exists", route.Route)}}
+	}
+
+	if err := s.routeRepo.Create(ctx, route); err != nil {
+		return nil, fmt.Errorf("failed to create route: %w", err)
+	}
+
+	log.Printf("[RouteCatalogueService] Created route %s for VA %s", route.Route, vaID)
+	return route, nil
+}
+
+// UpdateRoute replaces a route's details. Routes synced from Airtable keep their
+// route name, origin and destination; only catalogue details can be changed.
+func (s *RouteCatalogueService) UpdateRoute(ctx context.Context, vaID string, routeID string, req *dtos.RouteRequest) (*gormModels.RouteATSynced, error) {
+	route, err := s.GetRoute(ctx, vaID, routeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if problems := s.applyRequest(ctx, route, req); len(problems) > 0 {
+		return nil, &RouteCatalogueError{Problems: problems}
+	}
+
+	if existing, err := s.routeRepo.FindByName(ctx, vaID, route.Route); err != nil {
+		return nil, fmt.Errorf("failed to check for duplicate route: %w", err)
+	} else if existing != nil && existing.ID != route.ID {
+		return nil, &RouteCatalogueError{Problems: []string{fmt.Sprintf("route %s already exists", route.Route)}}
+	}
+
+	if err := s.routeRepo.Save(ctx, route); err != nil {
+		return nil, fmt.Errorf("failed to update route: %w", err)
+	}
+
+	log.Printf("[RouteCatalogueService] Updated route %s for VA %s", route.Route, vaID)
+	return route, nil
+}
+
+// SetRouteActive enables or disables a route without touching its other details
+func (s *RouteCatalogueService) SetRouteActive(ctx context.Context, vaID string, routeID string, active bool) (*gormModels.RouteATSynced, error) {
+	route, err := s.GetRoute(ctx, vaID, routeID)
+	if err != nil {
+		return nil, err
+	}
+
+	route.IsActive = active
+	if err := s.routeRepo.Save(ctx, route); err != nil {
+		return nil, fmt.Errorf("failed to update route: %w", err)
+	}
+
+	log.Printf("[RouteCatalogueService] Set route %s active=%t for VA %s", route.Route, active, vaID)
+	return route, nil
+}
+
+// DeleteRoute removes a native route. Airtable routes are recreated by the next sync,
+// so they are deactivated with is_active instead.
+func (s *RouteCatalogueService) DeleteRoute(ctx context.Context, vaID string, routeID string) error {
+	route, err := s.GetRoute(ctx, vaID, routeID)
+	if err != nil {
+		return err
+	}
+	if route.Source != constants.RouteSourceNative {
+		return ErrRouteManagedByProvider
+	}
+
+	if err := s.routeRepo.Delete(ctx, vaID, routeID); err != nil {
+		return fmt.Errorf("failed to delete route: %w", err)
+	}
+
+	log.Printf("[RouteCatalogueService] Deleted route %s for VA %s", route.Route, vaID)
+	return nil
+}
+
+// ImportCSV creates or updates routes from a CSV file with a header row.
+// Rows are matched to existing routes by route name. The import is all-or-nothing:
+// if any row is invalid nothing is saved and every problem is reported with its line number.
+func (s *RouteCatalogueService) ImportCSV(ctx context.Context, vaID string, r io.Reader) (*dtos.RouteImportResult, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, &RouteCatalogueError{Problems: []string{fmt.Sprintf("invalid CSV: %v", err)}}
+	}
+	if len(records) < 2 {
+		return nil, &RouteCatalogueError{Problems: []string{"CSV must contain a header row and at least one route"}}
+	}
+
+	columns, problems := parseRouteCSVHeader(records[0])
+	if len(problems) > 0 {
+		return nil, &RouteCatalogueError{Problems: problems}
+	}
+
+	existing, err := s.routeRepo.GetAllByVA(ctx, vaID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch existing routes: %w", err)
+	}
+	byName := make(map[string]*gormModels.RouteATSynced, len(existing))
+	for i := range existing {
+		byName[strings.ToLower(existing[i].Route)] = &existing[i]
+	}
+
+	result := &dtos.RouteImportResult{}
+	seen := make(map[string]int)
+	var toSave []*gormModels.RouteATSynced
+
+	for i, record := range records[1:] {
+		line := i + 2 // 1-based, after the header
+		req, rowProblems := routeRequestFromCSV(columns, record)
+
+		name := strings.ToUpper(strings.TrimSpace(req.Route))
+		if name == "" {
+			name = strings.ToUpper(strings.TrimSpace(req.Origin)) + "-" + strings.ToUpper(strings.TrimSpace(req.Destination))
+		}
+
+		route, ok := byName[strings.ToLower(name)]
+		if ok {
+			result.Updated++
+		} else {
+			route = &gormModels.RouteATSynced{ServerID: vaID, Source: constants.RouteSourceNative}
+			result.Created++
+		}
+
+		rowProblems = append(rowProblems, s.applyRequest(ctx, route, req)...)
+
+		if prev, dup := seen[strings.ToLower(route.Route)]; dup {
+			rowProblems = append(rowProblems, fmt.Sprintf("duplicate of line %d", prev))
+		}
+		seen[strings.ToLower(route.Route)] = line
+
+		for _, p := range rowProblems {
+			problems = append(problems, fmt.Sprintf("line %d: %s", line, p))
+		}
+		toSave = append(toSave, route)
+	}
+
+	if len(problems) > 0 {
+		return nil, &RouteCatalogueError{Problems: problems}
+	}
+
+	if err := s.routeRepo.SaveAll(ctx, toSave); err != nil {
+		return nil, fmt.Errorf("failed to save routes: %w", err)
+	}
+
+	log.Printf("[RouteCatalogueService] Imported routes for VA %s: %d created, %d updated", vaID, result.Created, result.Updated)
+	return result, nil
+}
+
+// ExportCSV writes the VA's full route catalogue as CSV in the layout accepted by ImportCSV
+func (s *RouteCatalogueService) ExportCSV(ctx context.Context, vaID string, w io.Writer) error {
+	routes, err := s.routeRepo.GetAllByVA(ctx, vaID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch routes: %w", err)
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(routeCSVColumns); err != nil {
+		return err
+	}
+
+	for _, route := range routes {
+		record := []string{
+			route.Route,
+			route.Origin,
+			route.Destination,
+			derefString(route.FlightNumber),
+			derefString(route.Aircraft),
+			formatOptionalFloat(route.DistanceNm),
+			formatOptionalInt(route.BlockTimeMinutes),
+			formatOptionalFloat(route.Multiplier),
+			formatRouteDate(route.ActiveFrom),
+			formatRouteDate(route.ActiveUntil),
+			strconv.FormatBool(route.IsActive),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// applyRequest validates a request and copies it onto the route, returning every problem found.
+// Identity fields (route, origin, destination) of Airtable routes must be left unchanged.
+func (s *RouteCatalogueService) applyRequest(ctx context.Context, route *gormModels.RouteATSynced, req *dtos.RouteRequest) []string {
+	var problems []string
+
+	origin := strings.ToUpper(strings.TrimSpace(req.Origin))
+	destination := strings.ToUpper(strings.TrimSpace(req.Destination))
+	name := strings.ToUpper(strings.TrimSpace(req.Route))
+
+	if route.Source == constants.RouteSourceNative {
+		if !icaoPattern.MatchString(origin) {
+			problems = append(problems, fmt.Sprintf("origin %q must be a 4-character ICAO code", req.Origin))
+		}
+		if !icaoPattern.MatchString(destination) {
+			problems = append(problems, fmt.Sprintf("destination %q must be a 4-character ICAO code", req.Destination))
+		}
+		if origin == destination && origin != "" {
+			problems = append(problems, "origin and destination must differ")
+		}
+		if name == "" {
+			name = origin + "-" + destination
+		}
+		if len(name) > 20 {
+			problems = append(problems, fmt.Sprintf("route name %q must be at most 20 characters", name))
+		}
+
+		if origin != route.Origin || destination != route.Destination {
+			route.Origin = origin
+			route.Destination = destination
+			s.setCoordinates(ctx, route)
+		}
+		route.Route = name
+	} else {
+		if (origin != "" && !strings.EqualFold(origin, route.Origin)) ||
+			(destination != "" && !strings.EqualFold(destination, route.Destination)) ||
+			(name != "" && !strings.EqualFold(name, route.Route)) {
+			problems = append(problems, fmt.Sprintf("route %s is synced from Airtable; its route, origin and destination can only be changed there", route.Route))
+		}
+	}
+
+	flightNumber := strings.TrimSpace(req.FlightNumber)
+	if len(flightNumber) > 20 {
+		problems = append(problems, "flight_number must be at most 20 characters")
+	}
+	aircraft := strings.TrimSpace(req.Aircraft)
+	if len(aircraft) > 100 {
+		problems = append(problems, "aircraft must be at most 100 characters")
+	}
+	if req.DistanceNm != nil && *req.DistanceNm < 0 {
+		problems = append(problems, "distance_nm must not be negative")
+	}
+	if req.BlockTimeMinutes != nil && *req.BlockTimeMinutes <= 0 {
+		problems = append(problems, "block_time_minutes must be greater than 0")
+	}
+	if req.Multiplier != nil && *req.Multiplier <= 0 {
+		problems = append(problems, "multiplier must be greater than 0")
+	}
+
+	activeFrom, err := parseRouteDate(req.ActiveFrom)
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("active_from %q must be a date (YYYY-MM-DD)", req.ActiveFrom))
+	}
+	activeUntil, err := parseRouteDate(req.ActiveUntil)
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("active_until %q must be a date (YYYY-MM-DD)", req.ActiveUntil))
+	}
+	if activeFrom != nil && activeUntil != nil && activeUntil.Before(*activeFrom) {
+		problems = append(problems, "active_until must not be before active_from")
+	}
+
+	route.FlightNumber = optionalString(flightNumber)
+	route.Aircraft = optionalString(aircraft)
+	route.DistanceNm = req.DistanceNm
+	route.BlockTimeMinutes = req.BlockTimeMinutes
+	route.Multiplier = req.Multiplier
+	route.ActiveFrom = activeFrom
+	route.ActiveUntil = activeUntil
+	route.IsActive = req.IsActive == nil || *req.IsActive
+
+	return problems
+}
+
+// setCoordinates fills the route's airport coordinates from the airports table, clearing unknown ones
+func (s *RouteCatalogueService) setCoordinates(ctx context.Context, route *gormModels.RouteATSynced) {
+	route.OriginLat.Valid, route.OriginLon.Valid = false, false
+	route.DestinationLat.Valid, route.DestinationLon.Valid = false, false
+
+	if origin, err := s.airportRepo.FindByICAO(ctx, route.Origin); err != nil {
+		log.Printf("[RouteCatalogueService] Error looking up origin airport %s: %v", route.Origin, err)
+	} else if origin != nil {
+		route.OriginLat.Float64, route.OriginLat.Valid = origin.Latitude, true
+		route.OriginLon.Float64, route.OriginLon.Valid = origin.Longitude, true
+	}
+
+	if dest, err := s.airportRepo.FindByICAO(ctx, route.Destination); err != nil {
+		log.Printf("[RouteCatalogueService] Error looking up destination airport %s: %v", route.Destination, err)
+	} else if dest != nil {
+		route.DestinationLat.Float64, route.DestinationLat.Valid = dest.Latitude, true
+		route.DestinationLon.Float64, route.DestinationLon.Valid = dest.Longitude, true
+	}
+
+	common.ApplyRouteEstimates(route)
+}
+
+// RouteMultiplier returns the route's own multiplier, falling back to the mode's multiplier
+func RouteMultiplier(route *gormModels.RouteATSynced, modeMultiplier float64) float64 {
+	if route != nil && route.Multiplier != nil {
+		return *route.Multiplier
+	}
+	return modeMultiplier
+}
+
+// RouteDistanceNm returns the route's scheduled distance, falling back to its great-circle distance
+func RouteDistanceNm(route *gormModels.RouteATSynced) *float64 {
+	if route.DistanceNm != nil {
+		return route.DistanceNm
+	}
+	return route.GreatCircleNm
+}
+
+// RouteHaul classifies the route by its distance; empty when the distance is unknown
+func RouteHaul(route *gormModels.RouteATSynced) string {
+	distance := RouteDistanceNm(route)
+	if distance == nil {
+		return ""
+	}
+	return common.ClassifyRouteHaul(*distance)
+}
+
+// RouteBlockMinutes returns the route's scheduled block time, falling back to the
+// estimate for the route's aircraft (narrowbody when no aircraft is set)
+func RouteBlockMinutes(route *gormModels.RouteATSynced) *int {
+	if route.BlockTimeMinutes != nil {
+		return route.BlockTimeMinutes
+	}
+	distance := RouteDistanceNm(route)
+	if distance == nil {
+		return nil
+	}
+	class := common.AircraftClassFor(derefString(route.Aircraft))
+	if class == "" {
+		class = common.AircraftClassNarrowbody
+	}
+	minutes := common.EstimateBlockMinutes(*distance, class)
+	return &minutes
+}
+
+// RouteBlockEstimates returns the route's per-aircraft-class block time estimates in minutes
+func RouteBlockEstimates(route *gormModels.RouteATSynced) map[string]int {
+	if len(route.EstimatedBlockMinutes) == 0 {
+		return nil
+	}
+	estimates := make(map[string]int, len(route.EstimatedBlockMinutes))
+	for class, v := range route.EstimatedBlockMinutes {
+		// Values are ints when freshly computed and float64 once read back from jsonb
+		switch minutes := v.(type) {
+		case int:
+			estimates[class] = minutes
+		case float64:
+			estimates[class] = int(minutes)
+		}
+	}
+	return estimates
+}
+
+// RouteInDistanceBands reports whether the route falls in one of the given haul bands.
+// An empty band list allows every route; routes of unknown distance never match a band.
+func RouteInDistanceBands(route *gormModels.RouteATSynced, bands []string) bool {
+	if len(bands) == 0 {
+		return true
+	}
+	haul := RouteHaul(route)
+	return haul != "" && containsFold(bands, haul)
+}
+
+// parseRouteCSVHeader maps CSV column names to their index
+func parseRouteCSVHeader(header []string) (map[string]int, []string) {
+	known := make(map[string]bool, len(routeCSVColumns))
+	for _, c := range routeCSVColumns {
+		known[c] = true
+	}
+
+	var problems []string
+	columns := make(map[string]int, len(header))
+	for i, h := range header {
+		name := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		if !known[name] {
+			problems = append(problems, fmt.Sprintf("unknown column %q", h))
+			continue
+		}
+		columns[name] = i
+	}
+	for _, required := range []string{"origin", "destination"} {
+		if _, ok := columns[required]; !ok {
+			problems = append(problems, fmt.Sprintf("missing required column %q", required))
+		}
+	}
+	return columns, problems
+}
+
+// routeRequestFromCSV converts a CSV record into a route request
+func routeRequestFromCSV(columns map[string]int, record []string) (*dtos.RouteRequest, []string) {
+	get := func(name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var problems []string
+	req := &dtos.RouteRequest{
+		Route:        get("route"),
+		Origin:       get("origin"),
+		Destination:  get("destination"),
+		FlightNumber: get("flight_number"),
+		Aircraft:     get("aircraft"),
+		ActiveFrom:   get("active_from"),
+		ActiveUntil:  get("active_until"),
+	}
+
+	if v := get("distance_nm"); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			req.DistanceNm = &f
+		} else {
+			problems = append(problems, fmt.Sprintf("distance_nm %q is not a number", v))
+		}
+	}
+	if v := get("block_time_minutes"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			req.BlockTimeMinutes = &n
+		} else {
+			problems = append(problems, fmt.Sprintf("block_time_minutes %q is not a whole number", v))
+		}
+	}
+	if v := get("multiplier"); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			req.Multiplier = &f
+		} else {
+			problems = append(problems, fmt.Sprintf("multiplier %q is not a number", v))
+		}
+	}
+	if v := get("is_active"); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			req.IsActive = &b
+		} else {
+			problems = append(problems, fmt.Sprintf("is_active %q must be true or false", v))
+		}
+	}
+
+	return req, problems
+}
+
+// parseRouteDate parses an optional YYYY-MM-DD date
+func parseRouteDate(value string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(routeDateLayout, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// formatRouteDate formats an optional route date as YYYY-MM-DD
+func formatRouteDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(routeDateLayout)
+}
+
+func formatOptionalFloat(f *float64) string {
+	if f == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*f, 'f', -1, 64)
+}
+
+func formatOptionalInt(n *int) string {
+	if n == nil {
+		return ""
+	}
+	return strconv.Itoa(*n)
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"infinite-experiment/politburo/internal/common"
 	"infinite-experiment/politburo/internal/db/repositories"
+	"log"
+	"sync"
 	"time"
 
 	"gorm.io/gorm"
@@ -13,7 +15,11 @@ type WorkersContainer struct {
 	CacheFiller MetaCacheWorker
 }
 
+// InitWorkers starts the background workers. They run until ctx is cancelled and are
+// added to background so shutdown can wait for them to finish their current work.
 func InitWorkers(
+	ctx context.Context,
+	background *sync.WaitGroup,
 	db *gorm.DB,
 	c *common.CacheInterface,
 	api *common.LiveAPIService,
@@ -32,11 +38,22 @@ func InitWorkers(
 	qWorker := NewPirepQueueWorker("pirep_queue", db, redQ, dataProvCfg, pirepSyncedRepo, vaSyncHRepo)
 	monitor := NewPirepQueueMonitor(db, redQ)
 
-	go qWorker.Start(context.Background(), 5)
-	go monitor.Start(context.Background(), 30*time.Second)
-
-	// Start workers
-	go mcf.Start()
+	background.Add(2)
+	go func() {
+		defer background.Done()
+		qWorker.Start(ctx, 5)
+	}()
+	go func() {
+		defer background.Done()
+		monitor.Start(ctx, 30*time.Second)
+	}()
+
+	// Fill this replica's world status cache; the scheduled livery sync job refreshes it
+	go func() {
+		if err := mcf.RefreshWorldStatus(); err != nil {
+			log.Printf("[Workers] Error filling world status: %v", err)
+		}
+	}()
 
 	return &WorkersContainer{
 		CacheFiller: *mcf,
@@ -2,6 +2,7 @@ package workers
 
 import (
 	"context"
+	"fmt"
 	"infinite-experiment/politburo/internal/common"
 	"infinite-experiment/politburo/internal/constants"
 	"infinite-experiment/politburo/internal/db/repositories"
@@ -17,20 +18,6 @@ type MetaCacheWorker struct {
 	liverySvc  *common.AircraftLiveryService
 }
 
-func (m *MetaCacheWorker) Start() {
-	ticker := time.NewTicker(6 * time.Hour) // 4x daily sync
-	defer ticker.Stop()
-
-	// Initial sync on startup
-	m.syncAircraftLiveriesTask()
-	m.refillWorldStatus()
-
-	for range ticker.C {
-		m.refillWorldStatus()
-		m.syncAircraftLiveriesTask()
-	}
-}
-
 func NewMetaCacheFiller(
 	c *common.CacheInterface,
 	api *common.LiveAPIService,
@@ -47,23 +34,30 @@ func NewMetaCacheFiller(
 
 }
 
-// syncAircraftLiveriesTask syncs aircraft/livery data from IF API to database with change detection
-func (m *MetaCacheWorker) syncAircraftLiveriesTask() {
-	ctx := context.Background()
+// Sync refreshes the world status and syncs the aircraft and livery data. It runs as the
+// scheduled livery sync job, so only one replica calls the Infinite Flight API per run.
+func (m *MetaCacheWorker) Sync(ctx context.Context) (int, error) {
+	if err := m.RefreshWorldStatus(); err != nil {
+		log.Printf("[MetaCacheWorker] Error refreshing world status: %v", err)
+	}
+	return m.SyncAircraftLiveries(ctx)
+}
+
+// SyncAircraftLiveries syncs aircraft/livery data from IF API to database and returns the
+// number of liveries added, updated or removed
+func (m *MetaCacheWorker) SyncAircraftLiveries(ctx context.Context) (int, error) {
 	startTime := time.Now()
 
 	// Fetch liveries from Infinite Flight API
 	resp, _, err := m.api.GetAircraftLiveries()
 	if err != nil {
-		log.Printf("Error while fetching liveries from IF API: %s", err.Error())
-		return
+		return 0, fmt.Errorf("failed to fetch liveries from IF API: %w", err)
 	}
 
 	// Load existing liveries from database into map for change detection
 	existingLiveries, err := m.liveryRepo.GetLiveryMap(ctx)
 	if err != nil {
-		log.Printf("Error while loading existing liveries from database: %s", err.Error())
-		return
+		return 0, fmt.Errorf("failed to load existing liveries from database: %w", err)
 	}
 
 	// Track changes
@@ -106,15 +100,13 @@ func (m *MetaCacheWorker) syncAircraftLiveriesTask() {
 
 	if len(toUpsert) > 0 {
 		if err := m.liveryRepo.UpsertBatch(ctx, toUpsert); err != nil {
-			log.Printf("Error while upserting liveries: %s", err.Error())
-			return
+			return 0, fmt.Errorf("failed to upsert liveries: %w", err)
 		}
 	}
 
 	if len(removedIDs) > 0 {
 		if err := m.liveryRepo.MarkInactive(ctx, removedIDs); err != nil {
-			log.Printf("Error while marking liveries inactive: %s", err.Error())
-			return
+			return len(toUpsert), fmt.Errorf("failed to mark liveries inactive: %w", err)
 		}
 	}
 
@@ -135,13 +127,16 @@ func (m *MetaCacheWorker) syncAircraftLiveriesTask() {
 		len(resp.Liveries),
 		len(existingLiveries),
 	)
+
+	return len(toUpsert) + len(removedIDs), nil
 }
 
-func (m *MetaCacheWorker) refillWorldStatus() {
+// RefreshWorldStatus caches the Infinite Flight sessions and the expert server ID
+func (m *MetaCacheWorker) RefreshWorldStatus() error {
 	resp, err := m.api.GetSessions()
 
 	if err != nil {
-		return
+		return fmt.Errorf("failed to fetch sessions from IF API: %w", err)
 	}
 	c := *m.c
 
@@ -153,4 +148,5 @@ func (m *MetaCacheWorker) refillWorldStatus() {
 			break
 		}
 	}
+	return nil
 }
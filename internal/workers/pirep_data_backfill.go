@@ -34,34 +34,31 @@ func NewPIREPBackfill(
 	}
 }
 
-// RunScheduled runs the backfill job on a schedule
-func (w *PIREPBackfill) RunScheduled(ctx context.Context, interval time.Duration) {
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			log.Printf("PIREP backfill scheduler stopped")
-			return
-		case <-ticker.C:
-			if err := w.BackfillPireps(100, 500); err != nil {
-				log.Printf("PIREP backfill error: %v", err)
-			}
-		}
-	}
+func (w *PIREPBackfill) BackfillPireps(batchSize int, delayMs int) error {
+	_, err := w.backfill(context.Background(), "", batchSize, delayMs)
+	return err
 }
 
-func (w *PIREPBackfill) BackfillPireps(batchSize int, delayMs int) error {
+// BackfillVAPireps backfills the pending PIREPs of one VA, or of every VA when vaID is empty,
+// and returns the number processed
+func (w *PIREPBackfill) BackfillVAPireps(ctx context.Context, vaID string, batchSize int, delayMs int) (int, error) {
+	return w.backfill(ctx, vaID, batchSize, delayMs)
+}
+
+// backfill fills in pilot callsigns and routes of pending PIREPs, of every VA when vaID is empty
+func (w *PIREPBackfill) backfill(ctx context.Context, vaID string, batchSize int, delayMs int) (int, error) {
 	// start streaming the repo
 	log.Printf("\n[BackfillPirepJob]Starting backfilling")
-	rows, err := w.DB.WithContext(context.Background()).
+	query := w.DB.WithContext(ctx).
 		Model(&models.PirepATSynced{}).
-		Where("backfill_status = 0").
-		Rows()
+		Where("backfill_status = 0")
+	if vaID != "" {
+		query = query.Where("server_id = ?", vaID)
+	}
+	rows, err := query.Rows()
 	if err != nil {
 		log.Printf("PIREP backfill query error: %v", err)
-		return err
+		return 0, err
 	}
 	defer rows.Close()
 
@@ -71,7 +68,7 @@ func (w *PIREPBackfill) BackfillPireps(batchSize int, delayMs int) error {
 		var rec models.PirepATSynced
 		if err := w.DB.ScanRows(rows, &rec); err != nil {
 			log.Printf("PIREP backfill scan error: %v", err)
-			return err
+			return count, err
 		}
 
 		// Validate required IDs exist
@@ -165,7 +162,7 @@ func (w *PIREPBackfill) BackfillPireps(batchSize int, delayMs int) error {
 		log.Printf("PIREP backfill completed: %d records processed", count)
 	}
 
-	return rows.Err()
+	return count, rows.Err()
 
 }
 
@@ -67,7 +67,7 @@ func (m *PirepQueueMonitor) checkQueues(ctx context.Context) {
 		Table("virtual_airlines va").
 		Select("va.id, va.name").
 		Joins("JOIN va_data_provider_configs cfg ON va.id = cfg.va_id").
-		Where("cfg.provider_type = ? AND cfg.is_active = ?", "airtable", true).
+		Where("cfg.is_active = ?", true).
 		Find(&vaData).Error
 
 	if err != nil {
@@ -155,7 +155,7 @@ func (m *PirepQueueMonitor) GetAllQueueStats(ctx context.Context) ([]QueueStats,
 		Table("virtual_airlines va").
 		Select("va.id, va.name").
 		Joins("JOIN va_data_provider_configs cfg ON va.id = cfg.va_id").
-		Where("cfg.provider_type = ? AND cfg.is_active = ?", "airtable", true).
+		Where("cfg.is_active = ?", true).
 		Find(&vaData).Error
 
 	if err != nil {
@@ -183,7 +183,7 @@ func (m *PirepQueueMonitor) TrimOldMessages(ctx context.Context, maxLen int64) e
 	var vaIDs []string
 	err := m.db.WithContext(ctx).
 		Table("va_data_provider_configs").
-		Where("provider_type = ? AND is_active = ?", "airtable", true).
+		Where("is_active = ?", true).
 		Pluck("va_id", &vaIDs).Error
 
 	if err != nil {
@@ -4,25 +4,41 @@ import (
 	"context"
 	"fmt"
 	"infinite-experiment/politburo/internal/common"
+	"infinite-experiment/politburo/internal/datasync"
 	"infinite-experiment/politburo/internal/db/repositories"
-	"infinite-experiment/politburo/internal/models/dtos"
-	gormModels "infinite-experiment/politburo/internal/models/gorm"
 	"log"
-	"strings"
 	"sync"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+const (
+	// pirepConsumerGroup is the Redis consumer group every PIREP queue worker joins
+	pirepConsumerGroup = "pirep-workers"
+
+	// vaDiscoveryInterval is how often the worker looks for VAs whose data provider
+	// config was activated or deactivated
+	vaDiscoveryInterval = 30 * time.Second
+)
+
+// vaConsumers are the running consumers of one VA's queue
+type vaConsumers struct {
+	stop chan struct{}  // Closed to drain the consumers
+	wg   sync.WaitGroup // Done once every consumer has finished its in-flight PIREP
+}
+
 // PirepQueueWorker processes PIREPs from Redis queue
 type PirepQueueWorker struct {
-	workerID          string
-	db                *gorm.DB
-	redisQueue        *common.RedisQueueService
-	configRepo        *repositories.DataProviderConfigRepo
-	pirepATSyncedRepo *repositories.PirepATSyncedRepo
-	syncHistoryRepo   *repositories.VASyncHistoryRepo
+	workerID        string
+	db              *gorm.DB
+	redisQueue      *common.RedisQueueService
+	configRepo      *repositories.DataProviderConfigRepo
+	pireps          *datasync.PirepHandler
+	syncHistoryRepo *repositories.VASyncHistoryRepo
+
+	mu        sync.Mutex
+	consumers map[string]*vaConsumers // Running consumers by VA ID
 }
 
 // NewPirepQueueWorker creates a new PIREP queue worker
@@ -35,75 +51,131 @@ func NewPirepQueueWorker(
 	syncHistoryRepo *repositories.VASyncHistoryRepo,
 ) *PirepQueueWorker {
 	return &PirepQueueWorker{
-		workerID:          workerID,
-		db:                db,
-		redisQueue:        redisQueue,
-		configRepo:        configRepo,
-		pirepATSyncedRepo: pirepATSyncedRepo,
-		syncHistoryRepo:   syncHistoryRepo,
+		workerID:        workerID,
+		db:              db,
+		redisQueue:      redisQueue,
+		configRepo:      configRepo,
+		pireps:          datasync.NewPirepHandler(pirepATSyncedRepo, nil),
+		syncHistoryRepo: syncHistoryRepo,
+		consumers:       make(map[string]*vaConsumers),
 	}
 }
 
-// Start begins processing PIREPs from all VA queues
-// Spawns multiple goroutines to handle different VA queues concurrently
+// Start begins processing PIREPs from all VA queues until ctx is cancelled.
+// Spawns numWorkers consumers per VA with an active data provider config, starting
+// consumers for VAs that activate a config later and draining those of VAs that deactivate.
 func (w *PirepQueueWorker) Start(ctx context.Context, numWorkers int) error {
-	log.Printf("[PirepQueueWorker] Starting %d workers with ID prefix: %s", numWorkers, w.workerID)
+	log.Printf("[PirepQueueWorker] Starting %d workers per VA with ID prefix: %s", numWorkers, w.workerID)
+
+	// A failed first lookup is retried on the next discovery tick
+	if err := w.refreshConsumers(ctx, numWorkers); err != nil {
+		log.Printf("[PirepQueueWorker] Error fetching VA consumers: %v", err)
+	}
 
+	// Start a goroutine to periodically claim stale messages
 	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		w.claimStaleMessages(ctx)
+	}()
 
-	// Get all active VAs with Airtable configs
-	var vaIDs []string
-	err := w.db.WithContext(ctx).
-		Table("va_data_provider_configs").
-		Where("provider_type = ? AND is_active = ?", "airtable", true).
-		Pluck("va_id", &vaIDs).Error
+	ticker := time.NewTicker(vaDiscoveryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			w.mu.Lock()
+			for vaID, consumers := range w.consumers {
+				consumers.wg.Wait()
+				delete(w.consumers, vaID)
+			}
+			w.mu.Unlock()
+			log.Printf("[PirepQueueWorker] All workers stopped")
+			return nil
+		case <-ticker.C:
+			if err := w.refreshConsumers(ctx, numWorkers); err != nil {
+				log.Printf("[PirepQueueWorker] Error refreshing VA consumers: %v", err)
+			}
+		}
+	}
+}
 
+// refreshConsumers starts consumers for newly active VAs and drains the consumers of VAs
+// that no longer have an active data provider config
+func (w *PirepQueueWorker) refreshConsumers(ctx context.Context, numWorkers int) error {
+	vaIDs, err := w.configRepo.GetActiveVAIDs(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to fetch active VAs: %w", err)
 	}
 
-	if len(vaIDs) == 0 {
-		log.Printf("[PirepQueueWorker] No VAs with active Airtable configs found")
-		return nil
+	active := make(map[string]bool, len(vaIDs))
+	for _, vaID := range vaIDs {
+		active[vaID] = true
 	}
 
-	log.Printf("[PirepQueueWorker] Found %d VAs to process", len(vaIDs))
+	w.mu.Lock()
+	defer w.mu.Unlock()
 
-	// Start workers for each VA
-	for _, vaID := range vaIDs {
-		streamName := fmt.Sprintf("pirep:sync:%s", vaID)
+	for vaID, consumers := range w.consumers {
+		if !active[vaID] {
+			log.Printf("[PirepQueueWorker] VA %s no longer has an active data provider config, draining its consumers", vaID)
+			close(consumers.stop)
+			delete(w.consumers, vaID)
+		}
+	}
 
-		// Ensure consumer group exists
-		if err := w.redisQueue.CreateConsumerGroup(ctx, streamName, "pirep-workers"); err != nil {
-			log.Printf("[PirepQueueWorker] Warning - failed to create consumer group for VA %s: %v", vaID, err)
+	for _, vaID := range vaIDs {
+		if _, running := w.consumers[vaID]; running {
+			continue
 		}
+		log.Printf("[PirepQueueWorker] Starting %d consumers for VA %s", numWorkers, vaID)
+		w.consumers[vaID] = w.startConsumers(ctx, vaID, numWorkers)
+	}
 
-		// Start multiple workers for this VA queue
-		for i := 0; i < numWorkers; i++ {
-			wg.Add(1)
-			workerName := fmt.Sprintf("%s-va-%s-worker-%d", w.workerID, vaID[:8], i)
+	return nil
+}
 
-			go func(vaID, workerName, streamName string) {
-				defer wg.Done()
-				w.processQueue(ctx, vaID, streamName, workerName)
-			}(vaID, workerName, streamName)
-		}
+// startConsumers starts numWorkers consumers of a VA's queue
+func (w *PirepQueueWorker) startConsumers(ctx context.Context, vaID string, numWorkers int) *vaConsumers {
+	streamName := fmt.Sprintf("pirep:sync:%s", vaID)
+
+	// Ensure consumer group exists
+	if err := w.redisQueue.CreateConsumerGroup(ctx, streamName, pirepConsumerGroup); err != nil {
+		log.Printf("[PirepQueueWorker] Warning - failed to create consumer group for VA %s: %v", vaID, err)
 	}
 
-	// Start a goroutine to periodically claim stale messages
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		w.claimStaleMessages(ctx, vaIDs)
-	}()
+	consumers := &vaConsumers{stop: make(chan struct{})}
+	for i := 0; i < numWorkers; i++ {
+		consumers.wg.Add(1)
+		workerName := fmt.Sprintf("%s-va-%s-worker-%d", w.workerID, vaID[:min(8, len(vaID))], i)
 
-	wg.Wait()
-	log.Printf("[PirepQueueWorker] All workers stopped")
-	return nil
+		go func(workerName string) {
+			defer consumers.wg.Done()
+			w.processQueue(ctx, consumers.stop, vaID, streamName, workerName)
+		}(workerName)
+	}
+
+	return consumers
 }
 
-// processQueue continuously processes PIREPs from a specific VA queue
-func (w *PirepQueueWorker) processQueue(ctx context.Context, vaID, streamName, workerName string) {
+// activeVAIDs returns the VAs whose queues are being consumed
+func (w *PirepQueueWorker) activeVAIDs() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	vaIDs := make([]string, 0, len(w.consumers))
+	for vaID := range w.consumers {
+		vaIDs = append(vaIDs, vaID)
+	}
+	return vaIDs
+}
+
+// processQueue continuously processes PIREPs from a specific VA queue until ctx is cancelled
+// or stop is closed. Unread messages stay in the stream for when the VA is consumed again.
+func (w *PirepQueueWorker) processQueue(ctx context.Context, stop <-chan struct{}, vaID, streamName, workerName string) {
 	log.Printf("[%s] Started processing queue: %s", workerName, streamName)
 
 	processedCount := 0
@@ -114,10 +186,16 @@ func (w *PirepQueueWorker) processQueue(ctx context.Context, vaID, streamName, w
 		case <-ctx.Done():
 			log.Printf("[%s] Shutting down. Processed: %d, Errors: %d", workerName, processedCount, errorCount)
 			return
+		case <-stop:
+			log.Printf("[%s] Drained. Processed: %d, Errors: %d", workerName, processedCount, errorCount)
+			return
 		default:
 			// Dequeue next PIREP (blocks for up to 5 seconds)
-			item, messageID, err := w.redisQueue.DequeuePirep(ctx, streamName, "pirep-workers", workerName, 5*time.Second)
+			item, messageID, err := w.redisQueue.DequeuePirep(ctx, streamName, pirepConsumerGroup, workerName, 5*time.Second)
 			if err != nil {
+				if ctx.Err() != nil {
+					continue // Shutting down
+				}
 				log.Printf("[%s] Error dequeuing: %v", workerName, err)
 				time.Sleep(1 * time.Second) // Back off on error
 				continue
@@ -128,8 +206,9 @@ func (w *PirepQueueWorker) processQueue(ctx context.Context, vaID, streamName, w
 				continue
 			}
 
-			// Process the PIREP
-			if err := w.processPirep(ctx, item); err != nil {
+			// Process and acknowledge the PIREP even if shutdown starts meanwhile
+			msgCtx := context.WithoutCancel(ctx)
+			if err := w.processPirep(msgCtx, item); err != nil {
 				log.Printf("[%s] Error processing PIREP %s: %v", workerName, item.AirtableRecordID, err)
 				errorCount++
 				// Note: We still acknowledge to avoid reprocessing indefinitely
@@ -139,7 +218,7 @@ func (w *PirepQueueWorker) processQueue(ctx context.Context, vaID, streamName, w
 			}
 
 			// Acknowledge message
-			if err := w.redisQueue.AckPirep(ctx, streamName, "pirep-workers", messageID); err != nil {
+			if err := w.redisQueue.AckPirep(msgCtx, streamName, pirepConsumerGroup, messageID); err != nil {
 				log.Printf("[%s] Error acknowledging message %s: %v", workerName, messageID, err)
 			}
 		}
@@ -149,7 +228,7 @@ func (w *PirepQueueWorker) processQueue(ctx context.Context, vaID, streamName, w
 // processPirep handles the actual PIREP upsert logic
 func (w *PirepQueueWorker) processPirep(ctx context.Context, item *common.PirepQueueItem) error {
 	// Get VA config to extract schema
-	config, err := w.configRepo.GetActiveConfig(ctx, item.VATID, "airtable")
+	config, err := w.configRepo.GetActiveConfigForVA(ctx, item.VATID)
 	if err != nil {
 		return fmt.Errorf("failed to get config: %w", err)
 	}
@@ -171,142 +250,11 @@ func (w *PirepQueueWorker) processPirep(ctx context.Context, item *common.PirepQ
 	}
 
 	// Extract and upsert PIREP
-	return w.upsertPirep(ctx, item.VATID, item.AirtableRecordID, item.Fields, item.CreatedTime, pirepSchema)
-}
-
-// upsertPirep inserts or updates a PIREP record (same logic as the job)
-func (w *PirepQueueWorker) upsertPirep(ctx context.Context, vaID string, airtableRecordID string, record map[string]interface{}, createdTime string, schema *dtos.EntitySchema) error {
-	// Extract field mappings
-	routeField := schema.GetFieldMapping("route")
-	flightModeField := schema.GetFieldMapping("flight_mode")
-	flightTimeField := schema.GetFieldMapping("flight_time")
-	pilotCallsignField := schema.GetFieldMapping("pilot_callsign")
-	aircraftField := schema.GetFieldMapping("aircraft")
-	liveryField := schema.GetFieldMapping("livery")
-	routeATIDField := schema.GetFieldMapping("route_at_id")
-	pilotATIDField := schema.GetFieldMapping("pilot_at_id")
-
-	// Extract route
-	var route string
-	if routeField != nil {
-		if rawRoute, ok := record[routeField.AirtableName]; ok {
-			if routeStr, ok := rawRoute.(string); ok {
-				route = strings.TrimSpace(routeStr)
-			}
-		}
-	}
-
-	// Extract flight mode
-	var flightMode string
-	if flightModeField != nil {
-		if rawMode, ok := record[flightModeField.AirtableName]; ok {
-			if modeStr, ok := rawMode.(string); ok {
-				flightMode = strings.TrimSpace(modeStr)
-			}
-		}
-	}
-
-	// Extract flight time
-	var flightTime *float64
-	if flightTimeField != nil {
-		if rawTime, ok := record[flightTimeField.AirtableName]; ok {
-			switch v := rawTime.(type) {
-			case float64:
-				flightTime = &v
-			case int:
-				ft := float64(v)
-				flightTime = &ft
-			}
-		}
-	}
-
-	// Extract pilot callsign
-	var pilotCallsign string
-	if pilotCallsignField != nil {
-		if rawCallsign, ok := record[pilotCallsignField.AirtableName]; ok {
-			if callsignStr, ok := rawCallsign.(string); ok {
-				pilotCallsign = strings.TrimSpace(callsignStr)
-			}
-		}
-	}
-
-	// Extract aircraft
-	var aircraft string
-	if aircraftField != nil {
-		if rawAircraft, ok := record[aircraftField.AirtableName]; ok {
-			if aircraftStr, ok := rawAircraft.(string); ok {
-				aircraft = strings.TrimSpace(aircraftStr)
-			}
-		}
-	}
-
-	// Extract livery
-	var livery string
-	if liveryField != nil {
-		if rawLivery, ok := record[liveryField.AirtableName]; ok {
-			if liveryStr, ok := rawLivery.(string); ok {
-				livery = strings.TrimSpace(liveryStr)
-			}
-		}
-	}
-
-	// Extract route_at_id
-	var routeATID *string
-	if routeATIDField != nil {
-		if rawRouteID, ok := record[routeATIDField.AirtableName]; ok {
-			if idArray, ok := rawRouteID.([]interface{}); ok && len(idArray) > 0 {
-				if idStr, ok := idArray[0].(string); ok {
-					routeATID = &idStr
-				}
-			}
-		}
-	}
-
-	// Extract pilot_at_id
-	var pilotATID *string
-	if pilotATIDField != nil {
-		if rawPilotID, ok := record[pilotATIDField.AirtableName]; ok {
-			if idArray, ok := rawPilotID.([]interface{}); ok && len(idArray) > 0 {
-				if idStr, ok := idArray[0].(string); ok {
-					pilotATID = &idStr
-				}
-			}
-		}
-	}
-
-	// Parse created time
-	var atCreatedTime *time.Time
-	if createdTime != "" {
-		if t, err := time.Parse(time.RFC3339, createdTime); err == nil {
-			atCreatedTime = &t
-		}
-	}
-
-	// Create PIREP entity
-	pirepATSynced := &gormModels.PirepATSynced{
-		ATID:          airtableRecordID,
-		ServerID:      vaID,
-		Route:         route,
-		FlightMode:    flightMode,
-		FlightTime:    flightTime,
-		PilotCallsign: pilotCallsign,
-		Aircraft:      aircraft,
-		Livery:        livery,
-		RouteATID:     routeATID,
-		PilotATID:     pilotATID,
-		ATCreatedTime: atCreatedTime,
-	}
-
-	// Upsert
-	if err := w.pirepATSyncedRepo.Upsert(ctx, pirepATSynced); err != nil {
-		return fmt.Errorf("failed to upsert: %w", err)
-	}
-
-	return nil
+	return w.pireps.UpsertPirep(ctx, item.VATID, item.AirtableRecordID, item.Fields, item.CreatedTime, pirepSchema)
 }
 
 // claimStaleMessages periodically claims messages that have been idle too long
-func (w *PirepQueueWorker) claimStaleMessages(ctx context.Context, vaIDs []string) {
+func (w *PirepQueueWorker) claimStaleMessages(ctx context.Context) {
 	ticker := time.NewTicker(2 * time.Minute)
 	defer ticker.Stop()
 
@@ -315,11 +263,11 @@ func (w *PirepQueueWorker) claimStaleMessages(ctx context.Context, vaIDs []strin
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			for _, vaID := range vaIDs {
+			for _, vaID := range w.activeVAIDs() {
 				streamName := fmt.Sprintf("pirep:sync:%s", vaID)
 				claimerName := fmt.Sprintf("%s-claimer", w.workerID)
 
-				items, messageIDs, err := w.redisQueue.ClaimStalePireps(ctx, streamName, "pirep-workers", claimerName, 5*time.Minute)
+				items, messageIDs, err := w.redisQueue.ClaimStalePireps(ctx, streamName, pirepConsumerGroup, claimerName, 5*time.Minute)
 				if err != nil {
 					log.Printf("[PirepQueueWorker] Error claiming stale messages for VA %s: %v", vaID, err)
 					continue
@@ -328,14 +276,15 @@ func (w *PirepQueueWorker) claimStaleMessages(ctx context.Context, vaIDs []strin
 				if len(items) > 0 {
 					log.Printf("[PirepQueueWorker] Claimed %d stale messages for VA %s", len(items), vaID)
 
-					// Process claimed items
+					// Process claimed items, finishing the batch even if shutdown starts meanwhile
+					msgCtx := context.WithoutCancel(ctx)
 					for i, item := range items {
-						if err := w.processPirep(ctx, item); err != nil {
+						if err := w.processPirep(msgCtx, item); err != nil {
 							log.Printf("[PirepQueueWorker] Error processing claimed PIREP: %v", err)
 						}
 
 						// Acknowledge
-						if err := w.redisQueue.AckPirep(ctx, streamName, "pirep-workers", messageIDs[i]); err != nil {
+						if err := w.redisQueue.AckPirep(msgCtx, streamName, pirepConsumerGroup, messageIDs[i]); err != nil {
 							log.Printf("[PirepQueueWorker] Error acknowledging claimed message: %v", err)
 						}
 					}
@@ -839,3 +839,809 @@ func RemovePilotHandler(
 		return
 	}
 }
+
+// pirepRow is the template view of a PIREP in the review queue
+type pirepRow struct {
+	ID              string
+	PilotCallsign   string
+	PilotUsername   string
+	Mode            string
+	ModeDisplayName string
+	Route           string
+	FlightTime      string
+	Multiplier      float64
+	Aircraft        string
+	Airline         string
+	PilotRemarks    string
+	Status          string
+	ReviewNotes     string
+	SubmittedAt     string
+	Reviewable      bool
+	ProviderError   string
+	ValidationError string
+	Discrepancies   string
+}
+
+// PirepsHandler serves the PIREP review queue page
+// Role check: Staff middleware ensures only staff and admin can access this
+func PirepsHandler(w http.ResponseWriter, r *http.Request) {
+	// Get session data from context (guaranteed by auth middleware)
+	sessionDataInterface := auth.GetSessionData(r.Context())
+	sessionData, ok := sessionDataInterface.(*common.SessionData)
+	if !ok {
+		http.Error(w, "Invalid session data", http.StatusInternalServerError)
+		return
+	}
+
+	activeVA := sessionData.GetActiveVA()
+	if activeVA == nil {
+		http.Error(w, "No active VA found", http.StatusInternalServerError)
+		return
+	}
+
+	// Default to the pending queue
+	status := r.URL.Query().Get("status")
+	if status == "" {
+		status = string(constants.PirepStatusPending)
+	}
+
+	data := map[string]interface{}{
+		"ActiveVA":        activeVA,
+		"VirtualAirlines": sessionData.VirtualAirlines,
+		"Username":        sessionData.Username,
+		"UserID":          sessionData.UserID,
+		"ActiveVAID":      sessionData.ActiveVAID,
+		"PageTitle":       "PIREPs",
+		"Status":          status,
+	}
+
+	RenderTemplate(w, "pages/pireps.html", data)
+}
+
+// PirepsListHandler returns the filtered PIREP list for the active VA (HTMX partial)
+func PirepsListHandler(
+	w http.ResponseWriter,
+	r *http.Request,
+	pirepReviewSvc *services.PirepReviewService,
+) {
+	// Get session data (guaranteed by auth middleware)
+	sessionDataInterface := auth.GetSessionData(r.Context())
+	sessionData, ok := sessionDataInterface.(*common.SessionData)
+	if !ok {
+		http.Error(w, "Invalid session data", http.StatusInternalServerError)
+		return
+	}
+
+	activeVA := sessionData.GetActiveVA()
+	if activeVA == nil {
+		http.Error(w, "No active VA found", http.StatusInternalServerError)
+		return
+	}
+
+	renderPirepsTable(w, r, pirepReviewSvc, activeVA, "")
+}
+
+// ReviewPirepHandler approves, rejects or requests changes on a PIREP (HTMX endpoint)
+// Role check: Staff middleware ensures staff or admin can access this
+func ReviewPirepHandler(
+	w http.ResponseWriter,
+	r *http.Request,
+	pirepReviewSvc *services.PirepReviewService,
+) {
+	// Get session data (guaranteed by auth middleware)
+	sessionDataInterface := auth.GetSessionData(r.Context())
+	sessionData, ok := sessionDataInterface.(*common.SessionData)
+	if !ok {
+		http.Error(w, "Invalid session data", http.StatusInternalServerError)
+		return
+	}
+
+	activeVA := sessionData.GetActiveVA()
+	if activeVA == nil {
+		http.Error(w, "No active VA found", http.StatusInternalServerError)
+		return
+	}
+
+	// Get PIREP ID from URL parameter
+	pirepID := chi.URLParam(r, "pirep_id")
+	if pirepID == "" {
+		http.Error(w, "Missing pirep_id in URL", http.StatusBadRequest)
+		return
+	}
+
+	// Review via service; validation errors are shown inline above the table
+	flash := ""
+	_, err := pirepReviewSvc.ReviewPirep(
+		r.Context(),
+		activeVA.VAID,
+		pirepID,
+		sessionData.UserID,
+		constants.PirepStatus(r.FormValue("decision")),
+		r.FormValue("notes"),
+	)
+	if err != nil {
+		flash = "Failed to review PIREP: " + err.Error()
+	}
+
+	// Re-render the table with the filters the reviewer had applied
+	renderPirepsTable(w, r, pirepReviewSvc, activeVA, flash)
+}
+
+// renderPirepsTable fetches PIREPs using the filters in the request and renders the table partial
+func renderPirepsTable(
+	w http.ResponseWriter,
+	r *http.Request,
+	pirepReviewSvc *services.PirepReviewService,
+	activeVA *common.VAMembership,
+	flash string,
+) {
+	filter := repositories.PirepFilter{
+		Status:        constants.PirepStatus(r.FormValue("status")),
+		PilotCallsign: strings.TrimSpace(r.FormValue("pilot")),
+		Mode:          strings.TrimSpace(r.FormValue("mode")),
+		Route:         strings.TrimSpace(r.FormValue("route")),
+		Limit:         100,
+	}
+
+	pireps, total, err := pirepReviewSvc.ListPireps(r.Context(), activeVA.VAID, filter)
+	if err != nil {
+		http.Error(w, "Failed to fetch PIREPs: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rows := make([]pirepRow, 0, len(pireps))
+	for _, p := range pireps {
+		row := pirepRow{
+			ID:              p.ID,
+			PilotCallsign:   p.PilotCallsign,
+			Mode:            p.Mode,
+			ModeDisplayName: p.ModeDisplayName,
+			Route:           p.Route,
+			FlightTime:      fmt.Sprintf("%d:%02d", p.FlightTimeSeconds/3600, (p.FlightTimeSeconds%3600)/60),
+			Multiplier:      p.Multiplier,
+			Aircraft:        p.Aircraft,
+			Airline:         p.Airline,
+			PilotRemarks:    p.PilotRemarks,
+			Status:          p.Status.String(),
+			SubmittedAt:     p.SubmittedAt.Format("2006-01-02 15:04"),
+			Reviewable:      p.Status.IsReviewable(),
+		}
+		if p.User.UserName != nil {
+			row.PilotUsername = *p.User.UserName
+		}
+		if p.ReviewNotes != nil {
+			row.ReviewNotes = *p.ReviewNotes
+		}
+		if p.ProviderError != nil {
+			row.ProviderError = *p.ProviderError
+		}
+		if p.ValidationStatus != nil && *p.ValidationStatus == constants.PirepValidationFailed && p.ValidationReason != nil {
+			row.ValidationError = *p.ValidationReason
+		}
+		if p.HistoryDiscrepancies != nil {
+			row.Discrepancies = *p.HistoryDiscrepancies
+		}
+		rows = append(rows, row)
+	}
+
+	data := map[string]interface{}{
+		"Pireps":   rows,
+		"Total":    total,
+		"ActiveVA": activeVA,
+		"Flash":    flash,
+	}
+
+	if err := RenderPartial(w, "partials/pireps-table.html", data); err != nil {
+		http.Error(w, "Error rendering PIREPs table", http.StatusInternalServerError)
+		return
+	}
+}
+
+// routeRow is the template view of a route in the route catalogue
+type routeRow struct {
+	ID           string
+	Source       string
+	Route        string
+	Origin       string
+	Destination  string
+	FlightNumber string
+	Aircraft     string
+	Distance     string
+	Haul         string
+	BlockTime    string
+	Multiplier   string
+	ActiveDates  string
+	IsActive     bool
+	IsNative     bool
+
+	BlockTimeEstimated bool // BlockTime is a distance estimate, not the scheduled block time
+}
+
+// RoutesHandler serves the route catalogue page
+// Role check: Staff middleware ensures only staff and admin can access this
+func RoutesHandler(w http.ResponseWriter, r *http.Request) {
+	// Get session data from context (guaranteed by auth middleware)
+	sessionDataInterface := auth.GetSessionData(r.Context())
+	sessionData, ok := sessionDataInterface.(*common.SessionData)
+	if !ok {
+		http.Error(w, "Invalid session data", http.StatusInternalServerError)
+		return
+	}
+
+	activeVA := sessionData.GetActiveVA()
+	if activeVA == nil {
+		http.Error(w, "No active VA found", http.StatusInternalServerError)
+		return
+	}
+
+	data := map[string]interface{}{
+		"ActiveVA":        activeVA,
+		"VirtualAirlines": sessionData.VirtualAirlines,
+		"Username":        sessionData.Username,
+		"UserID":          sessionData.UserID,
+		"ActiveVAID":      sessionData.ActiveVAID,
+		"PageTitle":       "Routes",
+		"IsAdmin":         activeVA.Role == "admin",
+	}
+
+	RenderTemplate(w, "pages/routes.html", data)
+}
+
+// RoutesListHandler returns the route catalogue for the active VA (HTMX partial)
+func RoutesListHandler(
+	w http.ResponseWriter,
+	r *http.Request,
+	routeCatalogueSvc *services.RouteCatalogueService,
+) {
+	activeVA, ok := activeVAFromSession(w, r)
+	if !ok {
+		return
+	}
+
+	renderRoutesTable(w, r, routeCatalogueSvc, activeVA, "", "")
+}
+
+// CreateRouteHandler adds a native route from the dashboard form (HTMX endpoint)
+// Role check: Admin middleware ensures only admins can access this
+func CreateRouteHandler(
+	w http.ResponseWriter,
+	r *http.Request,
+	routeCatalogueSvc *services.RouteCatalogueService,
+) {
+	activeVA, ok := activeVAFromSession(w, r)
+	if !ok {
+		return
+	}
+
+	req := dtos.RouteRequest{
+		Origin:       r.FormValue("origin"),
+		Destination:  r.FormValue("destination"),
+		Route:        r.FormValue("route"),
+		FlightNumber: r.FormValue("flight_number"),
+		Aircraft:     r.FormValue("aircraft"),
+		ActiveFrom:   r.FormValue("active_from"),
+		ActiveUntil:  r.FormValue("active_until"),
+	}
+
+	var problems []string
+	if v := strings.TrimSpace(r.FormValue("distance_nm")); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			req.DistanceNm = &f
+		} else {
+			problems = append(problems, "distance must be a number")
+		}
+	}
+	if v := strings.TrimSpace(r.FormValue("block_time_minutes")); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			req.BlockTimeMinutes = &n
+		} else {
+			problems = append(problems, "block time must be a whole number of minutes")
+		}
+	}
+	if v := strings.TrimSpace(r.FormValue("multiplier")); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			req.Multiplier = &f
+		} else {
+			problems = append(problems, "multiplier must be a number")
+		}
+	}
+
+	if len(problems) > 0 {
+		renderRoutesTable(w, r, routeCatalogueSvc, activeVA, "Failed to add route: "+strings.Join(problems, "; "), "")
+		return
+	}
+
+	flash, notice := "", ""
+	if route, err := routeCatalogueSvc.CreateRoute(r.Context(), activeVA.VAID, &req); err != nil {
+		flash = "Failed to add route: " + err.Error()
+	} else {
+		notice = "Added route " + route.Route
+	}
+
+	renderRoutesTable(w, r, routeCatalogueSvc, activeVA, flash, notice)
+}
+
+// SetRouteActiveHandler activates or deactivates a route (HTMX endpoint)
+// Role check: Admin middleware ensures only admins can access this
+func SetRouteActiveHandler(
+	w http.ResponseWriter,
+	r *http.Request,
+	routeCatalogueSvc *services.RouteCatalogueService,
+) {
+	activeVA, ok := activeVAFromSession(w, r)
+	if !ok {
+		return
+	}
+
+	routeID := chi.URLParam(r, "route_id")
+	if routeID == "" {
+		http.Error(w, "Missing route_id in URL", http.StatusBadRequest)
+		return
+	}
+
+	flash := ""
+	if _, err := routeCatalogueSvc.SetRouteActive(r.Context(), activeVA.VAID, routeID, r.FormValue("active") == "true"); err != nil {
+		flash = "Failed to update route: " + err.Error()
+	}
+
+	renderRoutesTable(w, r, routeCatalogueSvc, activeVA, flash, "")
+}
+
+// DeleteRouteHandler removes a native route (HTMX endpoint)
+// Role check: Admin middleware ensures only admins can access this
+func DeleteRouteHandler(
+	w http.ResponseWriter,
+	r *http.Request,
+	routeCatalogueSvc *services.RouteCatalogueService,
+) {
+	activeVA, ok := activeVAFromSession(w, r)
+	if !ok {
+		return
+	}
+
+	routeID := chi.URLParam(r, "route_id")
+	if routeID == "" {
+		http.Error(w, "Missing route_id in URL", http.StatusBadRequest)
+		return
+	}
+
+	flash := ""
+	if err := routeCatalogueSvc.DeleteRoute(r.Context(), activeVA.VAID, routeID); err != nil {
+		flash = "Failed to delete route: " + err.Error()
+	}
+
+	renderRoutesTable(w, r, routeCatalogueSvc, activeVA, flash, "")
+}
+
+// ImportRoutesHandler bulk imports routes from an uploaded CSV file (HTMX endpoint)
+// Role check: Admin middleware ensures only admins can access this
+func ImportRoutesHandler(
+	w http.ResponseWriter,
+	r *http.Request,
+	routeCatalogueSvc *services.RouteCatalogueService,
+) {
+	activeVA, ok := activeVAFromSession(w, r)
+	if !ok {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 5<<20)
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		renderRoutesTable(w, r, routeCatalogueSvc, activeVA, "Choose a CSV file to import", "")
+		return
+	}
+	defer file.Close()
+
+	flash, notice := "", ""
+	if result, err := routeCatalogueSvc.ImportCSV(r.Context(), activeVA.VAID, file); err != nil {
+		flash = "Import failed, no routes were changed: " + err.Error()
+	} else {
+		notice = fmt.Sprintf("Imported routes: %d added, %d updated", result.Created, result.Updated)
+	}
+
+	renderRoutesTable(w, r, routeCatalogueSvc, activeVA, flash, notice)
+}
+
+// ExportRoutesHandler downloads the route catalogue as CSV
+func ExportRoutesHandler(
+	w http.ResponseWriter,
+	r *http.Request,
+	routeCatalogueSvc *services.RouteCatalogueService,
+) {
+	activeVA, ok := activeVAFromSession(w, r)
+	if !ok {
+		return
+	}
+
+	var buf strings.Builder
+	if err := routeCatalogueSvc.ExportCSV(r.Context(), activeVA.VAID, &buf); err != nil {
+		http.Error(w, "Failed to export routes: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="routes.csv"`)
+	w.Write([]byte(buf.String()))
+}
+
+// activeVAFromSession returns the active VA from the session, writing an error response if it's missing
+func activeVAFromSession(w http.ResponseWriter, r *http.Request) (*common.VAMembership, bool) {
+	// Get session data (guaranteed by auth middleware)
+	sessionDataInterface := auth.GetSessionData(r.Context())
+	sessionData, ok := sessionDataInterface.(*common.SessionData)
+	if !ok {
+		http.Error(w, "Invalid session data", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	activeVA := sessionData.GetActiveVA()
+	if activeVA == nil {
+		http.Error(w, "No active VA found", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	return activeVA, true
+}
+
+// renderRoutesTable fetches the route catalogue, applies the search filter and renders the table partial
+func renderRoutesTable(
+	w http.ResponseWriter,
+	r *http.Request,
+	routeCatalogueSvc *services.RouteCatalogueService,
+	activeVA *common.VAMembership,
+	flash string,
+	notice string,
+) {
+	routes, err := routeCatalogueSvc.ListRoutes(r.Context(), activeVA.VAID)
+	if err != nil {
+		http.Error(w, "Failed to fetch routes: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	search := strings.ToLower(strings.TrimSpace(r.FormValue("q")))
+	showInactive := r.FormValue("show_inactive") == "on"
+
+	rows := make([]routeRow, 0, len(routes))
+	for _, route := range routes {
+		if !route.IsActive && !showInactive {
+			continue
+		}
+
+		row := routeRow{
+			ID:          route.ID,
+			Source:      route.Source,
+			Route:       route.Route,
+			Origin:      route.Origin,
+			Destination: route.Destination,
+			IsActive:    route.IsActive,
+			IsNative:    route.Source == constants.RouteSourceNative,
+		}
+		if route.FlightNumber != nil {
+			row.FlightNumber = *route.FlightNumber
+		}
+		if route.Aircraft != nil {
+			row.Aircraft = *route.Aircraft
+		}
+		if distance := services.RouteDistanceNm(&route); distance != nil {
+			row.Distance = fmt.Sprintf("%.0f nm", *distance)
+			row.Haul = strings.ReplaceAll(services.RouteHaul(&route), "_", " ")
+		}
+		if minutes := services.RouteBlockMinutes(&route); minutes != nil {
+			row.BlockTime = fmt.Sprintf("%d:%02d", *minutes/60, *minutes%60)
+			row.BlockTimeEstimated = route.BlockTimeMinutes == nil
+		}
+		if route.Multiplier != nil {
+			row.Multiplier = strconv.FormatFloat(*route.Multiplier, 'f', -1, 64)
+		}
+		switch {
+		case route.ActiveFrom != nil && route.ActiveUntil != nil:
+			row.ActiveDates = route.ActiveFrom.Format("2006-01-02") + " to " + route.ActiveUntil.Format("2006-01-02")
+		case route.ActiveFrom != nil:
+			row.ActiveDates = "from " + route.ActiveFrom.Format("2006-01-02")
+		case route.ActiveUntil != nil:
+			row.ActiveDates = "until " + route.ActiveUntil.Format("2006-01-02")
+		}
+
+		if search != "" && !strings.Contains(strings.ToLower(row.Route+" "+row.Origin+" "+row.Destination+" "+row.FlightNumber+" "+row.Aircraft), search) {
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	data := map[string]interface{}{
+		"Routes":   rows,
+		"Total":    len(routes),
+		"ActiveVA": activeVA,
+		"IsAdmin":  activeVA.Role == "admin",
+		"Flash":    flash,
+		"Notice":   notice,
+	}
+
+	if err := RenderPartial(w, "partials/routes-table.html", data); err != nil {
+		http.Error(w, "Error rendering routes table", http.StatusInternalServerError)
+		return
+	}
+}
+
+// rankRow is the template view of a pilot's rank progress
+type rankRow struct {
+	UserID       string
+	Callsign     string
+	Username     string
+	CurrentRank  string
+	NextRank     string
+	Hours        string
+	Flights      int
+	Progress     int
+	Requirements []string // Unmet requirements of the next rank
+	Checkrides   string
+}
+
+// RanksHandler serves the pilot ranks page
+// Role check: Staff middleware ensures only staff and admin can access this
+func RanksHandler(w http.ResponseWriter, r *http.Request) {
+	// Get session data from context (guaranteed by auth middleware)
+	sessionDataInterface := auth.GetSessionData(r.Context())
+	sessionData, ok := sessionDataInterface.(*common.SessionData)
+	if !ok {
+		http.Error(w, "Invalid session data", http.StatusInternalServerError)
+		return
+	}
+
+	activeVA := sessionData.GetActiveVA()
+	if activeVA == nil {
+		http.Error(w, "No active VA found", http.StatusInternalServerError)
+		return
+	}
+
+	data := map[string]interface{}{
+		"ActiveVA":        activeVA,
+		"VirtualAirlines": sessionData.VirtualAirlines,
+		"Username":        sessionData.Username,
+		"UserID":          sessionData.UserID,
+		"ActiveVAID":      sessionData.ActiveVAID,
+		"PageTitle":       "Ranks",
+		"IsAdmin":         activeVA.Role == "admin",
+	}
+
+	RenderTemplate(w, "pages/ranks.html", data)
+}
+
+// RanksListHandler returns the rank ladder and every pilot's progress for the active VA (HTMX partial)
+func RanksListHandler(
+	w http.ResponseWriter,
+	r *http.Request,
+	rankSvc *services.RankService,
+) {
+	activeVA, ok := activeVAFromSession(w, r)
+	if !ok {
+		return
+	}
+
+	renderRanksTable(w, r, rankSvc, activeVA, "", "")
+}
+
+// RecordCheckrideHandler records a passed checkride from the dashboard (HTMX endpoint)
+// Role check: Staff middleware ensures only staff and admin can access this
+func RecordCheckrideHandler(
+	w http.ResponseWriter,
+	r *http.Request,
+	rankSvc *services.RankService,
+) {
+	sessionData, ok := auth.GetSessionData(r.Context()).(*common.SessionData)
+	if !ok {
+		http.Error(w, "Invalid session data", http.StatusInternalServerError)
+		return
+	}
+
+	activeVA, ok := activeVAFromSession(w, r)
+	if !ok {
+		return
+	}
+
+	userID := chi.URLParam(r, "user_id")
+	if userID == "" {
+		http.Error(w, "Missing user_id in URL", http.StatusBadRequest)
+		return
+	}
+
+	req := dtos.CheckrideRequest{
+		Name:  r.FormValue("name"),
+		Notes: r.FormValue("notes"),
+	}
+
+	flash, notice := "", ""
+	if checkride, err := rankSvc.RecordCheckride(r.Context(), activeVA.VAID, userID, sessionData.UserID, &req); err != nil {
+		flash = "Failed to record checkride: " + err.Error()
+	} else {
+		notice = "Recorded checkride " + checkride.Name
+	}
+
+	renderRanksTable(w, r, rankSvc, activeVA, flash, notice)
+}
+
+// renderRanksTable fetches the ladder and pilot progress, applies the search filter and renders the table partial
+func renderRanksTable(
+	w http.ResponseWriter,
+	r *http.Request,
+	rankSvc *services.RankService,
+	activeVA *common.VAMembership,
+	flash string,
+	notice string,
+) {
+	ladder, err := rankSvc.ListRanks(r.Context(), activeVA.VAID)
+	if err != nil {
+		http.Error(w, "Failed to fetch ranks: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	progress, err := rankSvc.ListProgress(r.Context(), activeVA.VAID)
+	if err != nil {
+		http.Error(w, "Failed to fetch rank progress: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	ranks := make([]dtos.Rank, 0, len(ladder))
+	for i := range ladder {
+		ranks = append(ranks, services.RankDetail(&ladder[i]))
+	}
+
+	search := strings.ToLower(strings.TrimSpace(r.FormValue("q")))
+
+	rows := make([]rankRow, 0, len(progress))
+	for _, p := range progress {
+		row := rankRow{
+			UserID:     p.UserID,
+			Callsign:   p.Callsign,
+			Username:   p.Username,
+			Hours:      fmt.Sprintf("%.1f", p.CreditedHours),
+			Flights:    p.Flights,
+			Progress:   p.Progress,
+			Checkrides: strings.Join(p.Checkrides, ", "),
+		}
+		if p.CurrentRank != nil {
+			row.CurrentRank = p.CurrentRank.Name
+		}
+		if p.NextRank != nil {
+			row.NextRank = p.NextRank.Name
+		}
+		for _, req := range p.Requirements {
+			if req.Met {
+				continue
+			}
+			switch req.Type {
+			case "hours":
+				row.Requirements = append(row.Requirements, fmt.Sprintf("%.1f more hours", req.Required-req.Current))
+			case "flights":
+				row.Requirements = append(row.Requirements, fmt.Sprintf("%.0f more flights", req.Required-req.Current))
+			default:
+				row.Requirements = append(row.Requirements, req.Name+" checkride")
+			}
+		}
+
+		if search != "" && !strings.Contains(strings.ToLower(row.Callsign+" "+row.Username+" "+row.CurrentRank), search) {
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	data := map[string]interface{}{
+		"Ranks":    ranks,
+		"Pilots":   rows,
+		"Total":    len(progress),
+		"ActiveVA": activeVA,
+		"Flash":    flash,
+		"Notice":   notice,
+	}
+
+	if err := RenderPartial(w, "partials/ranks-table.html", data); err != nil {
+		http.Error(w, "Error rendering ranks table", http.StatusInternalServerError)
+		return
+	}
+}
+
+// syncRunRow is one sync run in the sync history table
+type syncRunRow struct {
+	Job        string
+	EntityType string
+	Trigger    string
+	Status     string
+	StartedAt  string
+	Duration   string
+	Fetched    int
+	Inserted   int
+	Updated    int
+	Failed     int
+	Deleted    int
+	RateLimit  string
+	ErrorCount int
+	Errors     []string
+	Message    string
+}
+
+// SyncHandler serves the data provider sync history page
+// Role check: Admin middleware ensures only admins can access this
+func SyncHandler(w http.ResponseWriter, r *http.Request) {
+	// Get session data from context (guaranteed by auth middleware)
+	sessionDataInterface := auth.GetSessionData(r.Context())
+	sessionData, ok := sessionDataInterface.(*common.SessionData)
+	if !ok {
+		http.Error(w, "Invalid session data", http.StatusInternalServerError)
+		return
+	}
+
+	activeVA := sessionData.GetActiveVA()
+	if activeVA == nil {
+		http.Error(w, "No active VA found", http.StatusInternalServerError)
+		return
+	}
+
+	data := map[string]interface{}{
+		"ActiveVA":        activeVA,
+		"VirtualAirlines": sessionData.VirtualAirlines,
+		"Username":        sessionData.Username,
+		"UserID":          sessionData.UserID,
+		"ActiveVAID":      sessionData.ActiveVAID,
+		"PageTitle":       "Sync",
+		"IsAdmin":         activeVA.Role == "admin",
+	}
+
+	RenderTemplate(w, "pages/sync.html", data)
+}
+
+// SyncRunsListHandler returns the active VA's recent sync runs (HTMX partial)
+func SyncRunsListHandler(
+	w http.ResponseWriter,
+	r *http.Request,
+	syncRunRepo *repositories.SyncRunRepo,
+) {
+	activeVA, ok := activeVAFromSession(w, r)
+	if !ok {
+		return
+	}
+
+	runs, err := syncRunRepo.ListByVA(r.Context(), activeVA.VAID, r.FormValue("job"), r.FormValue("entity_type"), 100)
+	if err != nil {
+		http.Error(w, "Failed to fetch sync runs: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rows := make([]syncRunRow, 0, len(runs))
+	for _, run := range runs {
+		row := syncRunRow{
+			Job:        run.Job,
+			EntityType: run.EntityType,
+			Trigger:    run.Trigger,
+			Status:     run.Status,
+			StartedAt:  run.StartedAt.Format("2006-01-02 15:04:05"),
+			Fetched:    run.RecordsFetched,
+			Inserted:   run.RecordsInserted,
+			Updated:    run.RecordsUpdated,
+			Failed:     run.RecordsFailed,
+			Deleted:    run.RecordsDeleted,
+			ErrorCount: run.ErrorCount,
+			Errors:     run.Errors,
+		}
+		if run.DurationMs != nil {
+			row.Duration = fmt.Sprintf("%.1fs", float64(*run.DurationMs)/1000)
+		}
+		if run.RateLimitWaits > 0 {
+			row.RateLimit = fmt.Sprintf("%d waits, %.1fs", run.RateLimitWaits, float64(run.RateLimitWaitMs)/1000)
+		}
+		if run.Message != nil {
+			row.Message = *run.Message
+		}
+		rows = append(rows, row)
+	}
+
+	data := map[string]interface{}{
+		"Runs":     rows,
+		"ActiveVA": activeVA,
+	}
+
+	if err := RenderPartial(w, "partials/sync-runs-table.html", data); err != nil {
+		http.Error(w, "Error rendering sync runs table", http.StatusInternalServerError)
+		return
+	}
+}